@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/configs"
+	"github.com/DangVTNhan/Scanner/be/internal/database"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository/mongodb"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/services"
+)
+
+// runAPIKeyCommand handles the "apikey" subcommand, which manages API keys without an existing key,
+// e.g. to create the first one:
+//
+//	api apikey [-tenant TENANT] create NAME
+//	api apikey [-tenant TENANT] list
+//	api apikey [-tenant TENANT] revoke ID
+//
+// Keys are created for, and listed and revoked within, the default tenant unless -tenant is given.
+func runAPIKeyCommand(config *configs.Config, args []string) error {
+	flags := flag.NewFlagSet("apikey", flag.ContinueOnError)
+	tenant := tenantFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	args = flags.Args()
+	if len(args) == 0 {
+		return fmt.Errorf("usage: apikey [-tenant TENANT] <create NAME|list|revoke ID>")
+	}
+	action := args[0]
+	switch {
+	case action == "create" && len(args) != 2:
+		return fmt.Errorf("usage: apikey create NAME")
+	case action == "revoke" && len(args) != 2:
+		return fmt.Errorf("usage: apikey revoke ID")
+	case action != "create" && action != "list" && action != "revoke":
+		return fmt.Errorf("unknown apikey action %q", action)
+	}
+
+	ctx, err := tenantContext(context.Background(), *tenant)
+	if err != nil {
+		return err
+	}
+	if config.IsDemoMode() {
+		return fmt.Errorf("apikey needs MongoDB: the in-memory demo storage prints its API key at startup")
+	}
+
+	client, db, err := database.InitDatabase(config.MongoURI, config.DatabaseName, false)
+	if err != nil {
+		return err
+	}
+	defer database.Disconnect(context.Background(), client)
+
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	apiKeyService := services.NewAPIKeyService(mongodb.NewMongoAPIKeyRepository(mongodb.NewMongoDatabaseWrapper(db)))
+
+	switch action {
+	case "create":
+		created, err := apiKeyService.CreateAPIKey(ctx, &request.APIKeyRequest{Name: args[1]})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created API key %s (%s) of tenant %s. Store it now, it cannot be shown again:\n%s\n", created.APIKey.ID, created.APIKey.Name, *tenant, created.Key)
+		return nil
+	case "revoke":
+		revoked, err := apiKeyService.RevokeAPIKey(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Revoked API key %s (%s)\n", revoked.ID, revoked.Name)
+		return nil
+	}
+
+	keys, err := apiKeyService.ListAPIKeys(ctx)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tPREFIX\tCREATED AT\tLAST USED AT\tREVOKED AT")
+	for _, key := range keys {
+		lastUsedAt, revokedAt := "never", "-"
+		if key.LastUsedAt != nil {
+			lastUsedAt = key.LastUsedAt.Format(time.RFC3339)
+		}
+		if key.RevokedAt != nil {
+			revokedAt = key.RevokedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s…\t%s\t%s\t%s\n", key.ID, strings.ReplaceAll(key.Name, "\t", " "), key.Prefix,
+			key.CreatedAt.Format(time.RFC3339), lastUsedAt, revokedAt)
+	}
+	return w.Flush()
+}
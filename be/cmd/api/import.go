@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/configs"
+	"github.com/DangVTNhan/Scanner/be/internal/database"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository/mongodb"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/services"
+)
+
+// runImportCommand handles the "import" subcommand, the CLI equivalent of POST /api/reports/import:
+//
+//	api import [-format csv|ndjson] [-dry-run] [-tenant TENANT] FILE
+//
+// The format defaults to ndjson for .ndjson and .jsonl files and to csv otherwise. Reports are imported
+// into the default tenant unless -tenant is given, within the tenant's report quota.
+func runImportCommand(config *configs.Config, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	formatFlag := flags.String("format", "", "File format, csv or ndjson (default from the file extension)")
+	dryRun := flags.Bool("dry-run", false, "Only validate the file and count the reports that would be imported")
+	tenant := tenantFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: import [-format csv|ndjson] [-dry-run] [-tenant TENANT] FILE")
+	}
+	path := flags.Arg(0)
+
+	if *formatFlag == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".ndjson", ".jsonl":
+			*formatFlag = string(request.ImportFormatNDJSON)
+		}
+	}
+	format, err := request.ParseImportFormat(*formatFlag)
+	if err != nil {
+		return err
+	}
+
+	ctx, err := tenantContext(context.Background(), *tenant)
+	if err != nil {
+		return err
+	}
+	reportQuota, err := newReportQuota(config)
+	if err != nil {
+		return err
+	}
+
+	if config.IsDemoMode() {
+		return fmt.Errorf("import needs MongoDB: reports of the in-memory demo storage cannot be imported from another process")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	client, db, err := database.InitDatabase(config.MongoURI, config.DatabaseName, false)
+	if err != nil {
+		return err
+	}
+	defer database.Disconnect(context.Background(), client)
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Minute)
+	defer cancel()
+
+	// Importing only uses the report repository
+	reportRepository := mongodb.NewMongoReportRepository(mongodb.NewMongoDatabaseWrapper(db))
+	reportService := services.NewReportService(reportRepository, nil, nil, nil)
+	reportService.SetReportQuota(reportQuota)
+
+	result, err := reportService.ImportReports(ctx, format, file, *dryRun)
+	if result != nil {
+		fmt.Printf("%d row(s) read, %d report(s) %s, %d duplicate(s) skipped, %d invalid\n",
+			result.Rows, result.Imported, pastTense("imported", *dryRun), result.Duplicates, result.Failed)
+
+		if len(result.Errors) > 0 {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "LINE\tFIELD\tERROR")
+			for _, lineErr := range result.Errors {
+				fmt.Fprintf(w, "%d\t%s\t%s\n", lineErr.Line, lineErr.Field, lineErr.Message)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			if result.ErrorsTruncated {
+				fmt.Printf("... %d more invalid row(s) not listed\n", result.Failed-len(result.Errors))
+			}
+		}
+	}
+	return err
+}
@@ -14,10 +14,17 @@ import (
 	_ "github.com/DangVTNhan/Scanner/be/docs" // Import swagger docs
 	"github.com/DangVTNhan/Scanner/be/internal/database"
 	"github.com/DangVTNhan/Scanner/be/internal/handlers"
+	"github.com/DangVTNhan/Scanner/be/internal/interfaces"
 	"github.com/DangVTNhan/Scanner/be/internal/middleware"
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository/memory"
 	"github.com/DangVTNhan/Scanner/be/internal/models/repository/mongodb"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
 	"github.com/DangVTNhan/Scanner/be/internal/services"
+	"github.com/DangVTNhan/Scanner/be/pkg/jwt"
 	"github.com/DangVTNhan/Scanner/be/pkg/openweather"
+	"github.com/MicahParks/keyfunc/v3"
 	"github.com/gorilla/mux"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
@@ -35,42 +42,175 @@ import (
 
 // @host      localhost:8080
 // @BasePath  /api
+
+// @securityDefinitions.apikey  ApiKeyAuth
+// @in                          header
+// @name                        X-API-Key
+// @description                 API key created with POST /api-keys or the apikey command. Clients that cannot set headers may pass it in the apiKey query parameter instead.
+
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+// @description                 "Bearer " followed by a JWT of the company SSO, when the API is configured with its JWKS.
 func main() {
 	// Load configuration
 	config := configs.LoadConfig()
 
+	// Run CLI subcommands instead of the server when requested
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			if err := runMigrateCommand(config, os.Args[2:]); err != nil {
+				log.Fatalf("Migration failed: %v", err)
+			}
+			return
+		case "import":
+			if err := runImportCommand(config, os.Args[2:]); err != nil {
+				log.Fatalf("Import failed: %v", err)
+			}
+			return
+		case "role":
+			if err := runRoleCommand(config, os.Args[2:]); err != nil {
+				log.Fatalf("Role command failed: %v", err)
+			}
+			return
+		case "apikey":
+			if err := runAPIKeyCommand(config, os.Args[2:]); err != nil {
+				log.Fatalf("API key command failed: %v", err)
+			}
+			return
+		}
+	}
+
 	if config.OpenWeatherAPIKey == "" {
 		log.Fatal("OPENWEATHER_API_KEY environment variable is required")
 	}
-
-	// Connect to MongoDB and initialize database with indexes
-	client, db, err := database.InitDatabase(config.MongoURI, config.DatabaseName)
+	if !models.Role(config.DefaultRole).IsValid() {
+		log.Fatalf("DEFAULT_ROLE must be one of viewer, analyst, operator or admin, got %q", config.DefaultRole)
+	}
+	reportQuota, err := newReportQuota(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defaultRateLimit, routeRateLimits, err := newRateLimits(config.RateLimit)
 	if err != nil {
-		log.Fatalf("Failed to initialize database: %v", err)
+		log.Fatal(err)
+	}
+	ipRateLimit, err := models.ParseRateLimit(config.RateLimit.IP)
+	if err != nil {
+		log.Fatalf("RATE_LIMIT_IP: %v", err)
+	}
+	if config.RateLimit.Store != configs.StorageMemory && config.RateLimit.Store != configs.StorageMongo {
+		log.Fatalf("RATE_LIMIT_STORE must be memory or mongo, got %q", config.RateLimit.Store)
 	}
-	defer func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-		if err = client.Disconnect(ctx); err != nil {
-			log.Fatalf("Failed to disconnect from MongoDB: %v", err)
+	if config.RateLimit.Store == configs.StorageMongo && config.IsDemoMode() {
+		log.Fatal("RATE_LIMIT_STORE=mongo requires STORAGE_BACKEND=mongo")
+	}
+
+	// Initialize repositories
+	var reportRepository repository.IReportRepository
+	var weatherCacheRepository repository.IWeatherCacheRepository
+	var comparisonRepository repository.IComparisonRepository
+	var thresholdRepository repository.IThresholdRepository
+	var apiKeyRepository repository.IAPIKeyRepository
+	var roleAssignmentRepository repository.IRoleAssignmentRepository
+	var auditLogRepository repository.IAuditLogRepository
+	var rateLimitRepository repository.IRateLimitRepository = memory.NewRateLimitRepository()
+
+	if config.IsDemoMode() {
+		fmt.Println("Demo mode enabled: reports are kept in memory and lost on restart")
+		reportRepository = memory.NewReportRepository()
+		weatherCacheRepository = memory.NewWeatherCacheRepository()
+		comparisonRepository = memory.NewComparisonRepository()
+		thresholdRepository = memory.NewThresholdRepository()
+		apiKeyRepository = memory.NewAPIKeyRepository()
+		roleAssignmentRepository = memory.NewRoleAssignmentRepository()
+		auditLogRepository = memory.NewAuditLogRepository()
+	} else {
+		// Connect to MongoDB and initialize database with indexes
+		client, db, err := database.InitDatabase(config.MongoURI, config.DatabaseName, config.MigrateOnStartup)
+		if err != nil {
+			log.Fatalf("Failed to initialize database: %v", err)
 		}
-	}()
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err = client.Disconnect(ctx); err != nil {
+				log.Fatalf("Failed to disconnect from MongoDB: %v", err)
+			}
+		}()
 
-	// Wrap the database with our interface wrapper
-	dbWrapper := mongodb.NewMongoDatabaseWrapper(db)
+		// Wrap the database with our interface wrapper
+		dbWrapper := mongodb.NewMongoDatabaseWrapper(db)
 
-	// Initialize repositories
-	reportRepository := mongodb.NewMongoReportRepository(dbWrapper)
-	weatherCacheRepository := mongodb.NewMongoWeatherCacheRepository(dbWrapper)
+		reportRepository = mongodb.NewMongoReportRepository(dbWrapper)
+		weatherCacheRepository = mongodb.NewMongoWeatherCacheRepository(dbWrapper)
+		comparisonRepository = mongodb.NewMongoComparisonRepository(dbWrapper)
+		thresholdRepository = mongodb.NewMongoThresholdRepository(dbWrapper)
+		apiKeyRepository = mongodb.NewMongoAPIKeyRepository(dbWrapper)
+		roleAssignmentRepository = mongodb.NewMongoRoleAssignmentRepository(dbWrapper)
+		auditLogRepository = mongodb.NewMongoAuditLogRepository(dbWrapper)
+		if config.RateLimit.Store == configs.StorageMongo {
+			// Share the buckets between replicas so that a client gets the configured limit in total
+			rateLimitRepository = mongodb.NewMongoRateLimitRepository(dbWrapper)
+		}
+	}
 
 	// Initialize weather service with caching
 	weatherService := openweather.NewWeatherService(config.OpenWeatherAPIKey)
 
 	// Initialize services with repositories
-	reportService := services.NewReportService(reportRepository, weatherCacheRepository, weatherService)
+	thresholdService := services.NewThresholdService(thresholdRepository)
+	reportService := services.NewReportService(reportRepository, weatherCacheRepository, weatherService, thresholdService)
+	reportService.SetReportQuota(reportQuota)
+	comparisonService := services.NewComparisonService(comparisonRepository, thresholdService)
+	apiKeyService := services.NewAPIKeyService(apiKeyRepository)
+	roleService := services.NewRoleService(roleAssignmentRepository, models.Role(config.DefaultRole))
+	auditService := services.NewAuditService(auditLogRepository)
+	rateLimitService := services.NewRateLimitService(rateLimitRepository, defaultRateLimit, routeRateLimits)
+	ipRateLimitService := services.NewRateLimitService(rateLimitRepository, ipRateLimit, nil)
+
+	var tokenService interfaces.ITokenService
+	if config.IsTokenAuthEnabled() {
+		verifier, err := newTokenVerifier(config.JWT)
+		if err != nil {
+			log.Fatalf("Failed to set up bearer token authentication: %v", err)
+		}
+		tokenService = services.NewTokenService(verifier, config.JWT.TenantClaim)
+		fmt.Printf("Bearer token authentication enabled for issuer %s\n", config.JWT.Issuer)
+	}
+
+	// In demo mode nobody can have created a key yet, so create an admin key for this run
+	if config.AuthEnabled && config.IsDemoMode() {
+		created, err := apiKeyService.CreateAPIKey(context.Background(), &request.APIKeyRequest{Name: "demo"})
+		if err != nil {
+			log.Fatalf("Failed to create demo API key: %v", err)
+		}
+		_, err = roleService.AssignRole(context.Background(), nil, models.AuthMethodAPIKey, created.APIKey.ID,
+			&request.RoleAssignmentRequest{Role: models.RoleAdmin})
+		if err != nil {
+			log.Fatalf("Failed to assign a role to the demo API key: %v", err)
+		}
+		fmt.Printf("Demo API key (admin): %s\n", created.Key)
+	}
+
+	// Purge soft-deleted reports once their retention window has passed
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	if config.PurgeInterval > 0 {
+		purgeJob := services.NewPurgeJob(reportService, config.DeletedReportRetention, config.PurgeInterval)
+		go purgeJob.Run(jobCtx)
+	}
 
 	// Initialize handlers
-	reportHandler := handlers.NewReportHandler(reportService)
+	reportHandler := handlers.NewReportHandler(reportService, comparisonService)
+	comparisonHandler := handlers.NewComparisonHandler(comparisonService)
+	thresholdHandler := handlers.NewThresholdHandler(thresholdService)
+	chartHandler := handlers.NewChartHandler(reportService)
+	feedHandler := handlers.NewFeedHandler(reportService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	roleHandler := handlers.NewRoleHandler(roleService)
+	auditHandler := handlers.NewAuditHandler(auditService)
 
 	// Set up router
 	router := mux.NewRouter()
@@ -78,12 +218,73 @@ func main() {
 	// Apply CORS middleware - must be added before routes
 	router.Use(middleware.CORSMiddleware)
 
+	// Limit requests per IP address before checking their credentials, so guessing them is limited too
+	if config.RateLimit.Enabled {
+		router.Use(middleware.IPRateLimitMiddleware(ipRateLimitService, config.RateLimit.TrustProxy, config.RateLimit.FailOpen))
+	}
+
+	// Require an API key or bearer token on API routes; CORS headers are still set on rejected requests
+	if config.AuthEnabled {
+		router.Use(middleware.AuthMiddleware(apiKeyService, tokenService))
+	} else {
+		fmt.Println("Authentication disabled: API routes are open to anyone")
+	}
+
+	// Record every mutating request, including those rejected by the client rate limit or for a missing permission
+	router.Use(middleware.AuditMiddleware(auditService, config.RateLimit.TrustProxy))
+
+	// Limit requests per API key, token subject or, for anonymous requests, IP address
+	if config.RateLimit.Enabled {
+		router.Use(middleware.RateLimitMiddleware(rateLimitService, config.RateLimit.TrustProxy, config.RateLimit.FailOpen))
+	}
+
+	// Every route requires a permission of the caller's role
+	authorize := middleware.PermissionMiddleware(roleService)
+
 	// API routes
-	router.HandleFunc("/api/reports", reportHandler.GenerateReport).Methods("POST", "OPTIONS")
-	router.HandleFunc("/api/reports", reportHandler.GetAllReports).Methods("GET", "OPTIONS")
-	router.HandleFunc("/api/reports/paginated", reportHandler.GetPaginatedReports).Methods("GET", "OPTIONS")
-	router.HandleFunc("/api/reports/{id}", reportHandler.GetReportByID).Methods("GET", "OPTIONS")
-	router.HandleFunc("/api/reports/compare", reportHandler.CompareReports).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/reports", authorize(models.PermissionGenerateReports, reportHandler.GenerateReport)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/reports", authorize(models.PermissionReadReports, reportHandler.GetAllReports)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/reports/batch", authorize(models.PermissionGenerateReports, reportHandler.GenerateReports)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/reports/paginated", authorize(models.PermissionReadReports, reportHandler.GetPaginatedReports)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/reports/export", authorize(models.PermissionReadReports, reportHandler.ExportReports)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/reports/import", authorize(models.PermissionImportReports, reportHandler.ImportReports)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/reports/compare/render", authorize(models.PermissionReadReports, reportHandler.RenderReportComparison)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/reports/{id}", authorize(models.PermissionReadReports, reportHandler.GetReportByID)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/reports/{id}", authorize(models.PermissionAnnotateReports, reportHandler.UpdateReport)).Methods("PATCH", "OPTIONS")
+	router.HandleFunc("/api/reports/{id}", authorize(models.PermissionDeleteReports, reportHandler.DeleteReport)).Methods("DELETE", "OPTIONS")
+	router.HandleFunc("/api/reports/{id}/restore", authorize(models.PermissionDeleteReports, reportHandler.RestoreReport)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/reports/{id}/render", authorize(models.PermissionReadReports, reportHandler.RenderReport)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/reports/compare", authorize(models.PermissionCompareReports, reportHandler.CompareReports)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/tenant/usage", authorize(models.PermissionReadReports, reportHandler.GetTenantUsage)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/tags", authorize(models.PermissionReadReports, reportHandler.GetTags)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/weather-cache/export", authorize(models.PermissionReadReports, reportHandler.ExportWeatherCaches)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/comparisons", authorize(models.PermissionCompareReports, reportHandler.CompareMultipleReports)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/comparisons", authorize(models.PermissionReadReports, comparisonHandler.ListComparisons)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/comparisons/periods", authorize(models.PermissionCompareReports, reportHandler.ComparePeriods)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/comparisons/{id}", authorize(models.PermissionReadReports, comparisonHandler.GetComparison)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/comparisons/{id}", authorize(models.PermissionCompareReports, comparisonHandler.DeleteComparison)).Methods("DELETE", "OPTIONS")
+	router.HandleFunc("/api/comparisons/{id}/render", authorize(models.PermissionReadReports, comparisonHandler.RenderComparison)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/thresholds", authorize(models.PermissionReadReports, thresholdHandler.GetThresholds)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/thresholds/{metric}", authorize(models.PermissionManageThresholds, thresholdHandler.SetThreshold)).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/api/thresholds/{metric}", authorize(models.PermissionManageThresholds, thresholdHandler.ResetThreshold)).Methods("DELETE", "OPTIONS")
+	router.HandleFunc("/api/charts/metric", authorize(models.PermissionReadReports, chartHandler.GetMetricChart)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/feeds/reports.atom", authorize(models.PermissionReadReports, feedHandler.GetReportsAtom)).Methods("GET", "HEAD", "OPTIONS")
+	router.HandleFunc("/api/feeds/reports.rss", authorize(models.PermissionReadReports, feedHandler.GetReportsRSS)).Methods("GET", "HEAD", "OPTIONS")
+	router.HandleFunc("/api/feeds/alerts.ics", authorize(models.PermissionReadReports, feedHandler.GetAlertsICS)).Methods("GET", "HEAD", "OPTIONS")
+	router.HandleFunc("/api/api-keys", authorize(models.PermissionManageAPIKeys, apiKeyHandler.CreateAPIKey)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/api-keys", authorize(models.PermissionManageAPIKeys, apiKeyHandler.ListAPIKeys)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/api-keys/{id}", authorize(models.PermissionManageAPIKeys, apiKeyHandler.RevokeAPIKey)).Methods("DELETE", "OPTIONS")
+	router.HandleFunc("/api/me", authorize(models.PermissionReadReports, roleHandler.GetCurrentIdentity)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/admin/roles", authorize(models.PermissionManageRoles, roleHandler.GetRoles)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/admin/role-assignments", authorize(models.PermissionManageRoles, roleHandler.ListRoleAssignments)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/admin/role-assignments/{method}/{subject:.+}", authorize(models.PermissionManageRoles, roleHandler.AssignRole)).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/api/admin/role-assignments/{method}/{subject:.+}", authorize(models.PermissionManageRoles, roleHandler.RemoveRoleAssignment)).Methods("DELETE", "OPTIONS")
+	router.HandleFunc("/api/admin/audit", authorize(models.PermissionReadAuditLog, auditHandler.ListAuditEntries)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/admin/audit/export", authorize(models.PermissionReadAuditLog, auditHandler.ExportAuditEntries)).Methods("GET", "OPTIONS")
+
+	if err := checkRateLimitRoutes(router, routeRateLimits); err != nil {
+		log.Fatal(err)
+	}
 
 	// Swagger documentation - only available in dev/stg environments
 	if config.IsSwaggerEnabled() {
@@ -130,3 +331,33 @@ func main() {
 
 	fmt.Println("Server gracefully stopped")
 }
+
+// newTokenVerifier creates the verifier of bearer tokens and loads its key set, so a misconfigured
+// key set fails at startup rather than on the first request
+func newTokenVerifier(config configs.JWTConfig) (*jwt.Verifier, error) {
+	if config.JWKSFile != "" && config.JWKSURL != "" {
+		return nil, fmt.Errorf("set only one of JWT_JWKS_FILE and JWT_JWKS_URL")
+	}
+	if config.Issuer == "" || config.Audience == "" {
+		return nil, fmt.Errorf("JWT_ISSUER and JWT_AUDIENCE are required")
+	}
+
+	// The context is never cancelled: the key set is reloaded in the background for the lifetime of the server
+	var keys keyfunc.Keyfunc
+	var err error
+	if config.JWKSURL != "" {
+		keys, err = jwt.NewURLKeys(context.Background(), &http.Client{Timeout: 10 * time.Second}, config.JWKSURL, config.RefreshInterval)
+	} else {
+		keys, err = jwt.NewFileKeys(context.Background(), config.JWKSFile, config.RefreshInterval)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &jwt.Verifier{
+		Keys:     keys,
+		Issuer:   config.Issuer,
+		Audience: config.Audience,
+		Leeway:   config.Leeway,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/configs"
+	"github.com/DangVTNhan/Scanner/be/internal/database"
+)
+
+// runMigrateCommand handles the "migrate" subcommand:
+//
+//	api migrate status
+//	api migrate up [-to VERSION] [-dry-run]
+//	api migrate down [-steps N] [-dry-run]
+func runMigrateCommand(config *configs.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate <status|up|down> [flags]")
+	}
+
+	action := args[0]
+	flags := flag.NewFlagSet("migrate "+action, flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "Only print the migrations that would run")
+	target := flags.Int("to", 0, "Apply migrations up to and including this version (0 means all)")
+	steps := flags.Int("steps", 1, "Number of migrations to revert")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	client, db, err := database.InitDatabase(config.MongoURI, config.DatabaseName, false)
+	if err != nil {
+		return err
+	}
+	defer database.Disconnect(context.Background(), client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	migrator := database.NewMigrator(db, database.Migrations)
+	migrator.SetDryRun(*dryRun)
+
+	switch action {
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tAPPLIED AT\tDESCRIPTION")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.Applied {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, appliedAt, status.Description)
+		}
+		return w.Flush()
+	case "up":
+		applied, err := migrator.Up(ctx, *target)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d migration(s) %s\n", len(applied), pastTense("applied", *dryRun))
+		return nil
+	case "down":
+		reverted, err := migrator.Down(ctx, *steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d migration(s) %s\n", len(reverted), pastTense("reverted", *dryRun))
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate action %q", action)
+	}
+}
+
+// pastTense describes the outcome of a migrate action, accounting for dry runs
+func pastTense(verb string, dryRun bool) string {
+	if dryRun {
+		return "would be " + verb
+	}
+	return verb
+}
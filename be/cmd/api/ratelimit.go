@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DangVTNhan/Scanner/be/configs"
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/gorilla/mux"
+)
+
+// newRateLimits builds the default limit from RATE_LIMIT_DEFAULT and the route limits from the
+// "METHOD /path=limit" pairs of RATE_LIMIT_ROUTES
+func newRateLimits(config configs.RateLimitConfig) (models.RateLimit, map[string]models.RateLimit, error) {
+	defaultLimit, err := models.ParseRateLimit(config.Default)
+	if err != nil {
+		return defaultLimit, nil, fmt.Errorf("RATE_LIMIT_DEFAULT: %w", err)
+	}
+
+	routeLimits := make(map[string]models.RateLimit)
+	for _, pair := range strings.Split(config.Routes, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		route, limitStr, ok := strings.Cut(pair, "=")
+		method, path, hasPath := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok || !hasPath || method == "" || !strings.HasPrefix(strings.TrimSpace(path), "/api/") {
+			return defaultLimit, nil, fmt.Errorf("RATE_LIMIT_ROUTES must be a list of \"METHOD /api/path=limit\" pairs, got %q", pair)
+		}
+		limit, err := models.ParseRateLimit(limitStr)
+		if err != nil {
+			return defaultLimit, nil, fmt.Errorf("RATE_LIMIT_ROUTES: %w", err)
+		}
+		routeLimits[strings.ToUpper(method)+" "+strings.TrimSpace(path)] = limit
+	}
+	return defaultLimit, routeLimits, nil
+}
+
+// checkRateLimitRoutes returns an error if a route limit names a route that the router does not have,
+// so that a typo does not silently leave the route with the default limit
+func checkRateLimitRoutes(router *mux.Router, routeLimits map[string]models.RateLimit) error {
+	routes := make(map[string]bool)
+	err := router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+		for _, method := range methods {
+			routes[method+" "+path] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for route := range routeLimits {
+		if !routes[route] {
+			return fmt.Errorf("RATE_LIMIT_ROUTES: unknown route %q, use the method and path template, e.g. \"GET /api/reports/{id}\"", route)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/configs"
+	"github.com/DangVTNhan/Scanner/be/internal/database"
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository/mongodb"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/services"
+)
+
+// runRoleCommand handles the "role" subcommand, which manages role assignments without an admin caller,
+// e.g. to make the first API key an admin:
+//
+//	api role [-tenant TENANT] assign api_key KEY_ID admin
+//	api role [-tenant TENANT] remove METHOD SUBJECT
+//	api role [-tenant TENANT] list
+//
+// Roles are assigned within the default tenant unless -tenant is given.
+func runRoleCommand(config *configs.Config, args []string) error {
+	flags := flag.NewFlagSet("role", flag.ContinueOnError)
+	tenant := tenantFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	args = flags.Args()
+	if len(args) == 0 {
+		return fmt.Errorf("usage: role [-tenant TENANT] <assign METHOD SUBJECT ROLE|remove METHOD SUBJECT|list>")
+	}
+	action := args[0]
+	switch {
+	case action == "assign" && len(args) != 4:
+		return fmt.Errorf("usage: role assign METHOD SUBJECT ROLE")
+	case action == "remove" && len(args) != 3:
+		return fmt.Errorf("usage: role remove METHOD SUBJECT")
+	case action != "assign" && action != "remove" && action != "list":
+		return fmt.Errorf("unknown role action %q", action)
+	}
+
+	ctx, err := tenantContext(context.Background(), *tenant)
+	if err != nil {
+		return err
+	}
+	if config.IsDemoMode() {
+		return fmt.Errorf("role needs MongoDB: the in-memory demo storage makes its API key an admin at startup")
+	}
+
+	client, db, err := database.InitDatabase(config.MongoURI, config.DatabaseName, false)
+	if err != nil {
+		return err
+	}
+	defer database.Disconnect(context.Background(), client)
+
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	roleService := services.NewRoleService(mongodb.NewMongoRoleAssignmentRepository(mongodb.NewMongoDatabaseWrapper(db)),
+		models.Role(config.DefaultRole))
+
+	switch action {
+	case "assign":
+		assignment, err := roleService.AssignRole(ctx, nil, models.AuthMethod(args[1]), args[2],
+			&request.RoleAssignmentRequest{Role: models.Role(args[3])})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Assigned role %s to %s %s\n", assignment.Role, assignment.Method, assignment.Subject)
+		return nil
+	case "remove":
+		if err := roleService.RemoveRoleAssignment(ctx, nil, models.AuthMethod(args[1]), args[2]); err != nil {
+			return err
+		}
+		fmt.Printf("Removed the role assignment of %s %s, who now has the default role %s\n", args[1], args[2], config.DefaultRole)
+		return nil
+	}
+
+	assignments, err := roleService.ListRoleAssignments(ctx)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tSUBJECT\tROLE\tUPDATED AT\tUPDATED BY")
+	for _, assignment := range assignments {
+		updatedBy := assignment.UpdatedBy
+		if updatedBy == "" {
+			updatedBy = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", assignment.Method, assignment.Subject, assignment.Role,
+			assignment.UpdatedAt.Format(time.RFC3339), updatedBy)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/DangVTNhan/Scanner/be/configs"
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+)
+
+// tenantFlag defines the -tenant flag of the subcommands that read or change data of a tenant
+func tenantFlag(flags *flag.FlagSet) *string {
+	return flags.String("tenant", models.DefaultTenant, "Tenant whose data the command sees and changes")
+}
+
+// tenantContext returns a copy of ctx whose repository calls only see and create data of tenant
+func tenantContext(ctx context.Context, tenant string) (context.Context, error) {
+	if !models.IsValidTenant(tenant) {
+		return nil, fmt.Errorf("invalid tenant %q: use lower-case letters, digits and dashes", tenant)
+	}
+	return models.ContextWithTenant(ctx, tenant), nil
+}
+
+// newReportQuota builds the report quota from TENANT_REPORT_QUOTA and the "tenant=limit" pairs of TENANT_REPORT_QUOTAS
+func newReportQuota(config *configs.Config) (models.ReportQuota, error) {
+	quota := models.ReportQuota{Default: config.TenantReportQuota, Tenants: make(map[string]int64)}
+	for _, pair := range strings.Split(config.TenantReportQuotas, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		tenant, limitStr, ok := strings.Cut(pair, "=")
+		tenant = strings.TrimSpace(tenant)
+		limit, err := strconv.ParseInt(strings.TrimSpace(limitStr), 10, 64)
+		if !ok || !models.IsValidTenant(tenant) || err != nil || limit < 0 {
+			return quota, fmt.Errorf("TENANT_REPORT_QUOTAS must be a list of tenant=limit pairs, got %q", pair)
+		}
+		quota.Tenants[tenant] = limit
+	}
+	return quota, nil
+}
@@ -2,7 +2,9 @@ package configs
 
 import (
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Environment type constants
@@ -12,6 +14,12 @@ const (
 	EnvProd = "prod"
 )
 
+// Storage backend constants
+const (
+	StorageMongo  = "mongo"
+	StorageMemory = "memory" // Demo mode: data lives in process memory and is lost on restart
+)
+
 // Config holds the application configuration
 type Config struct {
 	MongoURI          string
@@ -20,6 +28,19 @@ type Config struct {
 	Port              string
 	CORS              CORSConfig
 	Environment       string
+	StorageBackend    string
+	MigrateOnStartup  bool
+	AuthEnabled       bool // Require an API key or bearer token on /api routes
+	JWT               JWTConfig
+	DefaultRole       string // Role of callers without a role assignment
+
+	RateLimit RateLimitConfig
+
+	TenantReportQuota  int64  // How many reports a tenant may store, 0 for no limit
+	TenantReportQuotas string // Limits of individual tenants, e.g. "changi-ops=50000,seletar-ops=0"
+
+	DeletedReportRetention time.Duration // How long soft-deleted reports can be restored before they are purged
+	PurgeInterval          time.Duration // How often the purge job runs (0 disables it)
 }
 
 // CORSConfig holds the CORS configuration
@@ -31,6 +52,28 @@ type CORSConfig struct {
 	MaxAge           int
 }
 
+// JWTConfig holds the configuration of bearer token authentication. It is enabled when a JWKS file or URL is set.
+type JWTConfig struct {
+	JWKSFile        string        // Path of a JSON Web Key Set file
+	JWKSURL         string        // URL of a JSON Web Key Set, e.g. the jwks_uri of the identity provider
+	Issuer          string        // Required "iss" claim
+	Audience        string        // Value that the "aud" claim must contain
+	Leeway          time.Duration // Allowed clock skew when checking expiry
+	RefreshInterval time.Duration // How often the key set is reloaded
+	TenantClaim     string        // Claim that holds the tenant of the token's user
+}
+
+// RateLimitConfig holds the configuration of per-client rate limiting of /api routes
+type RateLimitConfig struct {
+	Enabled    bool
+	Default    string // Limit of routes without their own, e.g. "600/1m", or "0" for none
+	Routes     string // Limits of individual routes, e.g. "POST /api/reports=30/1m,POST /api/reports/batch=3/1m"
+	IP         string // Limit of each IP address on all routes, checked before authentication, or "0" for none
+	Store      string // Where buckets are kept: "memory" per replica, or "mongo" shared by all replicas
+	TrustProxy bool   // Take client addresses, for the IP limit, of anonymous clients and in the audit log, from the last X-Forwarded-For entry
+	FailOpen   bool   // Let requests through, rather than reject them, when the store cannot be reached
+}
+
 // LoadConfig loads the configuration from environment variables
 func LoadConfig() *Config {
 	// Default CORS allowed origins
@@ -55,8 +98,8 @@ func LoadConfig() *Config {
 	// CORS configuration
 	corsConfig := CORSConfig{
 		AllowedOrigins: allowedOrigins,
-		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders: []string{"Content-Type", "Authorization", "X-Requested-With"},
+		AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders: []string{"Content-Type", "Authorization", "X-Requested-With", "X-API-Key"},
 		MaxAge:         3600,
 	}
 
@@ -67,6 +110,34 @@ func LoadConfig() *Config {
 		Port:              getEnv("PORT", "8080"),
 		CORS:              corsConfig,
 		Environment:       getEnv("ENVIRONMENT", EnvDev),
+		StorageBackend:    getEnv("STORAGE_BACKEND", StorageMongo),
+		MigrateOnStartup:  getEnvBool("MIGRATE_ON_STARTUP", true),
+		AuthEnabled:       getEnvBool("AUTH_ENABLED", true),
+		DefaultRole:       getEnv("DEFAULT_ROLE", "viewer"),
+		JWT: JWTConfig{
+			JWKSFile:        os.Getenv("JWT_JWKS_FILE"),
+			JWKSURL:         os.Getenv("JWT_JWKS_URL"),
+			Issuer:          os.Getenv("JWT_ISSUER"),
+			Audience:        os.Getenv("JWT_AUDIENCE"),
+			Leeway:          getEnvDuration("JWT_LEEWAY", time.Minute),
+			RefreshInterval: getEnvDuration("JWT_JWKS_REFRESH_INTERVAL", time.Hour),
+			TenantClaim:     getEnv("JWT_TENANT_CLAIM", "tenant"),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:    getEnvBool("RATE_LIMIT_ENABLED", true),
+			Default:    getEnv("RATE_LIMIT_DEFAULT", "600/1m"),
+			Routes:     getEnv("RATE_LIMIT_ROUTES", "POST /api/reports=30/1m,POST /api/reports/batch=5/1m"),
+			IP:         getEnv("RATE_LIMIT_IP", "1200/1m"),
+			Store:      getEnv("RATE_LIMIT_STORE", StorageMemory),
+			TrustProxy: getEnvBool("RATE_LIMIT_TRUST_PROXY", false),
+			FailOpen:   getEnvBool("RATE_LIMIT_FAIL_OPEN", true),
+		},
+
+		TenantReportQuota:  getEnvInt64("TENANT_REPORT_QUOTA", 0),
+		TenantReportQuotas: os.Getenv("TENANT_REPORT_QUOTAS"),
+
+		DeletedReportRetention: getEnvDuration("DELETED_REPORT_RETENTION", 30*24*time.Hour),
+		PurgeInterval:          getEnvDuration("PURGE_INTERVAL", time.Hour),
 	}
 }
 
@@ -79,7 +150,44 @@ func getEnv(key, defaultValue string) string {
 	return value
 }
 
+// getEnvBool gets a boolean environment variable or returns a default value
+func getEnvBool(key string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvInt64 gets a non-negative integer environment variable or returns a default value
+func getEnvInt64(key string, defaultValue int64) int64 {
+	value, err := strconv.ParseInt(os.Getenv(key), 10, 64)
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvDuration gets a duration environment variable such as "720h" or returns a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+	return value
+}
+
 // IsSwaggerEnabled returns true if Swagger should be enabled based on the environment
 func (c *Config) IsSwaggerEnabled() bool {
 	return c.Environment == EnvDev || c.Environment == EnvStg
 }
+
+// IsTokenAuthEnabled returns true if bearer tokens are accepted
+func (c *Config) IsTokenAuthEnabled() bool {
+	return c.AuthEnabled && (c.JWT.JWKSFile != "" || c.JWT.JWKSURL != "")
+}
+
+// IsDemoMode returns true if the application keeps its data in memory instead of MongoDB
+func (c *Config) IsDemoMode() bool {
+	return c.StorageBackend == StorageMemory
+}
@@ -23,19 +23,83 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/reports": {
+        "/admin/audit": {
             "get": {
-                "description": "Get all weather reports (legacy endpoint, no pagination)",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the recorded POST, PUT, PATCH and DELETE requests of the caller's tenant, newest first: who made them, the route and parameters, the status and the latency.\nPass nextCursor as cursor to get the next, older page.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reports"
+                    "audit"
+                ],
+                "summary": "Search the audit log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subject of the caller: API key ID or token sub claim",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "HTTP method, e.g. DELETE",
+                        "name": "method",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Route path template, e.g. /api/reports/{id}",
+                        "name": "route",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the created or changed item",
+                        "name": "resourceId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "success (status below 400) or failure",
+                        "name": "outcome",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Include entries from this time (RFC3339 format)",
+                        "name": "fromTime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Include entries until this time (RFC3339 format)",
+                        "name": "toTime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of entries to return (default 50, at most 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "nextCursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get all weather reports",
                 "responses": {
                     "200": {
-                        "description": "Reports retrieved successfully",
+                        "description": "Audit entries retrieved successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -45,16 +109,31 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/docs.WeatherReport"
-                                            }
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.AuditLogPage"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -62,51 +141,99 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Generate a new weather report for Changi Airport at a specific time",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/admin/audit/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Download every audit entry matching the same filters as /admin/audit, newest first, streamed as CSV, NDJSON or a JSON array.",
                 "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
                     "application/json"
                 ],
                 "tags": [
-                    "reports"
+                    "audit"
                 ],
-                "summary": "Generate a new weather report",
+                "summary": "Export the audit log",
                 "parameters": [
                     {
-                        "description": "Report request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_request.ReportRequest"
-                        }
+                        "type": "string",
+                        "description": "Export format: csv (default), ndjson or json",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subject of the caller: API key ID or token sub claim",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "HTTP method, e.g. DELETE",
+                        "name": "method",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Route path template, e.g. /api/reports/{id}",
+                        "name": "route",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the created or changed item",
+                        "name": "resourceId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "success (status below 400) or failure",
+                        "name": "outcome",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Include entries from this time (RFC3339 format)",
+                        "name": "fromTime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Include entries until this time (RFC3339 format)",
+                        "name": "toTime",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Report generated successfully",
+                    "200": {
+                        "description": "Exported audit entries",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/docs.WeatherReport"
-                                        }
-                                    }
-                                }
-                            ]
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
                         "schema": {
                             "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
                         }
@@ -120,33 +247,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/reports/compare": {
-            "post": {
-                "description": "Compare two weather reports and calculate the differences",
-                "consumes": [
-                    "application/json"
+        "/admin/role-assignments": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "List the callers that have been assigned a role, ordered by method and subject. Other callers have the default role.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reports"
-                ],
-                "summary": "Compare two weather reports",
-                "parameters": [
-                    {
-                        "description": "Comparison request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_request.ComparisonRequest"
-                        }
-                    }
+                    "roles"
                 ],
+                "summary": "List role assignments",
                 "responses": {
                     "200": {
-                        "description": "Reports compared successfully",
+                        "description": "Role assignments retrieved successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -156,21 +277,24 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.ComparisonResult"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.RoleAssignment"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Invalid request",
+                    "401": {
+                        "description": "Missing or invalid credentials",
                         "schema": {
                             "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
                         }
                     },
-                    "404": {
-                        "description": "Report not found",
+                    "403": {
+                        "description": "Missing permission",
                         "schema": {
                             "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
                         }
@@ -184,57 +308,55 @@ const docTemplate = `{
                 }
             }
         },
-        "/reports/paginated": {
-            "get": {
-                "description": "Get paginated weather reports with optional filtering by time range",
+        "/admin/role-assignments/{method}/{subject}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Give a caller a role, replacing its current one. The caller is an API key (method api_key, subject the key ID)\nor a bearer token user (method token, subject the \"sub\" claim). Admins cannot change their own role.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reports"
+                    "roles"
                 ],
-                "summary": "Get paginated weather reports",
+                "summary": "Assign a role",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Limit number of results",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Offset for pagination",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by start time (RFC3339 format)",
-                        "name": "fromTime",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "Filter by end time (RFC3339 format)",
-                        "name": "toTime",
-                        "in": "query"
+                        "description": "Authentication method: api_key or token",
+                        "name": "method",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Field to sort by",
-                        "name": "sortBy",
-                        "in": "query"
+                        "description": "API key ID or token subject",
+                        "name": "subject",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Sort order (asc or desc)",
-                        "name": "sortOrder",
-                        "in": "query"
+                        "description": "Role assignment request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_request.RoleAssignmentRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Reports retrieved successfully",
+                        "description": "Role assigned successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -244,7 +366,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.PaginatedReportsResponse"
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.RoleAssignment"
                                         }
                                     }
                                 }
@@ -252,7 +374,19 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid parameters",
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
                         "schema": {
                             "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
                         }
@@ -264,30 +398,101 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/reports/{id}": {
-            "get": {
-                "description": "Get a specific weather report by its ID",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove the role assignment of a caller, who then has the default role. Admins cannot change their own role.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reports"
+                    "roles"
                 ],
-                "summary": "Get a weather report by ID",
+                "summary": "Remove a role assignment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Report ID",
-                        "name": "id",
+                        "description": "Authentication method: api_key or token",
+                        "name": "method",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
+                    },
+                    {
+                        "type": "string",
+                        "description": "API key ID or token subject",
+                        "name": "subject",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
                     "200": {
-                        "description": "Report retrieved successfully",
+                        "description": "Role assignment removed successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Role assignment not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/roles": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the roles from least to most privileged with their permissions. Every role has the permissions of the roles below it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "List roles",
+                "responses": {
+                    "200": {
+                        "description": "Roles retrieved successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -297,15 +502,225 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/docs.WeatherReport"
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.RolesResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List all API keys, revoked ones included, newest first. Keys are identified by their prefix; the keys themselves are never returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "List API keys",
+                "responses": {
+                    "200": {
+                        "description": "API keys retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.APIKey"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new API key. The key is only returned in this response; store it, only its hash is kept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Create an API key",
+                "parameters": [
+                    {
+                        "description": "API key request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_request.APIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "API key created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.CreatedAPIKey"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api-keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke an API key so it no longer authenticates. The key stays listed with its revocation time; revoking it again keeps that time.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Revoke an API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "API key revoked successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.APIKey"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
                     "404": {
-                        "description": "Report not found",
+                        "description": "API key not found",
                         "schema": {
                             "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
                         }
@@ -318,163 +733,3406 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "docs.WeatherReport": {
+        },
+        "/charts/metric": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Render the stored reports of a metric in a time range as a standalone SVG line or area chart, for embedding in emails and wiki pages.\nEvery report is plotted unless bucket is set, band is requested or there are more than 1000 reports; the reports are then aggregated per bucket\n(chosen to give at most about 120 points when not set), the line shows the bucket means and the band their min and max.\nWith format=json the plotted points are returned instead.",
+                "produces": [
+                    "image/svg+xml",
+                    "application/json"
+                ],
+                "tags": [
+                    "charts"
+                ],
+                "summary": "Chart the history of a metric",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Metric (temperature, pressure, humidity, cloudCover)",
+                        "name": "metric",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the chart (RFC3339 format, default 7 days before to)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the chart (RFC3339 format, default now)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Aggregate the reports in buckets of this length, e.g. 30m, 3h or 24h",
+                        "name": "bucket",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Set to true to draw the min/max band of every bucket",
+                        "name": "band",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chart style: line or area (default line)",
+                        "name": "style",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Width in pixels, 240 to 4000 (default 800)",
+                        "name": "width",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Height in pixels, 160 to 2000 (default 320)",
+                        "name": "height",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format: svg or json (default svg)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "API key, for clients that cannot send the X-API-Key header",
+                        "name": "apiKey",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Chart data, with format=json",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.MetricSeries"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/comparisons": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List saved comparisons, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comparisons"
+                ],
+                "summary": "List saved comparisons",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Limit number of results (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Comparisons retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.SavedComparisonsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Compare two or more weather reports against a baseline report (the first one unless baselineId is set).\nReturns each report's deviation from the baseline and the spread of every metric across all reports.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Compare several weather reports",
+                "parameters": [
+                    {
+                        "description": "Multi-report comparison request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_request.MultiComparisonRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reports compared successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.MultiComparisonResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Report not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/comparisons/periods": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Aggregate the reports in two time ranges (e.g. this week vs the same week last year) and compare\nthe mean, min and max of every metric. Significance hints are based on Welch's t-test.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Compare two time periods",
+                "parameters": [
+                    {
+                        "description": "Period comparison request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_request.PeriodComparisonRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Periods compared successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.PeriodComparisonResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No reports in a period",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/comparisons/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a saved comparison. The reports are the snapshots taken when it was saved.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comparisons"
+                ],
+                "summary": "Get a saved comparison by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comparison ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Comparison retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.SavedComparison"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Comparison not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a saved comparison by its ID. The compared reports are not affected.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comparisons"
+                ],
+                "summary": "Delete a saved comparison",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comparison ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Comparison deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Comparison not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/comparisons/{id}/render": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Render a saved comparison as a branded HTML page with print styles or as an A4 PDF document,\nwith the metric table and deviation column of the comparison page, its title and its notes.",
+                "produces": [
+                    "text/html",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "comparisons"
+                ],
+                "summary": "Render a printable saved comparison",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comparison ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Document format: html or pdf (default html)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rendered comparison",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Comparison not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/alerts.ics": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the alert events of a time range as an iCalendar feed for calendar clients. An alert event is a report whose deviation\nfrom the report before it reaches the minimum severity under the configured thresholds; the event spans the time between the two reports.\nAt most the 500 most recent events are included.\nResponses carry an ETag and a Last-Modified header; send them back in If-None-Match or If-Modified-Since to get 304 Not Modified while nothing changed.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "iCalendar feed of alert events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start of the range (RFC3339 format, default 30 days before to)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the range (RFC3339 format, default now)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Minimum severity: notable or major (default notable)",
+                        "name": "severity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "API key, for clients that cannot send the X-API-Key header",
+                        "name": "apiKey",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "iCalendar feed",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/reports.atom": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the reports with the most recent timestamps, newest first, as an Atom 1.0 feed for feed readers.\nResponses carry an ETag and a Last-Modified header; send them back in If-None-Match or If-Modified-Since to get 304 Not Modified while nothing changed.",
+                "produces": [
+                    "application/atom+xml"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Atom feed of the latest reports",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of reports, 1 to 100 (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "API key, for clients that cannot send the X-API-Key header",
+                        "name": "apiKey",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Atom feed",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/reports.rss": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the reports with the most recent timestamps, newest first, as an RSS 2.0 feed for feed readers.\nResponses carry an ETag and a Last-Modified header; send them back in If-None-Match or If-Modified-Since to get 304 Not Modified while nothing changed.",
+                "produces": [
+                    "application/rss+xml"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "RSS feed of the latest reports",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of reports, 1 to 100 (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "API key, for clients that cannot send the X-API-Key header",
+                        "name": "apiKey",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "RSS feed",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the identity of the caller, its role and the permissions of the role, e.g. to hide actions a user may not take.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Get the current caller",
+                "responses": {
+                    "200": {
+                        "description": "Identity retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.CurrentIdentity"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid credentials",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Authentication is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all weather reports (legacy endpoint, no pagination)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get all weather reports",
+                "responses": {
+                    "200": {
+                        "description": "Reports retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/docs.WeatherReport"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generate a new weather report for Changi Airport at a specific time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Generate a new weather report",
+                "parameters": [
+                    {
+                        "description": "Report request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_request.ReportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Report generated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/docs.WeatherReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission or report quota exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/batch": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generate a weather report for each timestamp, a few at a time. Equal timestamps are generated once.\nEvery distinct timestamp gets an item with its own status and error code; the status is 201 if all succeeded and 207 otherwise.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Generate weather reports in bulk",
+                "parameters": [
+                    {
+                        "description": "Batch report request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_request.BatchReportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Reports generated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BatchReportResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "207": {
+                        "description": "Some reports could not be generated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BatchReportResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission or report quota exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/compare": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Compare two weather reports and calculate the differences.\nSet save with a title to keep the comparison; it can then be retrieved at /comparisons/{id}.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Compare two weather reports",
+                "parameters": [
+                    {
+                        "description": "Comparison request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_request.ComparisonRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reports compared successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.ComparisonResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "201": {
+                        "description": "Reports compared and comparison saved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.ComparisonResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Report not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/compare/render": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Compare two reports and render the result as a branded HTML page with print styles or as an A4 PDF document,\nwith the metric table and deviation column of the comparison page. Saved comparisons are rendered at /comparisons/{id}/render.",
+                "produces": [
+                    "text/html",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Render a printable comparison of two weather reports",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the first report",
+                        "name": "reportId1",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the second report",
+                        "name": "reportId2",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Document format: html or pdf (default html)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rendered comparison",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Report not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download every report matching the same filters and sort as /reports/paginated, streamed as CSV, NDJSON, a JSON array, Apache Parquet or Apache Arrow IPC.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/json",
+                    "application/vnd.apache.parquet",
+                    "application/vnd.apache.arrow.file"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Export weather reports",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export format: csv (default), ndjson, json, parquet or arrow",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Set to true to include soft-deleted reports",
+                        "name": "includeDeleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by start time (RFC3339 format)",
+                        "name": "fromTime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by end time (RFC3339 format)",
+                        "name": "toTime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Metric conditions such as temperature\u003e=30 or humidity\u003c80 (repeat or comma-separate)",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "How metric conditions are combined: and (default) or or",
+                        "name": "logic",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated locations (ICAO codes) to include",
+                        "name": "location",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated data providers to include",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated report types to include (current, historical)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated tags; reports with any of them are included",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort keys, - prefix for descending, e.g. -temperature,timestamp",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to sort by (legacy, use sort)",
+                        "name": "sortBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order, asc or desc (legacy, use sort)",
+                        "name": "sortOrder",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Exported reports",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Import historical reports from a CSV file with a header row or an NDJSON file sent as the request body. Files from /reports/export can be imported as is; their id, createdAt and deletedAt are ignored.\nEvery row is validated against the report fields and the physical bounds of each metric, and rows whose timestamp already has a report are skipped as duplicates. Valid rows are inserted in batches.\nInvalid rows are listed by line number; the status is 201 if no row was invalid and 207 otherwise.",
+                "consumes": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Import weather reports",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File format: csv or ndjson (default from the Content-Type, else csv)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Set to true to validate the file without inserting reports",
+                        "name": "dryRun",
+                        "in": "query"
+                    },
+                    {
+                        "description": "CSV or NDJSON file",
+                        "name": "file",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Reports imported successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.ImportResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "207": {
+                        "description": "Some rows are invalid",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.ImportResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or file",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission, or report quota exceeded with the rows imported before the limit",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.ImportResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "413": {
+                        "description": "File too large, with the rows imported before the limit",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.ImportResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error, with the rows imported before the failure",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.ImportResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/paginated": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get paginated weather reports with optional filtering by time range, metric ranges, location, source, type and tags.\nPass the nextCursor or prevCursor of a previous page as cursor to page by keyset instead of offset.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get paginated weather reports",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Limit number of results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination (cannot be combined with cursor)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from a previous page (only valid with the same sort)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Set to false to skip counting all matching reports (totalCount is -1)",
+                        "name": "includeTotal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Set to true to include soft-deleted reports",
+                        "name": "includeDeleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by start time (RFC3339 format)",
+                        "name": "fromTime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by end time (RFC3339 format)",
+                        "name": "toTime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Metric conditions such as temperature\u003e=30 or humidity\u003c80 (repeat or comma-separate)",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "How metric conditions are combined: and (default) or or",
+                        "name": "logic",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated locations (ICAO codes) to include",
+                        "name": "location",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated data providers to include",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated report types to include (current, historical)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated tags; reports with any of them are included",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort keys, - prefix for descending, e.g. -temperature,timestamp. Fields: timestamp, createdAt, temperature, pressure, humidity, cloudCover",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to sort by (legacy, use sort)",
+                        "name": "sortBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order, asc or desc (legacy, use sort)",
+                        "name": "sortOrder",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reports retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.PaginatedReportsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a specific weather report by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get a weather report by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Report ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Report retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/docs.WeatherReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Report not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Soft-delete a weather report. It is hidden from every read and can be restored until the retention window has passed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Delete a weather report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Report ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Report deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Report not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the tags and/or notes of a report. Omitted fields are left unchanged and empty values clear them.\nTags are trimmed, lower-cased and deduplicated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Update the tags and notes of a weather report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Report ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Report update",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_request.ReportUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Report updated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/docs.WeatherReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Report not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/{id}/render": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Render a report as a branded HTML page with print styles or as an A4 PDF document, with its metrics, tags and notes.",
+                "produces": [
+                    "text/html",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Render a printable weather report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Report ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Document format: html or pdf (default html)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rendered report",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Report not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restore a soft-deleted weather report that has not been purged yet",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Restore a deleted weather report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Report ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Report restored successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/docs.WeatherReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission or report quota exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Deleted report not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List every tag in use with the number of reports that have it, most used first. Deleted reports are not counted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "List report tags",
+                "responses": {
+                    "200": {
+                        "description": "Tags retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.TagCount"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tenant/usage": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get how many reports the caller's tenant stores and how many it may store. Soft-deleted reports do not count.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tenants"
+                ],
+                "summary": "Get the report usage of the caller's tenant",
+                "responses": {
+                    "200": {
+                        "description": "Usage retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.TenantUsage"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/thresholds": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the threshold of every metric from which a deviation is notable or major. Metrics without a configured threshold use the defaults.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "thresholds"
+                ],
+                "summary": "Get deviation thresholds",
+                "responses": {
+                    "200": {
+                        "description": "Thresholds retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.Threshold"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/thresholds/{metric}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Configure from which absolute deviation a change of the metric is notable or major",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "thresholds"
+                ],
+                "summary": "Set the deviation threshold of a metric",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Metric (temperature, pressure, humidity, cloudCover)",
+                        "name": "metric",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Threshold request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_request.ThresholdRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Threshold saved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.Threshold"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove the configured threshold of the metric so the default applies again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "thresholds"
+                ],
+                "summary": "Reset the deviation threshold of a metric",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Metric (temperature, pressure, humidity, cloudCover)",
+                        "name": "metric",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Threshold reset successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.Threshold"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid metric",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/weather-cache/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download the raw weather data cached from the provider in a time range as Apache Parquet or Apache Arrow IPC, with units and provenance in the schema metadata.",
+                "produces": [
+                    "application/vnd.apache.parquet",
+                    "application/vnd.apache.arrow.file"
+                ],
+                "tags": [
+                    "cache"
+                ],
+                "summary": "Export weather cache entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export format: parquet (default) or arrow",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Include entries from this time (RFC3339 format)",
+                        "name": "fromTime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Include entries until this time (RFC3339 format)",
+                        "name": "toTime",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Exported cache entries",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "docs.WeatherReport": {
+            "type": "object",
+            "properties": {
+                "cloudCover": {
+                    "description": "in %",
+                    "type": "number",
+                    "example": 30
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2023-04-18T12:05:00Z"
+                },
+                "deletedAt": {
+                    "description": "only set on deleted reports",
+                    "type": "string",
+                    "example": "2023-04-20T08:00:00Z"
+                },
+                "humidity": {
+                    "description": "in %",
+                    "type": "number",
+                    "example": 60
+                },
+                "id": {
+                    "type": "string",
+                    "example": "60d21b4667d0d8992e89e9e5"
+                },
+                "location": {
+                    "type": "string",
+                    "example": "WSSS"
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "Runway 02L closed for maintenance"
+                },
+                "pressure": {
+                    "description": "in hPa",
+                    "type": "number",
+                    "example": 1013.2
+                },
+                "source": {
+                    "type": "string",
+                    "example": "openweather"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "runway closure",
+                        "sensor check"
+                    ]
+                },
+                "temperature": {
+                    "description": "in Celsius",
+                    "type": "number",
+                    "example": 25.5
+                },
+                "timestamp": {
+                    "type": "string",
+                    "example": "2023-04-18T12:00:00Z"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "historical"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models.APIKey": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "description": "Updated at most once a minute",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Who or what uses the key, e.g. \"grafana\"",
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "First characters of the key, to recognize it",
+                    "type": "string"
+                },
+                "revokedAt": {
+                    "description": "Set once the key no longer authenticates",
+                    "type": "string"
+                },
+                "tenant": {
+                    "description": "Tenant whose data the key gives access to",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models.AuditActor": {
+            "type": "object",
+            "properties": {
+                "method": {
+                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.AuthMethod"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "description": "Empty if the request was rejected before its permission was checked",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.Role"
+                        }
+                    ]
+                },
+                "subject": {
+                    "description": "ID of the API key, or the \"sub\" claim of the token",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "Nil when authentication is disabled",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.AuditActor"
+                        }
+                    ]
+                },
+                "body": {
+                    "description": "Start of a JSON request body",
+                    "type": "string"
+                },
+                "bodySize": {
+                    "description": "Bytes of the request body read by the handler",
+                    "type": "integer"
+                },
+                "clientIp": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "errorCode": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "latencyMs": {
+                    "type": "integer"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "pathParams": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "query": {
+                    "description": "Without credentials",
+                    "type": "string"
+                },
+                "resourceId": {
+                    "description": "ID of the created or changed item, if the response names one",
+                    "type": "string"
+                },
+                "route": {
+                    "description": "Path template, e.g. \"/api/reports/{id}\"",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "time": {
+                    "description": "When the request arrived",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models.AuthMethod": {
+            "type": "string",
+            "enum": [
+                "api_key",
+                "token"
+            ],
+            "x-enum-comments": {
+                "AuthMethodToken": "Bearer token of the company SSO"
+            },
+            "x-enum-varnames": [
+                "AuthMethodAPIKey",
+                "AuthMethodToken"
+            ]
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models.Identity": {
+            "type": "object",
+            "properties": {
+                "issuer": {
+                    "description": "Issuer of the token",
+                    "type": "string"
+                },
+                "method": {
+                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.AuthMethod"
+                },
+                "name": {
+                    "description": "Name of the API key, or the display name of the token's user",
+                    "type": "string"
+                },
+                "role": {
+                    "description": "Set once the caller's permissions have been checked",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.Role"
+                        }
+                    ]
+                },
+                "subject": {
+                    "description": "ID of the API key, or the \"sub\" claim of the token",
+                    "type": "string"
+                },
+                "tenant": {
+                    "description": "Tenant whose data the caller sees and changes",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models.Metric": {
+            "type": "string",
+            "enum": [
+                "temperature",
+                "pressure",
+                "humidity",
+                "cloudCover"
+            ],
+            "x-enum-comments": {
+                "MetricCloudCover": "in %",
+                "MetricHumidity": "in %",
+                "MetricPressure": "in hPa",
+                "MetricTemperature": "in Celsius"
+            },
+            "x-enum-varnames": [
+                "MetricTemperature",
+                "MetricPressure",
+                "MetricHumidity",
+                "MetricCloudCover"
+            ]
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models.Permission": {
+            "type": "string",
+            "enum": [
+                "reports:read",
+                "reports:annotate",
+                "reports:compare",
+                "reports:generate",
+                "reports:import",
+                "reports:delete",
+                "thresholds:manage",
+                "apikeys:manage",
+                "roles:manage",
+                "audit:read"
+            ],
+            "x-enum-comments": {
+                "PermissionGenerateReports": "Calls OpenWeather, which is billed"
+            },
+            "x-enum-varnames": [
+                "PermissionReadReports",
+                "PermissionAnnotateReports",
+                "PermissionCompareReports",
+                "PermissionGenerateReports",
+                "PermissionImportReports",
+                "PermissionDeleteReports",
+                "PermissionManageThresholds",
+                "PermissionManageAPIKeys",
+                "PermissionManageRoles",
+                "PermissionReadAuditLog"
+            ]
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models.Role": {
+            "type": "string",
+            "enum": [
+                "viewer",
+                "analyst",
+                "operator",
+                "admin"
+            ],
+            "x-enum-comments": {
+                "RoleAdmin": "Also manages API keys and role assignments and reads the audit log",
+                "RoleAnalyst": "Also annotates reports and compares them",
+                "RoleOperator": "Also generates, imports and deletes reports and sets thresholds",
+                "RoleViewer": "Reads reports, comparisons, charts and feeds"
+            },
+            "x-enum-varnames": [
+                "RoleViewer",
+                "RoleAnalyst",
+                "RoleOperator",
+                "RoleAdmin"
+            ]
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models.RoleAssignment": {
+            "type": "object",
+            "properties": {
+                "method": {
+                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.AuthMethod"
+                },
+                "role": {
+                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.Role"
+                },
+                "subject": {
+                    "description": "ID of the API key, or the \"sub\" claim of the token",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "description": "Name of the admin who assigned the role",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models.Severity": {
+            "type": "string",
+            "enum": [
+                "normal",
+                "notable",
+                "major"
+            ],
+            "x-enum-varnames": [
+                "SeverityNormal",
+                "SeverityNotable",
+                "SeverityMajor"
+            ]
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models.Threshold": {
+            "type": "object",
+            "properties": {
+                "isDefault": {
+                    "description": "Whether the built-in default applies",
+                    "type": "boolean"
+                },
+                "major": {
+                    "description": "Deviations of at least this much are major",
+                    "type": "number"
+                },
+                "metric": {
+                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.Metric"
+                },
+                "notable": {
+                    "description": "Deviations of at least this much are notable",
+                    "type": "number"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models.WeatherReport": {
+            "type": "object",
+            "properties": {
+                "cloudCover": {
+                    "description": "in %",
+                    "type": "number"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "description": "Set while the report is soft-deleted",
+                    "type": "string"
+                },
+                "humidity": {
+                    "description": "in %",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "location": {
+                    "description": "ICAO code of the airport",
+                    "type": "string"
+                },
+                "notes": {
+                    "description": "Free-text notes added by forecasters",
+                    "type": "string"
+                },
+                "pressure": {
+                    "description": "in hPa",
+                    "type": "number"
+                },
+                "source": {
+                    "description": "Provider of the weather data",
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "Labels added by forecasters, e.g. \"runway closure\"",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "temperature": {
+                    "description": "in Celsius",
+                    "type": "number"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "current or historical",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_request.APIKeyRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "Who or what uses the key, e.g. \"grafana\"",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_request.BatchReportRequest": {
+            "type": "object",
+            "properties": {
+                "timestamps": {
+                    "description": "Equal timestamps are generated once",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_request.ComparisonRequest": {
+            "type": "object",
+            "properties": {
+                "notes": {
+                    "description": "Optional notes of the saved comparison",
+                    "type": "string"
+                },
+                "reportId1": {
+                    "type": "string"
+                },
+                "reportId2": {
+                    "type": "string"
+                },
+                "save": {
+                    "description": "Optional: persist the comparison so it can be retrieved later",
+                    "type": "boolean"
+                },
+                "title": {
+                    "description": "Title of the saved comparison, required when save is true",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_request.MultiComparisonRequest": {
+            "type": "object",
+            "properties": {
+                "baselineId": {
+                    "description": "Optional: report the others are compared to, defaults to the first report",
+                    "type": "string"
+                },
+                "reportIds": {
+                    "description": "Reports to compare, at least two",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_request.PeriodComparisonRequest": {
+            "type": "object",
+            "properties": {
+                "period1": {
+                    "description": "The period compared against, e.g. the same week last year",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_request.TimeRange"
+                        }
+                    ]
+                },
+                "period2": {
+                    "description": "The period of interest, e.g. this week",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_request.TimeRange"
+                        }
+                    ]
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_request.ReportRequest": {
+            "type": "object",
+            "properties": {
+                "timestamp": {
+                    "description": "Optional: if not provided, current time will be used",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_request.ReportUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "notes": {
+                    "description": "Replaces the notes of the report",
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "Replaces all tags of the report",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_request.RoleAssignmentRequest": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "description": "viewer, analyst, operator or admin",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.Role"
+                        }
+                    ]
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_request.ThresholdRequest": {
+            "type": "object",
+            "properties": {
+                "major": {
+                    "description": "Deviations of at least this much are major, must not be below notable",
+                    "type": "number"
+                },
+                "notable": {
+                    "description": "Deviations of at least this much are notable, must be positive",
+                    "type": "number"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_request.TimeRange": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.AuditLogPage": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.AuditEntry"
+                    }
+                },
+                "hasMore": {
+                    "type": "boolean"
+                },
+                "nextCursor": {
+                    "description": "Pass as cursor to get the next, older page",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "Response data (can be any type)"
+                },
+                "errorCode": {
+                    "description": "Error code (empty if status is success)",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Human-readable message about the response",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status of the response (success, error)",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.BatchReportItem": {
+            "type": "object",
+            "properties": {
+                "errorCode": {
+                    "description": "Error code (empty if status is success)",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Error message (empty if status is success)",
+                    "type": "string"
+                },
+                "report": {
+                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.WeatherReport"
+                },
+                "status": {
+                    "description": "success or error, like BaseResponse",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.BatchReportResult": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "description": "Number of timestamps that failed",
+                    "type": "integer"
+                },
+                "items": {
+                    "description": "In the order the timestamps were first requested",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.BatchReportItem"
+                    }
+                },
+                "succeeded": {
+                    "description": "Number of reports generated",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.ComparisonResult": {
+            "type": "object",
+            "properties": {
+                "deviation": {
+                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.Deviation"
+                },
+                "id": {
+                    "description": "ID of the saved comparison, only set when saving was requested",
+                    "type": "string"
+                },
+                "report1": {
+                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.WeatherReport"
+                },
+                "report2": {
+                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.WeatherReport"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.CreatedAPIKey": {
+            "type": "object",
+            "properties": {
+                "apiKey": {
+                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.APIKey"
+                },
+                "key": {
+                    "description": "Send in the X-API-Key header",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.CurrentIdentity": {
+            "type": "object",
+            "properties": {
+                "identity": {
+                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.Identity"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.Permission"
+                    }
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.Deviation": {
             "type": "object",
             "properties": {
                 "cloudCover": {
-                    "description": "in %",
-                    "type": "number",
-                    "example": 30
+                    "type": "number"
                 },
-                "createdAt": {
-                    "type": "string",
-                    "example": "2023-04-18T12:05:00Z"
+                "hoursElapsed": {
+                    "description": "Hours from the first report's timestamp to the second's, negative if the second is earlier",
+                    "type": "number"
                 },
                 "humidity": {
-                    "description": "in %",
-                    "type": "number",
-                    "example": 60
+                    "type": "number"
                 },
-                "id": {
-                    "type": "string",
-                    "example": "60d21b4667d0d8992e89e9e5"
+                "metrics": {
+                    "description": "Signed and relative change of every metric",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.MetricDeviation"
+                    }
                 },
                 "pressure": {
-                    "description": "in hPa",
-                    "type": "number",
-                    "example": 1013.2
+                    "type": "number"
                 },
                 "temperature": {
-                    "description": "in Celsius",
-                    "type": "number",
-                    "example": 25.5
+                    "type": "number"
                 },
-                "timestamp": {
-                    "type": "string",
-                    "example": "2023-04-18T12:00:00Z"
+                "verdict": {
+                    "description": "Highest severity of any metric",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.Severity"
+                        }
+                    ]
                 }
             }
         },
-        "github_com_DangVTNhan_Scanner_be_internal_models.WeatherReport": {
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.Direction": {
+            "type": "string",
+            "enum": [
+                "increase",
+                "decrease",
+                "unchanged"
+            ],
+            "x-enum-varnames": [
+                "DirectionIncrease",
+                "DirectionDecrease",
+                "DirectionUnchanged"
+            ]
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.ImportLineError": {
             "type": "object",
             "properties": {
-                "cloudCover": {
-                    "description": "in %",
-                    "type": "number"
+                "field": {
+                    "description": "Field the error is about, if any",
+                    "type": "string"
                 },
-                "createdAt": {
+                "line": {
+                    "description": "1-based line number in the file; the CSV header is line 1",
+                    "type": "integer"
+                },
+                "message": {
                     "type": "string"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.ImportResult": {
+            "type": "object",
+            "properties": {
+                "dryRun": {
+                    "description": "Whether the rows were only validated, not inserted",
+                    "type": "boolean"
                 },
-                "humidity": {
-                    "description": "in %",
+                "duplicates": {
+                    "description": "Rows skipped because a report with the timestamp exists or appears earlier in the file",
+                    "type": "integer"
+                },
+                "errors": {
+                    "description": "Why rows were rejected, in file order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.ImportLineError"
+                    }
+                },
+                "errorsTruncated": {
+                    "description": "Whether more rows failed than are listed in errors",
+                    "type": "boolean"
+                },
+                "failed": {
+                    "description": "Rows rejected as invalid",
+                    "type": "integer"
+                },
+                "imported": {
+                    "description": "Number of reports inserted, or that would be inserted in a dry run",
+                    "type": "integer"
+                },
+                "rows": {
+                    "description": "Number of data rows read",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.MetricDeviation": {
+            "type": "object",
+            "properties": {
+                "delta": {
+                    "description": "Second value minus first value",
                     "type": "number"
                 },
-                "id": {
-                    "type": "string"
+                "direction": {
+                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.Direction"
                 },
-                "pressure": {
-                    "description": "in hPa",
+                "percentChange": {
+                    "description": "Delta relative to the first value in %, null if the first value is zero",
                     "type": "number"
                 },
-                "temperature": {
-                    "description": "in Celsius",
+                "ratePerHour": {
+                    "description": "Delta per hour between the timestamps, null if they are equal",
                     "type": "number"
                 },
-                "timestamp": {
-                    "type": "string"
+                "severity": {
+                    "description": "Classification of the absolute delta by the metric's threshold",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.Severity"
+                        }
+                    ]
                 }
             }
         },
-        "github_com_DangVTNhan_Scanner_be_internal_models_request.ComparisonRequest": {
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.MetricSeries": {
             "type": "object",
             "properties": {
-                "reportId1": {
+                "bucket": {
+                    "description": "Length of the buckets the reports are aggregated in, in seconds; zero if points are single reports",
+                    "type": "integer"
+                },
+                "from": {
                     "type": "string"
                 },
-                "reportId2": {
+                "metric": {
+                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.Metric"
+                },
+                "points": {
+                    "description": "In time order; empty buckets are omitted",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.SeriesPoint"
+                    }
+                },
+                "to": {
                     "type": "string"
                 }
             }
         },
-        "github_com_DangVTNhan_Scanner_be_internal_models_request.ReportRequest": {
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.MetricStatistics": {
             "type": "object",
             "properties": {
-                "timestamp": {
-                    "description": "Optional: if not provided, current time will be used",
-                    "type": "string"
+                "max": {
+                    "type": "number"
+                },
+                "mean": {
+                    "type": "number"
+                },
+                "min": {
+                    "type": "number"
+                },
+                "range": {
+                    "description": "Max - Min",
+                    "type": "number"
+                },
+                "stdDev": {
+                    "description": "Population standard deviation",
+                    "type": "number"
                 }
             }
         },
-        "github_com_DangVTNhan_Scanner_be_internal_models_response.BaseResponse": {
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.MultiComparisonResult": {
             "type": "object",
             "properties": {
-                "data": {
-                    "description": "Response data (can be any type)"
+                "baseline": {
+                    "description": "The report the others are compared to",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.WeatherReport"
+                        }
+                    ]
                 },
-                "errorCode": {
-                    "description": "Error code (empty if status is success)",
+                "reports": {
+                    "description": "The other reports in request order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.ReportDeviation"
+                    }
+                },
+                "statistics": {
+                    "description": "Spread of each metric across all reports, baseline included",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.MetricStatistics"
+                    }
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.PaginatedReportsResponse": {
+            "type": "object",
+            "properties": {
+                "hasMore": {
+                    "description": "Whether more reports follow this page",
+                    "type": "boolean"
+                },
+                "nextCursor": {
+                    "description": "Cursor for the page after this one",
                     "type": "string"
                 },
-                "message": {
-                    "description": "Human-readable message about the response",
+                "prevCursor": {
+                    "description": "Cursor for the page before this one",
                     "type": "string"
                 },
-                "status": {
-                    "description": "Status of the response (success, error)",
+                "reports": {
+                    "description": "List of reports for the current page",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.WeatherReport"
+                    }
+                },
+                "totalCount": {
+                    "description": "Total number of reports (for calculating total pages), -1 if not counted",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.PeriodComparisonResult": {
+            "type": "object",
+            "properties": {
+                "deviation": {
+                    "description": "Deviation of the period means; hoursElapsed is between the period midpoints",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.Deviation"
+                        }
+                    ]
+                },
+                "metrics": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.PeriodMetricComparison"
+                    }
+                },
+                "period1": {
+                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.PeriodStatistics"
+                },
+                "period2": {
+                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.PeriodStatistics"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.PeriodMetricComparison": {
+            "type": "object",
+            "properties": {
+                "maxDelta": {
+                    "description": "Max of the second period minus max of the first",
+                    "type": "number"
+                },
+                "meanDelta": {
+                    "description": "Mean of the second period minus mean of the first",
+                    "type": "number"
+                },
+                "minDelta": {
+                    "description": "Min of the second period minus min of the first",
+                    "type": "number"
+                },
+                "significance": {
+                    "description": "Hint based on TStatistic",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.Significance"
+                        }
+                    ]
+                },
+                "tStatistic": {
+                    "description": "Welch's t statistic, null if it cannot be calculated",
+                    "type": "number"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.PeriodStatistics": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Number of reports in the range",
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "metrics": {
+                    "description": "Spread of every metric, empty if there are no reports",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.MetricStatistics"
+                    }
+                },
+                "to": {
                     "type": "string"
                 }
             }
         },
-        "github_com_DangVTNhan_Scanner_be_internal_models_response.ComparisonResult": {
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.ReportDeviation": {
             "type": "object",
             "properties": {
                 "deviation": {
                     "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.Deviation"
                 },
+                "report": {
+                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.WeatherReport"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.RoleDefinition": {
+            "type": "object",
+            "properties": {
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.Permission"
+                    }
+                },
+                "role": {
+                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.Role"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.RolesResponse": {
+            "type": "object",
+            "properties": {
+                "defaultRole": {
+                    "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.Role"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.RoleDefinition"
+                    }
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.SavedComparison": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "deviation": {
+                    "description": "Calculated from the snapshots",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.Deviation"
+                        }
+                    ]
+                },
+                "id": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
                 "report1": {
                     "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.WeatherReport"
                 },
                 "report2": {
                     "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.WeatherReport"
+                },
+                "thresholds": {
+                    "description": "In effect when the comparison was saved",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.Threshold"
+                    }
+                },
+                "title": {
+                    "type": "string"
                 }
             }
         },
-        "github_com_DangVTNhan_Scanner_be_internal_models_response.Deviation": {
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.SavedComparisonsResponse": {
             "type": "object",
             "properties": {
-                "cloudCover": {
-                    "type": "number"
+                "comparisons": {
+                    "description": "Saved comparisons, newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models_response.SavedComparison"
+                    }
                 },
-                "humidity": {
+                "totalCount": {
+                    "description": "Total number of saved comparisons",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.SeriesPoint": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Number of reports",
+                    "type": "integer"
+                },
+                "max": {
+                    "description": "Highest value in the bucket; the value for reports",
                     "type": "number"
                 },
-                "pressure": {
+                "min": {
+                    "description": "Lowest value in the bucket; the value for reports",
                     "type": "number"
                 },
-                "temperature": {
+                "timestamp": {
+                    "description": "Time of the report, or the middle of the bucket",
+                    "type": "string"
+                },
+                "value": {
+                    "description": "Value of the report, or mean of the bucket",
                     "type": "number"
                 }
             }
         },
-        "github_com_DangVTNhan_Scanner_be_internal_models_response.PaginatedReportsResponse": {
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.Significance": {
+            "type": "string",
+            "enum": [
+                "significant",
+                "not_significant",
+                "insufficient_data"
+            ],
+            "x-enum-comments": {
+                "SignificanceInsufficientData": "A period has fewer than two reports",
+                "SignificanceNotSignificant": "The difference is within the variation of the periods",
+                "SignificanceSignificant": "|t| of Welch's t-test is at least 2, roughly p \u003c 0.05"
+            },
+            "x-enum-varnames": [
+                "SignificanceSignificant",
+                "SignificanceNotSignificant",
+                "SignificanceInsufficientData"
+            ]
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.TagCount": {
             "type": "object",
             "properties": {
-                "reports": {
-                    "description": "List of reports for the current page",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/github_com_DangVTNhan_Scanner_be_internal_models.WeatherReport"
-                    }
+                "count": {
+                    "type": "integer"
                 },
-                "totalCount": {
-                    "description": "Total number of reports (for calculating total pages)",
+                "tag": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_DangVTNhan_Scanner_be_internal_models_response.TenantUsage": {
+            "type": "object",
+            "properties": {
+                "reportQuota": {
+                    "description": "Reports the tenant may store, 0 for no limit",
                     "type": "integer"
+                },
+                "reports": {
+                    "description": "Stored reports, excluding soft-deleted ones",
+                    "type": "integer"
+                },
+                "tenant": {
+                    "type": "string"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "API key created with POST /api-keys or the apikey command. Clients that cannot set headers may pass it in the apiKey query parameter instead.",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "description": "\"Bearer \" followed by a JWT of the company SSO, when the API is configured with its JWKS.",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
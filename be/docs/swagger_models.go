@@ -14,13 +14,19 @@ import (
 type (
 	// WeatherReport is a reference to models.WeatherReport
 	WeatherReport struct {
-		ID          string    `json:"id" example:"60d21b4667d0d8992e89e9e5"`
-		Timestamp   time.Time `json:"timestamp" example:"2023-04-18T12:00:00Z"`
-		Temperature float64   `json:"temperature" example:"25.5"` // in Celsius
-		Pressure    float64   `json:"pressure" example:"1013.2"`  // in hPa
-		Humidity    float64   `json:"humidity" example:"60"`      // in %
-		CloudCover  float64   `json:"cloudCover" example:"30"`    // in %
-		CreatedAt   time.Time `json:"createdAt" example:"2023-04-18T12:05:00Z"`
+		ID          string     `json:"id" example:"60d21b4667d0d8992e89e9e5"`
+		Timestamp   time.Time  `json:"timestamp" example:"2023-04-18T12:00:00Z"`
+		Temperature float64    `json:"temperature" example:"25.5"` // in Celsius
+		Pressure    float64    `json:"pressure" example:"1013.2"`  // in hPa
+		Humidity    float64    `json:"humidity" example:"60"`      // in %
+		CloudCover  float64    `json:"cloudCover" example:"30"`    // in %
+		Location    string     `json:"location" example:"WSSS"`
+		Source      string     `json:"source" example:"openweather"`
+		Type        string     `json:"type" example:"historical"`
+		CreatedAt   time.Time  `json:"createdAt" example:"2023-04-18T12:05:00Z"`
+		DeletedAt   *time.Time `json:"deletedAt,omitempty" example:"2023-04-20T08:00:00Z"` // only set on deleted reports
+		Tags        []string   `json:"tags,omitempty" example:"runway closure,sensor check"`
+		Notes       string     `json:"notes,omitempty" example:"Runway 02L closed for maintenance"`
 	}
 
 	// ReportRequest is a reference to request.ReportRequest
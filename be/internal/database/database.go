@@ -31,9 +31,9 @@ func Connect(ctx context.Context, mongoURI, databaseName string) (*mongo.Databas
 	// Get database instance
 	db := client.Database(databaseName)
 	
-	// Ensure indexes
-	if err := EnsureIndexes(db); err != nil {
-		return nil, fmt.Errorf("failed to ensure indexes: %w", err)
+	// Apply pending schema migrations
+	if err := RunMigrations(db); err != nil {
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
 	
 	return db, nil
@@ -49,8 +49,8 @@ func Disconnect(ctx context.Context, client *mongo.Client) error {
 	return nil
 }
 
-// InitDatabase initializes the database connection and ensures indexes
-func InitDatabase(mongoURI, databaseName string) (*mongo.Client, *mongo.Database, error) {
+// InitDatabase initializes the database connection and, if requested, applies pending migrations
+func InitDatabase(mongoURI, databaseName string, runMigrations bool) (*mongo.Client, *mongo.Database, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	
@@ -73,9 +73,11 @@ func InitDatabase(mongoURI, databaseName string) (*mongo.Client, *mongo.Database
 	// Get database instance
 	db := client.Database(databaseName)
 	
-	// Ensure indexes
-	if err := EnsureIndexes(db); err != nil {
-		return nil, nil, fmt.Errorf("failed to ensure indexes: %w", err)
+	// Apply pending schema migrations
+	if runMigrations {
+		if err := RunMigrations(db); err != nil {
+			return nil, nil, fmt.Errorf("failed to run migrations: %w", err)
+		}
 	}
 	
 	return client, db, nil
@@ -0,0 +1,501 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MigrationHistoryCollection is the collection that records applied migrations
+const MigrationHistoryCollection = "schema_migrations"
+
+// MigrationLeaseTTL is how long a migrator holds the migration lease before another may take it over.
+// The holder renews it before each migration, so only a single migration that runs longer can lose it.
+const MigrationLeaseTTL = 10 * time.Minute
+
+// migrationLeaseID is the _id of the lease document kept alongside the history records
+const migrationLeaseID = "lease"
+
+// MigrationFunc applies or reverts a single schema change
+type MigrationFunc func(ctx context.Context, db *mongo.Database) error
+
+// Migration describes a versioned schema change
+type Migration struct {
+	Version     int           // Strictly increasing version number
+	Description string        // Human-readable summary of the change
+	Up          MigrationFunc // Applies the change
+	Down        MigrationFunc // Reverts the change (nil if irreversible)
+}
+
+// MigrationRecord is the stored history entry of an applied migration
+type MigrationRecord struct {
+	Version     int       `json:"version" bson:"_id"`
+	Description string    `json:"description" bson:"description"`
+	AppliedAt   time.Time `json:"appliedAt" bson:"appliedAt"`
+	DurationMs  int64     `json:"durationMs" bson:"durationMs"`
+}
+
+// MigrationStatus describes a known migration and whether it has been applied
+type MigrationStatus struct {
+	Version     int        `json:"version"`
+	Description string     `json:"description"`
+	Applied     bool       `json:"applied"`
+	AppliedAt   *time.Time `json:"appliedAt,omitempty"`
+}
+
+// IMigrationHistory stores which migrations have been applied
+type IMigrationHistory interface {
+	// Applied returns all applied migrations ordered by version
+	Applied(ctx context.Context) ([]MigrationRecord, error)
+
+	// Record stores an applied migration
+	Record(ctx context.Context, record MigrationRecord) error
+
+	// Remove deletes the record of a reverted migration
+	Remove(ctx context.Context, version int) error
+
+	// Lease takes or renews the migration lease for owner until ttl from now.
+	// It returns false if another owner holds an unexpired lease.
+	Lease(ctx context.Context, owner string, ttl time.Duration) (bool, error)
+
+	// Release gives up the lease if owner holds it
+	Release(ctx context.Context, owner string) error
+}
+
+// Migrator applies and reverts migrations against a database
+type Migrator struct {
+	db         *mongo.Database
+	history    IMigrationHistory
+	migrations []Migration
+	dryRun     bool
+	leaseTTL   time.Duration
+	leaseWait  time.Duration // Pause between attempts to take a lease held by another migrator
+}
+
+// NewMigrator creates a new Migrator that keeps its history in the schema_migrations collection
+func NewMigrator(db *mongo.Database, migrations []Migration) *Migrator {
+	return NewMigratorWithHistory(db, &mongoMigrationHistory{collection: db.Collection(MigrationHistoryCollection)}, migrations)
+}
+
+// NewMigratorWithHistory creates a new Migrator with a custom history store
+func NewMigratorWithHistory(db *mongo.Database, history IMigrationHistory, migrations []Migration) *Migrator {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	return &Migrator{
+		db:         db,
+		history:    history,
+		migrations: sorted,
+		leaseTTL:   MigrationLeaseTTL,
+		leaseWait:  2 * time.Second,
+	}
+}
+
+// SetDryRun makes the migrator only report what it would do without changing anything
+func (m *Migrator) SetDryRun(dryRun bool) {
+	m.dryRun = dryRun
+}
+
+// Validate checks that migration versions are positive and unique
+func (m *Migrator) Validate() error {
+	seen := make(map[int]bool)
+	for _, migration := range m.migrations {
+		if migration.Version <= 0 {
+			return fmt.Errorf("invalid migration version %d", migration.Version)
+		}
+		if seen[migration.Version] {
+			return fmt.Errorf("duplicate migration version %d", migration.Version)
+		}
+		if migration.Up == nil {
+			return fmt.Errorf("migration %d has no up step", migration.Version)
+		}
+		seen[migration.Version] = true
+	}
+	return nil
+}
+
+// Status returns every known migration and whether it has been applied
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.appliedByVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Description: migration.Description}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Up applies all pending migrations up to and including target (0 means all).
+// It returns the migrations that were applied, or would be applied in dry-run mode.
+// Outside dry-run mode it holds the migration lease, so replicas starting together apply each migration once.
+func (m *Migrator) Up(ctx context.Context, target int) ([]Migration, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	var owner string
+	if !m.dryRun {
+		var err error
+		if owner, err = m.acquireLease(ctx); err != nil {
+			return nil, err
+		}
+		defer m.releaseLease(owner)
+	}
+
+	// Read the history only once the lease is held; another migrator may have just applied some versions
+	applied, err := m.appliedByVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if target > 0 && migration.Version > target {
+			break
+		}
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+
+	for i, migration := range pending {
+		if m.dryRun {
+			log.Printf("[dry-run] Would apply migration %d: %s", migration.Version, migration.Description)
+			continue
+		}
+
+		if err := m.renewLease(ctx, owner); err != nil {
+			return pending[:i], err
+		}
+
+		log.Printf("Applying migration %d: %s", migration.Version, migration.Description)
+		start := time.Now()
+		if err := migration.Up(ctx, m.db); err != nil {
+			return pending[:i], fmt.Errorf("failed to apply migration %d: %w", migration.Version, err)
+		}
+
+		record := MigrationRecord{
+			Version:     migration.Version,
+			Description: migration.Description,
+			AppliedAt:   time.Now(),
+			DurationMs:  time.Since(start).Milliseconds(),
+		}
+		if err := m.history.Record(ctx, record); err != nil {
+			return pending[:i], fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+		}
+	}
+
+	return pending, nil
+}
+
+// Down reverts the most recently applied migrations, at most steps of them.
+// It returns the migrations that were reverted, or would be reverted in dry-run mode.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, fmt.Errorf("steps must be positive")
+	}
+
+	var owner string
+	if !m.dryRun {
+		var err error
+		if owner, err = m.acquireLease(ctx); err != nil {
+			return nil, err
+		}
+		defer m.releaseLease(owner)
+	}
+
+	records, err := m.history.Applied(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migration history: %w", err)
+	}
+
+	known := make(map[int]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	var reverted []Migration
+	for i := len(records) - 1; i >= 0 && len(reverted) < steps; i-- {
+		migration, ok := known[records[i].Version]
+		if !ok {
+			return reverted, fmt.Errorf("migration %d is applied but unknown to this build", records[i].Version)
+		}
+		if migration.Down == nil {
+			return reverted, fmt.Errorf("migration %d is irreversible", migration.Version)
+		}
+
+		if m.dryRun {
+			log.Printf("[dry-run] Would revert migration %d: %s", migration.Version, migration.Description)
+			reverted = append(reverted, migration)
+			continue
+		}
+
+		if err := m.renewLease(ctx, owner); err != nil {
+			return reverted, err
+		}
+
+		log.Printf("Reverting migration %d: %s", migration.Version, migration.Description)
+		if err := migration.Down(ctx, m.db); err != nil {
+			return reverted, fmt.Errorf("failed to revert migration %d: %w", migration.Version, err)
+		}
+		if err := m.history.Remove(ctx, migration.Version); err != nil {
+			return reverted, fmt.Errorf("failed to remove migration %d from history: %w", migration.Version, err)
+		}
+		reverted = append(reverted, migration)
+	}
+
+	return reverted, nil
+}
+
+// acquireLease waits until this migrator holds the migration lease and returns its owner ID
+func (m *Migrator) acquireLease(ctx context.Context) (string, error) {
+	owner := primitive.NewObjectID().Hex()
+	for {
+		ok, err := m.history.Lease(ctx, owner, m.leaseTTL)
+		if err != nil {
+			return "", fmt.Errorf("failed to take migration lease: %w", err)
+		}
+		if ok {
+			return owner, nil
+		}
+
+		log.Printf("Waiting for another migrator to finish")
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("failed to take migration lease: %w", ctx.Err())
+		case <-time.After(m.leaseWait):
+		}
+	}
+}
+
+// renewLease extends the migration lease before the next migration, failing if it was taken over
+func (m *Migrator) renewLease(ctx context.Context, owner string) error {
+	ok, err := m.history.Lease(ctx, owner, m.leaseTTL)
+	if err != nil {
+		return fmt.Errorf("failed to renew migration lease: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("migration lease expired and was taken by another migrator")
+	}
+	return nil
+}
+
+// releaseLease gives up the migration lease; if that fails it expires on its own
+func (m *Migrator) releaseLease(owner string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := m.history.Release(ctx, owner); err != nil {
+		log.Printf("Failed to release migration lease: %v", err)
+	}
+}
+
+// appliedByVersion loads the migration history keyed by version
+func (m *Migrator) appliedByVersion(ctx context.Context) (map[int]MigrationRecord, error) {
+	records, err := m.history.Applied(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migration history: %w", err)
+	}
+
+	applied := make(map[int]MigrationRecord, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// mongoMigrationHistory stores migration history in a MongoDB collection
+type mongoMigrationHistory struct {
+	collection *mongo.Collection
+}
+
+// migrationLease is the lease document; history records have numeric IDs, the lease a string one
+type migrationLease struct {
+	ID        string    `bson:"_id"`
+	Owner     string    `bson:"owner"`
+	ExpiresAt time.Time `bson:"expiresAt"`
+}
+
+func (h *mongoMigrationHistory) Applied(ctx context.Context) ([]MigrationRecord, error) {
+	filter := bson.M{"_id": bson.M{"$type": "number"}}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := h.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	records := []MigrationRecord{}
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (h *mongoMigrationHistory) Record(ctx context.Context, record MigrationRecord) error {
+	_, err := h.collection.InsertOne(ctx, record)
+	return err
+}
+
+func (h *mongoMigrationHistory) Remove(ctx context.Context, version int) error {
+	_, err := h.collection.DeleteOne(ctx, bson.M{"_id": version})
+	return err
+}
+
+func (h *mongoMigrationHistory) Lease(ctx context.Context, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	// Match the lease only if it is free; otherwise the upsert collides with the held lease's _id
+	filter := bson.M{
+		"_id": migrationLeaseID,
+		"$or": bson.A{
+			bson.M{"owner": owner},
+			bson.M{"expiresAt": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"owner": owner, "expiresAt": now.Add(ttl)}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var lease migrationLease
+	err := h.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&lease)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return lease.Owner == owner, nil
+}
+
+func (h *mongoMigrationHistory) Release(ctx context.Context, owner string) error {
+	_, err := h.collection.DeleteOne(ctx, bson.M{"_id": migrationLeaseID, "owner": owner})
+	return err
+}
+
+// CreateIndexes returns a migration step that creates indexes on a collection.
+// MongoDB creates the collection if it does not exist yet.
+func CreateIndexes(collectionName string, indexes ...mongo.IndexModel) MigrationFunc {
+	return func(ctx context.Context, db *mongo.Database) error {
+		if _, err := db.Collection(collectionName).Indexes().CreateMany(ctx, indexes); err != nil {
+			return fmt.Errorf("failed to create indexes for collection %s: %w", collectionName, err)
+		}
+		return nil
+	}
+}
+
+// DropIndexes returns a migration step that drops indexes by name, ignoring ones that do not exist
+func DropIndexes(collectionName string, names ...string) MigrationFunc {
+	return func(ctx context.Context, db *mongo.Database) error {
+		collection := db.Collection(collectionName)
+		existing, err := getExistingIndexes(ctx, collection)
+		if err != nil {
+			return fmt.Errorf("failed to get existing indexes for collection %s: %w", collectionName, err)
+		}
+
+		for _, name := range names {
+			if !indexExists(existing, name) {
+				continue
+			}
+			if _, err := collection.Indexes().DropOne(ctx, name); err != nil {
+				return fmt.Errorf("failed to drop index %s for collection %s: %w", name, collectionName, err)
+			}
+		}
+		return nil
+	}
+}
+
+// RenameField returns a migration step that renames a field on every document that has it
+func RenameField(collectionName, from, to string) MigrationFunc {
+	return func(ctx context.Context, db *mongo.Database) error {
+		filter := bson.M{from: bson.M{"$exists": true}}
+		update := bson.M{"$rename": bson.M{from: to}}
+		if _, err := db.Collection(collectionName).UpdateMany(ctx, filter, update); err != nil {
+			return fmt.Errorf("failed to rename field %s to %s in collection %s: %w", from, to, collectionName, err)
+		}
+		return nil
+	}
+}
+
+// BackfillField returns a migration step that sets a field on every document missing it
+func BackfillField(collectionName, field string, value interface{}) MigrationFunc {
+	return func(ctx context.Context, db *mongo.Database) error {
+		filter := bson.M{field: bson.M{"$exists": false}}
+		update := bson.M{"$set": bson.M{field: value}}
+		if _, err := db.Collection(collectionName).UpdateMany(ctx, filter, update); err != nil {
+			return fmt.Errorf("failed to backfill field %s in collection %s: %w", field, collectionName, err)
+		}
+		return nil
+	}
+}
+
+// UnsetField returns a migration step that removes a field from every document
+func UnsetField(collectionName, field string) MigrationFunc {
+	return func(ctx context.Context, db *mongo.Database) error {
+		filter := bson.M{field: bson.M{"$exists": true}}
+		update := bson.M{"$unset": bson.M{field: ""}}
+		if _, err := db.Collection(collectionName).UpdateMany(ctx, filter, update); err != nil {
+			return fmt.Errorf("failed to unset field %s in collection %s: %w", field, collectionName, err)
+		}
+		return nil
+	}
+}
+
+// RekeyDocuments returns a migration step that replaces every document matching filter with the document
+// that rekey returns for it, which may have a different _id. MongoDB cannot change the _id of a document,
+// so the new document is upserted before the old one is deleted; an interrupted step can be run again.
+func RekeyDocuments(collectionName string, filter bson.M, rekey func(doc bson.M) bson.M) MigrationFunc {
+	return func(ctx context.Context, db *mongo.Database) error {
+		collection := db.Collection(collectionName)
+		cursor, err := collection.Find(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("failed to find documents in collection %s: %w", collectionName, err)
+		}
+		var docs []bson.M
+		if err := cursor.All(ctx, &docs); err != nil {
+			return fmt.Errorf("failed to read documents in collection %s: %w", collectionName, err)
+		}
+
+		for _, doc := range docs {
+			oldID := doc["_id"]
+			replacement := rekey(doc)
+			opts := options.Replace().SetUpsert(true)
+			if _, err := collection.ReplaceOne(ctx, bson.M{"_id": replacement["_id"]}, replacement, opts); err != nil {
+				return fmt.Errorf("failed to rekey document %v in collection %s: %w", oldID, collectionName, err)
+			}
+			if replacement["_id"] == oldID {
+				continue
+			}
+			if _, err := collection.DeleteOne(ctx, bson.M{"_id": oldID}); err != nil {
+				return fmt.Errorf("failed to delete document %v in collection %s: %w", oldID, collectionName, err)
+			}
+		}
+		return nil
+	}
+}
+
+// Steps combines several migration steps into one, run in order
+func Steps(steps ...MigrationFunc) MigrationFunc {
+	return func(ctx context.Context, db *mongo.Database) error {
+		for _, step := range steps {
+			if err := step(ctx, db); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
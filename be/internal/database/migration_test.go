@@ -0,0 +1,288 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// memoryMigrationHistory is an in-memory implementation of IMigrationHistory
+type memoryMigrationHistory struct {
+	records    []MigrationRecord
+	leaseOwner string
+	leaseUntil time.Time
+	onLease    func(h *memoryMigrationHistory) // Called before each attempt to take the lease
+}
+
+func (h *memoryMigrationHistory) Applied(ctx context.Context) ([]MigrationRecord, error) {
+	return append([]MigrationRecord{}, h.records...), nil
+}
+
+func (h *memoryMigrationHistory) Record(ctx context.Context, record MigrationRecord) error {
+	h.records = append(h.records, record)
+	return nil
+}
+
+func (h *memoryMigrationHistory) Remove(ctx context.Context, version int) error {
+	for i, record := range h.records {
+		if record.Version == version {
+			h.records = append(h.records[:i], h.records[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (h *memoryMigrationHistory) Lease(ctx context.Context, owner string, ttl time.Duration) (bool, error) {
+	if h.onLease != nil {
+		h.onLease(h)
+	}
+	if h.leaseOwner != "" && h.leaseOwner != owner && time.Now().Before(h.leaseUntil) {
+		return false, nil
+	}
+	h.leaseOwner = owner
+	h.leaseUntil = time.Now().Add(ttl)
+	return true, nil
+}
+
+func (h *memoryMigrationHistory) Release(ctx context.Context, owner string) error {
+	if h.leaseOwner == owner {
+		h.leaseOwner = ""
+	}
+	return nil
+}
+
+// recordingMigrations returns migrations that append their steps to calls
+func recordingMigrations(calls *[]string, versions ...int) []Migration {
+	migrations := make([]Migration, len(versions))
+	for i, version := range versions {
+		version := version
+		migrations[i] = Migration{
+			Version:     version,
+			Description: "test migration",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				*calls = append(*calls, fmt.Sprintf("up%d", version))
+				return nil
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				*calls = append(*calls, fmt.Sprintf("down%d", version))
+				return nil
+			},
+		}
+	}
+	return migrations
+}
+
+func TestMigrator_UpAppliesPendingInOrder(t *testing.T) {
+	// Arrange
+	var calls []string
+	history := &memoryMigrationHistory{}
+	migrator := NewMigratorWithHistory(nil, history, recordingMigrations(&calls, 3, 1, 2))
+
+	// Act
+	applied, err := migrator.Up(context.Background(), 0)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, applied, 3)
+	assert.Equal(t, []string{"up1", "up2", "up3"}, calls)
+	assert.Len(t, history.records, 3)
+
+	// Running again applies nothing
+	applied, err = migrator.Up(context.Background(), 0)
+	assert.NoError(t, err)
+	assert.Empty(t, applied)
+	assert.Len(t, calls, 3)
+}
+
+func TestMigrator_UpWaitsForLease(t *testing.T) {
+	// Arrange
+	var calls []string
+	history := &memoryMigrationHistory{leaseOwner: "other", leaseUntil: time.Now().Add(time.Minute)}
+	attempts := 0
+	history.onLease = func(h *memoryMigrationHistory) {
+		attempts++
+		if attempts == 2 {
+			// The other migrator applies version 1 and finishes
+			h.records = append(h.records, MigrationRecord{Version: 1, AppliedAt: time.Now()})
+			h.leaseOwner = ""
+		}
+	}
+	migrator := NewMigratorWithHistory(nil, history, recordingMigrations(&calls, 1, 2))
+	migrator.leaseWait = time.Millisecond
+
+	// Act
+	applied, err := migrator.Up(context.Background(), 0)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, applied, 1)
+	assert.Equal(t, []string{"up2"}, calls)
+	assert.Len(t, history.records, 2)
+	assert.Empty(t, history.leaseOwner)
+}
+
+func TestMigrator_UpTakesExpiredLease(t *testing.T) {
+	// Arrange
+	var calls []string
+	history := &memoryMigrationHistory{leaseOwner: "crashed", leaseUntil: time.Now().Add(-time.Second)}
+	migrator := NewMigratorWithHistory(nil, history, recordingMigrations(&calls, 1))
+
+	// Act
+	applied, err := migrator.Up(context.Background(), 0)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, applied, 1)
+	assert.Empty(t, history.leaseOwner)
+}
+
+func TestMigrator_UpLeaseHeldUntilCancelled(t *testing.T) {
+	// Arrange
+	var calls []string
+	history := &memoryMigrationHistory{leaseOwner: "other", leaseUntil: time.Now().Add(time.Minute)}
+	migrator := NewMigratorWithHistory(nil, history, recordingMigrations(&calls, 1))
+	migrator.leaseWait = time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// Act
+	applied, err := migrator.Up(ctx, 0)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to take migration lease")
+	assert.Empty(t, applied)
+	assert.Empty(t, calls)
+	assert.Equal(t, "other", history.leaseOwner)
+}
+
+func TestMigrator_UpToTarget(t *testing.T) {
+	// Arrange
+	var calls []string
+	history := &memoryMigrationHistory{}
+	migrator := NewMigratorWithHistory(nil, history, recordingMigrations(&calls, 1, 2, 3))
+
+	// Act
+	applied, err := migrator.Up(context.Background(), 2)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, applied, 2)
+	assert.Equal(t, []string{"up1", "up2"}, calls)
+}
+
+func TestMigrator_UpDryRun(t *testing.T) {
+	// Arrange
+	var calls []string
+	history := &memoryMigrationHistory{}
+	migrator := NewMigratorWithHistory(nil, history, recordingMigrations(&calls, 1, 2))
+	migrator.SetDryRun(true)
+
+	// Act
+	applied, err := migrator.Up(context.Background(), 0)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, applied, 2)
+	assert.Empty(t, calls)
+	assert.Empty(t, history.records)
+}
+
+func TestMigrator_UpStopsOnError(t *testing.T) {
+	// Arrange
+	var calls []string
+	migrations := recordingMigrations(&calls, 1, 2, 3)
+	migrations[1].Up = func(ctx context.Context, db *mongo.Database) error {
+		return errors.New("boom")
+	}
+	history := &memoryMigrationHistory{}
+	migrator := NewMigratorWithHistory(nil, history, migrations)
+
+	// Act
+	applied, err := migrator.Up(context.Background(), 0)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to apply migration 2")
+	assert.Len(t, applied, 1)
+	assert.Equal(t, []string{"up1"}, calls)
+	assert.Len(t, history.records, 1)
+}
+
+func TestMigrator_DownRevertsMostRecent(t *testing.T) {
+	// Arrange
+	var calls []string
+	history := &memoryMigrationHistory{}
+	migrator := NewMigratorWithHistory(nil, history, recordingMigrations(&calls, 1, 2, 3))
+	_, err := migrator.Up(context.Background(), 0)
+	assert.NoError(t, err)
+	calls = nil
+
+	// Act
+	reverted, err := migrator.Down(context.Background(), 2)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, reverted, 2)
+	assert.Equal(t, []string{"down3", "down2"}, calls)
+	assert.Len(t, history.records, 1)
+	assert.Equal(t, 1, history.records[0].Version)
+}
+
+func TestMigrator_DownIrreversible(t *testing.T) {
+	// Arrange
+	var calls []string
+	migrations := recordingMigrations(&calls, 1)
+	migrations[0].Down = nil
+	history := &memoryMigrationHistory{records: []MigrationRecord{{Version: 1, AppliedAt: time.Now()}}}
+	migrator := NewMigratorWithHistory(nil, history, migrations)
+
+	// Act
+	_, err := migrator.Down(context.Background(), 1)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "irreversible")
+	assert.Len(t, history.records, 1)
+}
+
+func TestMigrator_Status(t *testing.T) {
+	// Arrange
+	var calls []string
+	history := &memoryMigrationHistory{records: []MigrationRecord{{Version: 1, AppliedAt: time.Now()}}}
+	migrator := NewMigratorWithHistory(nil, history, recordingMigrations(&calls, 1, 2))
+
+	// Act
+	statuses, err := migrator.Status(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 2)
+	assert.True(t, statuses[0].Applied)
+	assert.NotNil(t, statuses[0].AppliedAt)
+	assert.False(t, statuses[1].Applied)
+}
+
+func TestMigrator_ValidateDuplicateVersions(t *testing.T) {
+	// Arrange
+	var calls []string
+	migrator := NewMigratorWithHistory(nil, &memoryMigrationHistory{}, recordingMigrations(&calls, 1, 1))
+
+	// Act
+	_, err := migrator.Up(context.Background(), 0)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "duplicate migration version 1")
+}
+
+func TestMigrations_AreValid(t *testing.T) {
+	migrator := NewMigratorWithHistory(nil, &memoryMigrationHistory{}, Migrations)
+	assert.NoError(t, migrator.Validate())
+}
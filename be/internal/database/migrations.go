@@ -0,0 +1,431 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Migrations contains every schema migration of the application, in version order.
+// Never edit or renumber a migration that has shipped; add a new one instead.
+var Migrations = []Migration{
+	{
+		Version:     1,
+		Description: "Create timestamp indexes for reports and weather_cache",
+		Up: Steps(
+			CreateIndexes("reports", mongo.IndexModel{
+				Keys: bson.D{
+					{Key: "timestamp", Value: -1},
+					{Key: "_id", Value: -1},
+				},
+				Options: options.Index().SetName("timestamp_desc"),
+			}),
+			CreateIndexes("weather_cache", mongo.IndexModel{
+				Keys:    bson.D{{Key: "timestamp", Value: -1}},
+				Options: options.Index().SetName("timestamp_desc"),
+			}),
+		),
+		Down: Steps(
+			DropIndexes("reports", "timestamp_desc"),
+			DropIndexes("weather_cache", "timestamp_desc"),
+		),
+	},
+	{
+		Version:     2,
+		Description: "Backfill location and source on existing reports",
+		Up: Steps(
+			BackfillField("reports", "location", "WSSS"),
+			BackfillField("reports", "source", "openweather"),
+		),
+		Down: Steps(
+			UnsetField("reports", "location"),
+			UnsetField("reports", "source"),
+		),
+	},
+	{
+		Version:     3,
+		Description: "Create report filter indexes on metrics, location, source and type",
+		Up: CreateIndexes("reports",
+			categoryIndex("location"),
+			categoryIndex("source"),
+			categoryIndex("type"),
+			metricIndex("temperature"),
+			metricIndex("pressure"),
+			metricIndex("humidity"),
+			metricIndex("cloudCover"),
+		),
+		Down: DropIndexes("reports",
+			"location_timestamp", "source_timestamp", "type_timestamp",
+			"temperature_asc", "pressure_asc", "humidity_asc", "cloudCover_asc",
+		),
+	},
+	{
+		Version:     4,
+		Description: "Replace metric indexes with sort indexes that include the _id tie-break",
+		Up: Steps(
+			DropIndexes("reports", "temperature_asc", "pressure_asc", "humidity_asc", "cloudCover_asc"),
+			CreateIndexes("reports",
+				sortIndex("temperature"),
+				sortIndex("pressure"),
+				sortIndex("humidity"),
+				sortIndex("cloudCover"),
+				sortIndex("createdAt"),
+			),
+		),
+		Down: Steps(
+			DropIndexes("reports", "temperature_id", "pressure_id", "humidity_id", "cloudCover_id", "createdAt_id"),
+			CreateIndexes("reports",
+				metricIndex("temperature"),
+				metricIndex("pressure"),
+				metricIndex("humidity"),
+				metricIndex("cloudCover"),
+			),
+		),
+	},
+	{
+		Version:     5,
+		Description: "Create createdAt index for saved comparisons",
+		Up:          CreateIndexes("comparisons", sortIndex("createdAt")),
+		Down:        DropIndexes("comparisons", "createdAt_id"),
+	},
+	{
+		Version:     6,
+		Description: "Create partial deletedAt index for purging soft-deleted reports",
+		Up: CreateIndexes("reports", mongo.IndexModel{
+			Keys: bson.D{{Key: "deletedAt", Value: 1}},
+			Options: options.Index().
+				SetName("deletedAt_partial").
+				SetPartialFilterExpression(bson.M{"deletedAt": bson.M{"$exists": true}}),
+		}),
+		Down: DropIndexes("reports", "deletedAt_partial"),
+	},
+	{
+		Version:     7,
+		Description: "Create tags index for reports",
+		Up:          CreateIndexes("reports", categoryIndex("tags")),
+		Down:        DropIndexes("reports", "tags_timestamp"),
+	},
+	{
+		Version:     8,
+		Description: "Create unique hash and createdAt indexes for API keys",
+		Up: CreateIndexes("api_keys",
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "hash", Value: 1}},
+				Options: options.Index().SetName("hash_unique").SetUnique(true),
+			},
+			sortIndex("createdAt"),
+		),
+		Down: DropIndexes("api_keys", "hash_unique", "createdAt_id"),
+	},
+	{
+		Version:     9,
+		Description: "Move existing data into the default tenant",
+		Up: Steps(
+			BackfillField("reports", "tenant", defaultTenant),
+			BackfillField("weather_cache", "tenant", defaultTenant),
+			BackfillField("comparisons", "tenant", defaultTenant),
+			BackfillField("api_keys", "tenant", defaultTenant),
+			// Thresholds and role assignments are keyed by tenant
+			RekeyDocuments("thresholds", bson.M{"tenant": bson.M{"$exists": false}}, func(doc bson.M) bson.M {
+				doc["metric"] = doc["_id"]
+				doc["_id"] = fmt.Sprintf("%s:%v", defaultTenant, doc["_id"])
+				doc["tenant"] = defaultTenant
+				return doc
+			}),
+			RekeyDocuments("role_assignments", bson.M{"tenant": bson.M{"$exists": false}}, func(doc bson.M) bson.M {
+				doc["_id"] = fmt.Sprintf("%s:%v", defaultTenant, doc["_id"])
+				doc["tenant"] = defaultTenant
+				return doc
+			}),
+		),
+		Down: nil, // Reverting would mix the data of every tenant into one
+	},
+	{
+		Version:     10,
+		Description: "Replace report, cache, comparison and API key indexes with tenant-prefixed ones",
+		Up: Steps(
+			DropIndexes("reports", "timestamp_desc",
+				"location_timestamp", "source_timestamp", "type_timestamp", "tags_timestamp",
+				"temperature_id", "pressure_id", "humidity_id", "cloudCover_id", "createdAt_id"),
+			CreateIndexes("reports",
+				tenantIndex(timestampIndex()),
+				tenantIndex(categoryIndex("location")),
+				tenantIndex(categoryIndex("source")),
+				tenantIndex(categoryIndex("type")),
+				tenantIndex(categoryIndex("tags")),
+				tenantIndex(sortIndex("temperature")),
+				tenantIndex(sortIndex("pressure")),
+				tenantIndex(sortIndex("humidity")),
+				tenantIndex(sortIndex("cloudCover")),
+				tenantIndex(sortIndex("createdAt")),
+			),
+			DropIndexes("weather_cache", "timestamp_desc"),
+			CreateIndexes("weather_cache", tenantIndex(mongo.IndexModel{
+				Keys:    bson.D{{Key: "timestamp", Value: -1}},
+				Options: options.Index().SetName("timestamp_desc"),
+			})),
+			DropIndexes("comparisons", "createdAt_id"),
+			CreateIndexes("comparisons", tenantIndex(sortIndex("createdAt"))),
+			DropIndexes("api_keys", "createdAt_id"),
+			CreateIndexes("api_keys", tenantIndex(sortIndex("createdAt"))),
+			CreateIndexes("role_assignments", mongo.IndexModel{
+				Keys:    bson.D{{Key: "tenant", Value: 1}, {Key: "_id", Value: 1}},
+				Options: options.Index().SetName("tenant_id"),
+			}),
+		),
+		Down: Steps(
+			DropIndexes("reports", "tenant_timestamp_desc",
+				"tenant_location_timestamp", "tenant_source_timestamp", "tenant_type_timestamp", "tenant_tags_timestamp",
+				"tenant_temperature_id", "tenant_pressure_id", "tenant_humidity_id", "tenant_cloudCover_id", "tenant_createdAt_id"),
+			CreateIndexes("reports",
+				timestampIndex(),
+				categoryIndex("location"),
+				categoryIndex("source"),
+				categoryIndex("type"),
+				categoryIndex("tags"),
+				sortIndex("temperature"),
+				sortIndex("pressure"),
+				sortIndex("humidity"),
+				sortIndex("cloudCover"),
+				sortIndex("createdAt"),
+			),
+			DropIndexes("weather_cache", "tenant_timestamp_desc"),
+			CreateIndexes("weather_cache", mongo.IndexModel{
+				Keys:    bson.D{{Key: "timestamp", Value: -1}},
+				Options: options.Index().SetName("timestamp_desc"),
+			}),
+			DropIndexes("comparisons", "tenant_createdAt_id"),
+			CreateIndexes("comparisons", sortIndex("createdAt")),
+			DropIndexes("api_keys", "tenant_createdAt_id"),
+			CreateIndexes("api_keys", sortIndex("createdAt")),
+			DropIndexes("role_assignments", "tenant_id"),
+		),
+	},
+	{
+		Version:     11,
+		Description: "Create TTL index that removes rate limit buckets once they are full",
+		Up: CreateIndexes("rate_limits", mongo.IndexModel{
+			Keys:    bson.D{{Key: "fullAt", Value: 1}},
+			Options: options.Index().SetName("fullAt_ttl").SetExpireAfterSeconds(0),
+		}),
+		Down: DropIndexes("rate_limits", "fullAt_ttl"),
+	},
+	{
+		Version:     12,
+		Description: "Create audit log indexes for listing entries newest first by actor or changed item",
+		Up: CreateIndexes("audit_log",
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "tenant", Value: 1}, {Key: "_id", Value: -1}},
+				Options: options.Index().SetName("tenant_id_desc"),
+			},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "tenant", Value: 1}, {Key: "actor.subject", Value: 1}, {Key: "_id", Value: -1}},
+				Options: options.Index().SetName("tenant_actor_id_desc"),
+			},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "tenant", Value: 1}, {Key: "resourceId", Value: 1}, {Key: "_id", Value: -1}},
+				Options: options.Index().SetName("tenant_resourceId_id_desc"),
+			},
+		),
+		Down: DropIndexes("audit_log", "tenant_id_desc", "tenant_actor_id_desc", "tenant_resourceId_id_desc"),
+	},
+	{
+		Version:     13,
+		Description: "Backfill type on reports from before report types, which migration 2 missed",
+		Up:          backfillReportType,
+		// Keep the backfilled types; the filters of older versions expect every report to have one
+		Down: func(ctx context.Context, db *mongo.Database) error { return nil },
+	},
+	{
+		Version:     14,
+		Description: "Snapshot the thresholds in effect on saved comparisons, which classify their deviation",
+		Up:          snapshotComparisonThresholds,
+		Down:        UnsetField("comparisons", "thresholds"),
+	},
+}
+
+// comparisonDefaultThresholds are the built-in thresholds when migration 14 shipped, copied from
+// models.DefaultThresholds so that the migration keeps doing what it did then
+var comparisonDefaultThresholds = map[string][2]float64{
+	"temperature": {2, 5},
+	"pressure":    {3, 8},
+	"humidity":    {10, 25},
+	"cloudCover":  {20, 50},
+}
+
+// snapshotComparisonThresholds stores the thresholds of each tenant on its saved comparisons that have none,
+// the configured ones taking precedence over the defaults
+func snapshotComparisonThresholds(ctx context.Context, db *mongo.Database) error {
+	comparisons := db.Collection("comparisons")
+	filter := bson.M{"thresholds": bson.M{"$exists": false}}
+	tenants, err := comparisons.Distinct(ctx, "tenant", filter)
+	if err != nil {
+		return fmt.Errorf("failed to list tenants of comparisons: %w", err)
+	}
+
+	for _, tenant := range tenants {
+		thresholds := bson.M{}
+		for metric, levels := range comparisonDefaultThresholds {
+			thresholds[metric] = bson.M{"metric": metric, "notable": levels[0], "major": levels[1]}
+		}
+
+		cursor, err := db.Collection("thresholds").Find(ctx, bson.M{"tenant": tenant})
+		if err != nil {
+			return fmt.Errorf("failed to find thresholds of tenant %v: %w", tenant, err)
+		}
+		var configured []bson.M
+		if err := cursor.All(ctx, &configured); err != nil {
+			return fmt.Errorf("failed to decode thresholds of tenant %v: %w", tenant, err)
+		}
+		for _, threshold := range configured {
+			if metric, ok := threshold["metric"].(string); ok {
+				if _, known := comparisonDefaultThresholds[metric]; known {
+					thresholds[metric] = threshold
+				}
+			}
+		}
+
+		tenantFilter := bson.M{"tenant": tenant, "thresholds": bson.M{"$exists": false}}
+		if _, err := comparisons.UpdateMany(ctx, tenantFilter, bson.M{"$set": bson.M{"thresholds": thresholds}}); err != nil {
+			return fmt.Errorf("failed to snapshot thresholds of tenant %v: %w", tenant, err)
+		}
+	}
+	return nil
+}
+
+// backfillReportType sets the type of reports without one the way report generation chose it back then:
+// current if the report was created within 10 minutes of its timestamp, historical otherwise
+func backfillReportType(ctx context.Context, db *mongo.Database) error {
+	filter := bson.M{"type": bson.M{"$exists": false}}
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"type": bson.M{"$cond": bson.A{
+			bson.M{"$lt": bson.A{bson.M{"$subtract": bson.A{"$createdAt", "$timestamp"}}, (10 * time.Minute).Milliseconds()}},
+			"current",
+			"historical",
+		}},
+	}}}}
+	if _, err := db.Collection("reports").UpdateMany(ctx, filter, update); err != nil {
+		return fmt.Errorf("failed to backfill field type in collection reports: %w", err)
+	}
+	return nil
+}
+
+// defaultTenant owns the data from before tenants existed. It is models.DefaultTenant, copied so that
+// migration 9 keeps doing what it did when it shipped.
+const defaultTenant = "default"
+
+// timestampIndex returns the index of reports in timestamp order with the _id tie-break
+func timestampIndex() mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "timestamp", Value: -1},
+			{Key: "_id", Value: -1},
+		},
+		Options: options.Index().SetName("timestamp_desc"),
+	}
+}
+
+// tenantIndex returns index with the tenant as its leading key, so that queries of a tenant
+// only walk that tenant's part of it. The name gets a "tenant_" prefix; other options are not kept.
+func tenantIndex(index mongo.IndexModel) mongo.IndexModel {
+	keys := append(bson.D{{Key: "tenant", Value: 1}}, index.Keys.(bson.D)...)
+	name := "tenant_" + *index.Options.Name
+	return mongo.IndexModel{Keys: keys, Options: options.Index().SetName(name)}
+}
+
+// categoryIndex returns an index for equality filters on field combined with the timestamp order
+func categoryIndex(field string) mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys: bson.D{
+			{Key: field, Value: 1},
+			{Key: "timestamp", Value: -1},
+		},
+		Options: options.Index().SetName(field + "_timestamp"),
+	}
+}
+
+// metricIndex returns an index for range filters on a metric field
+func metricIndex(field string) mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys:    bson.D{{Key: field, Value: 1}},
+		Options: options.Index().SetName(field + "_asc"),
+	}
+}
+
+// sortIndex returns an index for sorting and paginating on field with the _id tie-break.
+// MongoDB can walk it in either direction, so one index serves both sort orders.
+func sortIndex(field string) mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys: bson.D{
+			{Key: field, Value: -1},
+			{Key: "_id", Value: -1},
+		},
+		Options: options.Index().SetName(field + "_id"),
+	}
+}
+
+// RunMigrations applies all pending migrations to the database
+func RunMigrations(db *mongo.Database) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	applied, err := NewMigrator(db, Migrations).Up(ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	if len(applied) == 0 {
+		log.Println("Database schema is up to date")
+	} else {
+		log.Printf("Applied %d migration(s)", len(applied))
+	}
+	return nil
+}
+
+// getExistingIndexes gets all existing indexes for a collection
+func getExistingIndexes(ctx context.Context, collection *mongo.Collection) ([]string, error) {
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		if isNamespaceNotFound(err) {
+			return nil, nil // Collection does not exist yet, so it has no indexes
+		}
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var indexes []bson.M
+	if err = cursor.All(ctx, &indexes); err != nil {
+		return nil, err
+	}
+
+	var indexNames []string
+	for _, index := range indexes {
+		if name, ok := index["name"].(string); ok {
+			indexNames = append(indexNames, name)
+		}
+	}
+
+	return indexNames, nil
+}
+
+// indexExists checks if an index exists in the list of existing indexes
+func indexExists(existingIndexes []string, indexName string) bool {
+	for _, name := range existingIndexes {
+		if name == indexName {
+			return true
+		}
+	}
+	return false
+}
+
+// isNamespaceNotFound reports whether err is MongoDB's NamespaceNotFound error
+func isNamespaceNotFound(err error) bool {
+	var cmdErr mongo.CommandError
+	return errors.As(err, &cmdErr) && cmdErr.Code == 26
+}
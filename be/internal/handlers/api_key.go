@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/DangVTNhan/Scanner/be/internal/interfaces"
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/errors"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// APIKeyHandler handles HTTP requests related to API keys
+type APIKeyHandler struct {
+	apiKeyService interfaces.IAPIKeyService
+}
+
+// NewAPIKeyHandler creates a new instance of APIKeyHandler
+func NewAPIKeyHandler(apiKeyService interfaces.IAPIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// CreateAPIKey handles requests to create an API key
+// @Summary Create an API key
+// @Description Create a new API key. The key is only returned in this response; store it, only its hash is kept.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param request body request.APIKeyRequest true "API key request"
+// @Success 201 {object} response.BaseResponse{data=response.CreatedAPIKey} "API key created successfully"
+// @Failure 400 {object} response.BaseResponse "Invalid request"
+// @Failure 401 {object} response.BaseResponse "Missing or invalid credentials"
+// @Failure 403 {object} response.BaseResponse "Missing permission"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req request.APIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, "Invalid request body", errors.ErrCodeInvalidRequest, nil, http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.apiKeyService.CreateAPIKey(r.Context(), &req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid api key:") {
+			respondWithError(w, err.Error(), errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+		} else {
+			errorCode := errors.ErrCodeServerError
+			if strings.Contains(err.Error(), "failed to save api key") {
+				errorCode = errors.ErrCodeDatabaseInsert
+			}
+			respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	responseData := response.NewSuccessResponse("API key created successfully", created)
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// ListAPIKeys handles requests to list API keys
+// @Summary List API keys
+// @Description List all API keys, revoked ones included, newest first. Keys are identified by their prefix; the keys themselves are never returned.
+// @Tags api-keys
+// @Produce json
+// @Success 200 {object} response.BaseResponse{data=[]models.APIKey} "API keys retrieved successfully"
+// @Failure 401 {object} response.BaseResponse "Missing or invalid credentials"
+// @Failure 403 {object} response.BaseResponse "Missing permission"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.apiKeyService.ListAPIKeys(r.Context())
+	if err != nil {
+		errorCode := errors.ErrCodeServerError
+		if strings.Contains(err.Error(), "failed to retrieve api keys") {
+			errorCode = errors.ErrCodeDatabaseQuery
+		}
+		respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+		return
+	}
+	if keys == nil {
+		keys = []models.APIKey{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	responseData := response.NewSuccessResponse("API keys retrieved successfully", keys)
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// RevokeAPIKey handles requests to revoke an API key
+// @Summary Revoke an API key
+// @Description Revoke an API key so it no longer authenticates. The key stays listed with its revocation time; revoking it again keeps that time.
+// @Tags api-keys
+// @Produce json
+// @Param id path string true "API key ID"
+// @Success 200 {object} response.BaseResponse{data=models.APIKey} "API key revoked successfully"
+// @Failure 401 {object} response.BaseResponse "Missing or invalid credentials"
+// @Failure 403 {object} response.BaseResponse "Missing permission"
+// @Failure 404 {object} response.BaseResponse "API key not found"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	apiKey, err := h.apiKeyService.RevokeAPIKey(r.Context(), id)
+	if err != nil {
+		if err.Error() == "api key not found" {
+			respondWithError(w, "API key not found", errors.ErrCodeNotFound, nil, http.StatusNotFound)
+		} else {
+			errorCode := errors.ErrCodeServerError
+			if strings.Contains(err.Error(), "failed to revoke api key") {
+				errorCode = errors.ErrCodeDatabaseUpdate
+			} else if strings.Contains(err.Error(), "failed to retrieve api key") {
+				errorCode = errors.ErrCodeDatabaseQuery
+			}
+			respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	responseData := response.NewSuccessResponse("API key revoked successfully", apiKey)
+	json.NewEncoder(w).Encode(responseData)
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/interfaces"
+	"github.com/DangVTNhan/Scanner/be/internal/models/errors"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+)
+
+// AuditHandler handles HTTP requests related to the audit log. The log can only be read through the API.
+type AuditHandler struct {
+	auditService interfaces.IAuditService
+}
+
+// NewAuditHandler creates a new instance of AuditHandler
+func NewAuditHandler(auditService interfaces.IAuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// ListAuditEntries handles requests to search the audit log
+// @Summary Search the audit log
+// @Description List the recorded POST, PUT, PATCH and DELETE requests of the caller's tenant, newest first: who made them, the route and parameters, the status and the latency.
+// @Description Pass nextCursor as cursor to get the next, older page.
+// @Tags audit
+// @Produce json
+// @Param actor query string false "Subject of the caller: API key ID or token sub claim"
+// @Param method query string false "HTTP method, e.g. DELETE"
+// @Param route query string false "Route path template, e.g. /api/reports/{id}"
+// @Param resourceId query string false "ID of the created or changed item"
+// @Param outcome query string false "success (status below 400) or failure"
+// @Param fromTime query string false "Include entries from this time (RFC3339 format)"
+// @Param toTime query string false "Include entries until this time (RFC3339 format)"
+// @Param limit query int false "Number of entries to return (default 50, at most 1000)"
+// @Param cursor query string false "nextCursor of the previous page"
+// @Success 200 {object} response.BaseResponse{data=response.AuditLogPage} "Audit entries retrieved successfully"
+// @Failure 400 {object} response.BaseResponse "Invalid parameters"
+// @Failure 401 {object} response.BaseResponse "Missing or invalid credentials"
+// @Failure 403 {object} response.BaseResponse "Missing permission"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /admin/audit [get]
+func (h *AuditHandler) ListAuditEntries(w http.ResponseWriter, r *http.Request) {
+	req, err := parseAuditRequest(r.URL.Query())
+	if err != nil {
+		respondWithError(w, err.Error(), errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.auditService.ListAuditEntries(r.Context(), req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid audit query") {
+			respondWithError(w, err.Error(), errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
+		errorCode := errors.ErrCodeServerError
+		if strings.Contains(err.Error(), "failed to retrieve audit entries") {
+			errorCode = errors.ErrCodeDatabaseQuery
+		}
+		respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	responseData := response.NewSuccessResponse("Audit entries retrieved successfully", page)
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// ExportAuditEntries handles requests to download the audit log
+// @Summary Export the audit log
+// @Description Download every audit entry matching the same filters as /admin/audit, newest first, streamed as CSV, NDJSON or a JSON array.
+// @Tags audit
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce json
+// @Param format query string false "Export format: csv (default), ndjson or json"
+// @Param actor query string false "Subject of the caller: API key ID or token sub claim"
+// @Param method query string false "HTTP method, e.g. DELETE"
+// @Param route query string false "Route path template, e.g. /api/reports/{id}"
+// @Param resourceId query string false "ID of the created or changed item"
+// @Param outcome query string false "success (status below 400) or failure"
+// @Param fromTime query string false "Include entries from this time (RFC3339 format)"
+// @Param toTime query string false "Include entries until this time (RFC3339 format)"
+// @Success 200 {file} file "Exported audit entries"
+// @Failure 400 {object} response.BaseResponse "Invalid parameters"
+// @Failure 401 {object} response.BaseResponse "Missing or invalid credentials"
+// @Failure 403 {object} response.BaseResponse "Missing permission"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /admin/audit/export [get]
+func (h *AuditHandler) ExportAuditEntries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	format, err := request.ParseExportFormat(query.Get("format"))
+	if err != nil {
+		respondWithError(w, "Invalid format parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+		return
+	}
+	req, err := parseAuditRequest(query)
+	if err != nil {
+		respondWithError(w, err.Error(), errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+		return
+	}
+
+	// Large exports take longer than the server's write timeout
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"audit-log.%s\"", format))
+
+	out := &exportWriter{w: w}
+	if err := h.auditService.ExportAuditEntries(r.Context(), req, format, out); err != nil {
+		if out.written {
+			log.Printf("Audit log export aborted: %v", err)
+			return
+		}
+
+		w.Header().Del("Content-Disposition")
+		if strings.HasPrefix(err.Error(), "invalid export") || strings.HasPrefix(err.Error(), "invalid audit query") {
+			respondWithError(w, err.Error(), errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
+		errorCode := errors.ErrCodeServerError
+		if strings.Contains(err.Error(), "failed to retrieve audit entries") {
+			errorCode = errors.ErrCodeDatabaseQuery
+		}
+		respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+	}
+}
+
+// parseAuditRequest reads the filters and paging of an audit log query
+func parseAuditRequest(query url.Values) (*request.AuditLogRequest, error) {
+	req := &request.AuditLogRequest{
+		Actor:      query.Get("actor"),
+		Method:     query.Get("method"),
+		Route:      query.Get("route"),
+		ResourceID: query.Get("resourceId"),
+		Cursor:     query.Get("cursor"),
+	}
+
+	outcome, err := request.ParseAuditOutcome(query.Get("outcome"))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid outcome parameter")
+	}
+	req.Outcome = outcome
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("Invalid limit parameter")
+		}
+		req.Limit = limit
+	}
+	if fromTimeStr := query.Get("fromTime"); fromTimeStr != "" {
+		fromTime, err := time.Parse(time.RFC3339, fromTimeStr)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid fromTime parameter")
+		}
+		req.From = fromTime
+	}
+	if toTimeStr := query.Get("toTime"); toTimeStr != "" {
+		toTime, err := time.Parse(time.RFC3339, toTimeStr)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid toTime parameter")
+		}
+		req.To = toTime
+	}
+	return req, nil
+}
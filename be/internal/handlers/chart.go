@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/DangVTNhan/Scanner/be/internal/interfaces"
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/errors"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+	"github.com/DangVTNhan/Scanner/be/internal/render"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChartHandler handles HTTP requests for charts of the report history
+type ChartHandler struct {
+	reportService interfaces.IReportService
+}
+
+// NewChartHandler creates a new instance of ChartHandler
+func NewChartHandler(reportService interfaces.IReportService) *ChartHandler {
+	return &ChartHandler{
+		reportService: reportService,
+	}
+}
+
+// chartMaxAge is how long clients and proxies may cache a chart, in seconds
+const chartMaxAge = 300
+
+// GetMetricChart handles requests for an SVG chart of the history of a metric
+// @Summary Chart the history of a metric
+// @Description Render the stored reports of a metric in a time range as a standalone SVG line or area chart, for embedding in emails and wiki pages.
+// @Description Every report is plotted unless bucket is set, band is requested or there are more than 1000 reports; the reports are then aggregated per bucket
+// @Description (chosen to give at most about 120 points when not set), the line shows the bucket means and the band their min and max.
+// @Description With format=json the plotted points are returned instead.
+// @Tags charts
+// @Produce image/svg+xml
+// @Produce json
+// @Param metric query string true "Metric (temperature, pressure, humidity, cloudCover)"
+// @Param from query string false "Start of the chart (RFC3339 format, default 7 days before to)"
+// @Param to query string false "End of the chart (RFC3339 format, default now)"
+// @Param bucket query string false "Aggregate the reports in buckets of this length, e.g. 30m, 3h or 24h"
+// @Param band query bool false "Set to true to draw the min/max band of every bucket"
+// @Param style query string false "Chart style: line or area (default line)"
+// @Param width query int false "Width in pixels, 240 to 4000 (default 800)"
+// @Param height query int false "Height in pixels, 160 to 2000 (default 320)"
+// @Param format query string false "Response format: svg or json (default svg)"
+// @Param apiKey query string false "API key, for clients that cannot send the X-API-Key header"
+// @Success 200 {file} file "SVG chart"
+// @Success 200 {object} response.BaseResponse{data=response.MetricSeries} "Chart data, with format=json"
+// @Failure 400 {object} response.BaseResponse "Invalid parameters"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /charts/metric [get]
+func (h *ChartHandler) GetMetricChart(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	req := &request.MetricChartRequest{
+		Metric: models.Metric(query.Get("metric")),
+		Width:  request.DefaultChartWidth,
+		Height: request.DefaultChartHeight,
+	}
+	if req.Metric == "" {
+		respondWithError(w, "metric is required", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+		return
+	}
+
+	if fromStr := query.Get("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			respondWithError(w, "Invalid from parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
+		req.From = from
+	}
+	if toStr := query.Get("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			respondWithError(w, "Invalid to parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
+		req.To = to
+	}
+
+	if bucketStr := query.Get("bucket"); bucketStr != "" {
+		bucket, err := time.ParseDuration(bucketStr)
+		if err != nil || bucket <= 0 {
+			respondWithError(w, "Invalid bucket parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
+		req.Bucket = bucket
+	}
+	if bandStr := query.Get("band"); bandStr != "" {
+		band, err := strconv.ParseBool(bandStr)
+		if err != nil {
+			respondWithError(w, "Invalid band parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
+		req.Band = band
+	}
+
+	asJSON := false
+	switch query.Get("format") {
+	case "", "svg":
+	case "json":
+		asJSON = true
+	default:
+		respondWithError(w, "Invalid format parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+		return
+	}
+
+	style, err := request.ParseChartStyle(query.Get("style"))
+	if err != nil {
+		respondWithError(w, "Invalid style parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+		return
+	}
+	req.Style = style
+
+	if widthStr := query.Get("width"); widthStr != "" {
+		width, err := strconv.Atoi(widthStr)
+		if err != nil || width < request.MinChartWidth || width > request.MaxChartWidth {
+			respondWithError(w, "Invalid width parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
+		req.Width = width
+	}
+	if heightStr := query.Get("height"); heightStr != "" {
+		height, err := strconv.Atoi(heightStr)
+		if err != nil || height < request.MinChartHeight || height > request.MaxChartHeight {
+			respondWithError(w, "Invalid height parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
+		req.Height = height
+	}
+
+	series, err := h.reportService.GetMetricSeries(r.Context(), req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid chart") {
+			respondWithError(w, err.Error(), errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
+		errorCode := errors.ErrCodeServerError
+		if strings.Contains(err.Error(), "failed to retrieve reports") || strings.Contains(err.Error(), "failed to aggregate reports") {
+			errorCode = errors.ErrCodeDatabaseQuery
+		}
+		respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+		return
+	}
+
+	if asJSON {
+		w.Header().Set("Content-Type", "application/json")
+		responseData := response.NewSuccessResponse("Chart data retrieved successfully", series)
+		json.NewEncoder(w).Encode(responseData)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := render.MetricChart(&buf, series, req.Style, req.Band, req.Width, req.Height); err != nil {
+		respondWithError(w, fmt.Sprintf("failed to render chart: %v", err), errors.ErrCodeServerError, nil, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", chartMaxAge))
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	buf.WriteTo(w)
+}
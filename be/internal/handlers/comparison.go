@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/DangVTNhan/Scanner/be/internal/interfaces"
+	"github.com/DangVTNhan/Scanner/be/internal/models/errors"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+	"github.com/DangVTNhan/Scanner/be/internal/render"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// ComparisonHandler handles HTTP requests related to saved comparisons
+type ComparisonHandler struct {
+	comparisonService interfaces.IComparisonService
+}
+
+// NewComparisonHandler creates a new instance of ComparisonHandler
+func NewComparisonHandler(comparisonService interfaces.IComparisonService) *ComparisonHandler {
+	return &ComparisonHandler{
+		comparisonService: comparisonService,
+	}
+}
+
+// ListComparisons handles requests to list saved comparisons
+// @Summary List saved comparisons
+// @Description List saved comparisons, newest first
+// @Tags comparisons
+// @Produce json
+// @Param limit query int false "Limit number of results (default 10)"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {object} response.BaseResponse{data=response.SavedComparisonsResponse} "Comparisons retrieved successfully"
+// @Failure 400 {object} response.BaseResponse "Invalid parameters"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /comparisons [get]
+func (h *ComparisonHandler) ListComparisons(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := 0
+	if limitStr := query.Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			respondWithError(w, "Invalid limit parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
+	}
+
+	offset := 0
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		var err error
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			respondWithError(w, "Invalid offset parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
+	}
+
+	comparisons, err := h.comparisonService.ListComparisons(r.Context(), limit, offset)
+	if err != nil {
+		errorCode := errors.ErrCodeServerError
+		if strings.Contains(err.Error(), "failed to retrieve comparisons") ||
+			strings.Contains(err.Error(), "failed to count comparisons") {
+			errorCode = errors.ErrCodeDatabaseQuery
+		}
+		respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	responseData := response.NewSuccessResponse("Comparisons retrieved successfully", comparisons)
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// GetComparison handles requests to retrieve a saved comparison
+// @Summary Get a saved comparison by ID
+// @Description Get a saved comparison. The reports are the snapshots taken when it was saved.
+// @Tags comparisons
+// @Produce json
+// @Param id path string true "Comparison ID"
+// @Success 200 {object} response.BaseResponse{data=response.SavedComparison} "Comparison retrieved successfully"
+// @Failure 404 {object} response.BaseResponse "Comparison not found"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /comparisons/{id} [get]
+func (h *ComparisonHandler) GetComparison(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	comparison, err := h.comparisonService.GetComparison(r.Context(), id)
+	if err != nil {
+		if err.Error() == "comparison not found" {
+			respondWithError(w, "Comparison not found", errors.ErrCodeComparisonNotFound, nil, http.StatusNotFound)
+		} else {
+			errorCode := errors.ErrCodeServerError
+			if strings.Contains(err.Error(), "failed to retrieve comparison") {
+				errorCode = errors.ErrCodeDatabaseQuery
+			}
+			respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	responseData := response.NewSuccessResponse("Comparison retrieved successfully", comparison)
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// RenderComparison handles requests for a printable saved comparison
+// @Summary Render a printable saved comparison
+// @Description Render a saved comparison as a branded HTML page with print styles or as an A4 PDF document,
+// @Description with the metric table and deviation column of the comparison page, its title and its notes.
+// @Tags comparisons
+// @Produce html
+// @Produce application/pdf
+// @Param id path string true "Comparison ID"
+// @Param format query string false "Document format: html or pdf (default html)"
+// @Success 200 {file} file "Rendered comparison"
+// @Failure 400 {object} response.BaseResponse "Invalid parameters"
+// @Failure 404 {object} response.BaseResponse "Comparison not found"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /comparisons/{id}/render [get]
+func (h *ComparisonHandler) RenderComparison(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	format, err := request.ParseRenderFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		respondWithError(w, "Invalid format parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+		return
+	}
+
+	comparison, err := h.comparisonService.GetComparison(r.Context(), id)
+	if err != nil {
+		if err.Error() == "comparison not found" {
+			respondWithError(w, "Comparison not found", errors.ErrCodeComparisonNotFound, nil, http.StatusNotFound)
+		} else {
+			errorCode := errors.ErrCodeServerError
+			if strings.Contains(err.Error(), "failed to retrieve comparison") {
+				errorCode = errors.ErrCodeDatabaseQuery
+			}
+			respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithDocument(w, format, "comparison-"+comparison.ID, func(out io.Writer) error {
+		return render.Comparison(out, format, comparison, time.Now())
+	})
+}
+
+// DeleteComparison handles requests to delete a saved comparison
+// @Summary Delete a saved comparison
+// @Description Delete a saved comparison by its ID. The compared reports are not affected.
+// @Tags comparisons
+// @Produce json
+// @Param id path string true "Comparison ID"
+// @Success 200 {object} response.BaseResponse "Comparison deleted successfully"
+// @Failure 403 {object} response.BaseResponse "Missing permission"
+// @Failure 404 {object} response.BaseResponse "Comparison not found"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /comparisons/{id} [delete]
+func (h *ComparisonHandler) DeleteComparison(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	if err := h.comparisonService.DeleteComparison(r.Context(), id); err != nil {
+		if err.Error() == "comparison not found" {
+			respondWithError(w, "Comparison not found", errors.ErrCodeComparisonNotFound, nil, http.StatusNotFound)
+		} else {
+			errorCode := errors.ErrCodeServerError
+			if strings.Contains(err.Error(), "failed to delete comparison") {
+				errorCode = errors.ErrCodeDatabaseDelete
+			}
+			respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	responseData := response.NewSuccessResponse("Comparison deleted successfully", nil)
+	json.NewEncoder(w).Encode(responseData)
+}
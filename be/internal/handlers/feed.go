@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/DangVTNhan/Scanner/be/internal/interfaces"
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/errors"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+	"github.com/DangVTNhan/Scanner/be/internal/render"
+	"github.com/DangVTNhan/Scanner/be/pkg/feed"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FeedHandler handles HTTP requests for feeds of reports and alerts
+type FeedHandler struct {
+	reportService interfaces.IReportService
+}
+
+// NewFeedHandler creates a new instance of FeedHandler
+func NewFeedHandler(reportService interfaces.IReportService) *FeedHandler {
+	return &FeedHandler{
+		reportService: reportService,
+	}
+}
+
+// GetReportsAtom handles requests for an Atom feed of the latest reports
+// @Summary Atom feed of the latest reports
+// @Description Get the reports with the most recent timestamps, newest first, as an Atom 1.0 feed for feed readers.
+// @Description Responses carry an ETag and a Last-Modified header; send them back in If-None-Match or If-Modified-Since to get 304 Not Modified while nothing changed.
+// @Tags feeds
+// @Produce application/atom+xml
+// @Param limit query int false "Number of reports, 1 to 100 (default 20)"
+// @Param apiKey query string false "API key, for clients that cannot send the X-API-Key header"
+// @Success 200 {file} file "Atom feed"
+// @Success 304 "Not modified"
+// @Failure 400 {object} response.BaseResponse "Invalid parameters"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /feeds/reports.atom [get]
+func (h *FeedHandler) GetReportsAtom(w http.ResponseWriter, r *http.Request) {
+	h.reportFeed(w, r, request.FeedFormatAtom, feed.AtomContentType)
+}
+
+// GetReportsRSS handles requests for an RSS feed of the latest reports
+// @Summary RSS feed of the latest reports
+// @Description Get the reports with the most recent timestamps, newest first, as an RSS 2.0 feed for feed readers.
+// @Description Responses carry an ETag and a Last-Modified header; send them back in If-None-Match or If-Modified-Since to get 304 Not Modified while nothing changed.
+// @Tags feeds
+// @Produce application/rss+xml
+// @Param limit query int false "Number of reports, 1 to 100 (default 20)"
+// @Param apiKey query string false "API key, for clients that cannot send the X-API-Key header"
+// @Success 200 {file} file "RSS feed"
+// @Success 304 "Not modified"
+// @Failure 400 {object} response.BaseResponse "Invalid parameters"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /feeds/reports.rss [get]
+func (h *FeedHandler) GetReportsRSS(w http.ResponseWriter, r *http.Request) {
+	h.reportFeed(w, r, request.FeedFormatRSS, feed.RSSContentType)
+}
+
+// reportFeed sends the latest reports as a feed of the given format
+func (h *FeedHandler) reportFeed(w http.ResponseWriter, r *http.Request, format request.FeedFormat, contentType string) {
+	limit := request.DefaultFeedLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil {
+			respondWithError(w, "Invalid limit parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	reports, err := h.reportService.GetLatestReports(r.Context(), limit)
+	if err != nil {
+		respondWithFeedError(w, err)
+		return
+	}
+
+	lastModified := reportsLastModified(reports)
+	apiURL := apiBaseURL(r)
+	respondWithFeed(w, r, contentType, lastModified, func(out io.Writer) error {
+		return render.ReportFeed(out, format, reports, apiURL, lastModified)
+	})
+}
+
+// GetAlertsICS handles requests for an iCalendar feed of alert events
+// @Summary iCalendar feed of alert events
+// @Description Get the alert events of a time range as an iCalendar feed for calendar clients. An alert event is a report whose deviation
+// @Description from the report before it reaches the minimum severity under the configured thresholds; the event spans the time between the two reports.
+// @Description At most the 500 most recent events are included.
+// @Description Responses carry an ETag and a Last-Modified header; send them back in If-None-Match or If-Modified-Since to get 304 Not Modified while nothing changed.
+// @Tags feeds
+// @Produce text/calendar
+// @Param from query string false "Start of the range (RFC3339 format, default 30 days before to)"
+// @Param to query string false "End of the range (RFC3339 format, default now)"
+// @Param severity query string false "Minimum severity: notable or major (default notable)"
+// @Param apiKey query string false "API key, for clients that cannot send the X-API-Key header"
+// @Success 200 {file} file "iCalendar feed"
+// @Success 304 "Not modified"
+// @Failure 400 {object} response.BaseResponse "Invalid parameters"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /feeds/alerts.ics [get]
+func (h *FeedHandler) GetAlertsICS(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	req := &request.AlertFeedRequest{MinSeverity: models.Severity(query.Get("severity"))}
+	if fromStr := query.Get("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			respondWithError(w, "Invalid from parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
+		req.From = from
+	}
+	if toStr := query.Get("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			respondWithError(w, "Invalid to parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
+		req.To = to
+	}
+
+	events, err := h.reportService.GetAlertEvents(r.Context(), req)
+	if err != nil {
+		respondWithFeedError(w, err)
+		return
+	}
+
+	lastModified := alertsLastModified(events)
+	apiURL := apiBaseURL(r)
+	respondWithFeed(w, r, feed.ICSContentType, lastModified, func(out io.Writer) error {
+		return render.AlertCalendar(out, events, apiURL)
+	})
+}
+
+// respondWithFeedError sends the error of building a feed
+func respondWithFeedError(w http.ResponseWriter, err error) {
+	if strings.HasPrefix(err.Error(), "invalid feed") {
+		respondWithError(w, err.Error(), errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+		return
+	}
+	errorCode := errors.ErrCodeServerError
+	if strings.Contains(err.Error(), "failed to retrieve reports") || strings.Contains(err.Error(), "failed to load thresholds") {
+		errorCode = errors.ErrCodeDatabaseQuery
+	}
+	respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+}
+
+// respondWithFeed sends a rendered feed with validators for conditional requests. The ETag is a hash of the
+// content, so it also changes when reports are edited or deleted, which lastModified does not reflect; it
+// takes precedence over If-Modified-Since. Clients are asked to revalidate on every poll.
+func respondWithFeed(w http.ResponseWriter, r *http.Request, contentType string, lastModified time.Time, write func(io.Writer) error) {
+	var buf bytes.Buffer
+	if err := write(&buf); err != nil {
+		respondWithError(w, fmt.Sprintf("failed to render feed: %v", err), errors.ErrCodeServerError, nil, http.StatusInternalServerError)
+		return
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	w.Header().Set("Cache-Control", "no-cache")
+	http.ServeContent(w, r, "", lastModified, bytes.NewReader(buf.Bytes()))
+}
+
+// apiBaseURL returns the absolute URL of the API as seen by the client, for links in feeds.
+// The scheme of a TLS-terminating proxy is taken from X-Forwarded-Proto.
+func apiBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return fmt.Sprintf("%s://%s/api", scheme, r.Host)
+}
+
+// reportsLastModified returns when the newest of the reports was created
+func reportsLastModified(reports []models.WeatherReport) time.Time {
+	var lastModified time.Time
+	for _, report := range reports {
+		if report.CreatedAt.After(lastModified) {
+			lastModified = report.CreatedAt
+		}
+	}
+	return lastModified
+}
+
+// alertsLastModified returns when the newest report of the alert events was created
+func alertsLastModified(events []response.AlertEvent) time.Time {
+	var lastModified time.Time
+	for _, event := range events {
+		if event.Report.CreatedAt.After(lastModified) {
+			lastModified = event.Report.CreatedAt
+		}
+	}
+	return lastModified
+}
@@ -1,12 +1,20 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"github.com/DangVTNhan/Scanner/be/internal/interfaces"
+	"github.com/DangVTNhan/Scanner/be/internal/models"
 	"github.com/DangVTNhan/Scanner/be/internal/models/errors"
 	"github.com/DangVTNhan/Scanner/be/internal/models/request"
 	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+	"github.com/DangVTNhan/Scanner/be/internal/render"
+	"io"
+	"log"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -16,13 +24,15 @@ import (
 
 // ReportHandler handles HTTP requests related to weather reports
 type ReportHandler struct {
-	reportService interfaces.IReportService
+	reportService     interfaces.IReportService
+	comparisonService interfaces.IComparisonService
 }
 
 // NewReportHandler creates a new instance of ReportHandler
-func NewReportHandler(reportService interfaces.IReportService) *ReportHandler {
+func NewReportHandler(reportService interfaces.IReportService, comparisonService interfaces.IComparisonService) *ReportHandler {
 	return &ReportHandler{
-		reportService: reportService,
+		reportService:     reportService,
+		comparisonService: comparisonService,
 	}
 }
 
@@ -35,7 +45,10 @@ func NewReportHandler(reportService interfaces.IReportService) *ReportHandler {
 // @Param request body request.ReportRequest true "Report request"
 // @Success 201 {object} response.BaseResponse{data=docs.WeatherReport} "Report generated successfully"
 // @Failure 400 {object} response.BaseResponse "Invalid request"
+// @Failure 403 {object} response.BaseResponse "Missing permission or report quota exceeded"
 // @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /reports [post]
 func (h *ReportHandler) GenerateReport(w http.ResponseWriter, r *http.Request) {
 	var req request.ReportRequest
@@ -46,17 +59,11 @@ func (h *ReportHandler) GenerateReport(w http.ResponseWriter, r *http.Request) {
 
 	report, err := h.reportService.GenerateReport(r.Context(), &req)
 	if err != nil {
-		errorCode := errors.ErrCodeServerError
-		statusCode := http.StatusInternalServerError
-
-		// Determine specific error code based on error message
-		if strings.Contains(err.Error(), "failed to get weather data") {
-			errorCode = errors.ErrCodeWeatherServiceResponse
-		} else if strings.Contains(err.Error(), "failed to save report") {
-			errorCode = errors.ErrCodeDatabaseInsert
+		if strings.HasPrefix(err.Error(), "quota exceeded") {
+			respondWithError(w, err.Error(), errors.ErrCodeQuotaExceeded, nil, http.StatusForbidden)
+			return
 		}
-
-		respondWithError(w, err.Error(), errorCode, nil, statusCode)
+		respondWithError(w, err.Error(), generateReportErrorCode(err), nil, http.StatusInternalServerError)
 		return
 	}
 
@@ -66,6 +73,73 @@ func (h *ReportHandler) GenerateReport(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(responseData)
 }
 
+// GenerateReports handles requests to generate weather reports for several timestamps
+// @Summary Generate weather reports in bulk
+// @Description Generate a weather report for each timestamp, a few at a time. Equal timestamps are generated once.
+// @Description Every distinct timestamp gets an item with its own status and error code; the status is 201 if all succeeded and 207 otherwise.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param request body request.BatchReportRequest true "Batch report request"
+// @Success 201 {object} response.BaseResponse{data=response.BatchReportResult} "Reports generated successfully"
+// @Success 207 {object} response.BaseResponse{data=response.BatchReportResult} "Some reports could not be generated"
+// @Failure 400 {object} response.BaseResponse "Invalid request"
+// @Failure 403 {object} response.BaseResponse "Missing permission or report quota exceeded"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /reports/batch [post]
+func (h *ReportHandler) GenerateReports(w http.ResponseWriter, r *http.Request) {
+	var req request.BatchReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, "Invalid request body", errors.ErrCodeInvalidRequest, nil, http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.reportService.GenerateReports(r.Context(), &req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid batch request") {
+			respondWithError(w, err.Error(), errors.ErrCodeInvalidRequest, nil, http.StatusBadRequest)
+			return
+		}
+		if strings.HasPrefix(err.Error(), "quota exceeded") {
+			respondWithError(w, err.Error(), errors.ErrCodeQuotaExceeded, nil, http.StatusForbidden)
+			return
+		}
+		respondWithError(w, err.Error(), errors.ErrCodeServerError, nil, http.StatusInternalServerError)
+		return
+	}
+
+	for i := range result.Items {
+		if result.Items[i].Err != nil {
+			result.Items[i].ErrorCode = generateReportErrorCode(result.Items[i].Err)
+		}
+	}
+
+	statusCode := http.StatusCreated
+	message := "Reports generated successfully"
+	if result.Failed > 0 {
+		statusCode = http.StatusMultiStatus
+		message = fmt.Sprintf("%d of %d reports could not be generated", result.Failed, len(result.Items))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	responseData := response.NewSuccessResponse(message, result)
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// generateReportErrorCode determines the error code of a report generation error from its message
+func generateReportErrorCode(err error) string {
+	if strings.Contains(err.Error(), "failed to get weather data") {
+		return errors.ErrCodeWeatherServiceResponse
+	} else if strings.Contains(err.Error(), "failed to save report") {
+		return errors.ErrCodeDatabaseInsert
+	} else if strings.HasPrefix(err.Error(), "quota exceeded") {
+		return errors.ErrCodeQuotaExceeded
+	}
+	return errors.ErrCodeServerError
+}
+
 // GetAllReports handles requests to retrieve all weather reports (legacy endpoint)
 // @Summary Get all weather reports
 // @Description Get all weather reports (legacy endpoint, no pagination)
@@ -73,6 +147,8 @@ func (h *ReportHandler) GenerateReport(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Success 200 {object} response.BaseResponse{data=[]docs.WeatherReport} "Reports retrieved successfully"
 // @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /reports [get]
 func (h *ReportHandler) GetAllReports(w http.ResponseWriter, r *http.Request) {
 	reports, err := h.reportService.GetAllReports(r.Context())
@@ -92,18 +168,31 @@ func (h *ReportHandler) GetAllReports(w http.ResponseWriter, r *http.Request) {
 
 // GetPaginatedReports handles requests to retrieve paginated weather reports with optional filtering
 // @Summary Get paginated weather reports
-// @Description Get paginated weather reports with optional filtering by time range
+// @Description Get paginated weather reports with optional filtering by time range, metric ranges, location, source, type and tags.
+// @Description Pass the nextCursor or prevCursor of a previous page as cursor to page by keyset instead of offset.
 // @Tags reports
 // @Produce json
 // @Param limit query int false "Limit number of results"
-// @Param offset query int false "Offset for pagination"
+// @Param offset query int false "Offset for pagination (cannot be combined with cursor)"
+// @Param cursor query string false "Opaque cursor from a previous page (only valid with the same sort)"
+// @Param includeTotal query bool false "Set to false to skip counting all matching reports (totalCount is -1)"
+// @Param includeDeleted query bool false "Set to true to include soft-deleted reports"
 // @Param fromTime query string false "Filter by start time (RFC3339 format)"
 // @Param toTime query string false "Filter by end time (RFC3339 format)"
-// @Param sortBy query string false "Field to sort by"
-// @Param sortOrder query string false "Sort order (asc or desc)"
+// @Param filter query []string false "Metric conditions such as temperature>=30 or humidity<80 (repeat or comma-separate)" collectionFormat(multi)
+// @Param logic query string false "How metric conditions are combined: and (default) or or"
+// @Param location query string false "Comma-separated locations (ICAO codes) to include"
+// @Param source query string false "Comma-separated data providers to include"
+// @Param type query string false "Comma-separated report types to include (current, historical)"
+// @Param tag query string false "Comma-separated tags; reports with any of them are included"
+// @Param sort query string false "Comma-separated sort keys, - prefix for descending, e.g. -temperature,timestamp. Fields: timestamp, createdAt, temperature, pressure, humidity, cloudCover"
+// @Param sortBy query string false "Field to sort by (legacy, use sort)"
+// @Param sortOrder query string false "Sort order, asc or desc (legacy, use sort)"
 // @Success 200 {object} response.BaseResponse{data=response.PaginatedReportsResponse} "Reports retrieved successfully"
 // @Failure 400 {object} response.BaseResponse "Invalid parameters"
 // @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /reports/paginated [get]
 func (h *ReportHandler) GetPaginatedReports(w http.ResponseWriter, r *http.Request) {
 	// Parse query parameters
@@ -112,8 +201,6 @@ func (h *ReportHandler) GetPaginatedReports(w http.ResponseWriter, r *http.Reque
 	// Create request object
 	req := &request.PaginatedReportsRequest{
 		IsFiltered: false,
-		SortBy:     query.Get("sortBy"),
-		SortOrder:  request.SortOrder(query.Get("sortOrder")),
 	}
 
 	// Parse limit
@@ -136,31 +223,49 @@ func (h *ReportHandler) GetPaginatedReports(w http.ResponseWriter, r *http.Reque
 		req.Offset = offset
 	}
 
-	// Parse from time
-	if fromTimeStr := query.Get("fromTime"); fromTimeStr != "" {
-		fromTime, err := time.Parse(time.RFC3339, fromTimeStr)
+	// Parse sort
+	if err := parseReportSort(query, req); err != nil {
+		respondWithError(w, err.Error(), errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+		return
+	}
+
+	// Parse cursor
+	if cursorStr := query.Get("cursor"); cursorStr != "" {
+		if req.Offset > 0 {
+			respondWithError(w, "Cursor cannot be combined with offset", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
+		cursor, err := request.DecodeReportCursor(cursorStr)
 		if err != nil {
-			respondWithError(w, "Invalid fromTime parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			respondWithError(w, "Invalid cursor parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
 			return
 		}
-		req.FromTime = fromTime
-		req.IsFiltered = true
+		req.Cursor = cursor
 	}
 
-	// Parse to time
-	if toTimeStr := query.Get("toTime"); toTimeStr != "" {
-		toTime, err := time.Parse(time.RFC3339, toTimeStr)
+	// Parse includeTotal
+	if includeTotalStr := query.Get("includeTotal"); includeTotalStr != "" {
+		includeTotal, err := strconv.ParseBool(includeTotalStr)
 		if err != nil {
-			respondWithError(w, "Invalid toTime parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			respondWithError(w, "Invalid includeTotal parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
 			return
 		}
-		req.ToTime = toTime
-		req.IsFiltered = true
+		req.SkipTotalCount = !includeTotal
+	}
+
+	// Parse filters
+	if err := parseReportFilters(query, req); err != nil {
+		respondWithError(w, err.Error(), errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+		return
 	}
 
 	// Get paginated reports
 	paginatedResponse, err := h.reportService.GetPaginatedReports(r.Context(), req)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid sort") || strings.HasPrefix(err.Error(), "invalid cursor") {
+			respondWithError(w, err.Error(), errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
 		errorCode := errors.ErrCodeServerError
 		if strings.Contains(err.Error(), "failed to retrieve reports") {
 			errorCode = errors.ErrCodeDatabaseQuery
@@ -174,6 +279,251 @@ func (h *ReportHandler) GetPaginatedReports(w http.ResponseWriter, r *http.Reque
 	json.NewEncoder(w).Encode(responseData)
 }
 
+// ExportReports handles requests to download the reports matching a set of filters
+// @Summary Export weather reports
+// @Description Download every report matching the same filters and sort as /reports/paginated, streamed as CSV, NDJSON, a JSON array, Apache Parquet or Apache Arrow IPC.
+// @Tags reports
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce json
+// @Produce application/vnd.apache.parquet
+// @Produce application/vnd.apache.arrow.file
+// @Param format query string false "Export format: csv (default), ndjson, json, parquet or arrow"
+// @Param includeDeleted query bool false "Set to true to include soft-deleted reports"
+// @Param fromTime query string false "Filter by start time (RFC3339 format)"
+// @Param toTime query string false "Filter by end time (RFC3339 format)"
+// @Param filter query []string false "Metric conditions such as temperature>=30 or humidity<80 (repeat or comma-separate)" collectionFormat(multi)
+// @Param logic query string false "How metric conditions are combined: and (default) or or"
+// @Param location query string false "Comma-separated locations (ICAO codes) to include"
+// @Param source query string false "Comma-separated data providers to include"
+// @Param type query string false "Comma-separated report types to include (current, historical)"
+// @Param tag query string false "Comma-separated tags; reports with any of them are included"
+// @Param sort query string false "Comma-separated sort keys, - prefix for descending, e.g. -temperature,timestamp"
+// @Param sortBy query string false "Field to sort by (legacy, use sort)"
+// @Param sortOrder query string false "Sort order, asc or desc (legacy, use sort)"
+// @Success 200 {file} file "Exported reports"
+// @Failure 400 {object} response.BaseResponse "Invalid parameters"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /reports/export [get]
+func (h *ReportHandler) ExportReports(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	format, err := request.ParseExportFormat(query.Get("format"))
+	if err != nil {
+		respondWithError(w, "Invalid format parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+		return
+	}
+
+	req := &request.PaginatedReportsRequest{}
+	if err := parseReportSort(query, req); err != nil {
+		respondWithError(w, err.Error(), errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+		return
+	}
+	if err := parseReportFilters(query, req); err != nil {
+		respondWithError(w, err.Error(), errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+		return
+	}
+
+	// Large exports take longer than the server's write timeout
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"reports.%s\"", format))
+
+	out := &exportWriter{w: w}
+	if err := h.reportService.ExportReports(r.Context(), req, format, out); err != nil {
+		if out.written {
+			// The status has been sent already, so the download just ends early
+			log.Printf("Report export aborted: %v", err)
+			return
+		}
+
+		w.Header().Del("Content-Disposition")
+		if strings.HasPrefix(err.Error(), "invalid sort") {
+			respondWithError(w, err.Error(), errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
+		errorCode := errors.ErrCodeServerError
+		if strings.Contains(err.Error(), "failed to retrieve reports") {
+			errorCode = errors.ErrCodeDatabaseQuery
+		}
+		respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+	}
+}
+
+// maxImportSize is the largest file accepted by ImportReports, in bytes
+const maxImportSize = 64 << 20
+
+// ImportReports handles requests to import historical reports from a file
+// @Summary Import weather reports
+// @Description Import historical reports from a CSV file with a header row or an NDJSON file sent as the request body. Files from /reports/export can be imported as is; their id, createdAt and deletedAt are ignored.
+// @Description Every row is validated against the report fields and the physical bounds of each metric, and rows whose timestamp already has a report are skipped as duplicates. Valid rows are inserted in batches.
+// @Description Invalid rows are listed by line number; the status is 201 if no row was invalid and 207 otherwise.
+// @Tags reports
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Produce json
+// @Param format query string false "File format: csv or ndjson (default from the Content-Type, else csv)"
+// @Param dryRun query bool false "Set to true to validate the file without inserting reports"
+// @Param file body string true "CSV or NDJSON file"
+// @Success 201 {object} response.BaseResponse{data=response.ImportResult} "Reports imported successfully"
+// @Success 207 {object} response.BaseResponse{data=response.ImportResult} "Some rows are invalid"
+// @Failure 400 {object} response.BaseResponse "Invalid parameters or file"
+// @Failure 403 {object} response.BaseResponse{data=response.ImportResult} "Missing permission, or report quota exceeded with the rows imported before the limit"
+// @Failure 413 {object} response.BaseResponse{data=response.ImportResult} "File too large, with the rows imported before the limit"
+// @Failure 500 {object} response.BaseResponse{data=response.ImportResult} "Server error, with the rows imported before the failure"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /reports/import [post]
+func (h *ReportHandler) ImportReports(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	formatStr := query.Get("format")
+	if formatStr == "" && strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-ndjson") {
+		formatStr = string(request.ImportFormatNDJSON)
+	}
+	format, err := request.ParseImportFormat(formatStr)
+	if err != nil {
+		respondWithError(w, "Invalid format parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+		return
+	}
+
+	dryRun := false
+	if dryRunStr := query.Get("dryRun"); dryRunStr != "" {
+		dryRun, err = strconv.ParseBool(dryRunStr)
+		if err != nil {
+			respondWithError(w, "Invalid dryRun parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Large files take longer to upload and insert than the server's timeouts
+	controller := http.NewResponseController(w)
+	controller.SetReadDeadline(time.Time{})
+	controller.SetWriteDeadline(time.Time{})
+
+	body := http.MaxBytesReader(w, r.Body, maxImportSize)
+	result, err := h.reportService.ImportReports(r.Context(), format, body, dryRun)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "request body too large"):
+			message := fmt.Sprintf("Import file is larger than %d MiB", maxImportSize>>20)
+			respondWithError(w, message, errors.ErrCodeInvalidRequest, result, http.StatusRequestEntityTooLarge)
+		case strings.HasPrefix(err.Error(), "invalid import"):
+			respondWithError(w, err.Error(), errors.ErrCodeInvalidRequest, nil, http.StatusBadRequest)
+		case strings.HasPrefix(err.Error(), "quota exceeded"):
+			respondWithError(w, err.Error(), errors.ErrCodeQuotaExceeded, result, http.StatusForbidden)
+		case strings.Contains(err.Error(), "failed to save reports"):
+			respondWithError(w, err.Error(), errors.ErrCodeDatabaseInsert, result, http.StatusInternalServerError)
+		case strings.Contains(err.Error(), "failed to retrieve reports"):
+			respondWithError(w, err.Error(), errors.ErrCodeDatabaseQuery, result, http.StatusInternalServerError)
+		default:
+			respondWithError(w, err.Error(), errors.ErrCodeServerError, result, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	statusCode := http.StatusCreated
+	message := fmt.Sprintf("%d reports imported", result.Imported)
+	if result.DryRun {
+		message = fmt.Sprintf("%d reports would be imported", result.Imported)
+	}
+	if result.Failed > 0 {
+		statusCode = http.StatusMultiStatus
+		message = fmt.Sprintf("%s, %d of %d rows are invalid", message, result.Failed, result.Rows)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	responseData := response.NewSuccessResponse(message, result)
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// ExportWeatherCaches handles requests to download raw weather cache entries
+// @Summary Export weather cache entries
+// @Description Download the raw weather data cached from the provider in a time range as Apache Parquet or Apache Arrow IPC, with units and provenance in the schema metadata.
+// @Tags cache
+// @Produce application/vnd.apache.parquet
+// @Produce application/vnd.apache.arrow.file
+// @Param format query string false "Export format: parquet (default) or arrow"
+// @Param fromTime query string false "Include entries from this time (RFC3339 format)"
+// @Param toTime query string false "Include entries until this time (RFC3339 format)"
+// @Success 200 {file} file "Exported cache entries"
+// @Failure 400 {object} response.BaseResponse "Invalid parameters"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /weather-cache/export [get]
+func (h *ReportHandler) ExportWeatherCaches(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	format := request.ExportFormatParquet
+	if formatStr := query.Get("format"); formatStr != "" {
+		parsed, err := request.ParseExportFormat(formatStr)
+		if err != nil || !parsed.IsColumnar() {
+			respondWithError(w, "Invalid format parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
+		format = parsed
+	}
+
+	var from, to time.Time
+	if fromTimeStr := query.Get("fromTime"); fromTimeStr != "" {
+		fromTime, err := time.Parse(time.RFC3339, fromTimeStr)
+		if err != nil {
+			respondWithError(w, "Invalid fromTime parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
+		from = fromTime
+	}
+	if toTimeStr := query.Get("toTime"); toTimeStr != "" {
+		toTime, err := time.Parse(time.RFC3339, toTimeStr)
+		if err != nil {
+			respondWithError(w, "Invalid toTime parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
+		to = toTime
+	}
+
+	// Large exports take longer than the server's write timeout
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"weather-cache.%s\"", format))
+
+	out := &exportWriter{w: w}
+	if err := h.reportService.ExportWeatherCaches(r.Context(), from, to, format, out); err != nil {
+		if out.written {
+			log.Printf("Weather cache export aborted: %v", err)
+			return
+		}
+
+		w.Header().Del("Content-Disposition")
+		if strings.HasPrefix(err.Error(), "invalid export") {
+			respondWithError(w, err.Error(), errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
+		errorCode := errors.ErrCodeServerError
+		if strings.Contains(err.Error(), "failed to retrieve weather caches") {
+			errorCode = errors.ErrCodeDatabaseQuery
+		}
+		respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+	}
+}
+
+// exportWriter records whether any part of an export has been written to the response
+type exportWriter struct {
+	w       http.ResponseWriter
+	written bool
+}
+
+func (e *exportWriter) Write(p []byte) (int, error) {
+	e.written = true
+	return e.w.Write(p)
+}
+
 // GetReportByID handles requests to retrieve a specific weather report
 // @Summary Get a weather report by ID
 // @Description Get a specific weather report by its ID
@@ -183,6 +533,8 @@ func (h *ReportHandler) GetPaginatedReports(w http.ResponseWriter, r *http.Reque
 // @Success 200 {object} response.BaseResponse{data=docs.WeatherReport} "Report retrieved successfully"
 // @Failure 404 {object} response.BaseResponse "Report not found"
 // @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /reports/{id} [get]
 func (h *ReportHandler) GetReportByID(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -207,17 +559,236 @@ func (h *ReportHandler) GetReportByID(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(responseData)
 }
 
+// RenderReport handles requests for a printable weather report
+// @Summary Render a printable weather report
+// @Description Render a report as a branded HTML page with print styles or as an A4 PDF document, with its metrics, tags and notes.
+// @Tags reports
+// @Produce html
+// @Produce application/pdf
+// @Param id path string true "Report ID"
+// @Param format query string false "Document format: html or pdf (default html)"
+// @Success 200 {file} file "Rendered report"
+// @Failure 400 {object} response.BaseResponse "Invalid parameters"
+// @Failure 404 {object} response.BaseResponse "Report not found"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /reports/{id}/render [get]
+func (h *ReportHandler) RenderReport(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	format, err := request.ParseRenderFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		respondWithError(w, "Invalid format parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.reportService.GetReportByID(r.Context(), id)
+	if err != nil {
+		if err.Error() == "report not found" {
+			respondWithError(w, "Report not found", errors.ErrCodeReportNotFound, nil, http.StatusNotFound)
+		} else {
+			errorCode := errors.ErrCodeServerError
+			if strings.Contains(err.Error(), "failed to retrieve report") {
+				errorCode = errors.ErrCodeDatabaseQuery
+			}
+			respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithDocument(w, format, "report-"+report.ID, func(out io.Writer) error {
+		return render.Report(out, format, report, time.Now())
+	})
+}
+
+// UpdateReport handles requests to update the tags and notes of a weather report
+// @Summary Update the tags and notes of a weather report
+// @Description Replace the tags and/or notes of a report. Omitted fields are left unchanged and empty values clear them.
+// @Description Tags are trimmed, lower-cased and deduplicated.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param id path string true "Report ID"
+// @Param request body request.ReportUpdateRequest true "Report update"
+// @Success 200 {object} response.BaseResponse{data=docs.WeatherReport} "Report updated successfully"
+// @Failure 400 {object} response.BaseResponse "Invalid request"
+// @Failure 403 {object} response.BaseResponse "Missing permission"
+// @Failure 404 {object} response.BaseResponse "Report not found"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /reports/{id} [patch]
+func (h *ReportHandler) UpdateReport(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	var req request.ReportUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, "Invalid request body", errors.ErrCodeInvalidRequest, nil, http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.reportService.UpdateReport(r.Context(), id, &req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid report update") {
+			respondWithError(w, err.Error(), errors.ErrCodeReportInvalid, nil, http.StatusBadRequest)
+		} else if err.Error() == "report not found" {
+			respondWithError(w, "Report not found", errors.ErrCodeReportNotFound, nil, http.StatusNotFound)
+		} else {
+			errorCode := errors.ErrCodeServerError
+			if strings.Contains(err.Error(), "failed to update report") {
+				errorCode = errors.ErrCodeDatabaseUpdate
+			} else if strings.Contains(err.Error(), "failed to retrieve report") {
+				errorCode = errors.ErrCodeDatabaseQuery
+			}
+			respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	responseData := response.NewSuccessResponse("Report updated successfully", report)
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// GetTags handles requests to list the tags of all reports
+// @Summary List report tags
+// @Description List every tag in use with the number of reports that have it, most used first. Deleted reports are not counted.
+// @Tags reports
+// @Produce json
+// @Success 200 {object} response.BaseResponse{data=[]response.TagCount} "Tags retrieved successfully"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /tags [get]
+func (h *ReportHandler) GetTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.reportService.GetTags(r.Context())
+	if err != nil {
+		errorCode := errors.ErrCodeServerError
+		if strings.Contains(err.Error(), "failed to count tags") {
+			errorCode = errors.ErrCodeDatabaseQuery
+		}
+		respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	responseData := response.NewSuccessResponse("Tags retrieved successfully", tags)
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// DeleteReport handles requests to delete a weather report
+// @Summary Delete a weather report
+// @Description Soft-delete a weather report. It is hidden from every read and can be restored until the retention window has passed.
+// @Tags reports
+// @Produce json
+// @Param id path string true "Report ID"
+// @Success 200 {object} response.BaseResponse "Report deleted successfully"
+// @Failure 403 {object} response.BaseResponse "Missing permission"
+// @Failure 404 {object} response.BaseResponse "Report not found"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /reports/{id} [delete]
+func (h *ReportHandler) DeleteReport(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	if err := h.reportService.DeleteReport(r.Context(), id); err != nil {
+		if err.Error() == "report not found" {
+			respondWithError(w, "Report not found", errors.ErrCodeReportNotFound, nil, http.StatusNotFound)
+		} else {
+			errorCode := errors.ErrCodeServerError
+			if strings.Contains(err.Error(), "failed to delete report") {
+				errorCode = errors.ErrCodeDatabaseDelete
+			}
+			respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	responseData := response.NewSuccessResponse("Report deleted successfully", nil)
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// RestoreReport handles requests to restore a deleted weather report
+// @Summary Restore a deleted weather report
+// @Description Restore a soft-deleted weather report that has not been purged yet
+// @Tags reports
+// @Produce json
+// @Param id path string true "Report ID"
+// @Success 200 {object} response.BaseResponse{data=docs.WeatherReport} "Report restored successfully"
+// @Failure 403 {object} response.BaseResponse "Missing permission or report quota exceeded"
+// @Failure 404 {object} response.BaseResponse "Deleted report not found"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /reports/{id}/restore [post]
+func (h *ReportHandler) RestoreReport(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	report, err := h.reportService.RestoreReport(r.Context(), id)
+	if err != nil {
+		if err.Error() == "deleted report not found" || err.Error() == "report not found" {
+			respondWithError(w, "Deleted report not found", errors.ErrCodeReportNotFound, nil, http.StatusNotFound)
+		} else if strings.HasPrefix(err.Error(), "quota exceeded") {
+			respondWithError(w, err.Error(), errors.ErrCodeQuotaExceeded, nil, http.StatusForbidden)
+		} else {
+			errorCode := errors.ErrCodeServerError
+			if strings.Contains(err.Error(), "failed to restore report") {
+				errorCode = errors.ErrCodeDatabaseUpdate
+			} else if strings.Contains(err.Error(), "failed to retrieve report") {
+				errorCode = errors.ErrCodeDatabaseQuery
+			}
+			respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	responseData := response.NewSuccessResponse("Report restored successfully", report)
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// GetTenantUsage handles requests for the report usage of the caller's tenant
+// @Summary Get the report usage of the caller's tenant
+// @Description Get how many reports the caller's tenant stores and how many it may store. Soft-deleted reports do not count.
+// @Tags tenants
+// @Produce json
+// @Success 200 {object} response.BaseResponse{data=response.TenantUsage} "Usage retrieved successfully"
+// @Failure 403 {object} response.BaseResponse "Missing permission"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /tenant/usage [get]
+func (h *ReportHandler) GetTenantUsage(w http.ResponseWriter, r *http.Request) {
+	usage, err := h.reportService.GetTenantUsage(r.Context())
+	if err != nil {
+		respondWithError(w, err.Error(), errors.ErrCodeDatabaseQuery, nil, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	responseData := response.NewSuccessResponse("Usage retrieved successfully", usage)
+	json.NewEncoder(w).Encode(responseData)
+}
+
 // CompareReports handles requests to compare two weather reports
 // @Summary Compare two weather reports
-// @Description Compare two weather reports and calculate the differences
+// @Description Compare two weather reports and calculate the differences.
+// @Description Set save with a title to keep the comparison; it can then be retrieved at /comparisons/{id}.
 // @Tags reports
 // @Accept json
 // @Produce json
 // @Param request body request.ComparisonRequest true "Comparison request"
 // @Success 200 {object} response.BaseResponse{data=response.ComparisonResult} "Reports compared successfully"
+// @Success 201 {object} response.BaseResponse{data=response.ComparisonResult} "Reports compared and comparison saved"
 // @Failure 400 {object} response.BaseResponse "Invalid request"
+// @Failure 403 {object} response.BaseResponse "Missing permission"
 // @Failure 404 {object} response.BaseResponse "Report not found"
 // @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /reports/compare [post]
 func (h *ReportHandler) CompareReports(w http.ResponseWriter, r *http.Request) {
 	var req request.ComparisonRequest
@@ -247,11 +818,281 @@ func (h *ReportHandler) CompareReports(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Save {
+		saved, err := h.comparisonService.SaveComparison(r.Context(), req.Title, req.Notes, result)
+		if err != nil {
+			if strings.HasPrefix(err.Error(), "invalid comparison") {
+				respondWithError(w, err.Error(), errors.ErrCodeComparisonInvalid, nil, http.StatusBadRequest)
+				return
+			}
+			errorCode := errors.ErrCodeServerError
+			if strings.Contains(err.Error(), "failed to save comparison") {
+				errorCode = errors.ErrCodeDatabaseInsert
+			}
+			respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+			return
+		}
+		result.ID = saved.ID
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		responseData := response.NewSuccessResponse("Comparison saved successfully", result)
+		json.NewEncoder(w).Encode(responseData)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	responseData := response.NewSuccessResponse("Reports compared successfully", result)
 	json.NewEncoder(w).Encode(responseData)
 }
 
+// RenderReportComparison handles requests for a printable comparison of two weather reports
+// @Summary Render a printable comparison of two weather reports
+// @Description Compare two reports and render the result as a branded HTML page with print styles or as an A4 PDF document,
+// @Description with the metric table and deviation column of the comparison page. Saved comparisons are rendered at /comparisons/{id}/render.
+// @Tags reports
+// @Produce html
+// @Produce application/pdf
+// @Param reportId1 query string true "ID of the first report"
+// @Param reportId2 query string true "ID of the second report"
+// @Param format query string false "Document format: html or pdf (default html)"
+// @Success 200 {file} file "Rendered comparison"
+// @Failure 400 {object} response.BaseResponse "Invalid parameters"
+// @Failure 404 {object} response.BaseResponse "Report not found"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /reports/compare/render [get]
+func (h *ReportHandler) RenderReportComparison(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	format, err := request.ParseRenderFormat(query.Get("format"))
+	if err != nil {
+		respondWithError(w, "Invalid format parameter", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+		return
+	}
+
+	req := request.ComparisonRequest{ReportID1: query.Get("reportId1"), ReportID2: query.Get("reportId2")}
+	if req.ReportID1 == "" || req.ReportID2 == "" {
+		respondWithError(w, "reportId1 and reportId2 are required", errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.reportService.CompareReports(r.Context(), &req)
+	if err != nil {
+		errorCode := errors.ErrCodeServerError
+		statusCode := http.StatusInternalServerError
+
+		if strings.Contains(err.Error(), "failed to retrieve first report") ||
+			strings.Contains(err.Error(), "failed to retrieve second report") {
+			if strings.Contains(err.Error(), "report not found") {
+				errorCode = errors.ErrCodeReportNotFound
+				statusCode = http.StatusNotFound
+			} else {
+				errorCode = errors.ErrCodeDatabaseQuery
+			}
+		} else if strings.Contains(err.Error(), "failed to compare reports") {
+			errorCode = errors.ErrCodeReportComparison
+		}
+
+		respondWithError(w, err.Error(), errorCode, nil, statusCode)
+		return
+	}
+
+	comparison := &response.SavedComparison{Report1: result.Report1, Report2: result.Report2, Deviation: result.Deviation}
+	respondWithDocument(w, format, fmt.Sprintf("comparison-%s-%s", req.ReportID1, req.ReportID2), func(out io.Writer) error {
+		return render.Comparison(out, format, comparison, time.Now())
+	})
+}
+
+// CompareMultipleReports handles requests to compare several weather reports against a baseline
+// @Summary Compare several weather reports
+// @Description Compare two or more weather reports against a baseline report (the first one unless baselineId is set).
+// @Description Returns each report's deviation from the baseline and the spread of every metric across all reports.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param request body request.MultiComparisonRequest true "Multi-report comparison request"
+// @Success 200 {object} response.BaseResponse{data=response.MultiComparisonResult} "Reports compared successfully"
+// @Failure 400 {object} response.BaseResponse "Invalid request"
+// @Failure 403 {object} response.BaseResponse "Missing permission"
+// @Failure 404 {object} response.BaseResponse "Report not found"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /comparisons [post]
+func (h *ReportHandler) CompareMultipleReports(w http.ResponseWriter, r *http.Request) {
+	var req request.MultiComparisonRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, "Invalid request body", errors.ErrCodeInvalidRequest, nil, http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.reportService.CompareMultipleReports(r.Context(), &req)
+	if err != nil {
+		errorCode := errors.ErrCodeServerError
+		statusCode := http.StatusInternalServerError
+
+		if strings.HasPrefix(err.Error(), "invalid comparison request") {
+			errorCode = errors.ErrCodeInvalidParameters
+			statusCode = http.StatusBadRequest
+		} else if strings.HasPrefix(err.Error(), "report not found") {
+			errorCode = errors.ErrCodeReportNotFound
+			statusCode = http.StatusNotFound
+		} else if strings.Contains(err.Error(), "failed to retrieve reports") {
+			errorCode = errors.ErrCodeDatabaseQuery
+		}
+
+		respondWithError(w, err.Error(), errorCode, nil, statusCode)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	responseData := response.NewSuccessResponse("Reports compared successfully", result)
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// ComparePeriods handles requests to compare the reports of two time periods
+// @Summary Compare two time periods
+// @Description Aggregate the reports in two time ranges (e.g. this week vs the same week last year) and compare
+// @Description the mean, min and max of every metric. Significance hints are based on Welch's t-test.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param request body request.PeriodComparisonRequest true "Period comparison request"
+// @Success 200 {object} response.BaseResponse{data=response.PeriodComparisonResult} "Periods compared successfully"
+// @Failure 400 {object} response.BaseResponse "Invalid request"
+// @Failure 403 {object} response.BaseResponse "Missing permission"
+// @Failure 404 {object} response.BaseResponse "No reports in a period"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /comparisons/periods [post]
+func (h *ReportHandler) ComparePeriods(w http.ResponseWriter, r *http.Request) {
+	var req request.PeriodComparisonRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, "Invalid request body", errors.ErrCodeInvalidRequest, nil, http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.reportService.ComparePeriods(r.Context(), &req)
+	if err != nil {
+		errorCode := errors.ErrCodeServerError
+		statusCode := http.StatusInternalServerError
+
+		if strings.HasPrefix(err.Error(), "invalid period comparison") {
+			errorCode = errors.ErrCodeInvalidParameters
+			statusCode = http.StatusBadRequest
+		} else if strings.HasPrefix(err.Error(), "no reports found") {
+			errorCode = errors.ErrCodeReportNotFound
+			statusCode = http.StatusNotFound
+		} else if strings.Contains(err.Error(), "failed to aggregate reports") {
+			errorCode = errors.ErrCodeDatabaseQuery
+		}
+
+		respondWithError(w, err.Error(), errorCode, nil, statusCode)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	responseData := response.NewSuccessResponse("Periods compared successfully", result)
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// parseReportSort parses either the multi-key sort spec or the legacy sortBy/sortOrder pair into req
+func parseReportSort(query url.Values, req *request.PaginatedReportsRequest) error {
+	req.SortBy = query.Get("sortBy")
+	req.SortOrder = request.SortOrder(query.Get("sortOrder"))
+
+	if sortStr := query.Get("sort"); sortStr != "" {
+		if req.SortBy != "" || req.SortOrder != "" {
+			return fmt.Errorf("sort cannot be combined with sortBy or sortOrder")
+		}
+		keys, err := request.ParseSortSpec(sortStr)
+		if err != nil {
+			return fmt.Errorf("Invalid sort parameter: %w", err)
+		}
+		req.Sort = keys
+	} else if _, err := request.LegacySortKeys(req.SortBy, req.SortOrder); err != nil {
+		return fmt.Errorf("Invalid sort parameters: %w", err)
+	}
+	return nil
+}
+
+// parseReportFilters parses the report filter parameters shared by listing and exporting into req
+func parseReportFilters(query url.Values, req *request.PaginatedReportsRequest) error {
+	// Parse includeDeleted
+	if includeDeletedStr := query.Get("includeDeleted"); includeDeletedStr != "" {
+		includeDeleted, err := strconv.ParseBool(includeDeletedStr)
+		if err != nil {
+			return fmt.Errorf("Invalid includeDeleted parameter")
+		}
+		req.IncludeDeleted = includeDeleted
+	}
+
+	// Parse from time
+	if fromTimeStr := query.Get("fromTime"); fromTimeStr != "" {
+		fromTime, err := time.Parse(time.RFC3339, fromTimeStr)
+		if err != nil {
+			return fmt.Errorf("Invalid fromTime parameter")
+		}
+		req.FromTime = fromTime
+		req.IsFiltered = true
+	}
+
+	// Parse to time
+	if toTimeStr := query.Get("toTime"); toTimeStr != "" {
+		toTime, err := time.Parse(time.RFC3339, toTimeStr)
+		if err != nil {
+			return fmt.Errorf("Invalid toTime parameter")
+		}
+		req.ToTime = toTime
+		req.IsFiltered = true
+	}
+
+	// Parse metric conditions
+	for _, expr := range request.ParseListValues(query["filter"]) {
+		condition, err := request.ParseFilterCondition(expr)
+		if err != nil {
+			return fmt.Errorf("Invalid filter parameter: %w", err)
+		}
+		req.Conditions = append(req.Conditions, *condition)
+		req.IsFiltered = true
+	}
+	if len(req.Conditions) > request.MaxFilterConditions {
+		return fmt.Errorf("Too many filter conditions (max %d)", request.MaxFilterConditions)
+	}
+
+	// Parse logic
+	logic, err := request.ParseLogicalOperator(query.Get("logic"))
+	if err != nil {
+		return fmt.Errorf("Invalid logic parameter")
+	}
+	req.Logic = logic
+
+	// Parse location, source, type and tag filters
+	req.Locations = request.ParseListValues(query["location"])
+	req.Sources = request.ParseListValues(query["source"])
+	req.Types = request.ParseListValues(query["type"])
+	for _, tag := range request.ParseListValues(query["tag"]) {
+		req.Tags = append(req.Tags, models.NormalizeTag(tag))
+	}
+	for _, source := range req.Sources {
+		if !slices.Contains(models.ReportSources, source) {
+			return fmt.Errorf("Invalid source parameter: %s", source)
+		}
+	}
+	for _, reportType := range req.Types {
+		if !slices.Contains(models.ReportTypes, reportType) {
+			return fmt.Errorf("Invalid type parameter: %s", reportType)
+		}
+	}
+	if len(req.Locations) > 0 || len(req.Sources) > 0 || len(req.Types) > 0 || len(req.Tags) > 0 {
+		req.IsFiltered = true
+	}
+	return nil
+}
+
 // respondWithError is a helper function to send standardized error responses
 func respondWithError(w http.ResponseWriter, message string, errorCode string, data interface{}, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
@@ -259,3 +1100,20 @@ func respondWithError(w http.ResponseWriter, message string, errorCode string, d
 	response := response.NewErrorResponse(message, errorCode, data)
 	json.NewEncoder(w).Encode(response)
 }
+
+// respondWithDocument sends a rendered report. The document is rendered in full first, so a
+// failure can still be reported as a JSON error. PDFs are named after filename for downloads.
+func respondWithDocument(w http.ResponseWriter, format request.RenderFormat, filename string, write func(io.Writer) error) {
+	var buf bytes.Buffer
+	if err := write(&buf); err != nil {
+		respondWithError(w, fmt.Sprintf("failed to render document: %v", err), errors.ErrCodeServerError, nil, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	if format == request.RenderFormatPDF {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"%s.pdf\"", filename))
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	buf.WriteTo(w)
+}
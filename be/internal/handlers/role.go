@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/DangVTNhan/Scanner/be/internal/interfaces"
+	"github.com/DangVTNhan/Scanner/be/internal/middleware"
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/errors"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// RoleHandler handles HTTP requests related to roles and their assignment
+type RoleHandler struct {
+	roleService interfaces.IRoleService
+}
+
+// NewRoleHandler creates a new instance of RoleHandler
+func NewRoleHandler(roleService interfaces.IRoleService) *RoleHandler {
+	return &RoleHandler{
+		roleService: roleService,
+	}
+}
+
+// GetCurrentIdentity handles requests for the caller's own identity
+// @Summary Get the current caller
+// @Description Get the identity of the caller, its role and the permissions of the role, e.g. to hide actions a user may not take.
+// @Tags roles
+// @Produce json
+// @Success 200 {object} response.BaseResponse{data=response.CurrentIdentity} "Identity retrieved successfully"
+// @Failure 401 {object} response.BaseResponse "Missing or invalid credentials"
+// @Failure 404 {object} response.BaseResponse "Authentication is disabled"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /me [get]
+func (h *RoleHandler) GetCurrentIdentity(w http.ResponseWriter, r *http.Request) {
+	identity := middleware.IdentityFromContext(r.Context())
+	if identity == nil {
+		respondWithError(w, "No authenticated caller: authentication is disabled", errors.ErrCodeNotFound, nil, http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	responseData := response.NewSuccessResponse("Identity retrieved successfully", response.CurrentIdentity{
+		Identity:    *identity,
+		Permissions: identity.Role.Permissions(),
+	})
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// GetRoles handles requests to list the roles
+// @Summary List roles
+// @Description List the roles from least to most privileged with their permissions. Every role has the permissions of the roles below it.
+// @Tags roles
+// @Produce json
+// @Success 200 {object} response.BaseResponse{data=response.RolesResponse} "Roles retrieved successfully"
+// @Failure 401 {object} response.BaseResponse "Missing or invalid credentials"
+// @Failure 403 {object} response.BaseResponse "Missing permission"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /admin/roles [get]
+func (h *RoleHandler) GetRoles(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	responseData := response.NewSuccessResponse("Roles retrieved successfully", h.roleService.GetRoles())
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// ListRoleAssignments handles requests to list role assignments
+// @Summary List role assignments
+// @Description List the callers that have been assigned a role, ordered by method and subject. Other callers have the default role.
+// @Tags roles
+// @Produce json
+// @Success 200 {object} response.BaseResponse{data=[]models.RoleAssignment} "Role assignments retrieved successfully"
+// @Failure 401 {object} response.BaseResponse "Missing or invalid credentials"
+// @Failure 403 {object} response.BaseResponse "Missing permission"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /admin/role-assignments [get]
+func (h *RoleHandler) ListRoleAssignments(w http.ResponseWriter, r *http.Request) {
+	assignments, err := h.roleService.ListRoleAssignments(r.Context())
+	if err != nil {
+		errorCode := errors.ErrCodeServerError
+		if strings.Contains(err.Error(), "failed to retrieve role assignments") {
+			errorCode = errors.ErrCodeDatabaseQuery
+		}
+		respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+		return
+	}
+	if assignments == nil {
+		assignments = []models.RoleAssignment{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	responseData := response.NewSuccessResponse("Role assignments retrieved successfully", assignments)
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// AssignRole handles requests to assign a role to a caller
+// @Summary Assign a role
+// @Description Give a caller a role, replacing its current one. The caller is an API key (method api_key, subject the key ID)
+// @Description or a bearer token user (method token, subject the "sub" claim). Admins cannot change their own role.
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param method path string true "Authentication method: api_key or token"
+// @Param subject path string true "API key ID or token subject"
+// @Param request body request.RoleAssignmentRequest true "Role assignment request"
+// @Success 200 {object} response.BaseResponse{data=models.RoleAssignment} "Role assigned successfully"
+// @Failure 400 {object} response.BaseResponse "Invalid request"
+// @Failure 401 {object} response.BaseResponse "Missing or invalid credentials"
+// @Failure 403 {object} response.BaseResponse "Missing permission"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /admin/role-assignments/{method}/{subject} [put]
+func (h *RoleHandler) AssignRole(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var req request.RoleAssignmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, "Invalid request body", errors.ErrCodeInvalidRequest, nil, http.StatusBadRequest)
+		return
+	}
+
+	assignment, err := h.roleService.AssignRole(r.Context(), middleware.IdentityFromContext(r.Context()),
+		models.AuthMethod(vars["method"]), vars["subject"], &req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid role assignment") {
+			respondWithError(w, err.Error(), errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+		} else {
+			errorCode := errors.ErrCodeServerError
+			if strings.Contains(err.Error(), "failed to save role assignment") {
+				errorCode = errors.ErrCodeDatabaseUpdate
+			}
+			respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	responseData := response.NewSuccessResponse("Role assigned successfully", assignment)
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// RemoveRoleAssignment handles requests to remove the role assignment of a caller
+// @Summary Remove a role assignment
+// @Description Remove the role assignment of a caller, who then has the default role. Admins cannot change their own role.
+// @Tags roles
+// @Produce json
+// @Param method path string true "Authentication method: api_key or token"
+// @Param subject path string true "API key ID or token subject"
+// @Success 200 {object} response.BaseResponse "Role assignment removed successfully"
+// @Failure 400 {object} response.BaseResponse "Invalid request"
+// @Failure 401 {object} response.BaseResponse "Missing or invalid credentials"
+// @Failure 403 {object} response.BaseResponse "Missing permission"
+// @Failure 404 {object} response.BaseResponse "Role assignment not found"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /admin/role-assignments/{method}/{subject} [delete]
+func (h *RoleHandler) RemoveRoleAssignment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	err := h.roleService.RemoveRoleAssignment(r.Context(), middleware.IdentityFromContext(r.Context()),
+		models.AuthMethod(vars["method"]), vars["subject"])
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid role assignment") {
+			respondWithError(w, err.Error(), errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+		} else if err.Error() == "role assignment not found" {
+			respondWithError(w, "Role assignment not found", errors.ErrCodeNotFound, nil, http.StatusNotFound)
+		} else {
+			errorCode := errors.ErrCodeServerError
+			if strings.Contains(err.Error(), "failed to delete role assignment") {
+				errorCode = errors.ErrCodeDatabaseDelete
+			}
+			respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	responseData := response.NewSuccessResponse("Role assignment removed successfully", nil)
+	json.NewEncoder(w).Encode(responseData)
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/DangVTNhan/Scanner/be/internal/interfaces"
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/errors"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// ThresholdHandler handles HTTP requests related to deviation thresholds
+type ThresholdHandler struct {
+	thresholdService interfaces.IThresholdService
+}
+
+// NewThresholdHandler creates a new instance of ThresholdHandler
+func NewThresholdHandler(thresholdService interfaces.IThresholdService) *ThresholdHandler {
+	return &ThresholdHandler{
+		thresholdService: thresholdService,
+	}
+}
+
+// GetThresholds handles requests to retrieve the deviation thresholds
+// @Summary Get deviation thresholds
+// @Description Get the threshold of every metric from which a deviation is notable or major. Metrics without a configured threshold use the defaults.
+// @Tags thresholds
+// @Produce json
+// @Success 200 {object} response.BaseResponse{data=[]models.Threshold} "Thresholds retrieved successfully"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /thresholds [get]
+func (h *ThresholdHandler) GetThresholds(w http.ResponseWriter, r *http.Request) {
+	thresholds, err := h.thresholdService.GetThresholds(r.Context())
+	if err != nil {
+		errorCode := errors.ErrCodeServerError
+		if strings.Contains(err.Error(), "failed to retrieve thresholds") {
+			errorCode = errors.ErrCodeDatabaseQuery
+		}
+		respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	responseData := response.NewSuccessResponse("Thresholds retrieved successfully", thresholds)
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// SetThreshold handles requests to configure the deviation threshold of a metric
+// @Summary Set the deviation threshold of a metric
+// @Description Configure from which absolute deviation a change of the metric is notable or major
+// @Tags thresholds
+// @Accept json
+// @Produce json
+// @Param metric path string true "Metric (temperature, pressure, humidity, cloudCover)"
+// @Param request body request.ThresholdRequest true "Threshold request"
+// @Success 200 {object} response.BaseResponse{data=models.Threshold} "Threshold saved successfully"
+// @Failure 400 {object} response.BaseResponse "Invalid request"
+// @Failure 403 {object} response.BaseResponse "Missing permission"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /thresholds/{metric} [put]
+func (h *ThresholdHandler) SetThreshold(w http.ResponseWriter, r *http.Request) {
+	metric := models.Metric(mux.Vars(r)["metric"])
+
+	var req request.ThresholdRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, "Invalid request body", errors.ErrCodeInvalidRequest, nil, http.StatusBadRequest)
+		return
+	}
+
+	threshold, err := h.thresholdService.SetThreshold(r.Context(), metric, &req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid threshold") {
+			respondWithError(w, err.Error(), errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
+		errorCode := errors.ErrCodeServerError
+		if strings.Contains(err.Error(), "failed to save threshold") {
+			errorCode = errors.ErrCodeDatabaseUpdate
+		}
+		respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	responseData := response.NewSuccessResponse("Threshold saved successfully", threshold)
+	json.NewEncoder(w).Encode(responseData)
+}
+
+// ResetThreshold handles requests to reset the deviation threshold of a metric to its default
+// @Summary Reset the deviation threshold of a metric
+// @Description Remove the configured threshold of the metric so the default applies again
+// @Tags thresholds
+// @Produce json
+// @Param metric path string true "Metric (temperature, pressure, humidity, cloudCover)"
+// @Success 200 {object} response.BaseResponse{data=models.Threshold} "Threshold reset successfully"
+// @Failure 400 {object} response.BaseResponse "Invalid metric"
+// @Failure 403 {object} response.BaseResponse "Missing permission"
+// @Failure 500 {object} response.BaseResponse "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /thresholds/{metric} [delete]
+func (h *ThresholdHandler) ResetThreshold(w http.ResponseWriter, r *http.Request) {
+	metric := models.Metric(mux.Vars(r)["metric"])
+
+	threshold, err := h.thresholdService.ResetThreshold(r.Context(), metric)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid threshold") {
+			respondWithError(w, err.Error(), errors.ErrCodeInvalidParameters, nil, http.StatusBadRequest)
+			return
+		}
+		errorCode := errors.ErrCodeServerError
+		if strings.Contains(err.Error(), "failed to delete threshold") {
+			errorCode = errors.ErrCodeDatabaseDelete
+		}
+		respondWithError(w, err.Error(), errorCode, nil, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	responseData := response.NewSuccessResponse("Threshold reset successfully", threshold)
+	json.NewEncoder(w).Encode(responseData)
+}
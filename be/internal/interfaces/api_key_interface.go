@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+)
+
+type IAPIKeyService interface {
+	CreateAPIKey(ctx context.Context, req *request.APIKeyRequest) (*response.CreatedAPIKey, error)
+	ListAPIKeys(ctx context.Context) ([]models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string) (*models.APIKey, error)
+	Authenticate(ctx context.Context, key string) (*models.APIKey, error)
+}
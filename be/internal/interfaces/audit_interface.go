@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+	"io"
+)
+
+type IAuditService interface {
+	RecordEntry(ctx context.Context, entry *models.AuditEntry) error
+	ListAuditEntries(ctx context.Context, req *request.AuditLogRequest) (*response.AuditLogPage, error)
+	ExportAuditEntries(ctx context.Context, req *request.AuditLogRequest, format request.ExportFormat, w io.Writer) error
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+)
+
+type IComparisonService interface {
+	SaveComparison(ctx context.Context, title, notes string, result *response.ComparisonResult) (*response.SavedComparison, error)
+	GetComparison(ctx context.Context, id string) (*response.SavedComparison, error)
+	ListComparisons(ctx context.Context, limit, offset int) (*response.SavedComparisonsResponse, error)
+	DeleteComparison(ctx context.Context, id string) error
+}
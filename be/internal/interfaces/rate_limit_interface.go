@@ -0,0 +1,10 @@
+package interfaces
+
+import (
+	"context"
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+)
+
+type IRateLimitService interface {
+	Take(ctx context.Context, client, route string) (*models.RateLimitResult, error)
+}
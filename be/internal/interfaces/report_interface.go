@@ -5,12 +5,28 @@ import (
 	"github.com/DangVTNhan/Scanner/be/internal/models"
 	"github.com/DangVTNhan/Scanner/be/internal/models/request"
 	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+	"io"
+	"time"
 )
 
 type IReportService interface {
 	GenerateReport(ctx context.Context, req *request.ReportRequest) (*models.WeatherReport, error)
+	GenerateReports(ctx context.Context, req *request.BatchReportRequest) (*response.BatchReportResult, error)
 	GetAllReports(ctx context.Context) ([]models.WeatherReport, error)
 	GetPaginatedReports(ctx context.Context, req *request.PaginatedReportsRequest) (*response.PaginatedReportsResponse, error)
+	ExportReports(ctx context.Context, req *request.PaginatedReportsRequest, format request.ExportFormat, w io.Writer) error
+	ImportReports(ctx context.Context, format request.ImportFormat, r io.Reader, dryRun bool) (*response.ImportResult, error)
+	ExportWeatherCaches(ctx context.Context, from, to time.Time, format request.ExportFormat, w io.Writer) error
 	GetReportByID(ctx context.Context, id string) (*models.WeatherReport, error)
+	UpdateReport(ctx context.Context, id string, req *request.ReportUpdateRequest) (*models.WeatherReport, error)
+	GetTags(ctx context.Context) ([]response.TagCount, error)
+	DeleteReport(ctx context.Context, id string) error
+	RestoreReport(ctx context.Context, id string) (*models.WeatherReport, error)
+	GetTenantUsage(ctx context.Context) (*response.TenantUsage, error)
 	CompareReports(ctx context.Context, req *request.ComparisonRequest) (*response.ComparisonResult, error)
+	ComparePeriods(ctx context.Context, req *request.PeriodComparisonRequest) (*response.PeriodComparisonResult, error)
+	CompareMultipleReports(ctx context.Context, req *request.MultiComparisonRequest) (*response.MultiComparisonResult, error)
+	GetMetricSeries(ctx context.Context, req *request.MetricChartRequest) (*response.MetricSeries, error)
+	GetLatestReports(ctx context.Context, limit int) ([]models.WeatherReport, error)
+	GetAlertEvents(ctx context.Context, req *request.AlertFeedRequest) ([]response.AlertEvent, error)
 }
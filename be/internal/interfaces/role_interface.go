@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+)
+
+type IRoleService interface {
+	GetRoles() *response.RolesResponse
+	ResolveRole(ctx context.Context, identity *models.Identity) (models.Role, error)
+	ListRoleAssignments(ctx context.Context) ([]models.RoleAssignment, error)
+	AssignRole(ctx context.Context, actor *models.Identity, method models.AuthMethod, subject string, req *request.RoleAssignmentRequest) (*models.RoleAssignment, error)
+	RemoveRoleAssignment(ctx context.Context, actor *models.Identity, method models.AuthMethod, subject string) error
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+)
+
+type IThresholdService interface {
+	GetThresholds(ctx context.Context) ([]models.Threshold, error)
+	GetEffectiveThresholds(ctx context.Context) (map[models.Metric]models.Threshold, error)
+	SetThreshold(ctx context.Context, metric models.Metric, req *request.ThresholdRequest) (*models.Threshold, error)
+	ResetThreshold(ctx context.Context, metric models.Metric) (*models.Threshold, error)
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import (
+	"context"
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+)
+
+type ITokenService interface {
+	Authenticate(ctx context.Context, token string) (*models.Identity, error)
+}
@@ -0,0 +1,200 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/interfaces"
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/gorilla/mux"
+)
+
+const (
+	maxAuditBodySize     = 4 << 10  // How much of a JSON request body an audit entry keeps
+	maxAuditResponseSize = 64 << 10 // How much of a response is read for its error or the ID of the changed item
+)
+
+// AuditMiddleware creates a middleware that records every POST, PUT, PATCH and DELETE request to /api routes
+// in the audit log once it has been handled: the caller and its role, the route with its parameters and the start
+// of a JSON body, the status with the error code and message of failed requests, and the latency. It runs after
+// AuthMiddleware, so requests without valid credentials are not recorded, but those rejected for a missing
+// permission, the report quota or the rate limit are. The ID of the changed item is taken from the "id" of the
+// response data, or else from the {id} path parameter. With trustProxy the client address is the last one
+// in X-Forwarded-For.
+//
+// Entries are written after the response, and a failure to write one is logged rather than failing the request.
+func AuditMiddleware(auditService interfaces.IAuditService, trustProxy bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isAuditedMethod(r.Method) || !strings.HasPrefix(r.URL.Path, "/api/") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			body := &auditRequestBody{ReadCloser: r.Body, capture: isJSONRequest(r)}
+			r.Body = body
+			recorder := &auditResponseWriter{ResponseWriter: w}
+
+			next.ServeHTTP(recorder, r)
+
+			entry := newAuditEntry(r, trustProxy, start)
+			entry.Body = body.captured.String()
+			entry.BodySize = body.size
+			recorder.complete(entry)
+			if entry.ResourceID == "" {
+				entry.ResourceID = mux.Vars(r)["id"]
+			}
+
+			// Record the entry even if the client has gone away in the meantime
+			if err := auditService.RecordEntry(context.WithoutCancel(r.Context()), entry); err != nil {
+				log.Printf("Failed to record audit entry for %s %s: %v", entry.Method, entry.Path, err)
+			}
+		})
+	}
+}
+
+// isAuditedMethod returns true for the methods of requests that change data
+func isAuditedMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isJSONRequest returns true if the request body is JSON, or has no declared type
+func isJSONRequest(r *http.Request) bool {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/json"
+}
+
+// newAuditEntry describes the caller and route of a handled request
+func newAuditEntry(r *http.Request, trustProxy bool, start time.Time) *models.AuditEntry {
+	entry := &models.AuditEntry{
+		Time:      start,
+		ClientIP:  clientIP(r, trustProxy),
+		Method:    r.Method,
+		Route:     r.URL.Path,
+		Path:      r.URL.Path,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			entry.Route = template
+		}
+	}
+	if vars := mux.Vars(r); len(vars) > 0 {
+		entry.PathParams = vars
+	}
+
+	// API keys passed in the query string must not end up in the log
+	query := r.URL.Query()
+	query.Del(APIKeyQueryParam)
+	entry.Query = query.Encode()
+
+	if identity := IdentityFromContext(r.Context()); identity != nil {
+		entry.Actor = &models.AuditActor{
+			Method:  identity.Method,
+			Subject: identity.Subject,
+			Name:    identity.Name,
+			Role:    identity.Role,
+		}
+	}
+	return entry
+}
+
+// auditRequestBody counts the bytes of a request body read by the handler and keeps the start of it
+type auditRequestBody struct {
+	io.ReadCloser
+	capture  bool
+	captured bytes.Buffer
+	size     int64
+}
+
+func (b *auditRequestBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += int64(n)
+	if b.capture {
+		if room := maxAuditBodySize - b.captured.Len(); room > 0 {
+			b.captured.Write(p[:min(n, room)])
+		}
+	}
+	return n, err
+}
+
+// auditResponseWriter records the status of a response and the start of its body
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (a *auditResponseWriter) WriteHeader(statusCode int) {
+	if a.status == 0 {
+		a.status = statusCode
+	}
+	a.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (a *auditResponseWriter) Write(p []byte) (int, error) {
+	if a.status == 0 {
+		a.status = http.StatusOK
+	}
+	if room := maxAuditResponseSize - a.body.Len(); room > 0 {
+		a.body.Write(p[:min(len(p), room)])
+	}
+	return a.ResponseWriter.Write(p)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer, e.g. to lift the write deadline of imports
+func (a *auditResponseWriter) Unwrap() http.ResponseWriter {
+	return a.ResponseWriter
+}
+
+// complete sets the status of entry and, from a JSON response, its error or the ID of the changed item
+func (a *auditResponseWriter) complete(entry *models.AuditEntry) {
+	entry.Status = a.status
+	if entry.Status == 0 {
+		entry.Status = http.StatusOK
+	}
+
+	var body struct {
+		Message   string          `json:"message"`
+		ErrorCode string          `json:"errorCode"`
+		Data      json.RawMessage `json:"data"`
+	}
+	if json.Unmarshal(a.body.Bytes(), &body) != nil {
+		return
+	}
+	if entry.IsFailure() {
+		entry.ErrorCode = body.ErrorCode
+		entry.Error = body.Message
+		return
+	}
+
+	var data struct {
+		ID     string `json:"id"`
+		APIKey struct {
+			ID string `json:"id"`
+		} `json:"apiKey"` // Created API keys are returned with the key itself
+	}
+	if json.Unmarshal(body.Data, &data) != nil {
+		return
+	}
+	entry.ResourceID = data.ID
+	if entry.ResourceID == "" {
+		entry.ResourceID = data.APIKey.ID
+	}
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/errors"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository/memory"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+	"github.com/DangVTNhan/Scanner/be/internal/services"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAuditRouter returns a router behind the audit middleware whose routes read their body and answer like the
+// report handlers, and the service whose log it writes
+func newAuditRouter() (*mux.Router, *services.AuditService) {
+	auditService := services.NewAuditService(memory.NewAuditLogRepository())
+
+	router := mux.NewRouter()
+	router.Use(AuditMiddleware(auditService, false))
+	router.HandleFunc("/api/reports", func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		if identity := IdentityFromContext(r.Context()); identity != nil {
+			identity.Role = models.RoleOperator // As set by PermissionMiddleware
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(response.NewSuccessResponse("Report generated successfully", models.WeatherReport{ID: "r1"}))
+	}).Methods("GET", "POST", "OPTIONS")
+	router.HandleFunc("/api/reports/import", func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		respondWithError(w, "quota exceeded: tenant default may store at most 10 reports", errors.ErrCodeQuotaExceeded, http.StatusForbidden)
+	}).Methods("POST")
+	router.HandleFunc("/api/reports/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}).Methods("DELETE")
+	return router, auditService
+}
+
+// auditEntries returns the entries of the default tenant, newest first
+func auditEntries(t *testing.T, auditService *services.AuditService) []models.AuditEntry {
+	page, err := auditService.ListAuditEntries(context.Background(), &request.AuditLogRequest{})
+	require.NoError(t, err)
+	return page.Entries
+}
+
+func TestAuditMiddleware(t *testing.T) {
+	// Arrange
+	router, auditService := newAuditRouter()
+	identity := &models.Identity{Method: models.AuthMethodAPIKey, Subject: "key1", Name: "ops", Tenant: models.DefaultTenant}
+	body := `{"timestamp":"2026-10-01T08:00:00Z"}`
+	req := httptest.NewRequest("POST", "/api/reports?dryRun=false&apiKey=wr_secret", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = "10.0.0.1:52000"
+	req = req.WithContext(ContextWithIdentity(req.Context(), identity))
+	rr := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	entries := auditEntries(t, auditService)
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	assert.Equal(t, &models.AuditActor{Method: models.AuthMethodAPIKey, Subject: "key1", Name: "ops", Role: models.RoleOperator}, entry.Actor)
+	assert.Equal(t, "10.0.0.1", entry.ClientIP)
+	assert.Equal(t, "POST", entry.Method)
+	assert.Equal(t, "/api/reports", entry.Route)
+	assert.Equal(t, "dryRun=false", entry.Query, "API keys are left out")
+	assert.Equal(t, body, entry.Body)
+	assert.Equal(t, int64(len(body)), entry.BodySize)
+	assert.Equal(t, http.StatusCreated, entry.Status)
+	assert.Equal(t, "r1", entry.ResourceID)
+	assert.Empty(t, entry.ErrorCode)
+	assert.False(t, entry.Time.IsZero())
+}
+
+func TestAuditMiddleware_Failure(t *testing.T) {
+	// Arrange
+	router, auditService := newAuditRouter()
+	csvBody := "timestamp,temperature\n2026-10-01T08:00:00Z,30\n"
+	req := httptest.NewRequest("POST", "/api/reports/import", strings.NewReader(csvBody))
+	req.Header.Set("Content-Type", "text/csv")
+	rr := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	entries := auditEntries(t, auditService)
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	assert.Nil(t, entry.Actor, "no actor with authentication disabled")
+	assert.Equal(t, http.StatusForbidden, entry.Status)
+	assert.Equal(t, errors.ErrCodeQuotaExceeded, entry.ErrorCode)
+	assert.Equal(t, "quota exceeded: tenant default may store at most 10 reports", entry.Error)
+	assert.Empty(t, entry.Body, "only JSON bodies are kept")
+	assert.Equal(t, int64(len(csvBody)), entry.BodySize)
+}
+
+func TestAuditMiddleware_PathParameters(t *testing.T) {
+	// Arrange
+	router, auditService := newAuditRouter()
+	rr := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(rr, httptest.NewRequest("DELETE", "/api/reports/r2", nil))
+
+	// Assert
+	entries := auditEntries(t, auditService)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "/api/reports/{id}", entries[0].Route)
+	assert.Equal(t, "/api/reports/r2", entries[0].Path)
+	assert.Equal(t, map[string]string{"id": "r2"}, entries[0].PathParams)
+	assert.Equal(t, http.StatusNoContent, entries[0].Status)
+	assert.Equal(t, "r2", entries[0].ResourceID)
+}
+
+func TestAuditMiddleware_ReadsNotRecorded(t *testing.T) {
+	// Arrange
+	router, auditService := newAuditRouter()
+
+	for _, method := range []string{"GET", "OPTIONS"} {
+		rr := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rr, httptest.NewRequest(method, "/api/reports", nil))
+
+		// Assert
+		assert.Less(t, rr.Code, 300)
+	}
+	assert.Empty(t, auditEntries(t, auditService))
+}
@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/DangVTNhan/Scanner/be/internal/interfaces"
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/errors"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+)
+
+const (
+	// APIKeyHeader is the header that carries the API key of a request
+	APIKeyHeader = "X-API-Key"
+
+	// APIKeyQueryParam carries the API key for clients that cannot set headers, such as feed readers,
+	// calendar clients and embedded charts
+	APIKeyQueryParam = "apiKey"
+)
+
+// identityContextKey is the context key of the authenticated caller
+type identityContextKey struct{}
+
+// AuthMiddleware creates a middleware that rejects requests to /api routes unless they carry a valid
+// bearer token in the Authorization header or a valid API key. Either service may be nil to disable
+// that method. The caller's identity and tenant are put into the request context, see IdentityFromContext
+// and models.TenantFromContext.
+// Preflight requests and routes outside /api, such as the Swagger UI, are not authenticated.
+func AuthMiddleware(apiKeyService interfaces.IAPIKeyService, tokenService interfaces.ITokenService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodOptions || !strings.HasPrefix(r.URL.Path, "/api/") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var identity *models.Identity
+			if token, ok := bearerToken(r); ok && tokenService != nil {
+				var err error
+				identity, err = tokenService.Authenticate(r.Context(), token)
+				if err != nil {
+					if strings.HasPrefix(err.Error(), "invalid token") {
+						respondWithAuthError(w, err.Error(), errors.ErrCodeUnauthorized, http.StatusUnauthorized, apiKeyService, tokenService)
+					} else {
+						respondWithAuthError(w, err.Error(), errors.ErrCodeServerError, http.StatusInternalServerError, apiKeyService, tokenService)
+					}
+					return
+				}
+			} else if key := apiKey(r); key != "" && apiKeyService != nil {
+				apiKey, err := apiKeyService.Authenticate(r.Context(), key)
+				if err != nil {
+					if err.Error() == "invalid api key" {
+						respondWithAuthError(w, "Invalid API key", errors.ErrCodeUnauthorized, http.StatusUnauthorized, apiKeyService, tokenService)
+						return
+					}
+					errorCode := errors.ErrCodeServerError
+					if strings.Contains(err.Error(), "failed to retrieve api key") {
+						errorCode = errors.ErrCodeDatabaseQuery
+					}
+					respondWithAuthError(w, err.Error(), errorCode, http.StatusInternalServerError, apiKeyService, tokenService)
+					return
+				}
+				identity = models.NewAPIKeyIdentity(apiKey)
+			} else {
+				message := "API key or bearer token required"
+				if tokenService == nil {
+					message = "API key required"
+				} else if apiKeyService == nil {
+					message = "Bearer token required"
+				}
+				respondWithAuthError(w, message, errors.ErrCodeUnauthorized, http.StatusUnauthorized, apiKeyService, tokenService)
+				return
+			}
+
+			ctx := models.ContextWithTenant(ContextWithIdentity(r.Context(), identity), identity.Tenant)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ContextWithIdentity returns a copy of ctx that carries the caller's identity
+func ContextWithIdentity(ctx context.Context, identity *models.Identity) context.Context {
+	return context.WithValue(ctx, identityContextKey{}, identity)
+}
+
+// IdentityFromContext returns the authenticated caller of a request, or nil when authentication is disabled
+func IdentityFromContext(ctx context.Context) *models.Identity {
+	identity, _ := ctx.Value(identityContextKey{}).(*models.Identity)
+	return identity
+}
+
+// bearerToken returns the token of an "Authorization: Bearer" header
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// apiKey returns the API key of a request from the header, or else from the query parameter
+func apiKey(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return key
+	}
+	return r.URL.Query().Get(APIKeyQueryParam)
+}
+
+// respondWithAuthError sends an error response in the standard envelope, with a challenge for every
+// enabled authentication method when the request is unauthorized
+func respondWithAuthError(w http.ResponseWriter, message string, errorCode string, statusCode int,
+	apiKeyService interfaces.IAPIKeyService, tokenService interfaces.ITokenService) {
+	if statusCode == http.StatusUnauthorized {
+		if tokenService != nil {
+			w.Header().Add("WWW-Authenticate", `Bearer realm="api"`)
+		}
+		if apiKeyService != nil {
+			w.Header().Add("WWW-Authenticate", `APIKey header="`+APIKeyHeader+`"`)
+		}
+	}
+	respondWithError(w, message, errorCode, statusCode)
+}
+
+// respondWithError sends an error response in the standard envelope
+func respondWithError(w http.ResponseWriter, message string, errorCode string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response.NewErrorResponse(message, errorCode, nil))
+}
@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/interfaces"
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/errors"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository/memory"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+	"github.com/DangVTNhan/Scanner/be/internal/services"
+	"github.com/DangVTNhan/Scanner/be/pkg/jwt"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// authFixture is the middleware around a handler that records the caller, with a valid API key of tenant changi-ops
+// and a token signing key
+type authFixture struct {
+	handler    http.Handler
+	identity   *models.Identity
+	tenant     string
+	apiKey     string
+	signingKey ed25519.PrivateKey
+}
+
+// newAuthFixture creates a fixture; withAPIKeys and withTokens enable the authentication methods
+func newAuthFixture(t *testing.T, withAPIKeys, withTokens bool) *authFixture {
+	f := &authFixture{}
+
+	apiKeyService := services.NewAPIKeyService(memory.NewAPIKeyRepository())
+	ctx := models.ContextWithTenant(context.Background(), "changi-ops")
+	created, err := apiKeyService.CreateAPIKey(ctx, &request.APIKeyRequest{Name: "test"})
+	require.NoError(t, err)
+	f.apiKey = created.Key
+
+	publicKey, signingKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	f.signingKey = signingKey
+
+	var apiKeys interfaces.IAPIKeyService
+	var tokens interfaces.ITokenService
+	if withAPIKeys {
+		apiKeys = apiKeyService
+	}
+	if withTokens {
+		keys, err := jwt.NewKeySet(jwt.Key{ID: "sso", Public: publicKey})
+		require.NoError(t, err)
+		tokens = services.NewTokenService(&jwt.Verifier{
+			Keys:     keys,
+			Issuer:   "https://sso.example.com",
+			Audience: "weather-api",
+		}, "tenant")
+	}
+
+	f.handler = AuthMiddleware(apiKeys, tokens)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.identity = IdentityFromContext(r.Context())
+		f.tenant = models.TenantFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+	return f
+}
+
+// token signs a token for user u1 that expires after ttl
+func (f *authFixture) token(t *testing.T, ttl time.Duration) string {
+	token := jwtv5.NewWithClaims(jwtv5.SigningMethodEdDSA, jwtv5.MapClaims{
+		"iss":  "https://sso.example.com",
+		"aud":  "weather-api",
+		"sub":  "u1",
+		"name": "Jane Doe",
+		"exp":  time.Now().Add(ttl).Unix(),
+	})
+	token.Header["kid"] = "sso"
+	signed, err := token.SignedString(f.signingKey)
+	require.NoError(t, err)
+	return signed
+}
+
+// decodeError decodes an error response and checks its envelope
+func decodeError(t *testing.T, rr *httptest.ResponseRecorder) response.BaseResponse {
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	var body response.BaseResponse
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+	assert.Equal(t, "error", body.Status)
+	return body
+}
+
+func TestAuthMiddleware_APIKey(t *testing.T) {
+	// Arrange
+	f := newAuthFixture(t, true, true)
+
+	for name, req := range map[string]*http.Request{
+		"Header":     httptest.NewRequest("GET", "/api/reports", nil),
+		"QueryParam": httptest.NewRequest("GET", "/api/feeds/reports.atom?apiKey="+f.apiKey, nil),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if name == "Header" {
+				req.Header.Set(APIKeyHeader, f.apiKey)
+			}
+			rr := httptest.NewRecorder()
+
+			// Act
+			f.handler.ServeHTTP(rr, req)
+
+			// Assert
+			assert.Equal(t, http.StatusOK, rr.Code)
+			require.NotNil(t, f.identity)
+			assert.Equal(t, models.AuthMethodAPIKey, f.identity.Method)
+			assert.Equal(t, "test", f.identity.Name)
+			assert.Equal(t, "changi-ops", f.identity.Tenant)
+			assert.Equal(t, "changi-ops", f.tenant)
+		})
+	}
+}
+
+func TestAuthMiddleware_BearerToken(t *testing.T) {
+	// Arrange
+	f := newAuthFixture(t, true, true)
+	req := httptest.NewRequest("GET", "/api/reports", nil)
+	req.Header.Set("Authorization", "Bearer "+f.token(t, time.Hour))
+	rr := httptest.NewRecorder()
+
+	// Act
+	f.handler.ServeHTTP(rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rr.Code)
+	require.NotNil(t, f.identity)
+	assert.Equal(t, models.AuthMethodToken, f.identity.Method)
+	assert.Equal(t, "u1", f.identity.Subject)
+	assert.Equal(t, "Jane Doe", f.identity.Name)
+	assert.Equal(t, "https://sso.example.com", f.identity.Issuer)
+	assert.Equal(t, models.DefaultTenant, f.tenant)
+}
+
+func TestAuthMiddleware_RejectsRequests(t *testing.T) {
+	for name, tc := range map[string]struct {
+		withAPIKeys, withTokens bool
+		header, value           func(f *authFixture) string
+		message                 string
+		challenges              int
+	}{
+		"MissingBoth": {withAPIKeys: true, withTokens: true, message: "API key or bearer token required", challenges: 2},
+		"MissingKey":  {withAPIKeys: true, message: "API key required", challenges: 1},
+		"InvalidKey": {
+			withAPIKeys: true, withTokens: true, message: "Invalid API key", challenges: 2,
+			header: func(f *authFixture) string { return APIKeyHeader },
+			value:  func(f *authFixture) string { return f.apiKey + "x" },
+		},
+		"ExpiredToken": {
+			withAPIKeys: true, withTokens: true, message: "invalid token: token has invalid claims: token is expired", challenges: 2,
+			header: func(f *authFixture) string { return "Authorization" },
+			value:  func(f *authFixture) string { return "Bearer " + f.token(t, -time.Hour) },
+		},
+		"TokenWhenOnlyAPIKeys": {
+			withAPIKeys: true, message: "API key required", challenges: 1,
+			header: func(f *authFixture) string { return "Authorization" },
+			value:  func(f *authFixture) string { return "Bearer " + f.token(t, time.Hour) },
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			f := newAuthFixture(t, tc.withAPIKeys, tc.withTokens)
+			req := httptest.NewRequest("GET", "/api/reports", nil)
+			if tc.header != nil {
+				req.Header.Set(tc.header(f), tc.value(f))
+			}
+			rr := httptest.NewRecorder()
+
+			// Act
+			f.handler.ServeHTTP(rr, req)
+
+			// Assert
+			assert.Equal(t, http.StatusUnauthorized, rr.Code)
+			assert.Len(t, rr.Header().Values("WWW-Authenticate"), tc.challenges)
+			body := decodeError(t, rr)
+			assert.Contains(t, body.Message, tc.message)
+			assert.Equal(t, errors.ErrCodeUnauthorized, body.ErrorCode)
+			assert.Nil(t, f.identity)
+		})
+	}
+}
+
+func TestAuthMiddleware_PublicRequests(t *testing.T) {
+	// Arrange
+	f := newAuthFixture(t, true, true)
+
+	for name, req := range map[string]*http.Request{
+		"Preflight": httptest.NewRequest("OPTIONS", "/api/reports", nil),
+		"Swagger":   httptest.NewRequest("GET", "/swagger/index.html", nil),
+	} {
+		t.Run(name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			// Act
+			f.handler.ServeHTTP(rr, req)
+
+			// Assert
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Nil(t, f.identity)
+		})
+	}
+}
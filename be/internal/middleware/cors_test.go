@@ -192,7 +192,7 @@ func TestCORSMiddleware_HeadersSet(t *testing.T) {
 
 	// Check all required CORS headers
 	assert.Equal(t, "http://localhost:3000", rr.Header().Get("Access-Control-Allow-Origin"))
-	assert.Equal(t, "GET, POST, PUT, DELETE, OPTIONS", rr.Header().Get("Access-Control-Allow-Methods"))
-	assert.Equal(t, "Content-Type, Authorization, X-Requested-With", rr.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "GET, POST, PUT, PATCH, DELETE, OPTIONS", rr.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Content-Type, Authorization, X-Requested-With, X-API-Key", rr.Header().Get("Access-Control-Allow-Headers"))
 	assert.Equal(t, "3600", rr.Header().Get("Access-Control-Max-Age"))
 }
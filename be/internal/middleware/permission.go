@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/DangVTNhan/Scanner/be/internal/interfaces"
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/errors"
+)
+
+// PermissionMiddleware creates a function that wraps the handler of a route so that it rejects callers whose
+// role lacks the permission with 403. It runs after AuthMiddleware and records the caller's role on its
+// identity. Requests without an identity, i.e. with authentication disabled, are let through.
+//
+//	authorize := middleware.PermissionMiddleware(roleService)
+//	router.HandleFunc("/api/reports", authorize(models.PermissionGenerateReports, handler)).Methods("POST", "OPTIONS")
+func PermissionMiddleware(roleService interfaces.IRoleService) func(permission models.Permission, next http.HandlerFunc) http.HandlerFunc {
+	return func(permission models.Permission, next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			identity := IdentityFromContext(r.Context())
+			if identity == nil || r.Method == http.MethodOptions {
+				next(w, r)
+				return
+			}
+
+			role, err := roleService.ResolveRole(r.Context(), identity)
+			if err != nil {
+				errorCode := errors.ErrCodeServerError
+				if strings.Contains(err.Error(), "failed to retrieve role assignment") {
+					errorCode = errors.ErrCodeDatabaseQuery
+				}
+				respondWithError(w, err.Error(), errorCode, http.StatusInternalServerError)
+				return
+			}
+			identity.Role = role
+
+			if !role.Can(permission) {
+				respondWithError(w, fmt.Sprintf("Permission %s required; role %s does not have it", permission, role),
+					errors.ErrCodeForbidden, http.StatusForbidden)
+				return
+			}
+			next(w, r)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/errors"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository/memory"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermissionMiddleware(t *testing.T) {
+	// Arrange
+	roleService := services.NewRoleService(memory.NewRoleAssignmentRepository(), models.RoleViewer)
+	_, err := roleService.AssignRole(context.Background(), nil, models.AuthMethodAPIKey, "operator-key", &request.RoleAssignmentRequest{Role: models.RoleOperator})
+	require.NoError(t, err)
+	authorize := PermissionMiddleware(roleService)
+
+	for name, tc := range map[string]struct {
+		identity   *models.Identity
+		method     string
+		permission models.Permission
+		wantStatus int
+		wantRole   models.Role
+	}{
+		"Allowed":        {identity: &models.Identity{Method: models.AuthMethodAPIKey, Subject: "operator-key"}, permission: models.PermissionDeleteReports, wantStatus: http.StatusOK, wantRole: models.RoleOperator},
+		"Forbidden":      {identity: &models.Identity{Method: models.AuthMethodAPIKey, Subject: "operator-key"}, permission: models.PermissionManageRoles, wantStatus: http.StatusForbidden, wantRole: models.RoleOperator},
+		"DefaultRole":    {identity: &models.Identity{Method: models.AuthMethodToken, Subject: "u1"}, permission: models.PermissionReadReports, wantStatus: http.StatusOK, wantRole: models.RoleViewer},
+		"DefaultDenied":  {identity: &models.Identity{Method: models.AuthMethodToken, Subject: "u1"}, permission: models.PermissionGenerateReports, wantStatus: http.StatusForbidden, wantRole: models.RoleViewer},
+		"AuthDisabled":   {permission: models.PermissionManageRoles, wantStatus: http.StatusOK},
+		"PreflightAllow": {identity: &models.Identity{Method: models.AuthMethodToken, Subject: "u1"}, method: "OPTIONS", permission: models.PermissionManageRoles, wantStatus: http.StatusOK},
+	} {
+		t.Run(name, func(t *testing.T) {
+			method := "POST"
+			if tc.method != "" {
+				method = tc.method
+			}
+			req := httptest.NewRequest(method, "/api/reports", nil)
+			if tc.identity != nil {
+				req = req.WithContext(ContextWithIdentity(req.Context(), tc.identity))
+			}
+			rr := httptest.NewRecorder()
+			handler := authorize(tc.permission, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			// Act
+			handler.ServeHTTP(rr, req)
+
+			// Assert
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			if tc.wantStatus == http.StatusForbidden {
+				body := decodeError(t, rr)
+				assert.Equal(t, errors.ErrCodeForbidden, body.ErrorCode)
+				assert.Contains(t, body.Message, string(tc.permission))
+			}
+			if tc.identity != nil && tc.wantRole != "" {
+				assert.Equal(t, tc.wantRole, tc.identity.Role)
+			}
+		})
+	}
+}
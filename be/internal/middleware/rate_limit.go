@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/interfaces"
+	"github.com/DangVTNhan/Scanner/be/internal/models/errors"
+	"github.com/gorilla/mux"
+)
+
+// RateLimitMiddleware creates a middleware that rejects requests to /api routes with 429 once their client
+// has used up its limit for the route. It runs after AuthMiddleware: authenticated callers are limited per
+// API key or token subject, and anonymous requests, e.g. with authentication disabled, per IP address.
+// With trustProxy the address is the last one in X-Forwarded-For, as added by a reverse proxy in front of the API.
+//
+// Limited responses carry the RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset and RateLimit-Policy
+// headers of the IETF RateLimit header fields draft, and rejected ones Retry-After. If the limit cannot be
+// checked, e.g. because the shared store is down, the error is logged and, with failOpen, the request is let
+// through; otherwise it is rejected with 503.
+func RateLimitMiddleware(rateLimitService interfaces.IRateLimitService, trustProxy, failOpen bool) func(http.Handler) http.Handler {
+	return rateLimit(rateLimitService, func(r *http.Request) string { return rateLimitClient(r, trustProxy) }, failOpen)
+}
+
+// IPRateLimitMiddleware creates a middleware like RateLimitMiddleware that limits the requests of each IP address,
+// whatever their credentials. It runs before AuthMiddleware, so that requests with invalid credentials count too.
+// Its buckets are named "addr:<address>", apart from the "ip:<address>" buckets of RateLimitMiddleware.
+func IPRateLimitMiddleware(rateLimitService interfaces.IRateLimitService, trustProxy, failOpen bool) func(http.Handler) http.Handler {
+	return rateLimit(rateLimitService, func(r *http.Request) string { return "addr:" + clientIP(r, trustProxy) }, failOpen)
+}
+
+// rateLimit creates a middleware that limits the requests of the client returned by client
+func rateLimit(rateLimitService interfaces.IRateLimitService, client func(r *http.Request) string, failOpen bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodOptions || !strings.HasPrefix(r.URL.Path, "/api/") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			result, err := rateLimitService.Take(r.Context(), client(r), rateLimitRoute(r))
+			if err != nil {
+				if failOpen {
+					log.Printf("Rate limit not enforced: %v", err)
+					next.ServeHTTP(w, r)
+					return
+				}
+				log.Printf("Request rejected, rate limit unavailable: %v", err)
+				respondWithError(w, "Rate limit could not be checked, please retry later",
+					errors.ErrCodeServerError, http.StatusServiceUnavailable)
+				return
+			}
+
+			if !result.Limit.IsUnlimited() {
+				w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit.Requests))
+				w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+				w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+				w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", result.Limit.Requests, ceilSeconds(result.Limit.Period)))
+			}
+			if !result.Allowed {
+				retryAfter := ceilSeconds(result.RetryAfter)
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				respondWithError(w, fmt.Sprintf("Rate limit of %d requests per %s exceeded, retry in %d s",
+					result.Limit.Requests, result.Limit.Period, retryAfter), errors.ErrCodeTooManyRequests, http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitClient identifies the client of a request by its credential, or else by its address
+func rateLimitClient(r *http.Request, trustProxy bool) string {
+	if identity := IdentityFromContext(r.Context()); identity != nil {
+		return string(identity.Method) + ":" + identity.Subject
+	}
+	return "ip:" + clientIP(r, trustProxy)
+}
+
+// clientIP returns the address of the client of a request. With trustProxy it is the last one in
+// X-Forwarded-For, as added by a reverse proxy in front of the API.
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		forwarded := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+		if ip := strings.TrimSpace(forwarded[len(forwarded)-1]); ip != "" {
+			return ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return host
+}
+
+// rateLimitRoute returns the method and path template of the route of a request, e.g. "GET /api/reports/{id}"
+func rateLimitRoute(r *http.Request) string {
+	path := r.URL.Path
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			path = template
+		}
+	}
+	return r.Method + " " + path
+}
+
+// ceilSeconds rounds d up to whole seconds
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,229 @@
+package middleware
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/errors"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository/memory"
+	"github.com/DangVTNhan/Scanner/be/internal/services"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRateLimitRouter returns a router with a generation route limited to 2 requests a minute
+// and other routes to 100, behind the rate limit middleware
+func newRateLimitRouter(trustProxy bool) *mux.Router {
+	rateLimitService := services.NewRateLimitService(memory.NewRateLimitRepository(),
+		models.RateLimit{Requests: 100, Period: time.Minute},
+		map[string]models.RateLimit{"POST /api/reports": {Requests: 2, Period: time.Minute}})
+
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	router := mux.NewRouter()
+	router.Use(RateLimitMiddleware(rateLimitService, trustProxy, true))
+	router.HandleFunc("/api/reports", ok).Methods("GET", "POST", "OPTIONS")
+	router.HandleFunc("/api/reports/{id}", ok).Methods("GET")
+	router.HandleFunc("/swagger/index.html", ok).Methods("GET")
+	return router
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	// Arrange
+	router := newRateLimitRouter(false)
+	send := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = "10.0.0.1:52000"
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Act
+	first := send("POST", "/api/reports")
+	second := send("POST", "/api/reports")
+	rejected := send("POST", "/api/reports")
+	otherRoute := send("GET", "/api/reports/r1")
+
+	// Assert
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "2", first.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", first.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", first.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "2;w=60", first.Header().Get("RateLimit-Policy"))
+	assert.Equal(t, "0", second.Header().Get("RateLimit-Remaining"))
+
+	assert.Equal(t, http.StatusTooManyRequests, rejected.Code)
+	assert.Equal(t, "30", rejected.Header().Get("Retry-After"))
+	body := decodeError(t, rejected)
+	assert.Equal(t, errors.ErrCodeTooManyRequests, body.ErrorCode)
+	assert.Equal(t, "Rate limit of 2 requests per 1m0s exceeded, retry in 30 s", body.Message)
+
+	assert.Equal(t, http.StatusOK, otherRoute.Code, "routes without their own limit have the default limit")
+	assert.Equal(t, "100", otherRoute.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "99", otherRoute.Header().Get("RateLimit-Remaining"))
+}
+
+func TestRateLimitMiddleware_Clients(t *testing.T) {
+	for name, tc := range map[string]struct {
+		trustProxy bool
+		prepare    func(req *http.Request, i int) *http.Request
+		wantCode   int
+	}{
+		"SameIP": {
+			prepare:  func(req *http.Request, i int) *http.Request { return req },
+			wantCode: http.StatusTooManyRequests,
+		},
+		"DifferentIPs": {
+			prepare: func(req *http.Request, i int) *http.Request {
+				req.RemoteAddr = []string{"10.0.0.1:1", "10.0.0.2:1", "10.0.0.3:1"}[i]
+				return req
+			},
+			wantCode: http.StatusOK,
+		},
+		"ForwardedIgnored": {
+			prepare: func(req *http.Request, i int) *http.Request {
+				req.Header.Set("X-Forwarded-For", []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}[i])
+				return req
+			},
+			wantCode: http.StatusTooManyRequests,
+		},
+		"ForwardedTrusted": {
+			trustProxy: true,
+			prepare: func(req *http.Request, i int) *http.Request {
+				req.Header.Set("X-Forwarded-For", "203.0.113.9, "+[]string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}[i])
+				return req
+			},
+			wantCode: http.StatusOK,
+		},
+		"DifferentAPIKeysSameIP": {
+			prepare: func(req *http.Request, i int) *http.Request {
+				identity := &models.Identity{Method: models.AuthMethodAPIKey, Subject: []string{"k1", "k2", "k3"}[i]}
+				return req.WithContext(ContextWithIdentity(req.Context(), identity))
+			},
+			wantCode: http.StatusOK,
+		},
+		"SameAPIKeyDifferentIPs": {
+			prepare: func(req *http.Request, i int) *http.Request {
+				req.RemoteAddr = []string{"10.0.0.1:1", "10.0.0.2:1", "10.0.0.3:1"}[i]
+				identity := &models.Identity{Method: models.AuthMethodAPIKey, Subject: "k1"}
+				return req.WithContext(ContextWithIdentity(context.Background(), identity))
+			},
+			wantCode: http.StatusTooManyRequests,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			router := newRateLimitRouter(tc.trustProxy)
+
+			// Act
+			var rr *httptest.ResponseRecorder
+			for i := 0; i < 3; i++ {
+				rr = httptest.NewRecorder()
+				router.ServeHTTP(rr, tc.prepare(httptest.NewRequest("POST", "/api/reports", nil), i))
+			}
+
+			// Assert
+			assert.Equal(t, tc.wantCode, rr.Code)
+		})
+	}
+}
+
+func TestRateLimitMiddleware_NotLimited(t *testing.T) {
+	// Arrange
+	router := newRateLimitRouter(false)
+
+	for name, req := range map[string]*http.Request{
+		"Preflight": httptest.NewRequest("OPTIONS", "/api/reports", nil),
+		"Swagger":   httptest.NewRequest("GET", "/swagger/index.html", nil),
+	} {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 3; i++ {
+				rr := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(rr, req)
+
+				// Assert
+				require.Equal(t, http.StatusOK, rr.Code)
+				assert.Empty(t, rr.Header().Get("RateLimit-Limit"))
+			}
+		})
+	}
+}
+
+// failingRateLimitService fails every check, like a shared store that is down
+type failingRateLimitService struct{}
+
+func (failingRateLimitService) Take(ctx context.Context, client, route string) (*models.RateLimitResult, error) {
+	return nil, stderrors.New("failed to check rate limit: connection refused")
+}
+
+func TestRateLimitMiddleware_StoreUnavailable(t *testing.T) {
+	for name, tc := range map[string]struct {
+		failOpen bool
+		wantCode int
+	}{
+		"FailOpen":   {failOpen: true, wantCode: http.StatusOK},
+		"FailClosed": {failOpen: false, wantCode: http.StatusServiceUnavailable},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			router := mux.NewRouter()
+			router.Use(RateLimitMiddleware(failingRateLimitService{}, false, tc.failOpen))
+			router.HandleFunc("/api/reports", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+			rr := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/reports", nil))
+
+			// Assert
+			assert.Equal(t, tc.wantCode, rr.Code)
+			assert.Empty(t, rr.Header().Get("RateLimit-Limit"))
+			if !tc.failOpen {
+				assert.Equal(t, errors.ErrCodeServerError, decodeError(t, rr).ErrorCode)
+			}
+		})
+	}
+}
+
+func TestIPRateLimitMiddleware(t *testing.T) {
+	// Arrange
+	repository := memory.NewRateLimitRepository()
+	ipService := services.NewRateLimitService(repository, models.RateLimit{Requests: 3, Period: time.Minute}, nil)
+	clientService := services.NewRateLimitService(repository, models.RateLimit{Requests: 2, Period: time.Minute}, nil)
+	router := mux.NewRouter()
+	router.Use(IPRateLimitMiddleware(ipService, false, true))
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Authenticate requests with a key named in the query, like AuthMiddleware
+			if key := r.URL.Query().Get("key"); key != "" {
+				r = r.WithContext(ContextWithIdentity(r.Context(), &models.Identity{Method: models.AuthMethodAPIKey, Subject: key}))
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	router.Use(RateLimitMiddleware(clientService, false, true))
+	router.HandleFunc("/api/reports", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	send := func(ip, key string) int {
+		req := httptest.NewRequest("GET", "/api/reports?key="+key, nil)
+		req.RemoteAddr = ip + ":52000"
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	// Act & Assert
+	assert.Equal(t, http.StatusOK, send("10.0.0.1", "k1"))
+	assert.Equal(t, http.StatusOK, send("10.0.0.1", "k1"))
+	assert.Equal(t, http.StatusTooManyRequests, send("10.0.0.1", "k1"), "the key has used up its limit")
+	assert.Equal(t, http.StatusTooManyRequests, send("10.0.0.1", "k2"), "the address has used up its limit")
+	assert.Equal(t, http.StatusOK, send("10.0.0.2", "k2"))
+	assert.Equal(t, http.StatusOK, send("10.0.0.2", ""), "anonymous requests of an address do not share its bucket")
+	assert.Equal(t, http.StatusOK, send("10.0.0.2", ""))
+}
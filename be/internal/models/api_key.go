@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// APIKey is a credential that authenticates calls to the API.
+// Only a hash of the key is stored; the key itself is shown once when it is created.
+type APIKey struct {
+	ID         string     `json:"id" bson:"_id,omitempty"`
+	Tenant     string     `json:"tenant" bson:"tenant"` // Tenant whose data the key gives access to
+	Name       string     `json:"name" bson:"name"`     // Who or what uses the key, e.g. "grafana"
+	Prefix     string     `json:"prefix" bson:"prefix"` // First characters of the key, to recognize it
+	Hash       string     `json:"-" bson:"hash"`        // Hex SHA-256 of the key
+	CreatedAt  time.Time  `json:"createdAt" bson:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty" bson:"lastUsedAt,omitempty"` // Updated at most once a minute
+	RevokedAt  *time.Time `json:"revokedAt,omitempty" bson:"revokedAt,omitempty"`   // Set once the key no longer authenticates
+}
+
+// IsRevoked reports whether the key has been revoked
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
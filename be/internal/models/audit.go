@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+)
+
+// AuditEntry records a request that changed or could have changed data: who made it, what it asked for
+// and how it ended. Entries are append-only; nothing in the API updates or deletes them.
+type AuditEntry struct {
+	ID         string            `json:"id" bson:"_id,omitempty"`
+	Tenant     string            `json:"-" bson:"tenant"`                        // Set by the repository from the context
+	Time       time.Time         `json:"time" bson:"time"`                       // When the request arrived
+	Actor      *AuditActor       `json:"actor,omitempty" bson:"actor,omitempty"` // Nil when authentication is disabled
+	ClientIP   string            `json:"clientIp" bson:"clientIp"`
+	Method     string            `json:"method" bson:"method"`
+	Route      string            `json:"route" bson:"route"` // Path template, e.g. "/api/reports/{id}"
+	Path       string            `json:"path" bson:"path"`
+	PathParams map[string]string `json:"pathParams,omitempty" bson:"pathParams,omitempty"`
+	Query      string            `json:"query,omitempty" bson:"query,omitempty"` // Without credentials
+	Body       string            `json:"body,omitempty" bson:"body,omitempty"`   // Start of a JSON request body
+	BodySize   int64             `json:"bodySize" bson:"bodySize"`               // Bytes of the request body read by the handler
+	Status     int               `json:"status" bson:"status"`
+	ErrorCode  string            `json:"errorCode,omitempty" bson:"errorCode,omitempty"`
+	Error      string            `json:"error,omitempty" bson:"error,omitempty"`
+	ResourceID string            `json:"resourceId,omitempty" bson:"resourceId,omitempty"` // ID of the created or changed item, if the response names one
+	LatencyMs  int64             `json:"latencyMs" bson:"latencyMs"`
+}
+
+// AuditActor is the caller of an audited request
+type AuditActor struct {
+	Method  AuthMethod `json:"method" bson:"method"`
+	Subject string     `json:"subject" bson:"subject"` // ID of the API key, or the "sub" claim of the token
+	Name    string     `json:"name" bson:"name"`
+	Role    Role       `json:"role,omitempty" bson:"role,omitempty"` // Empty if the request was rejected before its permission was checked
+}
+
+// IsFailure returns true if the request was rejected or failed
+func (e *AuditEntry) IsFailure() bool {
+	return e.Status >= 400
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// Comparison is a saved comparison of two weather reports.
+// The reports and the thresholds are stored as snapshots, so later changes to either do not affect it.
+type Comparison struct {
+	ID        string        `json:"id" bson:"_id,omitempty"`
+	Tenant    string        `json:"-" bson:"tenant"` // Set by the repository from the context
+	Title     string        `json:"title" bson:"title"`
+	Notes     string        `json:"notes" bson:"notes"`
+	Report1   WeatherReport `json:"report1" bson:"report1"` // Snapshot of the first report when the comparison was saved
+	Report2   WeatherReport `json:"report2" bson:"report2"` // Snapshot of the second report when the comparison was saved
+	CreatedAt time.Time     `json:"createdAt" bson:"createdAt"`
+
+	// Thresholds in effect when the comparison was saved, which classify its deviation
+	Thresholds map[Metric]Threshold `json:"thresholds" bson:"thresholds"`
+}
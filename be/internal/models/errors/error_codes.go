@@ -32,6 +32,11 @@ const (
 	ErrCodeReportNotFound   = "ERR4001" // Report not found
 	ErrCodeReportComparison = "ERR4002" // Report comparison error
 	ErrCodeReportInvalid    = "ERR4003" // Invalid report data
+	ErrCodeQuotaExceeded    = "ERR4004" // Tenant's report quota exceeded
+
+	// Comparison error codes (5000-5999)
+	ErrCodeComparisonNotFound = "ERR5000" // Saved comparison not found
+	ErrCodeComparisonInvalid  = "ERR5001" // Invalid comparison data
 )
 
 // ErrorCodeToHTTPStatus maps error codes to HTTP status codes
@@ -66,4 +71,8 @@ var ErrorCodeToHTTPStatus = map[string]int{
 	ErrCodeReportNotFound:   404,
 	ErrCodeReportComparison: 500,
 	ErrCodeReportInvalid:    400,
+
+	// Comparison error codes
+	ErrCodeComparisonNotFound: 404,
+	ErrCodeComparisonInvalid:  400,
 }
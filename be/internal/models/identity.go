@@ -0,0 +1,37 @@
+package models
+
+// AuthMethod is how the caller of a request authenticated
+type AuthMethod string
+
+// Authentication methods
+const (
+	AuthMethodAPIKey AuthMethod = "api_key"
+	AuthMethodToken  AuthMethod = "token" // Bearer token of the company SSO
+)
+
+// IsValid returns true if m is a known authentication method
+func (m AuthMethod) IsValid() bool {
+	return m == AuthMethodAPIKey || m == AuthMethodToken
+}
+
+// Identity is the authenticated caller of a request
+type Identity struct {
+	Method  AuthMethod `json:"method"`
+	Subject string     `json:"subject"`          // ID of the API key, or the "sub" claim of the token
+	Name    string     `json:"name"`             // Name of the API key, or the display name of the token's user
+	Issuer  string     `json:"issuer,omitempty"` // Issuer of the token
+	Tenant  string     `json:"tenant"`           // Tenant whose data the caller sees and changes
+	Role    Role       `json:"role,omitempty"`   // Set once the caller's permissions have been checked
+
+	Claims map[string]interface{} `json:"-"` // All claims of the token, nil for API keys
+}
+
+// NewAPIKeyIdentity returns the identity of a caller authenticated with an API key
+func NewAPIKeyIdentity(apiKey *APIKey) *Identity {
+	return &Identity{
+		Method:  AuthMethodAPIKey,
+		Subject: apiKey.ID,
+		Name:    apiKey.Name,
+		Tenant:  apiKey.Tenant,
+	}
+}
@@ -0,0 +1,66 @@
+package models
+
+// Metric identifies a numeric weather measurement of a report by its JSON/BSON field name
+type Metric string
+
+const (
+	MetricTemperature Metric = "temperature" // in Celsius
+	MetricPressure    Metric = "pressure"    // in hPa
+	MetricHumidity    Metric = "humidity"    // in %
+	MetricCloudCover  Metric = "cloudCover"  // in %
+)
+
+// Metrics lists every metric of a weather report in display order
+var Metrics = []Metric{MetricTemperature, MetricPressure, MetricHumidity, MetricCloudCover}
+
+// IsValid returns true if m is a known metric
+func (m Metric) IsValid() bool {
+	for _, metric := range Metrics {
+		if m == metric {
+			return true
+		}
+	}
+	return false
+}
+
+// Unit returns the unit the metric is measured in
+func (m Metric) Unit() string {
+	switch m {
+	case MetricTemperature:
+		return "°C"
+	case MetricPressure:
+		return "hPa"
+	case MetricHumidity, MetricCloudCover:
+		return "%"
+	}
+	return ""
+}
+
+// PhysicalBounds returns the range a measurement of the metric can physically take.
+// Temperature and pressure bounds are just beyond the recorded extremes at the surface.
+func (m Metric) PhysicalBounds() (min, max float64) {
+	switch m {
+	case MetricTemperature:
+		return -90, 60
+	case MetricPressure:
+		return 870, 1085
+	case MetricHumidity, MetricCloudCover:
+		return 0, 100
+	}
+	return 0, 0
+}
+
+// Value returns the value of metric m in the report
+func (r *WeatherReport) Value(m Metric) float64 {
+	switch m {
+	case MetricTemperature:
+		return r.Temperature
+	case MetricPressure:
+		return r.Pressure
+	case MetricHumidity:
+		return r.Humidity
+	case MetricCloudCover:
+		return r.CloudCover
+	}
+	return 0
+}
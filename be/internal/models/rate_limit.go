@@ -0,0 +1,102 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit allows a client Requests requests per Period. It is a token bucket that holds Requests tokens and
+// refills at Requests per Period, so a client may also make all of them at once. The zero value allows any number.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseRateLimit parses a rate limit written as "requests/period", e.g. "30/1m" or "1000/24h". "0" is no limit.
+func ParseRateLimit(s string) (RateLimit, error) {
+	if strings.TrimSpace(s) == "0" {
+		return RateLimit{}, nil
+	}
+	requestsStr, periodStr, ok := strings.Cut(s, "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: use requests/period, e.g. 30/1m", s)
+	}
+	requests, err := strconv.Atoi(strings.TrimSpace(requestsStr))
+	if err != nil || requests <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: requests must be a positive number", s)
+	}
+	period, err := time.ParseDuration(strings.TrimSpace(periodStr))
+	if err != nil || period <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: period must be a positive duration", s)
+	}
+	limit := RateLimit{Requests: requests, Period: period}
+	if limit.Interval() == 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: at most one request per millisecond", s)
+	}
+	return limit, nil
+}
+
+// IsUnlimited returns true if the limit allows any number of requests
+func (l RateLimit) IsUnlimited() bool {
+	return l.Requests <= 0
+}
+
+// String returns the limit as "requests/period"
+func (l RateLimit) String() string {
+	if l.IsUnlimited() {
+		return "0"
+	}
+	return fmt.Sprintf("%d/%s", l.Requests, l.Period)
+}
+
+// Interval returns how long the bucket takes to refill one token, in whole milliseconds as stored in MongoDB
+func (l RateLimit) Interval() time.Duration {
+	return (l.Period / time.Duration(l.Requests)).Truncate(time.Millisecond)
+}
+
+// Window returns how long an empty bucket takes to fill up, which is Period up to rounding
+func (l RateLimit) Window() time.Duration {
+	return l.Interval() * time.Duration(l.Requests)
+}
+
+// Take takes a token at now from a bucket that is full again at fullAt, which is zero or in the past for a full
+// bucket. It returns when the bucket is full again after the request and whether the request is allowed;
+// the bucket is unchanged if it is not.
+func (l RateLimit) Take(fullAt, now time.Time) (time.Time, RateLimitResult) {
+	if fullAt.Before(now) {
+		fullAt = now
+	}
+	next := fullAt.Add(l.Interval())
+	allowed := next.Sub(now) <= l.Window()
+	if allowed {
+		fullAt = next
+	}
+	return fullAt, l.Result(fullAt, now, allowed)
+}
+
+// Result returns the outcome of a request at now that left the bucket full again at fullAt
+func (l RateLimit) Result(fullAt, now time.Time, allowed bool) RateLimitResult {
+	result := RateLimitResult{Allowed: allowed, Limit: l}
+	if fullAt.After(now) {
+		result.Reset = fullAt.Sub(now)
+	}
+	result.Remaining = int((l.Window() - result.Reset) / l.Interval())
+	if result.Remaining < 0 {
+		result.Remaining = 0
+	}
+	if !allowed {
+		result.RetryAfter = result.Reset + l.Interval() - l.Window()
+	}
+	return result
+}
+
+// RateLimitResult is the outcome of taking a token from a client's bucket
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      RateLimit
+	Remaining  int           // Tokens left in the bucket
+	Reset      time.Duration // Until the bucket is full again
+	RetryAfter time.Duration // Until the next token, if the request is not allowed
+}
@@ -1,15 +1,51 @@
 package models
 
 import (
+	"strings"
 	"time"
 )
 
+// Report locations (ICAO airport codes)
+const (
+	LocationChangi = "WSSS" // Singapore Changi Airport
+)
+
+// Report sources (the provider the weather data came from)
+const (
+	SourceOpenWeather = "openweather"
+	SourceImport      = "import" // Imported from a file of historical observations
+)
+
+// Report types
+const (
+	ReportTypeCurrent    = "current"    // Generated from current conditions
+	ReportTypeHistorical = "historical" // Generated from historical data for a past timestamp
+)
+
+// ReportSources lists every known report source
+var ReportSources = []string{SourceOpenWeather, SourceImport}
+
+// ReportTypes lists every known report type
+var ReportTypes = []string{ReportTypeCurrent, ReportTypeHistorical}
+
 type WeatherReport struct {
-	ID          string    `json:"id" bson:"_id,omitempty"`
-	Timestamp   time.Time `json:"timestamp" bson:"timestamp"`
-	Temperature float64   `json:"temperature" bson:"temperature"` // in Celsius
-	Pressure    float64   `json:"pressure" bson:"pressure"`       // in hPa
-	Humidity    float64   `json:"humidity" bson:"humidity"`       // in %
-	CloudCover  float64   `json:"cloudCover" bson:"cloudCover"`   // in %
-	CreatedAt   time.Time `json:"createdAt" bson:"createdAt"`
+	ID          string     `json:"id" bson:"_id,omitempty"`
+	Tenant      string     `json:"-" bson:"tenant"` // Set by the repository from the context
+	Timestamp   time.Time  `json:"timestamp" bson:"timestamp"`
+	Temperature float64    `json:"temperature" bson:"temperature"` // in Celsius
+	Pressure    float64    `json:"pressure" bson:"pressure"`       // in hPa
+	Humidity    float64    `json:"humidity" bson:"humidity"`       // in %
+	CloudCover  float64    `json:"cloudCover" bson:"cloudCover"`   // in %
+	Location    string     `json:"location" bson:"location"`       // ICAO code of the airport
+	Source      string     `json:"source" bson:"source"`           // Provider of the weather data
+	Type        string     `json:"type" bson:"type"`               // current or historical
+	CreatedAt   time.Time  `json:"createdAt" bson:"createdAt"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"` // Set while the report is soft-deleted
+	Tags        []string   `json:"tags,omitempty" bson:"tags,omitempty"`           // Labels added by forecasters, e.g. "runway closure"
+	Notes       string     `json:"notes,omitempty" bson:"notes,omitempty"`         // Free-text notes added by forecasters
+}
+
+// NormalizeTag returns the canonical form of a tag: trimmed and lower case
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
 }
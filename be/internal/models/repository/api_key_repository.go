@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+)
+
+// IAPIKeyRepository defines the interface for API key data access.
+// Every method only sees the keys of the tenant of ctx, see models.TenantFromContext, and inserted keys
+// are stamped with it; only FindAPIKeyByHash works across tenants, as the tenant is not known before authentication.
+type IAPIKeyRepository interface {
+	// InsertAPIKey inserts a new API key into the database
+	InsertAPIKey(ctx context.Context, key *models.APIKey) (string, error)
+
+	// FindAPIKeyByID retrieves an API key by its ID, including revoked keys
+	FindAPIKeyByID(ctx context.Context, id string) (*models.APIKey, error)
+
+	// FindAPIKeyByHash retrieves the API key with the given hash of any tenant, including revoked keys
+	FindAPIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error)
+
+	// FindAPIKeys retrieves all API keys, revoked ones included, newest first
+	FindAPIKeys(ctx context.Context) ([]models.APIKey, error)
+
+	// RevokeAPIKey marks an API key as revoked at revokedAt. Revoking a revoked key keeps its original time.
+	// It returns "api key not found" if the key does not exist.
+	RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error
+
+	// UpdateAPIKeyLastUsed records that an API key was used at usedAt
+	UpdateAPIKeyLastUsed(ctx context.Context, id string, usedAt time.Time) error
+}
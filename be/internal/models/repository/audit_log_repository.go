@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+)
+
+// IAuditLogRepository defines the interface for audit log data access. The log is append-only, so there
+// are no methods to change or remove entries. Every method only sees the entries of the tenant of ctx,
+// see models.TenantFromContext, and inserted entries are stamped with it.
+type IAuditLogRepository interface {
+	// InsertAuditEntry appends an entry to the log and returns its ID. IDs increase with insertion order.
+	InsertAuditEntry(ctx context.Context, entry *models.AuditEntry) (string, error)
+
+	// FindAuditEntries retrieves up to limit entries matching the filters of req, newest first,
+	// starting after the entry with ID req.Cursor if set
+	FindAuditEntries(ctx context.Context, req *request.AuditLogRequest, limit int) ([]models.AuditEntry, error)
+
+	// StreamAuditEntries passes every entry matching the filters of req to fn, newest first.
+	// It stops at the first error returned by fn.
+	StreamAuditEntries(ctx context.Context, req *request.AuditLogRequest, fn func(*models.AuditEntry) error) error
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+)
+
+// IComparisonRepository defines the interface for saved comparison data access.
+// Every method only sees the comparisons of the tenant of ctx, see models.TenantFromContext, and inserted
+// comparisons are stamped with it.
+type IComparisonRepository interface {
+	// InsertComparison inserts a new saved comparison into the database
+	InsertComparison(ctx context.Context, comparison *models.Comparison) (string, error)
+
+	// FindComparisonByID retrieves a saved comparison by its ID
+	FindComparisonByID(ctx context.Context, id string) (*models.Comparison, error)
+
+	// FindComparisons retrieves saved comparisons, newest first
+	FindComparisons(ctx context.Context, limit, offset int) ([]models.Comparison, error)
+
+	// CountComparisons counts the total number of saved comparisons
+	CountComparisons(ctx context.Context) (int64, error)
+
+	// DeleteComparison deletes a saved comparison by its ID
+	DeleteComparison(ctx context.Context, id string) error
+}
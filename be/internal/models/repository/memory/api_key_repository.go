@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// APIKeyRepository implements the IAPIKeyRepository interface in memory.
+// It is safe for concurrent use and mirrors the semantics of the MongoDB implementation.
+type APIKeyRepository struct {
+	mu   sync.RWMutex
+	keys []models.APIKey // kept in insertion order, like a collection's natural order
+}
+
+// NewAPIKeyRepository creates a new, empty instance of APIKeyRepository
+func NewAPIKeyRepository() repository.IAPIKeyRepository {
+	return &APIKeyRepository{}
+}
+
+// InsertAPIKey inserts a new API key into the store
+func (r *APIKeyRepository) InsertAPIKey(ctx context.Context, key *models.APIKey) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.keys {
+		if r.keys[i].Hash == key.Hash {
+			return "", fmt.Errorf("failed to save api key: duplicate key hash")
+		}
+	}
+
+	stored := *key
+	stored.ID = primitive.NewObjectID().Hex()
+	stored.Tenant = models.TenantFromContext(ctx)
+	stored.CreatedAt = normalizeTime(stored.CreatedAt)
+	stored.LastUsedAt = normalizeTimePtr(stored.LastUsedAt)
+	stored.RevokedAt = normalizeTimePtr(stored.RevokedAt)
+
+	r.keys = append(r.keys, stored)
+	return stored.ID, nil
+}
+
+// FindAPIKeyByID retrieves an API key by its ID, including revoked keys
+func (r *APIKeyRepository) FindAPIKeyByID(ctx context.Context, id string) (*models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if i := r.indexOf(ctx, id); i >= 0 {
+		key := r.keys[i]
+		return &key, nil
+	}
+	return nil, fmt.Errorf("api key not found")
+}
+
+// FindAPIKeyByHash retrieves the API key with the given hash of any tenant, including revoked keys
+func (r *APIKeyRepository) FindAPIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := range r.keys {
+		if r.keys[i].Hash == hash {
+			key := r.keys[i]
+			return &key, nil
+		}
+	}
+	return nil, fmt.Errorf("api key not found")
+}
+
+// FindAPIKeys retrieves all API keys, revoked ones included, newest first
+func (r *APIKeyRepository) FindAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenant := models.TenantFromContext(ctx)
+	keys := []models.APIKey{}
+	for _, key := range r.keys {
+		if key.Tenant == tenant {
+			keys = append(keys, key)
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		if c := compareTimes(keys[i].CreatedAt, keys[j].CreatedAt); c != 0 {
+			return c > 0
+		}
+		return keys[i].ID > keys[j].ID
+	})
+	return keys, nil
+}
+
+// RevokeAPIKey marks an API key as revoked at revokedAt. Revoking a revoked key keeps its original time.
+func (r *APIKeyRepository) RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(ctx, id)
+	if i < 0 {
+		return fmt.Errorf("api key not found")
+	}
+	if r.keys[i].RevokedAt == nil {
+		r.keys[i].RevokedAt = normalizeTimePtr(&revokedAt)
+	}
+	return nil
+}
+
+// UpdateAPIKeyLastUsed records that an API key was used at usedAt
+func (r *APIKeyRepository) UpdateAPIKeyLastUsed(ctx context.Context, id string, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(ctx, id)
+	if i < 0 {
+		return fmt.Errorf("api key not found")
+	}
+	r.keys[i].LastUsedAt = normalizeTimePtr(&usedAt)
+	return nil
+}
+
+// indexOf returns the position of the key of the tenant of ctx with the given ID, or -1. Callers must hold the lock.
+func (r *APIKeyRepository) indexOf(ctx context.Context, id string) int {
+	tenant := models.TenantFromContext(ctx)
+	for i := range r.keys {
+		if r.keys[i].ID == id && r.keys[i].Tenant == tenant {
+			return i
+		}
+	}
+	return -1
+}
+
+// normalizeTimePtr returns a copy of t normalized like a stored time, or nil
+func normalizeTimePtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	normalized := normalizeTime(*t)
+	return &normalized
+}
+
+// Ensure that APIKeyRepository implements the interface
+var _ repository.IAPIKeyRepository = (*APIKeyRepository)(nil)
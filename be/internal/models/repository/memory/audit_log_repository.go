@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditLogRepository implements the IAuditLogRepository interface in memory.
+// It is safe for concurrent use and mirrors the semantics of the MongoDB implementation.
+type AuditLogRepository struct {
+	mu      sync.RWMutex
+	entries []models.AuditEntry // kept in insertion order, so in ID order
+}
+
+// NewAuditLogRepository creates a new, empty instance of AuditLogRepository
+func NewAuditLogRepository() repository.IAuditLogRepository {
+	return &AuditLogRepository{}
+}
+
+// InsertAuditEntry appends an entry to the log
+func (r *AuditLogRepository) InsertAuditEntry(ctx context.Context, entry *models.AuditEntry) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *entry
+	stored.ID = primitive.NewObjectID().Hex()
+	stored.Tenant = models.TenantFromContext(ctx)
+	stored.Time = normalizeTime(stored.Time)
+	if stored.Actor != nil {
+		actor := *stored.Actor
+		stored.Actor = &actor
+	}
+	if stored.PathParams != nil {
+		params := make(map[string]string, len(stored.PathParams))
+		for key, value := range stored.PathParams {
+			params[key] = value
+		}
+		stored.PathParams = params
+	}
+
+	r.entries = append(r.entries, stored)
+	return stored.ID, nil
+}
+
+// FindAuditEntries retrieves up to limit matching entries, newest first
+func (r *AuditLogRepository) FindAuditEntries(ctx context.Context, req *request.AuditLogRequest, limit int) ([]models.AuditEntry, error) {
+	entries := []models.AuditEntry{}
+	err := r.StreamAuditEntries(ctx, req, func(entry *models.AuditEntry) error {
+		if len(entries) < limit {
+			entries = append(entries, *entry)
+		}
+		return nil
+	})
+	return entries, err
+}
+
+// StreamAuditEntries passes every matching entry to fn, newest first
+func (r *AuditLogRepository) StreamAuditEntries(ctx context.Context, req *request.AuditLogRequest, fn func(*models.AuditEntry) error) error {
+	r.mu.RLock()
+	var matched []models.AuditEntry
+	tenant := models.TenantFromContext(ctx)
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		if r.entries[i].Tenant == tenant && matchesAuditRequest(&r.entries[i], req) {
+			matched = append(matched, r.entries[i])
+		}
+	}
+	r.mu.RUnlock()
+
+	// fn runs without the lock, so a slow export does not hold up requests being audited
+	for i := range matched {
+		if err := fn(&matched[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchesAuditRequest reports whether entry passes the filters and cursor of req
+func matchesAuditRequest(entry *models.AuditEntry, req *request.AuditLogRequest) bool {
+	switch {
+	case req.Cursor != "" && entry.ID >= req.Cursor:
+		return false
+	case req.Actor != "" && (entry.Actor == nil || entry.Actor.Subject != req.Actor):
+		return false
+	case req.Method != "" && entry.Method != req.Method:
+		return false
+	case req.Route != "" && entry.Route != req.Route:
+		return false
+	case req.ResourceID != "" && entry.ResourceID != req.ResourceID:
+		return false
+	case req.Outcome == request.AuditOutcomeSuccess && entry.IsFailure():
+		return false
+	case req.Outcome == request.AuditOutcomeFailure && !entry.IsFailure():
+		return false
+	case !req.From.IsZero() && entry.Time.Before(req.From):
+		return false
+	case !req.To.IsZero() && entry.Time.After(req.To):
+		return false
+	}
+	return true
+}
+
+// Ensure that AuditLogRepository implements the interface
+var _ repository.IAuditLogRepository = (*AuditLogRepository)(nil)
@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ComparisonRepository implements the IComparisonRepository interface in memory.
+// It is safe for concurrent use and mirrors the semantics of the MongoDB implementation.
+type ComparisonRepository struct {
+	mu          sync.RWMutex
+	comparisons []models.Comparison // kept in insertion order, like a collection's natural order
+}
+
+// NewComparisonRepository creates a new, empty instance of ComparisonRepository
+func NewComparisonRepository() repository.IComparisonRepository {
+	return &ComparisonRepository{}
+}
+
+// InsertComparison inserts a new saved comparison into the store
+func (r *ComparisonRepository) InsertComparison(ctx context.Context, comparison *models.Comparison) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *comparison
+	stored.ID = primitive.NewObjectID().Hex()
+	stored.Tenant = models.TenantFromContext(ctx)
+	stored.Report1 = normalizeReport(stored.Report1)
+	stored.Report2 = normalizeReport(stored.Report2)
+	stored.CreatedAt = normalizeTime(stored.CreatedAt)
+
+	r.comparisons = append(r.comparisons, stored)
+	return stored.ID, nil
+}
+
+// FindComparisonByID retrieves a saved comparison by its ID
+func (r *ComparisonRepository) FindComparisonByID(ctx context.Context, id string) (*models.Comparison, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.indexOf(ctx, id)
+	if i < 0 {
+		return nil, fmt.Errorf("comparison not found")
+	}
+
+	comparison := r.comparisons[i]
+	return &comparison, nil
+}
+
+// FindComparisons retrieves saved comparisons, newest first
+func (r *ComparisonRepository) FindComparisons(ctx context.Context, limit, offset int) ([]models.Comparison, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	comparisons := r.snapshot(ctx)
+	sort.SliceStable(comparisons, func(i, j int) bool {
+		if c := compareTimes(comparisons[i].CreatedAt, comparisons[j].CreatedAt); c != 0 {
+			return c > 0
+		}
+		return comparisons[i].ID > comparisons[j].ID
+	})
+
+	if offset >= len(comparisons) {
+		return []models.Comparison{}, nil
+	}
+	comparisons = comparisons[offset:]
+	if limit > 0 && len(comparisons) > limit {
+		comparisons = comparisons[:limit]
+	}
+	return comparisons, nil
+}
+
+// CountComparisons counts the total number of saved comparisons
+func (r *ComparisonRepository) CountComparisons(ctx context.Context) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return int64(len(r.snapshot(ctx))), nil
+}
+
+// DeleteComparison deletes a saved comparison by its ID
+func (r *ComparisonRepository) DeleteComparison(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(ctx, id)
+	if i < 0 {
+		return fmt.Errorf("comparison not found")
+	}
+
+	r.comparisons = append(r.comparisons[:i], r.comparisons[i+1:]...)
+	return nil
+}
+
+// indexOf returns the position of the comparison of the tenant of ctx with the given ID, or -1. Callers must hold the lock.
+func (r *ComparisonRepository) indexOf(ctx context.Context, id string) int {
+	tenant := models.TenantFromContext(ctx)
+	for i := range r.comparisons {
+		if r.comparisons[i].ID == id && r.comparisons[i].Tenant == tenant {
+			return i
+		}
+	}
+	return -1
+}
+
+// snapshot copies the comparisons of the tenant of ctx. Callers must hold the lock.
+func (r *ComparisonRepository) snapshot(ctx context.Context) []models.Comparison {
+	tenant := models.TenantFromContext(ctx)
+	comparisons := []models.Comparison{}
+	for _, comparison := range r.comparisons {
+		if comparison.Tenant == tenant {
+			comparisons = append(comparisons, comparison)
+		}
+	}
+	return comparisons
+}
+
+// Ensure that ComparisonRepository implements the interface
+var _ repository.IComparisonRepository = (*ComparisonRepository)(nil)
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+)
+
+// rateLimitSweepInterval is how often buckets that have filled up again are forgotten
+const rateLimitSweepInterval = time.Minute
+
+// RateLimitRepository implements the IRateLimitRepository interface in memory. Its buckets are local to
+// the process, so every replica of the API limits clients on its own.
+// It is safe for concurrent use and mirrors the semantics of the MongoDB implementation.
+type RateLimitRepository struct {
+	mu        sync.Mutex
+	fullAt    map[string]time.Time // When each bucket is full again; full buckets are swept
+	nextSweep time.Time
+}
+
+// NewRateLimitRepository creates a new instance of RateLimitRepository whose buckets are all full
+func NewRateLimitRepository() repository.IRateLimitRepository {
+	return &RateLimitRepository{fullAt: make(map[string]time.Time)}
+}
+
+// TakeToken takes a token at now from the bucket of key
+func (r *RateLimitRepository) TakeToken(ctx context.Context, key string, limit models.RateLimit, now time.Time) (*models.RateLimitResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now = normalizeTime(now)
+	if now.After(r.nextSweep) {
+		for bucket, fullAt := range r.fullAt {
+			if !fullAt.After(now) {
+				delete(r.fullAt, bucket)
+			}
+		}
+		r.nextSweep = now.Add(rateLimitSweepInterval)
+	}
+
+	fullAt, result := limit.Take(r.fullAt[key], now)
+	r.fullAt[key] = fullAt
+	return &result, nil
+}
+
+// Ensure that RateLimitRepository implements the interface
+var _ repository.IRateLimitRepository = (*RateLimitRepository)(nil)
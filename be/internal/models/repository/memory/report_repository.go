@@ -0,0 +1,580 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReportRepository implements the IReportRepository interface in memory.
+// It is safe for concurrent use and mirrors the semantics of the MongoDB implementation.
+type ReportRepository struct {
+	mu      sync.RWMutex
+	reports []models.WeatherReport // kept in insertion order, like a collection's natural order
+}
+
+// NewReportRepository creates a new, empty instance of ReportRepository
+func NewReportRepository() repository.IReportRepository {
+	return &ReportRepository{}
+}
+
+// InsertReport inserts a new weather report into the store
+func (r *ReportRepository) InsertReport(ctx context.Context, report *models.WeatherReport) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := normalizeReport(*report)
+	stored.Tenant = models.TenantFromContext(ctx)
+	if stored.ID == "" {
+		stored.ID = primitive.NewObjectID().Hex()
+	} else if r.hasID(stored.ID) {
+		return "", fmt.Errorf("failed to save report: duplicate id %s", stored.ID)
+	}
+
+	r.reports = append(r.reports, stored)
+	return stored.ID, nil
+}
+
+// InsertReports inserts weather reports and returns their IDs in order. Nothing is inserted if an ID is taken.
+func (r *ReportRepository) InsertReports(ctx context.Context, reports []models.WeatherReport) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenant := models.TenantFromContext(ctx)
+	batch := make([]models.WeatherReport, len(reports))
+	ids := make([]string, len(reports))
+	for i, report := range reports {
+		stored := normalizeReport(report)
+		stored.Tenant = tenant
+		if stored.ID == "" {
+			stored.ID = primitive.NewObjectID().Hex()
+		} else if r.hasID(stored.ID) || contains(ids[:i], stored.ID) {
+			return nil, fmt.Errorf("failed to save reports: duplicate id %s", stored.ID)
+		}
+		batch[i] = stored
+		ids[i] = stored.ID
+	}
+
+	r.reports = append(r.reports, batch...)
+	return ids, nil
+}
+
+// FindAllReports retrieves all weather reports, newest first
+func (r *ReportRepository) FindAllReports(ctx context.Context) ([]models.WeatherReport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reports := r.snapshot(ctx, isActive)
+	sortReports(reports, sortKey{"timestamp", -1})
+	return reports, nil
+}
+
+// FindPaginatedReports retrieves weather reports with pagination and filtering
+func (r *ReportRepository) FindPaginatedReports(ctx context.Context, req *request.PaginatedReportsRequest) (*response.PaginatedReportsResponse, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	limit := repository.PageLimit(req)
+
+	keys, err := req.EffectiveSort()
+	if err != nil {
+		return nil, fmt.Errorf("invalid sort: %w", err)
+	}
+	if err := repository.ValidateCursor(req, keys); err != nil {
+		return nil, err
+	}
+
+	matched := r.snapshot(ctx, func(report models.WeatherReport) bool {
+		return matchesRequest(report, req)
+	})
+	totalCount := len(matched)
+
+	// A prev cursor walks the sort order backwards from the cursor position
+	backward := req.Cursor != nil && req.Cursor.Direction == request.CursorPrev
+	order := toSortKeys(keys, backward)
+	sortReports(matched, order...)
+
+	var rows []models.WeatherReport
+	if req.Cursor != nil {
+		boundary := req.Cursor.Boundary()
+		for _, report := range matched {
+			if compareByKeys(&report, &boundary, order) > 0 {
+				rows = append(rows, report)
+			}
+		}
+	} else if req.Offset < len(matched) {
+		rows = matched[req.Offset:]
+	}
+
+	// Fetch one extra to check if there are more
+	if len(rows) > limit+1 {
+		rows = rows[:limit+1]
+	}
+
+	page := repository.BuildReportPage(append([]models.WeatherReport{}, rows...), req, keys)
+	page.TotalCount = -1
+	if !req.SkipTotalCount {
+		page.TotalCount = totalCount
+	}
+
+	return page, nil
+}
+
+// StreamReports passes every report matching the filters and sort of req to fn.
+// The matching reports are copied first, so fn may call back into the repository.
+func (r *ReportRepository) StreamReports(ctx context.Context, req *request.PaginatedReportsRequest, fn func(*models.WeatherReport) error) error {
+	keys, err := req.EffectiveSort()
+	if err != nil {
+		return fmt.Errorf("invalid sort: %w", err)
+	}
+
+	r.mu.RLock()
+	matched := r.snapshot(ctx, func(report models.WeatherReport) bool {
+		return matchesRequest(report, req)
+	})
+	r.mu.RUnlock()
+
+	sortReports(matched, toSortKeys(keys, false)...)
+	for i := range matched {
+		if err := fn(&matched[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindReportByID retrieves a weather report by its ID
+func (r *ReportRepository) FindReportByID(ctx context.Context, id string) (*models.WeatherReport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.indexOf(ctx, id)
+	if i < 0 || !isActive(r.reports[i]) {
+		return nil, fmt.Errorf("report not found")
+	}
+
+	report := r.reports[i]
+	return &report, nil
+}
+
+// FindReportsByIDs retrieves the weather reports with the given IDs
+func (r *ReportRepository) FindReportsByIDs(ctx context.Context, ids []string) ([]models.WeatherReport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.snapshot(ctx, func(report models.WeatherReport) bool {
+		return isActive(report) && contains(ids, report.ID)
+	}), nil
+}
+
+// FindExistingTimestamps returns which of the given timestamps already have a report, including deleted ones
+func (r *ReportRepository) FindExistingTimestamps(ctx context.Context, timestamps []time.Time) ([]time.Time, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenant := models.TenantFromContext(ctx)
+	existing := []time.Time{}
+	for _, timestamp := range timestamps {
+		timestamp = normalizeTime(timestamp)
+		for _, report := range r.reports {
+			if report.Tenant == tenant && report.Timestamp.Equal(timestamp) {
+				existing = append(existing, report.Timestamp)
+				break
+			}
+		}
+	}
+	return existing, nil
+}
+
+// AggregateReports calculates the statistics of every metric over the reports with a timestamp in [from, to]
+func (r *ReportRepository) AggregateReports(ctx context.Context, from, to time.Time) (*response.PeriodStatistics, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reports := r.snapshot(ctx, func(report models.WeatherReport) bool {
+		return isActive(report) && !report.Timestamp.Before(from) && !report.Timestamp.After(to)
+	})
+
+	stats := &response.PeriodStatistics{
+		From:    from,
+		To:      to,
+		Metrics: map[models.Metric]response.MetricStatistics{},
+	}
+	addStatistics(stats, reports)
+	return stats, nil
+}
+
+// AggregateReportBuckets calculates the statistics of every metric per bucket of the reports with a timestamp in [from, to]
+func (r *ReportRepository) AggregateReportBuckets(ctx context.Context, from, to time.Time, bucket time.Duration) ([]response.PeriodStatistics, error) {
+	r.mu.RLock()
+	reports := r.snapshot(ctx, func(report models.WeatherReport) bool {
+		return isActive(report) && !report.Timestamp.Before(from) && !report.Timestamp.After(to)
+	})
+	r.mu.RUnlock()
+
+	byBucket := map[int][]models.WeatherReport{}
+	for _, report := range reports {
+		i := int(report.Timestamp.Sub(from) / bucket)
+		byBucket[i] = append(byBucket[i], report)
+	}
+
+	buckets := make([]response.PeriodStatistics, 0, len(byBucket))
+	for i, bucketReports := range byBucket {
+		start := from.Add(time.Duration(i) * bucket)
+		end := start.Add(bucket)
+		if end.After(to) {
+			end = to
+		}
+		stats := response.PeriodStatistics{From: start, To: end, Metrics: map[models.Metric]response.MetricStatistics{}}
+		addStatistics(&stats, bucketReports)
+		buckets = append(buckets, stats)
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].From.Before(buckets[j].From) })
+	return buckets, nil
+}
+
+// addStatistics sets the count and the statistics of every metric of reports, if there are any
+func addStatistics(stats *response.PeriodStatistics, reports []models.WeatherReport) {
+	stats.Count = len(reports)
+	if len(reports) == 0 {
+		return
+	}
+
+	for _, metric := range models.Metrics {
+		metricStats := response.MetricStatistics{Min: reports[0].Value(metric), Max: reports[0].Value(metric)}
+
+		var sum float64
+		for i := range reports {
+			value := reports[i].Value(metric)
+			metricStats.Min = math.Min(metricStats.Min, value)
+			metricStats.Max = math.Max(metricStats.Max, value)
+			sum += value
+		}
+		metricStats.Mean = sum / float64(len(reports))
+
+		// Population standard deviation, like $stdDevPop
+		var squares float64
+		for i := range reports {
+			diff := reports[i].Value(metric) - metricStats.Mean
+			squares += diff * diff
+		}
+		metricStats.StdDev = math.Sqrt(squares / float64(len(reports)))
+		metricStats.Range = metricStats.Max - metricStats.Min
+
+		stats.Metrics[metric] = metricStats
+	}
+}
+
+// CountReports counts the total number of reports
+func (r *ReportRepository) CountReports(ctx context.Context) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return int64(len(r.snapshot(ctx, isActive))), nil
+}
+
+// UpdateReport sets the annotations given in update, leaving nil fields unchanged
+func (r *ReportRepository) UpdateReport(ctx context.Context, id string, update *request.ReportUpdateRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(ctx, id)
+	if i < 0 || !isActive(r.reports[i]) {
+		return fmt.Errorf("report not found")
+	}
+
+	if update.Tags != nil {
+		r.reports[i].Tags = nil
+		if len(*update.Tags) > 0 {
+			r.reports[i].Tags = append([]string{}, *update.Tags...)
+		}
+	}
+	if update.Notes != nil {
+		r.reports[i].Notes = *update.Notes
+	}
+	return nil
+}
+
+// FindTagCounts counts the reports of every tag, most used first and then by tag
+func (r *ReportRepository) FindTagCounts(ctx context.Context) ([]response.TagCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	countByTag := make(map[string]int)
+	for _, report := range r.snapshot(ctx, isActive) {
+		for _, tag := range report.Tags {
+			countByTag[tag]++
+		}
+	}
+
+	counts := make([]response.TagCount, 0, len(countByTag))
+	for tag, count := range countByTag {
+		counts = append(counts, response.TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Tag < counts[j].Tag
+	})
+	return counts, nil
+}
+
+// DeleteReport soft-deletes a report by setting its deletedAt
+func (r *ReportRepository) DeleteReport(ctx context.Context, id string, deletedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(ctx, id)
+	if i < 0 || !isActive(r.reports[i]) {
+		return fmt.Errorf("report not found")
+	}
+
+	deletedAt = normalizeTime(deletedAt)
+	r.reports[i].DeletedAt = &deletedAt
+	return nil
+}
+
+// RestoreReport clears the deletedAt of a soft-deleted report
+func (r *ReportRepository) RestoreReport(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(ctx, id)
+	if i < 0 || isActive(r.reports[i]) {
+		return fmt.Errorf("deleted report not found")
+	}
+
+	r.reports[i].DeletedAt = nil
+	return nil
+}
+
+// PurgeDeletedReports permanently removes the reports of every tenant deleted at or before the given time
+func (r *ReportRepository) PurgeDeletedReports(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.reports[:0]
+	for _, report := range r.reports {
+		if report.DeletedAt == nil || report.DeletedAt.After(before) {
+			kept = append(kept, report)
+		}
+	}
+
+	purged := int64(len(r.reports) - len(kept))
+	r.reports = kept
+	return purged, nil
+}
+
+// indexOf returns the position of the report of the tenant of ctx with the given ID, or -1. Callers must hold the lock.
+func (r *ReportRepository) indexOf(ctx context.Context, id string) int {
+	tenant := models.TenantFromContext(ctx)
+	for i := range r.reports {
+		if r.reports[i].ID == id && r.reports[i].Tenant == tenant {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasID reports whether any tenant has a report with the given ID. Callers must hold the lock.
+func (r *ReportRepository) hasID(id string) bool {
+	for i := range r.reports {
+		if r.reports[i].ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// isActive reports whether a report is not soft-deleted
+func isActive(report models.WeatherReport) bool {
+	return report.DeletedAt == nil
+}
+
+// snapshot copies the reports of the tenant of ctx accepted by keep. Callers must hold the lock.
+func (r *ReportRepository) snapshot(ctx context.Context, keep func(models.WeatherReport) bool) []models.WeatherReport {
+	tenant := models.TenantFromContext(ctx)
+	reports := []models.WeatherReport{}
+	for _, report := range r.reports {
+		if report.Tenant == tenant && keep(report) {
+			reports = append(reports, report)
+		}
+	}
+	return reports
+}
+
+// sortKey is a bson field name and its order (1 ascending, -1 descending)
+type sortKey struct {
+	field string
+	order int
+}
+
+// sortReports stable-sorts reports by bson field names. Unknown fields compare as equal,
+// which leaves the natural order untouched just like MongoDB does for missing fields.
+func sortReports(reports []models.WeatherReport, keys ...sortKey) {
+	sort.SliceStable(reports, func(i, j int) bool {
+		return compareByKeys(&reports[i], &reports[j], keys) < 0
+	})
+}
+
+// compareByKeys compares two reports in the order defined by keys
+func compareByKeys(a, b *models.WeatherReport, keys []sortKey) int {
+	for _, key := range keys {
+		if c := compareReportField(a, b, key.field); c != 0 {
+			return c * key.order
+		}
+	}
+	return 0
+}
+
+// toSortKeys converts request sort keys, optionally reversed
+func toSortKeys(keys []request.SortKey, reverse bool) []sortKey {
+	converted := make([]sortKey, len(keys))
+	for i, key := range keys {
+		converted[i] = sortKey{field: key.Field, order: key.Direction()}
+		if reverse {
+			converted[i].order = -converted[i].order
+		}
+	}
+	return converted
+}
+
+// matchesRequest reports whether a report satisfies the filters of a paginated request
+func matchesRequest(report models.WeatherReport, req *request.PaginatedReportsRequest) bool {
+	if !req.IncludeDeleted && !isActive(report) {
+		return false
+	}
+	if !req.FromTime.IsZero() && report.Timestamp.Before(req.FromTime) {
+		return false
+	}
+	if !req.ToTime.IsZero() && report.Timestamp.After(req.ToTime) {
+		return false
+	}
+	if len(req.Locations) > 0 && !contains(req.Locations, report.Location) {
+		return false
+	}
+	if len(req.Sources) > 0 && !contains(req.Sources, report.Source) {
+		return false
+	}
+	if len(req.Types) > 0 && !contains(req.Types, report.Type) {
+		return false
+	}
+	if len(req.Tags) > 0 && !containsAny(req.Tags, report.Tags) {
+		return false
+	}
+	if len(req.Conditions) == 0 {
+		return true
+	}
+
+	for _, condition := range req.Conditions {
+		matched := condition.Matches(report.Value(condition.Metric))
+		if req.Logic == request.LogicOr && matched {
+			return true
+		}
+		if req.Logic != request.LogicOr && !matched {
+			return false
+		}
+	}
+	return req.Logic != request.LogicOr
+}
+
+// contains reports whether values contains value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAny reports whether values contains any of candidates
+func containsAny(values, candidates []string) bool {
+	for _, candidate := range candidates {
+		if contains(values, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// compareReportField compares two reports on the given bson field name
+func compareReportField(a, b *models.WeatherReport, field string) int {
+	switch field {
+	case "_id":
+		return compareStrings(a.ID, b.ID)
+	case "timestamp":
+		return compareTimes(a.Timestamp, b.Timestamp)
+	case "createdAt":
+		return compareTimes(a.CreatedAt, b.CreatedAt)
+	case "temperature":
+		return compareFloats(a.Temperature, b.Temperature)
+	case "pressure":
+		return compareFloats(a.Pressure, b.Pressure)
+	case "humidity":
+		return compareFloats(a.Humidity, b.Humidity)
+	case "cloudCover":
+		return compareFloats(a.CloudCover, b.CloudCover)
+	case "location":
+		return compareStrings(a.Location, b.Location)
+	case "source":
+		return compareStrings(a.Source, b.Source)
+	case "type":
+		return compareStrings(a.Type, b.Type)
+	}
+	return 0
+}
+
+// normalizeReport applies the same time normalization a BSON round trip would
+func normalizeReport(report models.WeatherReport) models.WeatherReport {
+	report.Timestamp = normalizeTime(report.Timestamp)
+	report.CreatedAt = normalizeTime(report.CreatedAt)
+	report.Tags = append([]string(nil), report.Tags...) // Empty tags are omitted like in BSON
+	if report.DeletedAt != nil {
+		deletedAt := normalizeTime(*report.DeletedAt)
+		report.DeletedAt = &deletedAt
+	}
+	return report
+}
+
+// normalizeTime truncates to millisecond precision in UTC, as BSON datetimes do
+func normalizeTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Millisecond)
+}
+
+func compareTimes(a, b time.Time) int {
+	return a.Compare(b)
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareStrings(a, b string) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Ensure that ReportRepository implements the interface
+var _ repository.IReportRepository = (*ReportRepository)(nil)
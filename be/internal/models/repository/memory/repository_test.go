@@ -0,0 +1,22 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository/repositorytest"
+)
+
+func TestRepositoryConformance(t *testing.T) {
+	repositorytest.RunBackendTests(t, func(t *testing.T) repositorytest.Repositories {
+		return repositorytest.Repositories{
+			Reports:         NewReportRepository(),
+			WeatherCaches:   NewWeatherCacheRepository(),
+			Comparisons:     NewComparisonRepository(),
+			Thresholds:      NewThresholdRepository(),
+			APIKeys:         NewAPIKeyRepository(),
+			RoleAssignments: NewRoleAssignmentRepository(),
+			RateLimits:      NewRateLimitRepository(),
+			AuditLogs:       NewAuditLogRepository(),
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+)
+
+// RoleAssignmentRepository implements the IRoleAssignmentRepository interface in memory.
+// It is safe for concurrent use and mirrors the semantics of the MongoDB implementation.
+type RoleAssignmentRepository struct {
+	mu          sync.RWMutex
+	assignments map[string]models.RoleAssignment // by ID
+}
+
+// NewRoleAssignmentRepository creates a new, empty instance of RoleAssignmentRepository
+func NewRoleAssignmentRepository() repository.IRoleAssignmentRepository {
+	return &RoleAssignmentRepository{
+		assignments: make(map[string]models.RoleAssignment),
+	}
+}
+
+// FindRoleAssignment retrieves the role assignment of a caller
+func (r *RoleAssignmentRepository) FindRoleAssignment(ctx context.Context, method models.AuthMethod, subject string) (*models.RoleAssignment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	assignment, ok := r.assignments[models.RoleAssignmentID(models.TenantFromContext(ctx), method, subject)]
+	if !ok {
+		return nil, fmt.Errorf("role assignment not found")
+	}
+	return &assignment, nil
+}
+
+// FindRoleAssignments retrieves all role assignments, ordered by method and subject
+func (r *RoleAssignmentRepository) FindRoleAssignments(ctx context.Context) ([]models.RoleAssignment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenant := models.TenantFromContext(ctx)
+	assignments := []models.RoleAssignment{}
+	for _, assignment := range r.assignments {
+		if assignment.Tenant == tenant {
+			assignments = append(assignments, assignment)
+		}
+	}
+	sort.Slice(assignments, func(i, j int) bool {
+		if assignments[i].Method != assignments[j].Method {
+			return assignments[i].Method < assignments[j].Method
+		}
+		return assignments[i].Subject < assignments[j].Subject
+	})
+	return assignments, nil
+}
+
+// UpsertRoleAssignment creates or replaces the role assignment of a caller
+func (r *RoleAssignmentRepository) UpsertRoleAssignment(ctx context.Context, assignment *models.RoleAssignment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *assignment
+	stored.Tenant = models.TenantFromContext(ctx)
+	stored.ID = models.RoleAssignmentID(stored.Tenant, stored.Method, stored.Subject)
+	stored.UpdatedAt = normalizeTime(stored.UpdatedAt)
+	r.assignments[stored.ID] = stored
+	return nil
+}
+
+// DeleteRoleAssignment removes the role assignment of a caller
+func (r *RoleAssignmentRepository) DeleteRoleAssignment(ctx context.Context, method models.AuthMethod, subject string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := models.RoleAssignmentID(models.TenantFromContext(ctx), method, subject)
+	if _, ok := r.assignments[id]; !ok {
+		return fmt.Errorf("role assignment not found")
+	}
+	delete(r.assignments, id)
+	return nil
+}
+
+// Ensure that RoleAssignmentRepository implements the interface
+var _ repository.IRoleAssignmentRepository = (*RoleAssignmentRepository)(nil)
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+)
+
+// ThresholdRepository implements the IThresholdRepository interface in memory.
+// It is safe for concurrent use and mirrors the semantics of the MongoDB implementation.
+type ThresholdRepository struct {
+	mu         sync.RWMutex
+	thresholds []models.Threshold // kept in insertion order, like a collection's natural order
+}
+
+// NewThresholdRepository creates a new, empty instance of ThresholdRepository
+func NewThresholdRepository() repository.IThresholdRepository {
+	return &ThresholdRepository{}
+}
+
+// FindThresholds retrieves all configured thresholds
+func (r *ThresholdRepository) FindThresholds(ctx context.Context) ([]models.Threshold, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenant := models.TenantFromContext(ctx)
+	thresholds := []models.Threshold{}
+	for _, threshold := range r.thresholds {
+		if threshold.Tenant == tenant {
+			thresholds = append(thresholds, threshold)
+		}
+	}
+	return thresholds, nil
+}
+
+// UpsertThreshold creates or replaces the threshold of a metric
+func (r *ThresholdRepository) UpsertThreshold(ctx context.Context, threshold *models.Threshold) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *threshold
+	stored.Tenant = models.TenantFromContext(ctx)
+	stored.ID = models.ThresholdID(stored.Tenant, stored.Metric)
+	stored.IsDefault = false // Not persisted
+	if stored.UpdatedAt != nil {
+		updatedAt := normalizeTime(*stored.UpdatedAt)
+		stored.UpdatedAt = &updatedAt
+	}
+
+	for i := range r.thresholds {
+		if r.thresholds[i].ID == stored.ID {
+			r.thresholds[i] = stored
+			return nil
+		}
+	}
+	r.thresholds = append(r.thresholds, stored)
+	return nil
+}
+
+// DeleteThreshold removes the configured threshold of a metric
+func (r *ThresholdRepository) DeleteThreshold(ctx context.Context, metric models.Metric) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := models.ThresholdID(models.TenantFromContext(ctx), metric)
+	for i := range r.thresholds {
+		if r.thresholds[i].ID == id {
+			r.thresholds = append(r.thresholds[:i], r.thresholds[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// Ensure that ThresholdRepository implements the interface
+var _ repository.IThresholdRepository = (*ThresholdRepository)(nil)
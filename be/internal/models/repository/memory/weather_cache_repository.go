@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WeatherCacheRepository implements the IWeatherCacheRepository interface in memory.
+// It is safe for concurrent use and mirrors the semantics of the MongoDB implementation.
+type WeatherCacheRepository struct {
+	mu      sync.RWMutex
+	entries []models.WeatherCache // kept in insertion order, like a collection's natural order
+}
+
+// NewWeatherCacheRepository creates a new, empty instance of WeatherCacheRepository
+func NewWeatherCacheRepository() repository.IWeatherCacheRepository {
+	return &WeatherCacheRepository{}
+}
+
+// SaveWeatherCache saves a weather data cache entry
+func (r *WeatherCacheRepository) SaveWeatherCache(ctx context.Context, cache *models.WeatherCache) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *cache
+	stored.ID = primitive.NewObjectID().Hex()
+	stored.Tenant = models.TenantFromContext(ctx)
+	stored.Timestamp = normalizeTime(stored.Timestamp)
+	stored.CreatedAt = normalizeTime(stored.CreatedAt)
+
+	r.entries = append(r.entries, stored)
+	return stored.ID, nil
+}
+
+// FindLatestWeatherCache retrieves the latest valid weather cache entry.
+// Cache entries carry no expiry, so just like the MongoDB filter on expiresAt
+// no entry is ever considered live and nil is returned.
+func (r *WeatherCacheRepository) FindLatestWeatherCache(ctx context.Context) (*models.WeatherCache, error) {
+	return nil, nil
+}
+
+// FindWeatherCacheByTimestamp retrieves a weather cache entry by timestamp within a time window
+func (r *WeatherCacheRepository) FindWeatherCacheByTimestamp(ctx context.Context, timestamp time.Time, windowMinutes ...int) (*models.WeatherCache, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	window := 10 // Default window in minutes
+	if len(windowMinutes) > 0 {
+		window = windowMinutes[0]
+	}
+	windowStart := timestamp.Add(-time.Duration(window) * time.Minute)
+	windowEnd := timestamp.Add(time.Duration(window) * time.Minute)
+
+	tenant := models.TenantFromContext(ctx)
+	for _, entry := range r.entries {
+		if entry.Tenant != tenant || entry.Timestamp.Before(windowStart) || entry.Timestamp.After(windowEnd) {
+			continue
+		}
+		found := entry
+		return &found, nil
+	}
+
+	return nil, nil // No cache found, not an error
+}
+
+// DeleteExpiredCaches removes expired cache entries. Entries carry no expiry, so nothing is removed.
+func (r *WeatherCacheRepository) DeleteExpiredCaches(ctx context.Context) error {
+	return nil
+}
+
+// StreamWeatherCaches calls fn for every cache entry in the time range in timestamp order
+func (r *WeatherCacheRepository) StreamWeatherCaches(ctx context.Context, from, to time.Time, fn func(*models.WeatherCache) error) error {
+	tenant := models.TenantFromContext(ctx)
+	r.mu.RLock()
+	var entries []models.WeatherCache
+	for _, entry := range r.entries {
+		if entry.Tenant != tenant {
+			continue
+		}
+		if !from.IsZero() && entry.Timestamp.Before(from) {
+			continue
+		}
+		if !to.IsZero() && entry.Timestamp.After(to) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	r.mu.RUnlock()
+
+	// Stable, so entries with equal timestamps keep insertion order like the _id tie-break in MongoDB
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Timestamp.Before(entries[j].Timestamp) })
+
+	for i := range entries {
+		if err := fn(&entries[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Ensure that WeatherCacheRepository implements the interface
+var _ repository.IWeatherCacheRepository = (*WeatherCacheRepository)(nil)
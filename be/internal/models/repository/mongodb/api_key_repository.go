@@ -0,0 +1,119 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoAPIKeyRepository implements the IAPIKeyRepository interface for MongoDB
+type MongoAPIKeyRepository struct {
+	db         IDatabase
+	collection ICollection
+}
+
+// NewMongoAPIKeyRepository creates a new instance of MongoAPIKeyRepository
+func NewMongoAPIKeyRepository(db IDatabase) repository.IAPIKeyRepository {
+	return &MongoAPIKeyRepository{
+		db:         db,
+		collection: db.Collection("api_keys"),
+	}
+}
+
+// InsertAPIKey inserts a new API key into the database
+func (r *MongoAPIKeyRepository) InsertAPIKey(ctx context.Context, key *models.APIKey) (string, error) {
+	key.Tenant = models.TenantFromContext(ctx)
+	result, err := r.collection.InsertOne(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to save api key: %w", err)
+	}
+
+	// Convert ObjectID to string
+	objectID := result.InsertedID.(primitive.ObjectID)
+	return objectID.Hex(), nil
+}
+
+// FindAPIKeyByID retrieves an API key by its ID, including revoked keys
+func (r *MongoAPIKeyRepository) FindAPIKeyByID(ctx context.Context, id string) (*models.APIKey, error) {
+	return r.findOne(ctx, withTenant(ctx, bson.M{"_id": idValue(id)}))
+}
+
+// FindAPIKeyByHash retrieves the API key with the given hash of any tenant, including revoked keys
+func (r *MongoAPIKeyRepository) FindAPIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	return r.findOne(ctx, bson.M{"hash": hash})
+}
+
+// findOne retrieves the API key matching filter
+func (r *MongoAPIKeyRepository) findOne(ctx context.Context, filter bson.M) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.collection.FindOne(ctx, filter).Decode(&key)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("api key not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve api key: %w", err)
+	}
+
+	return &key, nil
+}
+
+// FindAPIKeys retrieves all API keys, revoked ones included, newest first
+func (r *MongoAPIKeyRepository) FindAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, withTenant(ctx, bson.M{}), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve api keys: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	keys := []models.APIKey{}
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, fmt.Errorf("failed to decode api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey marks an API key as revoked at revokedAt. Revoking a revoked key keeps its original time.
+func (r *MongoAPIKeyRepository) RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error {
+	filter := withTenant(ctx, bson.M{"_id": idValue(id), "revokedAt": nil})
+	update := bson.M{"$set": bson.M{"revokedAt": revokedAt}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	// Nothing matched: the key is either already revoked or does not exist
+	count, err := r.collection.CountDocuments(ctx, withTenant(ctx, bson.M{"_id": idValue(id)}))
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("api key not found")
+	}
+	return nil
+}
+
+// UpdateAPIKeyLastUsed records that an API key was used at usedAt
+func (r *MongoAPIKeyRepository) UpdateAPIKeyLastUsed(ctx context.Context, id string, usedAt time.Time) error {
+	result, err := r.collection.UpdateOne(ctx, withTenant(ctx, bson.M{"_id": idValue(id)}), bson.M{"$set": bson.M{"lastUsedAt": usedAt}})
+	if err != nil {
+		return fmt.Errorf("failed to update api key: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("api key not found")
+	}
+	return nil
+}
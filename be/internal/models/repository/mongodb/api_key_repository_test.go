@@ -0,0 +1,141 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestInsertAPIKey(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "api_keys", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoAPIKeyRepository(mockDB)
+
+	ctx := context.Background()
+	key := &models.APIKey{Name: "grafana", Prefix: "wr_abcd", Hash: "hash", CreatedAt: time.Now()}
+
+	objectID := primitive.NewObjectID()
+	mockCollection.On("InsertOne", ctx, key, mock.Anything).Return(&mongo.InsertOneResult{InsertedID: objectID}, nil)
+
+	// Act
+	id, err := repo.InsertAPIKey(ctx, key)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, objectID.Hex(), id)
+	mockCollection.AssertExpectations(t)
+}
+
+func TestFindAPIKeyByHash(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "api_keys", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoAPIKeyRepository(mockDB)
+
+	ctx := context.Background()
+	expected := &models.APIKey{ID: primitive.NewObjectID().Hex(), Name: "grafana", Hash: "hash"}
+	mockCollection.On("FindOne", ctx, bson.M{"hash": "hash"}, mock.Anything).Return(NewMockSingleResult(nil, expected))
+
+	// Act
+	key, err := repo.FindAPIKeyByHash(ctx, "hash")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expected, key)
+	mockCollection.AssertExpectations(t)
+}
+
+func TestFindAPIKeyByHash_NotFound(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "api_keys", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoAPIKeyRepository(mockDB)
+
+	ctx := context.Background()
+	mockCollection.On("FindOne", ctx, mock.Anything, mock.Anything).Return(NewMockSingleResult(mongo.ErrNoDocuments, nil))
+
+	// Act
+	key, err := repo.FindAPIKeyByHash(ctx, "unknown")
+
+	// Assert
+	assert.Nil(t, key)
+	assert.EqualError(t, err, "api key not found")
+	mockCollection.AssertExpectations(t)
+}
+
+func TestRevokeAPIKey_AlreadyRevoked(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "api_keys", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoAPIKeyRepository(mockDB)
+
+	ctx := context.Background()
+	objectID := primitive.NewObjectID()
+	revokedAt := time.Now()
+	mockCollection.On("UpdateOne", ctx, bson.M{"_id": objectID, "revokedAt": nil, "tenant": models.DefaultTenant}, bson.M{"$set": bson.M{"revokedAt": revokedAt}}, mock.Anything).
+		Return(&mongo.UpdateResult{MatchedCount: 0}, nil)
+	mockCollection.On("CountDocuments", ctx, bson.M{"_id": objectID, "tenant": models.DefaultTenant}, mock.Anything).Return(int64(1), nil)
+
+	// Act
+	err := repo.RevokeAPIKey(ctx, objectID.Hex(), revokedAt)
+
+	// Assert
+	assert.NoError(t, err)
+	mockCollection.AssertExpectations(t)
+}
+
+func TestRevokeAPIKey_NotFound(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "api_keys", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoAPIKeyRepository(mockDB)
+
+	ctx := context.Background()
+	mockCollection.On("UpdateOne", ctx, mock.Anything, mock.Anything, mock.Anything).Return(&mongo.UpdateResult{MatchedCount: 0}, nil)
+	mockCollection.On("CountDocuments", ctx, mock.Anything, mock.Anything).Return(int64(0), nil)
+
+	// Act
+	err := repo.RevokeAPIKey(ctx, primitive.NewObjectID().Hex(), time.Now())
+
+	// Assert
+	assert.EqualError(t, err, "api key not found")
+	mockCollection.AssertExpectations(t)
+}
+
+func TestUpdateAPIKeyLastUsed_Error(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "api_keys", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoAPIKeyRepository(mockDB)
+
+	ctx := context.Background()
+	mockCollection.On("UpdateOne", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+	// Act
+	err := repo.UpdateAPIKeyLastUsed(ctx, primitive.NewObjectID().Hex(), time.Now())
+
+	// Assert
+	assert.EqualError(t, err, "failed to update api key: database error")
+	mockCollection.AssertExpectations(t)
+}
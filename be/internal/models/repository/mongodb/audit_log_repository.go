@@ -0,0 +1,121 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoAuditLogRepository implements the IAuditLogRepository interface for MongoDB.
+// Entries are ordered by their ObjectID, which the driver generates in insertion order.
+type MongoAuditLogRepository struct {
+	db         IDatabase
+	collection ICollection
+}
+
+// NewMongoAuditLogRepository creates a new instance of MongoAuditLogRepository
+func NewMongoAuditLogRepository(db IDatabase) repository.IAuditLogRepository {
+	return &MongoAuditLogRepository{
+		db:         db,
+		collection: db.Collection("audit_log"),
+	}
+}
+
+// InsertAuditEntry appends an entry to the log
+func (r *MongoAuditLogRepository) InsertAuditEntry(ctx context.Context, entry *models.AuditEntry) (string, error) {
+	entry.Tenant = models.TenantFromContext(ctx)
+	result, err := r.collection.InsertOne(ctx, entry)
+	if err != nil {
+		return "", fmt.Errorf("failed to save audit entry: %w", err)
+	}
+
+	objectID := result.InsertedID.(primitive.ObjectID)
+	return objectID.Hex(), nil
+}
+
+// FindAuditEntries retrieves up to limit matching entries, newest first
+func (r *MongoAuditLogRepository) FindAuditEntries(ctx context.Context, req *request.AuditLogRequest, limit int) ([]models.AuditEntry, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, buildAuditFilter(ctx, req), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve audit entries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	entries := []models.AuditEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode audit entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// StreamAuditEntries decodes matching entries one at a time from the cursor and passes them to fn
+func (r *MongoAuditLogRepository) StreamAuditEntries(ctx context.Context, req *request.AuditLogRequest, fn func(*models.AuditEntry) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, buildAuditFilter(ctx, req), opts)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve audit entries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var entry models.AuditEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return fmt.Errorf("failed to decode audit entry: %w", err)
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to retrieve audit entries: %w", err)
+	}
+	return nil
+}
+
+// buildAuditFilter converts the filters and cursor of req to a MongoDB filter of the tenant of ctx
+func buildAuditFilter(ctx context.Context, req *request.AuditLogRequest) bson.M {
+	filter := withTenant(ctx, bson.M{})
+	if req.Cursor != "" {
+		filter["_id"] = bson.M{"$lt": idValue(req.Cursor)}
+	}
+	if req.Actor != "" {
+		filter["actor.subject"] = req.Actor
+	}
+	if req.Method != "" {
+		filter["method"] = req.Method
+	}
+	if req.Route != "" {
+		filter["route"] = req.Route
+	}
+	if req.ResourceID != "" {
+		filter["resourceId"] = req.ResourceID
+	}
+	switch req.Outcome {
+	case request.AuditOutcomeSuccess:
+		filter["status"] = bson.M{"$lt": 400}
+	case request.AuditOutcomeFailure:
+		filter["status"] = bson.M{"$gte": 400}
+	}
+
+	timeFilter := bson.M{}
+	if !req.From.IsZero() {
+		timeFilter["$gte"] = req.From
+	}
+	if !req.To.IsZero() {
+		timeFilter["$lte"] = req.To
+	}
+	if len(timeFilter) > 0 {
+		filter["time"] = timeFilter
+	}
+	return filter
+}
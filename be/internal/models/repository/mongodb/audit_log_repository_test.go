@@ -0,0 +1,118 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestInsertAuditEntry(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "audit_log", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoAuditLogRepository(mockDB)
+
+	ctx := models.ContextWithTenant(context.Background(), "changi-ops")
+	entry := &models.AuditEntry{Time: time.Now(), Method: "DELETE", Route: "/api/reports/{id}", Status: 204}
+
+	objectID := primitive.NewObjectID()
+	mockCollection.On("InsertOne", ctx, entry, mock.Anything).Return(&mongo.InsertOneResult{InsertedID: objectID}, nil)
+
+	// Act
+	id, err := repo.InsertAuditEntry(ctx, entry)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, objectID.Hex(), id)
+	assert.Equal(t, "changi-ops", entry.Tenant)
+	mockCollection.AssertExpectations(t)
+}
+
+func TestInsertAuditEntry_Error(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "audit_log", mock.Anything).Return(mockCollection)
+	mockCollection.On("InsertOne", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+	repo := NewMongoAuditLogRepository(mockDB)
+
+	// Act
+	id, err := repo.InsertAuditEntry(context.Background(), &models.AuditEntry{})
+
+	// Assert
+	assert.Empty(t, id)
+	assert.EqualError(t, err, "failed to save audit entry: database error")
+}
+
+func TestFindAuditEntries_Filter(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "audit_log", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoAuditLogRepository(mockDB)
+
+	ctx := context.Background()
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	cursorID := primitive.NewObjectID()
+	req := &request.AuditLogRequest{
+		Actor:   "key1",
+		Method:  "DELETE",
+		Route:   "/api/reports/{id}",
+		Outcome: request.AuditOutcomeFailure,
+		From:    from,
+		Cursor:  cursorID.Hex(),
+	}
+	wantFilter := bson.M{
+		"tenant":        models.DefaultTenant,
+		"_id":           bson.M{"$lt": cursorID},
+		"actor.subject": "key1",
+		"method":        "DELETE",
+		"route":         "/api/reports/{id}",
+		"status":        bson.M{"$gte": 400},
+		"time":          bson.M{"$gte": from},
+	}
+
+	mockCursor := NewMockCursor(nil)
+	mockCursor.On("All", ctx, mock.AnythingOfType("*[]models.AuditEntry")).Return(nil)
+	mockCursor.On("Close", ctx).Return(nil)
+	mockCollection.On("Find", ctx, wantFilter, mock.Anything).Return(mockCursor, nil)
+
+	// Act
+	entries, err := repo.FindAuditEntries(ctx, req, 10)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+	mockCollection.AssertExpectations(t)
+	mockCursor.AssertExpectations(t)
+}
+
+func TestFindAuditEntries_Error(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "audit_log", mock.Anything).Return(mockCollection)
+	mockCollection.On("Find", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+	repo := NewMongoAuditLogRepository(mockDB)
+
+	// Act
+	entries, err := repo.FindAuditEntries(context.Background(), &request.AuditLogRequest{}, 10)
+
+	// Assert
+	assert.Nil(t, entries)
+	assert.EqualError(t, err, "failed to retrieve audit entries: database error")
+}
@@ -0,0 +1,97 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoComparisonRepository implements the IComparisonRepository interface for MongoDB
+type MongoComparisonRepository struct {
+	db         IDatabase
+	collection ICollection
+}
+
+// NewMongoComparisonRepository creates a new instance of MongoComparisonRepository
+func NewMongoComparisonRepository(db IDatabase) repository.IComparisonRepository {
+	return &MongoComparisonRepository{
+		db:         db,
+		collection: db.Collection("comparisons"),
+	}
+}
+
+// InsertComparison inserts a new saved comparison into the database
+func (r *MongoComparisonRepository) InsertComparison(ctx context.Context, comparison *models.Comparison) (string, error) {
+	comparison.Tenant = models.TenantFromContext(ctx)
+	result, err := r.collection.InsertOne(ctx, comparison)
+	if err != nil {
+		return "", fmt.Errorf("failed to save comparison: %w", err)
+	}
+
+	// Convert ObjectID to string
+	objectID := result.InsertedID.(primitive.ObjectID)
+	return objectID.Hex(), nil
+}
+
+// FindComparisonByID retrieves a saved comparison by its ID
+func (r *MongoComparisonRepository) FindComparisonByID(ctx context.Context, id string) (*models.Comparison, error) {
+	var comparison models.Comparison
+	err := r.collection.FindOne(ctx, withTenant(ctx, bson.M{"_id": idValue(id)})).Decode(&comparison)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("comparison not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve comparison: %w", err)
+	}
+
+	return &comparison, nil
+}
+
+// FindComparisons retrieves saved comparisons, newest first
+func (r *MongoComparisonRepository) FindComparisons(ctx context.Context, limit, offset int) ([]models.Comparison, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, withTenant(ctx, bson.M{}), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve comparisons: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	comparisons := []models.Comparison{}
+	if err := cursor.All(ctx, &comparisons); err != nil {
+		return nil, fmt.Errorf("failed to decode comparisons: %w", err)
+	}
+
+	return comparisons, nil
+}
+
+// CountComparisons counts the total number of saved comparisons
+func (r *MongoComparisonRepository) CountComparisons(ctx context.Context) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, withTenant(ctx, bson.M{}))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count comparisons: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteComparison deletes a saved comparison by its ID
+func (r *MongoComparisonRepository) DeleteComparison(ctx context.Context, id string) error {
+	result, err := r.collection.DeleteOne(ctx, withTenant(ctx, bson.M{"_id": idValue(id)}))
+	if err != nil {
+		return fmt.Errorf("failed to delete comparison: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("comparison not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestInsertComparison(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "comparisons", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoComparisonRepository(mockDB)
+
+	ctx := context.Background()
+	comparison := &models.Comparison{
+		Title:     "Title",
+		Report1:   models.WeatherReport{ID: "report1"},
+		Report2:   models.WeatherReport{ID: "report2"},
+		CreatedAt: time.Now(),
+	}
+
+	objectID := primitive.NewObjectID()
+	mockCollection.On("InsertOne", ctx, comparison, mock.Anything).Return(&mongo.InsertOneResult{InsertedID: objectID}, nil)
+
+	// Act
+	id, err := repo.InsertComparison(ctx, comparison)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, objectID.Hex(), id)
+	mockCollection.AssertExpectations(t)
+}
+
+func TestFindComparisonByID(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "comparisons", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoComparisonRepository(mockDB)
+
+	ctx := context.Background()
+	objectID := primitive.NewObjectID()
+	expected := &models.Comparison{ID: objectID.Hex(), Title: "Title"}
+
+	mockCollection.On("FindOne", ctx, bson.M{"_id": objectID, "tenant": models.DefaultTenant}, mock.Anything).Return(NewMockSingleResult(nil, expected))
+
+	// Act
+	comparison, err := repo.FindComparisonByID(ctx, objectID.Hex())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expected, comparison)
+	mockCollection.AssertExpectations(t)
+}
+
+func TestFindComparisonByID_NotFound(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "comparisons", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoComparisonRepository(mockDB)
+
+	ctx := context.Background()
+	mockCollection.On("FindOne", ctx, mock.Anything, mock.Anything).Return(NewMockSingleResult(mongo.ErrNoDocuments, nil))
+
+	// Act
+	comparison, err := repo.FindComparisonByID(ctx, primitive.NewObjectID().Hex())
+
+	// Assert
+	assert.Nil(t, comparison)
+	assert.EqualError(t, err, "comparison not found")
+	mockCollection.AssertExpectations(t)
+}
+
+func TestDeleteComparison(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "comparisons", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoComparisonRepository(mockDB)
+
+	ctx := context.Background()
+	objectID := primitive.NewObjectID()
+	mockCollection.On("DeleteOne", ctx, bson.M{"_id": objectID, "tenant": models.DefaultTenant}, mock.Anything).Return(&mongo.DeleteResult{DeletedCount: 1}, nil)
+
+	// Act
+	err := repo.DeleteComparison(ctx, objectID.Hex())
+
+	// Assert
+	assert.NoError(t, err)
+	mockCollection.AssertExpectations(t)
+}
+
+func TestDeleteComparison_NotFound(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "comparisons", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoComparisonRepository(mockDB)
+
+	ctx := context.Background()
+	mockCollection.On("DeleteOne", ctx, mock.Anything, mock.Anything).Return(&mongo.DeleteResult{DeletedCount: 0}, nil)
+
+	// Act
+	err := repo.DeleteComparison(ctx, primitive.NewObjectID().Hex())
+
+	// Assert
+	assert.EqualError(t, err, "comparison not found")
+	mockCollection.AssertExpectations(t)
+}
+
+func TestDeleteComparison_Error(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "comparisons", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoComparisonRepository(mockDB)
+
+	ctx := context.Background()
+	mockCollection.On("DeleteOne", ctx, mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+	// Act
+	err := repo.DeleteComparison(ctx, primitive.NewObjectID().Hex())
+
+	// Assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to delete comparison")
+	mockCollection.AssertExpectations(t)
+}
@@ -0,0 +1,59 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository/repositorytest"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// newTestDatabase connects to the MongoDB instance in MONGO_TEST_URI and returns a
+// throwaway database that is dropped when the test finishes. Tests are skipped when
+// the variable is not set.
+func newTestDatabase(t *testing.T) IDatabase {
+	t.Helper()
+
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGO_TEST_URI not set, skipping MongoDB conformance tests")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	require.NoError(t, err)
+	require.NoError(t, client.Ping(ctx, nil))
+
+	db := client.Database(fmt.Sprintf("conformance_%d", time.Now().UnixNano()))
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = db.Drop(ctx)
+		_ = client.Disconnect(ctx)
+	})
+
+	return NewMongoDatabaseWrapper(db)
+}
+
+func TestMongoRepositoryConformance(t *testing.T) {
+	repositorytest.RunBackendTests(t, func(t *testing.T) repositorytest.Repositories {
+		db := newTestDatabase(t)
+		return repositorytest.Repositories{
+			Reports:         NewMongoReportRepository(db),
+			WeatherCaches:   NewMongoWeatherCacheRepository(db),
+			Comparisons:     NewMongoComparisonRepository(db),
+			Thresholds:      NewMongoThresholdRepository(db),
+			APIKeys:         NewMongoAPIKeyRepository(db),
+			RoleAssignments: NewMongoRoleAssignmentRepository(db),
+			RateLimits:      NewMongoRateLimitRepository(db),
+			AuditLogs:       NewMongoAuditLogRepository(db),
+		}
+	})
+}
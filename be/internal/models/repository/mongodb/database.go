@@ -19,6 +19,9 @@ type ICursor interface {
 
 	// All decodes all documents from the cursor into the provided slice
 	All(ctx context.Context, results interface{}) error
+
+	// Err returns the error that stopped Next, if any
+	Err() error
 }
 
 // ICollection defines the interface for MongoDB collection operations
@@ -26,12 +29,27 @@ type ICollection interface {
 	// InsertOne inserts a single document into the collection
 	InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error)
 
+	// InsertMany inserts multiple documents into the collection
+	InsertMany(ctx context.Context, documents []interface{}, opts ...*options.InsertManyOptions) (*mongo.InsertManyResult, error)
+
 	// FindOne finds a single document in the collection
 	FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) ISingleResult
 
 	// Find finds all documents in the collection that match the filter
 	Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (ICursor, error)
 
+	// UpdateOne updates a single document in the collection that matches the filter
+	UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error)
+
+	// FindOneAndUpdate updates a single document that matches the filter and returns it
+	FindOneAndUpdate(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) ISingleResult
+
+	// Aggregate runs an aggregation pipeline on the collection
+	Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (ICursor, error)
+
+	// DeleteOne deletes a single document from the collection that matches the filter
+	DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error)
+
 	// DeleteMany deletes all documents from the collection that match the filter
 	DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error)
 
@@ -63,6 +81,10 @@ func (w *MongoCollectionWrapper) InsertOne(ctx context.Context, document interfa
 	return w.coll.InsertOne(ctx, document, opts...)
 }
 
+func (w *MongoCollectionWrapper) InsertMany(ctx context.Context, documents []interface{}, opts ...*options.InsertManyOptions) (*mongo.InsertManyResult, error) {
+	return w.coll.InsertMany(ctx, documents, opts...)
+}
+
 func (w *MongoCollectionWrapper) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) ISingleResult {
 	return &MongoSingleResultWrapper{result: w.coll.FindOne(ctx, filter, opts...)}
 }
@@ -75,6 +97,26 @@ func (w *MongoCollectionWrapper) Find(ctx context.Context, filter interface{}, o
 	return &MongoCursorWrapper{cursor: cursor}, nil
 }
 
+func (w *MongoCollectionWrapper) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	return w.coll.UpdateOne(ctx, filter, update, opts...)
+}
+
+func (w *MongoCollectionWrapper) FindOneAndUpdate(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) ISingleResult {
+	return &MongoSingleResultWrapper{result: w.coll.FindOneAndUpdate(ctx, filter, update, opts...)}
+}
+
+func (w *MongoCollectionWrapper) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (ICursor, error) {
+	cursor, err := w.coll.Aggregate(ctx, pipeline, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &MongoCursorWrapper{cursor: cursor}, nil
+}
+
+func (w *MongoCollectionWrapper) DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	return w.coll.DeleteOne(ctx, filter, opts...)
+}
+
 func (w *MongoCollectionWrapper) DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
 	return w.coll.DeleteMany(ctx, filter, opts...)
 }
@@ -130,6 +172,10 @@ func (w *MongoCursorWrapper) All(ctx context.Context, results interface{}) error
 	return w.cursor.All(ctx, results)
 }
 
+func (w *MongoCursorWrapper) Err() error {
+	return w.cursor.Err()
+}
+
 // Ensure that our wrappers implement the interfaces
 var _ ICollection = (*MongoCollectionWrapper)(nil)
 var _ ISingleResult = (*MongoSingleResultWrapper)(nil)
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"github.com/DangVTNhan/Scanner/be/internal/models"
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -12,8 +13,9 @@ import (
 // MockCursor is a mock implementation of ICursor
 type MockCursor struct {
 	mock.Mock
-	results []models.WeatherReport
-	current int
+	results   []models.WeatherReport
+	documents []bson.M
+	current   int
 }
 
 func NewMockCursor(results []models.WeatherReport) *MockCursor {
@@ -23,6 +25,11 @@ func NewMockCursor(results []models.WeatherReport) *MockCursor {
 	}
 }
 
+// NewMockDocumentCursor returns a cursor over raw documents, as returned by aggregations
+func NewMockDocumentCursor(documents []bson.M) *MockCursor {
+	return &MockCursor{documents: documents}
+}
+
 func (m *MockCursor) Close(ctx context.Context) error {
 	args := m.Called(ctx)
 	return args.Error(0)
@@ -38,6 +45,11 @@ func (m *MockCursor) Decode(val interface{}) error {
 	return args.Error(0)
 }
 
+func (m *MockCursor) Err() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
 func (m *MockCursor) All(ctx context.Context, results interface{}) error {
 	args := m.Called(ctx, results)
 
@@ -47,6 +59,11 @@ func (m *MockCursor) All(ctx context.Context, results interface{}) error {
 			*resultsPtr = m.results
 		}
 	}
+	if len(m.documents) > 0 {
+		if documentsPtr, ok := results.(*[]bson.M); ok {
+			*documentsPtr = m.documents
+		}
+	}
 
 	return args.Error(0)
 }
@@ -64,6 +81,14 @@ func (m *MockCollection) InsertOne(ctx context.Context, document interface{}, op
 	return args.Get(0).(*mongo.InsertOneResult), args.Error(1)
 }
 
+func (m *MockCollection) InsertMany(ctx context.Context, documents []interface{}, opts ...*options.InsertManyOptions) (*mongo.InsertManyResult, error) {
+	args := m.Called(ctx, documents, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mongo.InsertManyResult), args.Error(1)
+}
+
 func (m *MockCollection) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) ISingleResult {
 	args := m.Called(ctx, filter, opts)
 	return args.Get(0).(ISingleResult)
@@ -77,6 +102,35 @@ func (m *MockCollection) Find(ctx context.Context, filter interface{}, opts ...*
 	return args.Get(0).(ICursor), args.Error(1)
 }
 
+func (m *MockCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	args := m.Called(ctx, filter, update, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mongo.UpdateResult), args.Error(1)
+}
+
+func (m *MockCollection) FindOneAndUpdate(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) ISingleResult {
+	args := m.Called(ctx, filter, update, opts)
+	return args.Get(0).(ISingleResult)
+}
+
+func (m *MockCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (ICursor, error) {
+	args := m.Called(ctx, pipeline, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(ICursor), args.Error(1)
+}
+
+func (m *MockCollection) DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	args := m.Called(ctx, filter, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mongo.DeleteResult), args.Error(1)
+}
+
 func (m *MockCollection) DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
 	args := m.Called(ctx, filter, opts)
 	if args.Get(0) == nil {
@@ -117,6 +171,26 @@ func (m *MockSingleResult) Decode(v interface{}) error {
 			*cache = *doc
 			return nil
 		}
+	case *models.Comparison:
+		if comparison, ok := v.(*models.Comparison); ok {
+			*comparison = *doc
+			return nil
+		}
+	case *models.APIKey:
+		if key, ok := v.(*models.APIKey); ok {
+			*key = *doc
+			return nil
+		}
+	case *models.RoleAssignment:
+		if assignment, ok := v.(*models.RoleAssignment); ok {
+			*assignment = *doc
+			return nil
+		}
+	case *rateLimitBucket:
+		if bucket, ok := v.(*rateLimitBucket); ok {
+			*bucket = *doc
+			return nil
+		}
 	}
 	return errors.New("could not decode value")
 }
@@ -0,0 +1,64 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRateLimitRepository implements the IRateLimitRepository interface for MongoDB, so that every replica
+// of the API shares the buckets. A bucket is a document with the time it is full again, taken from in a single
+// atomic update; a TTL index removes it once it is full.
+type MongoRateLimitRepository struct {
+	db         IDatabase
+	collection ICollection
+}
+
+// rateLimitBucket is the stored state of a bucket after taking a token
+type rateLimitBucket struct {
+	FullAt  time.Time `bson:"fullAt"`
+	Allowed bool      `bson:"allowed"` // Whether the last request was allowed
+}
+
+// NewMongoRateLimitRepository creates a new instance of MongoRateLimitRepository
+func NewMongoRateLimitRepository(db IDatabase) repository.IRateLimitRepository {
+	return &MongoRateLimitRepository{
+		db:         db,
+		collection: db.Collection("rate_limits"),
+	}
+}
+
+// TakeToken takes a token at now from the bucket of key, computing the same as models.RateLimit.Take on the server
+func (r *MongoRateLimitRepository) TakeToken(ctx context.Context, key string, limit models.RateLimit, now time.Time) (*models.RateLimitResult, error) {
+	now = now.UTC().Truncate(time.Millisecond)
+	interval := limit.Interval().Milliseconds()
+	update := bson.A{
+		bson.M{"$set": bson.M{"fullAt": bson.M{"$max": bson.A{bson.M{"$ifNull": bson.A{"$fullAt", now}}, now}}}},
+		bson.M{"$set": bson.M{"allowed": bson.M{"$lte": bson.A{
+			bson.M{"$subtract": bson.A{bson.M{"$add": bson.A{"$fullAt", interval}}, now}},
+			limit.Window().Milliseconds(),
+		}}}},
+		bson.M{"$set": bson.M{"fullAt": bson.M{"$cond": bson.A{"$allowed", bson.M{"$add": bson.A{"$fullAt", interval}}, "$fullAt"}}}},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	// Two replicas upserting a new bucket at once both insert, and one fails on the _id index;
+	// its retry then updates the bucket the other inserted
+	var bucket rateLimitBucket
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&bucket)
+	if mongo.IsDuplicateKeyError(err) {
+		err = r.collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&bucket)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update rate limit: %w", err)
+	}
+
+	result := limit.Result(bucket.FullAt, now, bucket.Allowed)
+	return &result, nil
+}
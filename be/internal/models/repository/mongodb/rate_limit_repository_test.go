@@ -0,0 +1,92 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestTakeToken(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "rate_limits", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoRateLimitRepository(mockDB)
+
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	limit := models.RateLimit{Requests: 3, Period: time.Minute}
+
+	bucket := &rateLimitBucket{FullAt: now.Add(40 * time.Second), Allowed: true}
+	mockCollection.On("FindOneAndUpdate", ctx, bson.M{"_id": "ip:10.0.0.1 *"}, mock.AnythingOfType("primitive.A"),
+		mock.MatchedBy(func(opts []*options.FindOneAndUpdateOptions) bool {
+			return len(opts) == 1 && opts[0].Upsert != nil && *opts[0].Upsert &&
+				opts[0].ReturnDocument != nil && *opts[0].ReturnDocument == options.After
+		})).Return(NewMockSingleResult(nil, bucket))
+
+	// Act
+	result, err := repo.TakeToken(ctx, "ip:10.0.0.1 *", limit, now)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+	assert.Equal(t, 40*time.Second, result.Reset)
+	mockCollection.AssertExpectations(t)
+}
+
+func TestTakeToken_RetriesConcurrentInsert(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "rate_limits", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoRateLimitRepository(mockDB)
+
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	duplicate := mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000, Message: "E11000 duplicate key error"}}}
+	bucket := &rateLimitBucket{FullAt: now.Add(40 * time.Second), Allowed: true}
+	mockCollection.On("FindOneAndUpdate", ctx, bson.M{"_id": "ip:10.0.0.1 *"}, mock.Anything, mock.Anything).
+		Return(NewMockSingleResult(duplicate, nil)).Once()
+	mockCollection.On("FindOneAndUpdate", ctx, bson.M{"_id": "ip:10.0.0.1 *"}, mock.Anything, mock.Anything).
+		Return(NewMockSingleResult(nil, bucket)).Once()
+
+	// Act
+	result, err := repo.TakeToken(ctx, "ip:10.0.0.1 *", models.RateLimit{Requests: 3, Period: time.Minute}, now)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+	mockCollection.AssertNumberOfCalls(t, "FindOneAndUpdate", 2)
+}
+
+func TestTakeToken_Error(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "rate_limits", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoRateLimitRepository(mockDB)
+
+	ctx := context.Background()
+	mockCollection.On("FindOneAndUpdate", ctx, mock.Anything, mock.Anything, mock.Anything).
+		Return(NewMockSingleResult(errors.New("database error"), nil))
+
+	// Act
+	result, err := repo.TakeToken(ctx, "ip:10.0.0.1 *", models.RateLimit{Requests: 3, Period: time.Minute}, time.Now())
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "failed to update rate limit")
+}
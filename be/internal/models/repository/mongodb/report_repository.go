@@ -3,6 +3,7 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/DangVTNhan/Scanner/be/internal/models"
 	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
@@ -30,6 +31,7 @@ func NewMongoReportRepository(db IDatabase) repository.IReportRepository {
 
 // InsertReport inserts a new weather report into the database
 func (r *MongoReportRepository) InsertReport(ctx context.Context, report *models.WeatherReport) (string, error) {
+	report.Tenant = models.TenantFromContext(ctx)
 	result, err := r.collection.InsertOne(ctx, report)
 	if err != nil {
 		return "", fmt.Errorf("failed to save report: %w", err)
@@ -40,10 +42,35 @@ func (r *MongoReportRepository) InsertReport(ctx context.Context, report *models
 	return objectID.Hex(), nil
 }
 
+// InsertReports inserts weather reports in a single InsertMany and returns their IDs in order
+func (r *MongoReportRepository) InsertReports(ctx context.Context, reports []models.WeatherReport) ([]string, error) {
+	if len(reports) == 0 {
+		return []string{}, nil
+	}
+
+	tenant := models.TenantFromContext(ctx)
+	documents := make([]interface{}, len(reports))
+	for i := range reports {
+		reports[i].Tenant = tenant
+		documents[i] = &reports[i]
+	}
+
+	result, err := r.collection.InsertMany(ctx, documents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save reports: %w", err)
+	}
+
+	ids := make([]string, len(result.InsertedIDs))
+	for i, insertedID := range result.InsertedIDs {
+		ids[i] = insertedID.(primitive.ObjectID).Hex()
+	}
+	return ids, nil
+}
+
 // FindAllReports retrieves all weather reports
 func (r *MongoReportRepository) FindAllReports(ctx context.Context) ([]models.WeatherReport, error) {
 	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}})
-	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	cursor, err := r.collection.Find(ctx, withTenant(ctx, notDeleted()), opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve reports: %w", err)
 	}
@@ -57,32 +84,113 @@ func (r *MongoReportRepository) FindAllReports(ctx context.Context) ([]models.We
 	return reports, nil
 }
 
-// FindPaginatedReports retrieves weather reports with pagination and filtering
+// FindPaginatedReports retrieves weather reports with pagination and filtering.
+// When req.Cursor is set it pages by keyset on the sort keys instead of skipping rows.
 func (r *MongoReportRepository) FindPaginatedReports(ctx context.Context, req *request.PaginatedReportsRequest) (*response.PaginatedReportsResponse, error) {
-	// Set default limit if not provided
-	limit := 10
-	if req.Limit > 0 {
-		limit = req.Limit
+	limit := repository.PageLimit(req)
+
+	keys, err := req.EffectiveSort()
+	if err != nil {
+		return nil, fmt.Errorf("invalid sort: %w", err)
+	}
+	if err := repository.ValidateCursor(req, keys); err != nil {
+		return nil, err
 	}
 
 	// Build the filter
-	filter := bson.M{}
-	// Set up options for sorting and limiting
-	// Determine sort field and order
-	sortField := "timestamp" // Default sort field
-	if req.SortBy != "" {
-		sortField = req.SortBy
-	}
+	filter := buildReportFilter(ctx, req)
 
-	sortOrder := -1 // Default sort order (descending)
-	if req.SortOrder == request.SortOrderAsc {
-		sortOrder = 1 // Ascending order
-	}
+	// A prev cursor walks the sort order backwards from the cursor position
+	backward := req.Cursor != nil && req.Cursor.Direction == request.CursorPrev
 
+	query := filter
 	opts := options.Find().
-		SetSort(bson.D{{Key: sortField, Value: sortOrder}}).
-		SetSkip(int64(req.Offset)).
+		SetSort(sortDocument(keys, backward)).
 		SetLimit(int64(limit + 1)) // Fetch one extra to check if there are more
+	if req.Cursor != nil {
+		query = andFilters(filter, keysetFilter(req.Cursor, keys, backward))
+	} else {
+		opts.SetSkip(int64(req.Offset))
+	}
+
+	// Execute the query
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve reports: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	// Decode the results
+	reports := []models.WeatherReport{}
+	if err := cursor.All(ctx, &reports); err != nil {
+		return nil, fmt.Errorf("failed to decode reports: %w", err)
+	}
+
+	page := repository.BuildReportPage(reports, req, keys)
+
+	// Count total documents for pagination using the same filter, unless the caller opted out
+	page.TotalCount = -1
+	if !req.SkipTotalCount {
+		totalCount, err := r.collection.CountDocuments(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count reports: %w", err)
+		}
+		page.TotalCount = int(totalCount)
+	}
+
+	return page, nil
+}
+
+// StreamReports decodes matching reports one at a time from the cursor and passes them to fn
+func (r *MongoReportRepository) StreamReports(ctx context.Context, req *request.PaginatedReportsRequest, fn func(*models.WeatherReport) error) error {
+	keys, err := req.EffectiveSort()
+	if err != nil {
+		return fmt.Errorf("invalid sort: %w", err)
+	}
+
+	opts := options.Find().SetSort(sortDocument(keys, false))
+	cursor, err := r.collection.Find(ctx, buildReportFilter(ctx, req), opts)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve reports: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var report models.WeatherReport
+		if err := cursor.Decode(&report); err != nil {
+			return fmt.Errorf("failed to decode report: %w", err)
+		}
+		if err := fn(&report); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to retrieve reports: %w", err)
+	}
+	return nil
+}
+
+// sortDocument converts sort keys to a MongoDB sort document, optionally reversed
+func sortDocument(keys []request.SortKey, reverse bool) bson.D {
+	sort := make(bson.D, len(keys))
+	for i, key := range keys {
+		direction := key.Direction()
+		if reverse {
+			direction = -direction
+		}
+		sort[i] = bson.E{Key: key.Field, Value: direction}
+	}
+	return sort
+}
+
+// buildReportFilter builds the query filter of a paginated request of the tenant of ctx, without any cursor
+// condition. Metric conditions are combined with the request's logic; all other filters are always ANDed.
+func buildReportFilter(ctx context.Context, req *request.PaginatedReportsRequest) bson.M {
+	filter := bson.M{}
+	if !req.IncludeDeleted {
+		filter = notDeleted()
+	}
+	filter = withTenant(ctx, filter)
 
 	// Add time range filter if provided
 	if !req.FromTime.IsZero() || !req.ToTime.IsZero() {
@@ -96,74 +204,412 @@ func (r *MongoReportRepository) FindPaginatedReports(ctx context.Context, req *r
 		filter["timestamp"] = timeFilter
 	}
 
-	// Execute the query
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	if len(req.Locations) > 0 {
+		filter["location"] = bson.M{"$in": req.Locations}
+	}
+	if len(req.Sources) > 0 {
+		filter["source"] = bson.M{"$in": req.Sources}
+	}
+	if len(req.Types) > 0 {
+		filter["type"] = bson.M{"$in": req.Types}
+	}
+	if len(req.Tags) > 0 {
+		filter["tags"] = bson.M{"$in": req.Tags}
+	}
+
+	if len(req.Conditions) == 0 {
+		return filter
+	}
+
+	conditions := make(bson.A, len(req.Conditions))
+	for i, condition := range req.Conditions {
+		conditions[i] = bson.M{string(condition.Metric): bson.M{mongoOperators[condition.Operator]: condition.Value}}
+	}
+
+	logic := "$and"
+	if req.Logic == request.LogicOr {
+		logic = "$or"
+	}
+	return andFilters(filter, bson.M{logic: conditions})
+}
+
+// notDeleted matches reports that are not soft-deleted. A null query also matches a missing field.
+func notDeleted() bson.M {
+	return bson.M{"deletedAt": nil}
+}
+
+// withTenant restricts filter to the documents of the tenant of ctx and returns it
+func withTenant(ctx context.Context, filter bson.M) bson.M {
+	filter["tenant"] = models.TenantFromContext(ctx)
+	return filter
+}
+
+// mongoOperators maps filter comparison operators to MongoDB query operators
+var mongoOperators = map[request.ComparisonOperator]string{
+	request.OpGreaterThan:      "$gt",
+	request.OpGreaterThanEqual: "$gte",
+	request.OpLessThan:         "$lt",
+	request.OpLessThanEqual:    "$lte",
+	request.OpEqual:            "$eq",
+	request.OpNotEqual:         "$ne",
+}
+
+// keysetFilter matches reports strictly after the cursor position in the order of keys,
+// or strictly before it when walking backward. For keys k1..kn it expands to
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ... with > flipped to < for descending keys.
+func keysetFilter(cursor *request.ReportCursor, keys []request.SortKey, backward bool) bson.M {
+	boundary := cursor.Boundary()
+
+	branches := make(bson.A, len(keys))
+	for i, key := range keys {
+		branch := bson.M{}
+		for _, previous := range keys[:i] {
+			branch[previous.Field] = keyValue(&boundary, previous.Field)
+		}
+
+		direction := key.Direction()
+		if backward {
+			direction = -direction
+		}
+		op := "$lt"
+		if direction > 0 {
+			op = "$gt"
+		}
+		branch[key.Field] = bson.M{op: keyValue(&boundary, key.Field)}
+		branches[i] = branch
+	}
+
+	return bson.M{"$or": branches}
+}
+
+// keyValue returns the value of a sortable field as stored in MongoDB
+func keyValue(report *models.WeatherReport, field string) interface{} {
+	switch field {
+	case request.TieBreakField:
+		return idValue(report.ID)
+	case "timestamp":
+		return report.Timestamp
+	case "createdAt":
+		return report.CreatedAt
+	}
+	return report.Value(models.Metric(field))
+}
+
+// andFilters combines filters with $and, skipping empty ones
+func andFilters(filters ...bson.M) bson.M {
+	var nonEmpty bson.A
+	for _, filter := range filters {
+		if len(filter) > 0 {
+			nonEmpty = append(nonEmpty, filter)
+		}
+	}
+
+	switch len(nonEmpty) {
+	case 0:
+		return bson.M{}
+	case 1:
+		return nonEmpty[0].(bson.M)
+	}
+	return bson.M{"$and": nonEmpty}
+}
+
+// idValue converts a hex string to an ObjectID, falling back to the raw string for non-ObjectID ids
+func idValue(id string) interface{} {
+	if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+		return objectID
+	}
+	return id
+}
+
+// FindReportByID retrieves a weather report by its ID
+func (r *MongoReportRepository) FindReportByID(ctx context.Context, id string) (*models.WeatherReport, error) {
+	// IDs that are not valid ObjectIDs are looked up by their raw string value
+	var report models.WeatherReport
+	err := r.collection.FindOne(ctx, withTenant(ctx, bson.M{"_id": idValue(id), "deletedAt": nil})).Decode(&report)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("report not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve report: %w", err)
+	}
+
+	return &report, nil
+}
+
+// FindReportsByIDs retrieves the weather reports with the given IDs using a single $in query
+func (r *MongoReportRepository) FindReportsByIDs(ctx context.Context, ids []string) ([]models.WeatherReport, error) {
+	values := make(bson.A, len(ids))
+	for i, id := range ids {
+		values[i] = idValue(id)
+	}
+
+	cursor, err := r.collection.Find(ctx, withTenant(ctx, bson.M{"_id": bson.M{"$in": values}, "deletedAt": nil}))
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve reports: %w", err)
 	}
 	defer cursor.Close(ctx)
 
-	// Decode the results
 	reports := []models.WeatherReport{}
 	if err := cursor.All(ctx, &reports); err != nil {
 		return nil, fmt.Errorf("failed to decode reports: %w", err)
 	}
 
-	// If we fetched one extra item to check for more pages, remove it
-	if len(reports) > limit && limit > 0 {
-		reports = reports[:limit] // Remove the extra item
+	return reports, nil
+}
+
+// FindExistingTimestamps returns which of the given timestamps already have a report, including deleted ones
+func (r *MongoReportRepository) FindExistingTimestamps(ctx context.Context, timestamps []time.Time) ([]time.Time, error) {
+	if len(timestamps) == 0 {
+		return []time.Time{}, nil
 	}
 
-	// Count total documents for pagination using the same filter
-	totalCount, err := r.collection.CountDocuments(ctx, filter)
+	opts := options.Find().SetProjection(bson.M{"_id": 0, "timestamp": 1})
+	cursor, err := r.collection.Find(ctx, withTenant(ctx, bson.M{"timestamp": bson.M{"$in": timestamps}}), opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to count reports: %w", err)
+		return nil, fmt.Errorf("failed to retrieve reports: %w", err)
 	}
+	defer cursor.Close(ctx)
 
-	// Create response with pagination metadata
-	response := &response.PaginatedReportsResponse{
-		Reports:    reports,
-		TotalCount: int(totalCount),
+	var reports []models.WeatherReport
+	if err := cursor.All(ctx, &reports); err != nil {
+		return nil, fmt.Errorf("failed to decode reports: %w", err)
 	}
 
-	return response, nil
+	existing := make([]time.Time, len(reports))
+	for i, report := range reports {
+		existing[i] = report.Timestamp
+	}
+	return existing, nil
 }
 
-// FindReportByID retrieves a weather report by its ID
-func (r *MongoReportRepository) FindReportByID(ctx context.Context, id string) (*models.WeatherReport, error) {
-	// Check if the ID is a valid ObjectID
-	objectID, err := primitive.ObjectIDFromHex(id)
+// AggregateReports calculates the statistics of every metric over the reports with a timestamp in [from, to]
+// using a single $group stage
+func (r *MongoReportRepository) AggregateReports(ctx context.Context, from, to time.Time) (*response.PeriodStatistics, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: withTenant(ctx, bson.M{"timestamp": bson.M{"$gte": from, "$lte": to}, "deletedAt": nil})}},
+		{{Key: "$group", Value: statisticsGroup(nil)}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
 	if err != nil {
-		// If not a valid ObjectID, try to find by string ID directly
-		var report models.WeatherReport
-		err = r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&report)
-		if err != nil {
-			if err == mongo.ErrNoDocuments {
-				return nil, fmt.Errorf("report not found")
-			}
-			return nil, fmt.Errorf("failed to retrieve report: %w", err)
-		}
-		return &report, nil
+		return nil, fmt.Errorf("failed to aggregate reports: %w", err)
 	}
+	defer cursor.Close(ctx)
 
-	// If it's a valid ObjectID, search by ObjectID
-	var report models.WeatherReport
-	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&report)
+	var results []bson.M
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode report statistics: %w", err)
+	}
+
+	stats := &response.PeriodStatistics{
+		From:    from,
+		To:      to,
+		Metrics: map[models.Metric]response.MetricStatistics{},
+	}
+	if len(results) == 0 {
+		return stats, nil // No reports in the range
+	}
+	decodeStatistics(results[0], stats)
+	return stats, nil
+}
+
+// AggregateReportBuckets calculates the statistics of every metric per bucket of the reports with a timestamp
+// in [from, to]. The bucket of a report is computed from its offset to from, so it works without $dateTrunc.
+func (r *MongoReportRepository) AggregateReportBuckets(ctx context.Context, from, to time.Time, bucket time.Duration) ([]response.PeriodStatistics, error) {
+	index := bson.M{"$floor": bson.M{"$divide": bson.A{
+		bson.M{"$subtract": bson.A{"$timestamp", from}}, // Milliseconds since from
+		bucket.Milliseconds(),
+	}}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: withTenant(ctx, bson.M{"timestamp": bson.M{"$gte": from, "$lte": to}, "deletedAt": nil})}},
+		{{Key: "$group", Value: statisticsGroup(index)}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("report not found")
+		return nil, fmt.Errorf("failed to aggregate reports: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []bson.M
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode report statistics: %w", err)
+	}
+
+	buckets := make([]response.PeriodStatistics, len(results))
+	for i, result := range results {
+		buckets[i] = bucketStatistics(from, to, bucket, int(numberValue(result["_id"])))
+		decodeStatistics(result, &buckets[i])
+	}
+	return buckets, nil
+}
+
+// statisticsGroup returns a $group stage grouping by id that counts the reports and calculates
+// the mean, min, max and standard deviation of every metric
+func statisticsGroup(id interface{}) bson.M {
+	group := bson.M{
+		"_id":   id,
+		"count": bson.M{"$sum": 1},
+	}
+	for _, metric := range models.Metrics {
+		field := "$" + string(metric)
+		group[string(metric)+"_avg"] = bson.M{"$avg": field}
+		group[string(metric)+"_min"] = bson.M{"$min": field}
+		group[string(metric)+"_max"] = bson.M{"$max": field}
+		group[string(metric)+"_stdDev"] = bson.M{"$stdDevPop": field}
+	}
+	return group
+}
+
+// decodeStatistics fills stats from a result of a statisticsGroup stage
+func decodeStatistics(result bson.M, stats *response.PeriodStatistics) {
+	stats.Count = int(numberValue(result["count"]))
+	for _, metric := range models.Metrics {
+		metricStats := response.MetricStatistics{
+			Mean:   numberValue(result[string(metric)+"_avg"]),
+			Min:    numberValue(result[string(metric)+"_min"]),
+			Max:    numberValue(result[string(metric)+"_max"]),
+			StdDev: numberValue(result[string(metric)+"_stdDev"]),
 		}
-		return nil, fmt.Errorf("failed to retrieve report: %w", err)
+		metricStats.Range = metricStats.Max - metricStats.Min
+		stats.Metrics[metric] = metricStats
 	}
+}
 
-	return &report, nil
+// bucketStatistics returns the empty statistics of bucket i of the range [from, to]
+func bucketStatistics(from, to time.Time, bucket time.Duration, i int) response.PeriodStatistics {
+	start := from.Add(time.Duration(i) * bucket)
+	end := start.Add(bucket)
+	if end.After(to) {
+		end = to
+	}
+	return response.PeriodStatistics{From: start, To: end, Metrics: map[models.Metric]response.MetricStatistics{}}
+}
+
+// numberValue converts a numeric BSON value to float64; other values become 0
+func numberValue(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+	return 0
 }
 
 // CountReports counts the total number of reports
 func (r *MongoReportRepository) CountReports(ctx context.Context) (int64, error) {
-	count, err := r.collection.CountDocuments(ctx, bson.M{})
+	count, err := r.collection.CountDocuments(ctx, withTenant(ctx, notDeleted()))
 	if err != nil {
 		return 0, fmt.Errorf("failed to count reports: %w", err)
 	}
 	return count, nil
 }
+
+// UpdateReport sets the annotations given in update. Empty values are unset so that
+// cleared reports look the same as reports that were never annotated.
+func (r *MongoReportRepository) UpdateReport(ctx context.Context, id string, update *request.ReportUpdateRequest) error {
+	set := bson.M{}
+	unset := bson.M{}
+	if update.Tags != nil {
+		if len(*update.Tags) > 0 {
+			set["tags"] = *update.Tags
+		} else {
+			unset["tags"] = ""
+		}
+	}
+	if update.Notes != nil {
+		if *update.Notes != "" {
+			set["notes"] = *update.Notes
+		} else {
+			unset["notes"] = ""
+		}
+	}
+
+	changes := bson.M{}
+	if len(set) > 0 {
+		changes["$set"] = set
+	}
+	if len(unset) > 0 {
+		changes["$unset"] = unset
+	}
+
+	result, err := r.collection.UpdateOne(ctx, withTenant(ctx, bson.M{"_id": idValue(id), "deletedAt": nil}), changes)
+	if err != nil {
+		return fmt.Errorf("failed to update report: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("report not found")
+	}
+	return nil
+}
+
+// FindTagCounts counts the reports of every tag by unwinding the tags arrays
+func (r *MongoReportRepository) FindTagCounts(ctx context.Context) ([]response.TagCount, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: withTenant(ctx, notDeleted())}},
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$group", Value: bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tags: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []bson.M
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode tag counts: %w", err)
+	}
+
+	counts := make([]response.TagCount, 0, len(results))
+	for _, result := range results {
+		tag, _ := result["_id"].(string)
+		counts = append(counts, response.TagCount{Tag: tag, Count: int(numberValue(result["count"]))})
+	}
+	return counts, nil
+}
+
+// DeleteReport soft-deletes a report by setting its deletedAt
+func (r *MongoReportRepository) DeleteReport(ctx context.Context, id string, deletedAt time.Time) error {
+	filter := withTenant(ctx, bson.M{"_id": idValue(id), "deletedAt": nil})
+	update := bson.M{"$set": bson.M{"deletedAt": deletedAt}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to delete report: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("report not found")
+	}
+	return nil
+}
+
+// RestoreReport clears the deletedAt of a soft-deleted report
+func (r *MongoReportRepository) RestoreReport(ctx context.Context, id string) error {
+	filter := withTenant(ctx, bson.M{"_id": idValue(id), "deletedAt": bson.M{"$ne": nil}})
+	update := bson.M{"$unset": bson.M{"deletedAt": ""}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to restore report: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("deleted report not found")
+	}
+	return nil
+}
+
+// PurgeDeletedReports permanently removes the reports of every tenant deleted at or before the given time
+func (r *MongoReportRepository) PurgeDeletedReports(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"deletedAt": bson.M{"$lte": before}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge reports: %w", err)
+	}
+	return result.DeletedCount, nil
+}
@@ -8,10 +8,13 @@ import (
 
 	"github.com/DangVTNhan/Scanner/be/internal/models"
 	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func TestNewMongoReportRepository(t *testing.T) {
@@ -59,6 +62,7 @@ func TestInsertReport(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	assert.Equal(t, objectID.Hex(), id)
+	assert.Equal(t, models.DefaultTenant, report.Tenant)
 	mockCollection.AssertExpectations(t)
 }
 
@@ -93,6 +97,51 @@ func TestInsertReport_Error(t *testing.T) {
 	mockCollection.AssertExpectations(t)
 }
 
+func TestInsertReports(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	reports := []models.WeatherReport{{Temperature: 25.5}, {Temperature: 26.0}}
+	objectIDs := []interface{}{primitive.NewObjectID(), primitive.NewObjectID()}
+
+	mockCollection.On("InsertMany", ctx, []interface{}{&reports[0], &reports[1]}, mock.Anything).
+		Return(&mongo.InsertManyResult{InsertedIDs: objectIDs}, nil)
+
+	// Act
+	ids, err := repo.InsertReports(ctx, reports)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{objectIDs[0].(primitive.ObjectID).Hex(), objectIDs[1].(primitive.ObjectID).Hex()}, ids)
+	mockCollection.AssertExpectations(t)
+}
+
+func TestInsertReports_Error(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	mockCollection.On("InsertMany", ctx, mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+	// Act
+	ids, err := repo.InsertReports(ctx, []models.WeatherReport{{Temperature: 25.5}})
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, ids)
+	assert.Contains(t, err.Error(), "failed to save reports")
+	mockCollection.AssertExpectations(t)
+}
+
 func TestFindAllReports(t *testing.T) {
 	// Arrange
 	mockDB := new(MockDatabase)
@@ -491,6 +540,66 @@ func TestFindPaginatedReports_CountError(t *testing.T) {
 	mockCursor.AssertExpectations(t)
 }
 
+func TestFindPaginatedReports_WithCursor(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	cursorID := primitive.NewObjectID()
+	cursorTime := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	req := &request.PaginatedReportsRequest{
+		Limit:          2,
+		SkipTotalCount: true,
+		Cursor: &request.ReportCursor{
+			Sort:      "-timestamp,-_id",
+			Timestamp: cursorTime,
+			ID:        cursorID.Hex(),
+			Direction: request.CursorNext,
+		},
+	}
+
+	mockCursor := NewMockCursor(nil)
+	mockCursor.On("All", ctx, mock.AnythingOfType("*[]models.WeatherReport")).Return(nil)
+	mockCursor.On("Close", ctx).Return(nil)
+
+	// The keyset condition must walk the (timestamp, _id) index below the cursor position,
+	// ANDed with the condition that excludes deleted reports
+	keysetFilter := mock.MatchedBy(func(filter interface{}) bool {
+		and, ok := filter.(bson.M)["$and"].(bson.A)
+		if !ok || len(and) != 2 || !assert.ObjectsAreEqual(bson.M{"deletedAt": nil, "tenant": models.DefaultTenant}, and[0]) {
+			return false
+		}
+		or, ok := and[1].(bson.M)["$or"].(bson.A)
+		if !ok || len(or) != 2 {
+			return false
+		}
+		tie := or[1].(bson.M)
+		return tie["timestamp"] == cursorTime && tie["_id"].(bson.M)["$lt"] == cursorID
+	})
+	keysetOptions := mock.MatchedBy(func(opts []*options.FindOptions) bool {
+		return len(opts) == 1 && opts[0].Skip == nil && *opts[0].Limit == 3
+	})
+
+	mockCollection.On("Find", ctx, keysetFilter, keysetOptions).Return(mockCursor, nil)
+
+	// Act
+	response, err := repo.FindPaginatedReports(ctx, req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotNil(t, response)
+	assert.Empty(t, response.Reports)
+	assert.False(t, response.HasMore)
+	assert.Equal(t, -1, response.TotalCount)
+	assert.NotEmpty(t, response.PrevCursor)
+	mockCollection.AssertExpectations(t)
+	mockCollection.AssertNotCalled(t, "CountDocuments", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestFindReportByID_ValidObjectID(t *testing.T) {
 	// Arrange
 	mockDB := new(MockDatabase)
@@ -527,6 +636,30 @@ func TestFindReportByID_ValidObjectID(t *testing.T) {
 	mockCollection.AssertExpectations(t)
 }
 
+func TestFindReportByID_ScopedToTenant(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := models.ContextWithTenant(context.Background(), "changi-ops")
+	objectID := primitive.NewObjectID()
+	expectedFilter := bson.M{"_id": objectID, "deletedAt": nil, "tenant": "changi-ops"}
+
+	mockCollection.On("FindOne", ctx, expectedFilter, mock.Anything).Return(NewMockSingleResult(mongo.ErrNoDocuments, nil))
+
+	// Act
+	report, err := repo.FindReportByID(ctx, objectID.Hex())
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, report)
+	assert.Equal(t, "report not found", err.Error())
+	mockCollection.AssertExpectations(t)
+}
+
 func TestFindReportByID_InvalidObjectID(t *testing.T) {
 	// Arrange
 	mockDB := new(MockDatabase)
@@ -613,6 +746,217 @@ func TestFindReportByID_Error(t *testing.T) {
 	mockCollection.AssertExpectations(t)
 }
 
+func TestFindReportsByIDs(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	objectID := primitive.NewObjectID()
+	expectedReports := []models.WeatherReport{{ID: objectID.Hex(), Temperature: 25.5}}
+
+	expectedFilter := bson.M{"_id": bson.M{"$in": bson.A{objectID, "legacy-id"}}, "deletedAt": nil, "tenant": models.DefaultTenant}
+	mockCursor := NewMockCursor(expectedReports)
+	mockCursor.On("All", ctx, mock.Anything).Return(nil)
+	mockCursor.On("Close", ctx).Return(nil)
+	mockCollection.On("Find", ctx, expectedFilter, mock.Anything).Return(mockCursor, nil)
+
+	// Act
+	reports, err := repo.FindReportsByIDs(ctx, []string{objectID.Hex(), "legacy-id"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expectedReports, reports)
+	mockCollection.AssertExpectations(t)
+	mockCursor.AssertExpectations(t)
+}
+
+func TestFindReportsByIDs_FindError(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	expectedErr := errors.New("database error")
+
+	mockCollection.On("Find", ctx, mock.Anything, mock.Anything).Return(nil, expectedErr)
+
+	// Act
+	reports, err := repo.FindReportsByIDs(ctx, []string{"report1"})
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, reports)
+	assert.Contains(t, err.Error(), "failed to retrieve reports")
+	mockCollection.AssertExpectations(t)
+}
+
+func TestFindExistingTimestamps(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	existing := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	timestamps := []time.Time{existing, existing.Add(time.Hour)}
+
+	// Deleted reports count too, so the filter has no deletedAt condition
+	expectedFilter := bson.M{"timestamp": bson.M{"$in": timestamps}, "tenant": models.DefaultTenant}
+	mockCursor := NewMockCursor([]models.WeatherReport{{Timestamp: existing}})
+	mockCursor.On("All", ctx, mock.Anything).Return(nil)
+	mockCursor.On("Close", ctx).Return(nil)
+	mockCollection.On("Find", ctx, expectedFilter, mock.Anything).Return(mockCursor, nil)
+
+	// Act
+	found, err := repo.FindExistingTimestamps(ctx, timestamps)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{existing}, found)
+	mockCollection.AssertExpectations(t)
+	mockCursor.AssertExpectations(t)
+}
+
+func TestAggregateReports(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	mockCursor := NewMockDocumentCursor([]bson.M{{
+		"_id":                nil,
+		"count":              int32(4),
+		"temperature_avg":    27.5,
+		"temperature_min":    25.0,
+		"temperature_max":    30.0,
+		"temperature_stdDev": 1.5,
+		"humidity_min":       int32(60),
+	}})
+	mockCursor.On("All", ctx, mock.Anything).Return(nil)
+	mockCursor.On("Close", ctx).Return(nil)
+	mockCollection.On("Aggregate", ctx, mock.MatchedBy(func(pipeline mongo.Pipeline) bool {
+		return len(pipeline) == 2 && pipeline[0][0].Key == "$match" && pipeline[1][0].Key == "$group"
+	}), mock.Anything).Return(mockCursor, nil)
+
+	// Act
+	stats, err := repo.AggregateReports(ctx, from, to)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 4, stats.Count)
+	assert.Equal(t, from, stats.From)
+	assert.Equal(t, to, stats.To)
+	assert.Equal(t, 27.5, stats.Metrics[models.MetricTemperature].Mean)
+	assert.Equal(t, 5.0, stats.Metrics[models.MetricTemperature].Range)
+	assert.Equal(t, 1.5, stats.Metrics[models.MetricTemperature].StdDev)
+	assert.Equal(t, 60.0, stats.Metrics[models.MetricHumidity].Min)
+	mockCollection.AssertExpectations(t)
+	mockCursor.AssertExpectations(t)
+}
+
+func TestAggregateReports_NoReports(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	mockCursor := NewMockDocumentCursor(nil)
+	mockCursor.On("All", ctx, mock.Anything).Return(nil)
+	mockCursor.On("Close", ctx).Return(nil)
+	mockCollection.On("Aggregate", ctx, mock.Anything, mock.Anything).Return(mockCursor, nil)
+
+	// Act
+	stats, err := repo.AggregateReports(ctx, time.Now().Add(-time.Hour), time.Now())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 0, stats.Count)
+	assert.Empty(t, stats.Metrics)
+	mockCollection.AssertExpectations(t)
+}
+
+func TestAggregateReports_Error(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	mockCollection.On("Aggregate", ctx, mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+	// Act
+	stats, err := repo.AggregateReports(ctx, time.Now().Add(-time.Hour), time.Now())
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, stats)
+	assert.Contains(t, err.Error(), "failed to aggregate reports")
+	mockCollection.AssertExpectations(t)
+}
+
+func TestAggregateReportBuckets(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(30 * time.Hour)
+
+	mockCursor := NewMockDocumentCursor([]bson.M{
+		{"_id": 0.0, "count": int32(3), "temperature_avg": 26.0, "temperature_min": 25.0, "temperature_max": 27.0},
+		{"_id": 2.0, "count": int32(1), "temperature_avg": 29.0, "temperature_min": 29.0, "temperature_max": 29.0},
+	})
+	mockCursor.On("All", ctx, mock.Anything).Return(nil)
+	mockCursor.On("Close", ctx).Return(nil)
+	mockCollection.On("Aggregate", ctx, mock.MatchedBy(func(pipeline mongo.Pipeline) bool {
+		if len(pipeline) != 3 || pipeline[1][0].Key != "$group" || pipeline[2][0].Key != "$sort" {
+			return false
+		}
+		index := pipeline[1][0].Value.(bson.M)["_id"].(bson.M)["$floor"].(bson.M)["$divide"].(bson.A)
+		return index[1] == int64(12*time.Hour/time.Millisecond)
+	}), mock.Anything).Return(mockCursor, nil)
+
+	// Act
+	buckets, err := repo.AggregateReportBuckets(ctx, from, to, 12*time.Hour)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, buckets, 2)
+	assert.Equal(t, from, buckets[0].From)
+	assert.Equal(t, from.Add(12*time.Hour), buckets[0].To)
+	assert.Equal(t, 3, buckets[0].Count)
+	assert.Equal(t, 2.0, buckets[0].Metrics[models.MetricTemperature].Range)
+	assert.Equal(t, from.Add(24*time.Hour), buckets[1].From)
+	assert.Equal(t, to, buckets[1].To, "the last bucket is capped at to")
+	assert.Equal(t, 29.0, buckets[1].Metrics[models.MetricTemperature].Mean)
+	mockCollection.AssertExpectations(t)
+	mockCursor.AssertExpectations(t)
+}
+
 func TestCountReports(t *testing.T) {
 	// Arrange
 	mockDB := new(MockDatabase)
@@ -657,3 +1001,342 @@ func TestCountReports_Error(t *testing.T) {
 	assert.Contains(t, err.Error(), expectedErr.Error())
 	mockCollection.AssertExpectations(t)
 }
+
+func TestDeleteReport(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	objectID := primitive.NewObjectID()
+	deletedAt := time.Now()
+
+	expectedFilter := bson.M{"_id": objectID, "deletedAt": nil, "tenant": models.DefaultTenant}
+	expectedUpdate := bson.M{"$set": bson.M{"deletedAt": deletedAt}}
+	mockCollection.On("UpdateOne", ctx, expectedFilter, expectedUpdate, mock.Anything).Return(&mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil)
+
+	// Act
+	err := repo.DeleteReport(ctx, objectID.Hex(), deletedAt)
+
+	// Assert
+	assert.NoError(t, err)
+	mockCollection.AssertExpectations(t)
+}
+
+func TestDeleteReport_NotFound(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	mockCollection.On("UpdateOne", ctx, mock.Anything, mock.Anything, mock.Anything).Return(&mongo.UpdateResult{MatchedCount: 0}, nil)
+
+	// Act
+	err := repo.DeleteReport(ctx, "legacy-id", time.Now())
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, "report not found", err.Error())
+	mockCollection.AssertExpectations(t)
+}
+
+func TestDeleteReport_Error(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	mockCollection.On("UpdateOne", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+	// Act
+	err := repo.DeleteReport(ctx, primitive.NewObjectID().Hex(), time.Now())
+
+	// Assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to delete report")
+	mockCollection.AssertExpectations(t)
+}
+
+func TestRestoreReport(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	objectID := primitive.NewObjectID()
+
+	expectedFilter := bson.M{"_id": objectID, "deletedAt": bson.M{"$ne": nil}, "tenant": models.DefaultTenant}
+	expectedUpdate := bson.M{"$unset": bson.M{"deletedAt": ""}}
+	mockCollection.On("UpdateOne", ctx, expectedFilter, expectedUpdate, mock.Anything).Return(&mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil)
+
+	// Act
+	err := repo.RestoreReport(ctx, objectID.Hex())
+
+	// Assert
+	assert.NoError(t, err)
+	mockCollection.AssertExpectations(t)
+}
+
+func TestRestoreReport_NotDeleted(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	mockCollection.On("UpdateOne", ctx, mock.Anything, mock.Anything, mock.Anything).Return(&mongo.UpdateResult{MatchedCount: 0}, nil)
+
+	// Act
+	err := repo.RestoreReport(ctx, primitive.NewObjectID().Hex())
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, "deleted report not found", err.Error())
+	mockCollection.AssertExpectations(t)
+}
+
+func TestPurgeDeletedReports(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	before := time.Now().Add(-30 * 24 * time.Hour)
+	mockCollection.On("DeleteMany", ctx, bson.M{"deletedAt": bson.M{"$lte": before}}, mock.Anything).Return(&mongo.DeleteResult{DeletedCount: 3}, nil)
+
+	// Act
+	purged, err := repo.PurgeDeletedReports(ctx, before)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+	mockCollection.AssertExpectations(t)
+}
+
+func TestPurgeDeletedReports_Error(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	mockCollection.On("DeleteMany", ctx, mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+	// Act
+	purged, err := repo.PurgeDeletedReports(ctx, time.Now())
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, int64(0), purged)
+	assert.Contains(t, err.Error(), "failed to purge reports")
+	mockCollection.AssertExpectations(t)
+}
+
+func TestUpdateReport(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	objectID := primitive.NewObjectID()
+	tags := []string{"runway closure"}
+	notes := ""
+
+	// Empty notes are unset rather than stored
+	expectedFilter := bson.M{"_id": objectID, "deletedAt": nil, "tenant": models.DefaultTenant}
+	expectedUpdate := bson.M{
+		"$set":   bson.M{"tags": tags},
+		"$unset": bson.M{"notes": ""},
+	}
+	mockCollection.On("UpdateOne", ctx, expectedFilter, expectedUpdate, mock.Anything).Return(&mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil)
+
+	// Act
+	err := repo.UpdateReport(ctx, objectID.Hex(), &request.ReportUpdateRequest{Tags: &tags, Notes: &notes})
+
+	// Assert
+	assert.NoError(t, err)
+	mockCollection.AssertExpectations(t)
+}
+
+func TestUpdateReport_NotFound(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	notes := "sensor check"
+	mockCollection.On("UpdateOne", ctx, mock.Anything, bson.M{"$set": bson.M{"notes": notes}}, mock.Anything).Return(&mongo.UpdateResult{MatchedCount: 0}, nil)
+
+	// Act
+	err := repo.UpdateReport(ctx, primitive.NewObjectID().Hex(), &request.ReportUpdateRequest{Notes: &notes})
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, "report not found", err.Error())
+	mockCollection.AssertExpectations(t)
+}
+
+func TestUpdateReport_Error(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	notes := "sensor check"
+	mockCollection.On("UpdateOne", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+	// Act
+	err := repo.UpdateReport(ctx, primitive.NewObjectID().Hex(), &request.ReportUpdateRequest{Notes: &notes})
+
+	// Assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to update report")
+	mockCollection.AssertExpectations(t)
+}
+
+func TestFindTagCounts(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	mockCursor := NewMockDocumentCursor([]bson.M{
+		{"_id": "runway closure", "count": int32(3)},
+		{"_id": "sensor check", "count": int32(1)},
+	})
+	mockCursor.On("All", ctx, mock.Anything).Return(nil)
+	mockCursor.On("Close", ctx).Return(nil)
+	mockCollection.On("Aggregate", ctx, mock.MatchedBy(func(pipeline mongo.Pipeline) bool {
+		return len(pipeline) == 4 && pipeline[1][0].Key == "$unwind" && pipeline[2][0].Key == "$group"
+	}), mock.Anything).Return(mockCursor, nil)
+
+	// Act
+	counts, err := repo.FindTagCounts(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []response.TagCount{{Tag: "runway closure", Count: 3}, {Tag: "sensor check", Count: 1}}, counts)
+	mockCollection.AssertExpectations(t)
+}
+
+func TestFindTagCounts_Error(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	mockCollection.On("Aggregate", ctx, mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+	// Act
+	counts, err := repo.FindTagCounts(ctx)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, counts)
+	assert.Contains(t, err.Error(), "failed to count tags")
+	mockCollection.AssertExpectations(t)
+}
+
+func TestStreamReports(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	reports := []models.WeatherReport{{ID: "report1", Temperature: 25}, {ID: "report2", Temperature: 26}}
+
+	// The cursor is read one document at a time instead of with All
+	mockCursor := NewMockCursor(nil)
+	mockCursor.On("Next", ctx).Return(true).Times(len(reports))
+	mockCursor.On("Next", ctx).Return(false).Once()
+	decoded := 0
+	mockCursor.On("Decode", mock.AnythingOfType("*models.WeatherReport")).Run(func(args mock.Arguments) {
+		*args.Get(0).(*models.WeatherReport) = reports[decoded]
+		decoded++
+	}).Return(nil)
+	mockCursor.On("Err").Return(nil)
+	mockCursor.On("Close", ctx).Return(nil)
+
+	sortOptions := mock.MatchedBy(func(opts []*options.FindOptions) bool {
+		return len(opts) == 1 && opts[0].Limit == nil && opts[0].Skip == nil &&
+			assert.ObjectsAreEqual(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}, opts[0].Sort)
+	})
+	mockCollection.On("Find", ctx, bson.M{"deletedAt": nil, "tenant": models.DefaultTenant}, sortOptions).Return(mockCursor, nil)
+
+	// Act
+	var streamed []string
+	err := repo.StreamReports(ctx, &request.PaginatedReportsRequest{Limit: 1}, func(report *models.WeatherReport) error {
+		streamed = append(streamed, report.ID)
+		return nil
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"report1", "report2"}, streamed)
+	mockCollection.AssertExpectations(t)
+	mockCursor.AssertExpectations(t)
+	mockCursor.AssertNotCalled(t, "All", mock.Anything, mock.Anything)
+}
+
+func TestStreamReports_CursorError(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "reports", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoReportRepository(mockDB)
+
+	ctx := context.Background()
+	mockCursor := NewMockCursor(nil)
+	mockCursor.On("Next", ctx).Return(false)
+	mockCursor.On("Err").Return(errors.New("connection reset"))
+	mockCursor.On("Close", ctx).Return(nil)
+	mockCollection.On("Find", ctx, mock.Anything, mock.Anything).Return(mockCursor, nil)
+
+	// Act
+	err := repo.StreamReports(ctx, &request.PaginatedReportsRequest{}, func(*models.WeatherReport) error {
+		return nil
+	})
+
+	// Assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to retrieve reports")
+	mockCursor.AssertExpectations(t)
+}
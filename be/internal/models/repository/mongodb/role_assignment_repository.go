@@ -0,0 +1,93 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRoleAssignmentRepository implements the IRoleAssignmentRepository interface for MongoDB.
+// Assignments are keyed by tenant, method and subject, so each caller has at most one document.
+type MongoRoleAssignmentRepository struct {
+	db         IDatabase
+	collection ICollection
+}
+
+// NewMongoRoleAssignmentRepository creates a new instance of MongoRoleAssignmentRepository
+func NewMongoRoleAssignmentRepository(db IDatabase) repository.IRoleAssignmentRepository {
+	return &MongoRoleAssignmentRepository{
+		db:         db,
+		collection: db.Collection("role_assignments"),
+	}
+}
+
+// FindRoleAssignment retrieves the role assignment of a caller
+func (r *MongoRoleAssignmentRepository) FindRoleAssignment(ctx context.Context, method models.AuthMethod, subject string) (*models.RoleAssignment, error) {
+	var assignment models.RoleAssignment
+	id := models.RoleAssignmentID(models.TenantFromContext(ctx), method, subject)
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&assignment)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("role assignment not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve role assignment: %w", err)
+	}
+
+	return &assignment, nil
+}
+
+// FindRoleAssignments retrieves all role assignments, ordered by method and subject
+func (r *MongoRoleAssignmentRepository) FindRoleAssignments(ctx context.Context) ([]models.RoleAssignment, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "method", Value: 1}, {Key: "subject", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"tenant": models.TenantFromContext(ctx)}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve role assignments: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	assignments := []models.RoleAssignment{}
+	if err := cursor.All(ctx, &assignments); err != nil {
+		return nil, fmt.Errorf("failed to decode role assignments: %w", err)
+	}
+
+	return assignments, nil
+}
+
+// UpsertRoleAssignment creates or replaces the role assignment of a caller
+func (r *MongoRoleAssignmentRepository) UpsertRoleAssignment(ctx context.Context, assignment *models.RoleAssignment) error {
+	tenant := models.TenantFromContext(ctx)
+	update := bson.M{"$set": bson.M{
+		"tenant":    tenant,
+		"method":    assignment.Method,
+		"subject":   assignment.Subject,
+		"role":      assignment.Role,
+		"updatedAt": assignment.UpdatedAt,
+		"updatedBy": assignment.UpdatedBy,
+	}}
+	opts := options.Update().SetUpsert(true)
+
+	filter := bson.M{"_id": models.RoleAssignmentID(tenant, assignment.Method, assignment.Subject)}
+	if _, err := r.collection.UpdateOne(ctx, filter, update, opts); err != nil {
+		return fmt.Errorf("failed to save role assignment: %w", err)
+	}
+	return nil
+}
+
+// DeleteRoleAssignment removes the role assignment of a caller
+func (r *MongoRoleAssignmentRepository) DeleteRoleAssignment(ctx context.Context, method models.AuthMethod, subject string) error {
+	id := models.RoleAssignmentID(models.TenantFromContext(ctx), method, subject)
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete role assignment: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("role assignment not found")
+	}
+	return nil
+}
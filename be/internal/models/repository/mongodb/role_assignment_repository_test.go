@@ -0,0 +1,101 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestFindRoleAssignment(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "role_assignments", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoRoleAssignmentRepository(mockDB)
+
+	ctx := context.Background()
+	expected := &models.RoleAssignment{ID: "api_key:key1", Method: models.AuthMethodAPIKey, Subject: "key1", Role: models.RoleAdmin}
+	mockCollection.On("FindOne", ctx, bson.M{"_id": "default:api_key:key1"}, mock.Anything).Return(NewMockSingleResult(nil, expected))
+
+	// Act
+	assignment, err := repo.FindRoleAssignment(ctx, models.AuthMethodAPIKey, "key1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expected, assignment)
+	mockCollection.AssertExpectations(t)
+}
+
+func TestFindRoleAssignment_Errors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		err     error
+		wantErr string
+	}{
+		"NotFound":      {err: mongo.ErrNoDocuments, wantErr: "role assignment not found"},
+		"DatabaseError": {err: errors.New("database error"), wantErr: "failed to retrieve role assignment: database error"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			mockDB := new(MockDatabase)
+			mockCollection := new(MockCollection)
+			mockDB.On("Collection", "role_assignments", mock.Anything).Return(mockCollection)
+			mockCollection.On("FindOne", mock.Anything, mock.Anything, mock.Anything).Return(NewMockSingleResult(tc.err, nil))
+
+			repo := NewMongoRoleAssignmentRepository(mockDB)
+
+			// Act
+			assignment, err := repo.FindRoleAssignment(context.Background(), models.AuthMethodToken, "u1")
+
+			// Assert
+			assert.Nil(t, assignment)
+			assert.EqualError(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestUpsertRoleAssignment(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "role_assignments", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoRoleAssignmentRepository(mockDB)
+
+	ctx := context.Background()
+	assignment := &models.RoleAssignment{Method: models.AuthMethodToken, Subject: "u1", Role: models.RoleOperator, UpdatedAt: time.Now()}
+	mockCollection.On("UpdateOne", ctx, bson.M{"_id": "default:token:u1"}, mock.Anything, mock.Anything).Return(&mongo.UpdateResult{UpsertedCount: 1}, nil)
+
+	// Act
+	err := repo.UpsertRoleAssignment(ctx, assignment)
+
+	// Assert
+	assert.NoError(t, err)
+	mockCollection.AssertExpectations(t)
+}
+
+func TestDeleteRoleAssignment_NotFound(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "role_assignments", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoRoleAssignmentRepository(mockDB)
+
+	ctx := context.Background()
+	mockCollection.On("DeleteOne", ctx, bson.M{"_id": "default:token:u1"}, mock.Anything).Return(&mongo.DeleteResult{DeletedCount: 0}, nil)
+
+	// Act
+	err := repo.DeleteRoleAssignment(ctx, models.AuthMethodToken, "u1")
+
+	// Assert
+	assert.EqualError(t, err, "role assignment not found")
+	mockCollection.AssertExpectations(t)
+}
@@ -0,0 +1,69 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoThresholdRepository implements the IThresholdRepository interface for MongoDB.
+// Thresholds are keyed by tenant and metric, so each metric has at most one document per tenant.
+type MongoThresholdRepository struct {
+	db         IDatabase
+	collection ICollection
+}
+
+// NewMongoThresholdRepository creates a new instance of MongoThresholdRepository
+func NewMongoThresholdRepository(db IDatabase) repository.IThresholdRepository {
+	return &MongoThresholdRepository{
+		db:         db,
+		collection: db.Collection("thresholds"),
+	}
+}
+
+// FindThresholds retrieves all configured thresholds
+func (r *MongoThresholdRepository) FindThresholds(ctx context.Context) ([]models.Threshold, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"tenant": models.TenantFromContext(ctx)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve thresholds: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	thresholds := []models.Threshold{}
+	if err := cursor.All(ctx, &thresholds); err != nil {
+		return nil, fmt.Errorf("failed to decode thresholds: %w", err)
+	}
+
+	return thresholds, nil
+}
+
+// UpsertThreshold creates or replaces the threshold of a metric
+func (r *MongoThresholdRepository) UpsertThreshold(ctx context.Context, threshold *models.Threshold) error {
+	tenant := models.TenantFromContext(ctx)
+	update := bson.M{"$set": bson.M{
+		"tenant":    tenant,
+		"metric":    threshold.Metric,
+		"notable":   threshold.Notable,
+		"major":     threshold.Major,
+		"updatedAt": threshold.UpdatedAt,
+	}}
+	opts := options.Update().SetUpsert(true)
+
+	filter := bson.M{"_id": models.ThresholdID(tenant, threshold.Metric)}
+	if _, err := r.collection.UpdateOne(ctx, filter, update, opts); err != nil {
+		return fmt.Errorf("failed to save threshold: %w", err)
+	}
+	return nil
+}
+
+// DeleteThreshold removes the configured threshold of a metric
+func (r *MongoThresholdRepository) DeleteThreshold(ctx context.Context, metric models.Metric) error {
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"_id": models.ThresholdID(models.TenantFromContext(ctx), metric)}); err != nil {
+		return fmt.Errorf("failed to delete threshold: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestUpsertThreshold(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "thresholds", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoThresholdRepository(mockDB)
+
+	ctx := context.Background()
+	updatedAt := time.Now()
+	threshold := &models.Threshold{Metric: models.MetricTemperature, Notable: 1, Major: 3, UpdatedAt: &updatedAt}
+
+	expectedUpdate := bson.M{"$set": bson.M{
+		"tenant": models.DefaultTenant, "metric": models.MetricTemperature, "notable": 1.0, "major": 3.0, "updatedAt": &updatedAt,
+	}}
+	mockCollection.On("UpdateOne", ctx, bson.M{"_id": "default:temperature"}, expectedUpdate,
+		mock.MatchedBy(func(opts []*options.UpdateOptions) bool {
+			return len(opts) == 1 && opts[0].Upsert != nil && *opts[0].Upsert
+		})).Return(&mongo.UpdateResult{UpsertedCount: 1}, nil)
+
+	// Act
+	err := repo.UpsertThreshold(ctx, threshold)
+
+	// Assert
+	assert.NoError(t, err)
+	mockCollection.AssertExpectations(t)
+}
+
+func TestUpsertThreshold_Error(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "thresholds", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoThresholdRepository(mockDB)
+
+	ctx := context.Background()
+	mockCollection.On("UpdateOne", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+	// Act
+	err := repo.UpsertThreshold(ctx, &models.Threshold{Metric: models.MetricTemperature, Notable: 1, Major: 3})
+
+	// Assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to save threshold")
+	mockCollection.AssertExpectations(t)
+}
+
+func TestFindThresholds_FindError(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "thresholds", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoThresholdRepository(mockDB)
+
+	ctx := context.Background()
+	mockCollection.On("Find", ctx, mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+	// Act
+	thresholds, err := repo.FindThresholds(ctx)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, thresholds)
+	assert.Contains(t, err.Error(), "failed to retrieve thresholds")
+	mockCollection.AssertExpectations(t)
+}
+
+func TestDeleteThreshold(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "thresholds", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoThresholdRepository(mockDB)
+
+	ctx := context.Background()
+	mockCollection.On("DeleteOne", ctx, bson.M{"_id": "default:humidity"}, mock.Anything).Return(&mongo.DeleteResult{DeletedCount: 0}, nil)
+
+	// Act
+	err := repo.DeleteThreshold(ctx, models.MetricHumidity)
+
+	// Assert
+	assert.NoError(t, err)
+	mockCollection.AssertExpectations(t)
+}
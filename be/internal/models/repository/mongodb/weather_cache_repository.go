@@ -29,6 +29,7 @@ func NewMongoWeatherCacheRepository(db IDatabase) repository.IWeatherCacheReposi
 
 // SaveWeatherCache saves a weather data cache entry
 func (r *MongoWeatherCacheRepository) SaveWeatherCache(ctx context.Context, cache *models.WeatherCache) (string, error) {
+	cache.Tenant = models.TenantFromContext(ctx)
 	result, err := r.collection.InsertOne(ctx, cache)
 	if err != nil {
 		return "", fmt.Errorf("failed to save weather cache: %w", err)
@@ -45,6 +46,7 @@ func (r *MongoWeatherCacheRepository) FindLatestWeatherCache(ctx context.Context
 
 	// Find the latest non-expired cache entry
 	filter := bson.M{
+		"tenant":    models.TenantFromContext(ctx),
 		"expiresAt": bson.M{"$gt": now},
 	}
 
@@ -74,6 +76,7 @@ func (r *MongoWeatherCacheRepository) FindWeatherCacheByTimestamp(ctx context.Co
 
 	// Find a cache entry within the time window that hasn't expired
 	filter := bson.M{
+		"tenant": models.TenantFromContext(ctx),
 		"timestamp": bson.M{
 			"$gte": windowStart,
 			"$lte": windowEnd,
@@ -97,6 +100,7 @@ func (r *MongoWeatherCacheRepository) DeleteExpiredCaches(ctx context.Context) e
 	now := time.Now()
 
 	filter := bson.M{
+		"tenant":    models.TenantFromContext(ctx),
 		"expiresAt": bson.M{"$lte": now},
 	}
 
@@ -107,3 +111,39 @@ func (r *MongoWeatherCacheRepository) DeleteExpiredCaches(ctx context.Context) e
 
 	return nil
 }
+
+// StreamWeatherCaches calls fn for every cache entry in the time range, decoding them one at a time
+func (r *MongoWeatherCacheRepository) StreamWeatherCaches(ctx context.Context, from, to time.Time, fn func(*models.WeatherCache) error) error {
+	filter := bson.M{"tenant": models.TenantFromContext(ctx)}
+	timeFilter := bson.M{}
+	if !from.IsZero() {
+		timeFilter["$gte"] = from
+	}
+	if !to.IsZero() {
+		timeFilter["$lte"] = to
+	}
+	if len(timeFilter) > 0 {
+		filter["timestamp"] = timeFilter
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve weather caches: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var cache models.WeatherCache
+		if err := cursor.Decode(&cache); err != nil {
+			return fmt.Errorf("failed to decode weather cache: %w", err)
+		}
+		if err := fn(&cache); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to retrieve weather caches: %w", err)
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/DangVTNhan/Scanner/be/pkg/openweather"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -356,3 +357,69 @@ func TestDeleteExpiredCaches_Error(t *testing.T) {
 	assert.Contains(t, err.Error(), expectedErr.Error())
 	mockCollection.AssertExpectations(t)
 }
+
+func TestStreamWeatherCaches(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "weather_cache", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoWeatherCacheRepository(mockDB)
+
+	ctx := context.Background()
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	caches := []models.WeatherCache{
+		{ID: "cache1", Timestamp: from, WeatherData: openweather.WeatherData{Temperature: 25}},
+		{ID: "cache2", Timestamp: to, WeatherData: openweather.WeatherData{Temperature: 26}},
+	}
+
+	mockCursor := NewMockCursor(nil)
+	mockCursor.On("Next", ctx).Return(true).Times(len(caches))
+	mockCursor.On("Next", ctx).Return(false).Once()
+	decoded := 0
+	mockCursor.On("Decode", mock.AnythingOfType("*models.WeatherCache")).Run(func(args mock.Arguments) {
+		*args.Get(0).(*models.WeatherCache) = caches[decoded]
+		decoded++
+	}).Return(nil)
+	mockCursor.On("Err").Return(nil)
+	mockCursor.On("Close", ctx).Return(nil)
+
+	expectedFilter := bson.M{"tenant": models.DefaultTenant, "timestamp": bson.M{"$gte": from, "$lte": to}}
+	mockCollection.On("Find", ctx, expectedFilter, mock.Anything).Return(mockCursor, nil)
+
+	// Act
+	var streamed []string
+	err := repo.StreamWeatherCaches(ctx, from, to, func(cache *models.WeatherCache) error {
+		streamed = append(streamed, cache.ID)
+		return nil
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cache1", "cache2"}, streamed)
+	mockCollection.AssertExpectations(t)
+	mockCursor.AssertExpectations(t)
+}
+
+func TestStreamWeatherCaches_Error(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabase)
+	mockCollection := new(MockCollection)
+	mockDB.On("Collection", "weather_cache", mock.Anything).Return(mockCollection)
+
+	repo := NewMongoWeatherCacheRepository(mockDB)
+
+	ctx := context.Background()
+	mockCollection.On("Find", ctx, bson.M{"tenant": models.DefaultTenant}, mock.Anything).Return(nil, errors.New("database error"))
+
+	// Act
+	err := repo.StreamWeatherCaches(ctx, time.Time{}, time.Time{}, func(*models.WeatherCache) error {
+		return nil
+	})
+
+	// Assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to retrieve weather caches")
+	mockCollection.AssertExpectations(t)
+}
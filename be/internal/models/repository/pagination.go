@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+)
+
+// DefaultPageLimit is the number of reports returned when a request sets no limit
+const DefaultPageLimit = 10
+
+// PageLimit returns the effective page size of a paginated request
+func PageLimit(req *request.PaginatedReportsRequest) int {
+	if req.Limit > 0 {
+		return req.Limit
+	}
+	return DefaultPageLimit
+}
+
+// ValidateCursor checks that the request's cursor was issued for the effective sort keys
+func ValidateCursor(req *request.PaginatedReportsRequest, keys []request.SortKey) error {
+	if req.Cursor != nil && req.Cursor.Sort != request.FormatSortSpec(keys) {
+		return fmt.Errorf("invalid cursor: cursor was issued for a different sort order")
+	}
+	return nil
+}
+
+// BuildReportPage assembles a page from rows fetched in query order. Backends fetch up to
+// PageLimit+1 rows; the extra row only signals that more results exist in the fetch direction.
+// For a backward (prev) cursor the rows are expected in reverse sort order and are flipped back.
+// keys are the effective sort keys, including the tie-break. TotalCount is left for the caller to fill in.
+func BuildReportPage(rows []models.WeatherReport, req *request.PaginatedReportsRequest, keys []request.SortKey) *response.PaginatedReportsResponse {
+	limit := PageLimit(req)
+
+	extra := len(rows) > limit
+	if extra {
+		rows = rows[:limit]
+	}
+
+	backward := req.Cursor != nil && req.Cursor.Direction == request.CursorPrev
+	if backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	page := &response.PaginatedReportsResponse{Reports: rows}
+	if page.Reports == nil {
+		page.Reports = []models.WeatherReport{}
+	}
+
+	switch {
+	case backward:
+		// We came from a later page, so there is always more after this one
+		page.HasMore = true
+		if extra && len(rows) > 0 {
+			page.PrevCursor = cursorAt(rows[0], keys, request.CursorPrev)
+		}
+		if len(rows) > 0 {
+			page.NextCursor = cursorAt(rows[len(rows)-1], keys, request.CursorNext)
+		} else {
+			page.NextCursor = req.Cursor.WithDirection(request.CursorNext).Encode()
+		}
+	case req.Cursor != nil:
+		page.HasMore = extra
+		if extra {
+			page.NextCursor = cursorAt(rows[len(rows)-1], keys, request.CursorNext)
+		}
+		if len(rows) > 0 {
+			page.PrevCursor = cursorAt(rows[0], keys, request.CursorPrev)
+		} else {
+			page.PrevCursor = req.Cursor.WithDirection(request.CursorPrev).Encode()
+		}
+	default:
+		page.HasMore = extra
+		if extra {
+			page.NextCursor = cursorAt(rows[len(rows)-1], keys, request.CursorNext)
+		}
+		if req.Offset > 0 && len(rows) > 0 {
+			page.PrevCursor = cursorAt(rows[0], keys, request.CursorPrev)
+		}
+	}
+
+	return page
+}
+
+// cursorAt returns the encoded cursor positioned at report
+func cursorAt(report models.WeatherReport, keys []request.SortKey, direction request.CursorDirection) string {
+	return request.NewReportCursor(report, keys, direction).Encode()
+}
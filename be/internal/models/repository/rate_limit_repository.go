@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+)
+
+// IRateLimitRepository defines the interface for the token buckets of rate limiting.
+// Buckets are not scoped to a tenant; their keys identify the client and the routes they limit.
+type IRateLimitRepository interface {
+	// TakeToken takes a token at now from the bucket of key, which holds the tokens of limit.
+	// A bucket that does not exist yet is full.
+	TakeToken(ctx context.Context, key string, limit models.RateLimit, now time.Time) (*models.RateLimitResult, error)
+}
@@ -2,26 +2,73 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/DangVTNhan/Scanner/be/internal/models"
 	"github.com/DangVTNhan/Scanner/be/internal/models/request"
 	"github.com/DangVTNhan/Scanner/be/internal/models/response"
 )
 
-// IReportRepository defines the interface for report data access
+// IReportRepository defines the interface for report data access.
+// Soft-deleted reports are excluded from every read unless a request explicitly includes them.
+// Every method only sees the reports of the tenant of ctx, see models.TenantFromContext, and inserted
+// reports are stamped with it; only PurgeDeletedReports works across tenants.
 type IReportRepository interface {
 	// InsertReport inserts a new weather report into the database
 	InsertReport(ctx context.Context, report *models.WeatherReport) (string, error)
 
+	// InsertReports inserts weather reports in a single batch and returns their IDs in order
+	InsertReports(ctx context.Context, reports []models.WeatherReport) ([]string, error)
+
 	// FindAllReports retrieves all weather reports
 	FindAllReports(ctx context.Context) ([]models.WeatherReport, error)
 
 	// FindPaginatedReports retrieves weather reports with pagination and filtering
 	FindPaginatedReports(ctx context.Context, req *request.PaginatedReportsRequest) (*response.PaginatedReportsResponse, error)
 
+	// StreamReports calls fn for every report matching the filters and sort of req, one at a time,
+	// without loading them all into memory. Limit, offset and cursor are ignored.
+	// Streaming stops at the first error returned by fn, which is returned as is.
+	StreamReports(ctx context.Context, req *request.PaginatedReportsRequest, fn func(*models.WeatherReport) error) error
+
 	// FindReportByID retrieves a weather report by its ID
 	FindReportByID(ctx context.Context, id string) (*models.WeatherReport, error)
 
+	// FindReportsByIDs retrieves the weather reports with the given IDs in a single query.
+	// IDs that do not exist are skipped, and the order of the result is unspecified.
+	FindReportsByIDs(ctx context.Context, ids []string) ([]models.WeatherReport, error)
+
+	// FindExistingTimestamps returns which of the given timestamps already have a report,
+	// including soft-deleted reports, in unspecified order
+	FindExistingTimestamps(ctx context.Context, timestamps []time.Time) ([]time.Time, error)
+
+	// AggregateReports calculates the statistics of every metric over the reports with a timestamp in [from, to]
+	AggregateReports(ctx context.Context, from, to time.Time) (*response.PeriodStatistics, error)
+
+	// AggregateReportBuckets calculates the statistics of every metric per bucket of the reports with a timestamp
+	// in [from, to]. Bucket i covers [from + i*bucket, from + (i+1)*bucket), capped at to. Buckets are returned in
+	// time order and buckets without reports are omitted.
+	AggregateReportBuckets(ctx context.Context, from, to time.Time, bucket time.Duration) ([]response.PeriodStatistics, error)
+
 	// CountReports counts the total number of reports
 	CountReports(ctx context.Context) (int64, error)
+
+	// UpdateReport sets the annotations given in update, leaving nil fields unchanged.
+	// It returns "report not found" if the report does not exist or is deleted.
+	UpdateReport(ctx context.Context, id string, update *request.ReportUpdateRequest) error
+
+	// FindTagCounts counts the reports of every tag, most used first and then by tag
+	FindTagCounts(ctx context.Context) ([]response.TagCount, error)
+
+	// DeleteReport soft-deletes a report by setting its deletedAt.
+	// It returns "report not found" if the report does not exist or is already deleted.
+	DeleteReport(ctx context.Context, id string, deletedAt time.Time) error
+
+	// RestoreReport clears the deletedAt of a soft-deleted report.
+	// It returns "deleted report not found" if there is no deleted report with the ID.
+	RestoreReport(ctx context.Context, id string) error
+
+	// PurgeDeletedReports permanently removes the reports of every tenant deleted at or before the given time
+	// and returns how many were removed
+	PurgeDeletedReports(ctx context.Context, before time.Time) (int64, error)
 }
@@ -0,0 +1,153 @@
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// APIKeyRepositoryFactory returns an empty API key repository for a single test
+type APIKeyRepositoryFactory func(t *testing.T) repository.IAPIKeyRepository
+
+// RunAPIKeyRepositoryTests runs the IAPIKeyRepository conformance suite against newRepo
+func RunAPIKeyRepositoryTests(t *testing.T, newRepo APIKeyRepositoryFactory) {
+	t.Run("InsertAndFind", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		key := fixtureAPIKey("grafana", 0)
+		id, err := repo.InsertAPIKey(ctx, &key)
+		require.NoError(t, err)
+		assert.NotEmpty(t, id)
+
+		found, err := repo.FindAPIKeyByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, id, found.ID)
+		assert.Equal(t, key.Name, found.Name)
+		assert.Equal(t, key.Prefix, found.Prefix)
+		assert.Equal(t, key.Hash, found.Hash)
+		assert.True(t, key.CreatedAt.Equal(found.CreatedAt))
+		assert.Nil(t, found.LastUsedAt)
+		assert.Nil(t, found.RevokedAt)
+
+		found, err = repo.FindAPIKeyByHash(ctx, key.Hash)
+		require.NoError(t, err)
+		assert.Equal(t, id, found.ID)
+	})
+
+	t.Run("FindNotFound", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		_, err := repo.FindAPIKeyByID(ctx, "000000000000000000000000")
+		require.Error(t, err)
+		assert.Equal(t, "api key not found", err.Error())
+
+		_, err = repo.FindAPIKeyByHash(ctx, "unknown")
+		require.Error(t, err)
+		assert.Equal(t, "api key not found", err.Error())
+	})
+
+	t.Run("ListNewestFirst", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		ids := make([]string, 3)
+		for i := range ids {
+			key := fixtureAPIKey("key", i)
+			id, err := repo.InsertAPIKey(ctx, &key)
+			require.NoError(t, err)
+			ids[i] = id
+		}
+
+		keys, err := repo.FindAPIKeys(ctx)
+		require.NoError(t, err)
+		require.Len(t, keys, 3)
+		assert.Equal(t, []string{ids[2], ids[1], ids[0]}, []string{keys[0].ID, keys[1].ID, keys[2].ID})
+	})
+
+	t.Run("RevokeKeepsFirstTime", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		key := fixtureAPIKey("ci", 0)
+		id, err := repo.InsertAPIKey(ctx, &key)
+		require.NoError(t, err)
+
+		revokedAt := baseTime.Add(time.Hour)
+		require.NoError(t, repo.RevokeAPIKey(ctx, id, revokedAt))
+		require.NoError(t, repo.RevokeAPIKey(ctx, id, revokedAt.Add(time.Hour)))
+
+		found, err := repo.FindAPIKeyByHash(ctx, key.Hash)
+		require.NoError(t, err)
+		require.NotNil(t, found.RevokedAt)
+		assert.True(t, revokedAt.Equal(*found.RevokedAt))
+
+		err = repo.RevokeAPIKey(ctx, "000000000000000000000000", revokedAt)
+		require.Error(t, err)
+		assert.Equal(t, "api key not found", err.Error())
+	})
+
+	t.Run("UpdateLastUsed", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		key := fixtureAPIKey("ci", 0)
+		id, err := repo.InsertAPIKey(ctx, &key)
+		require.NoError(t, err)
+
+		usedAt := baseTime.Add(2 * time.Hour)
+		require.NoError(t, repo.UpdateAPIKeyLastUsed(ctx, id, usedAt))
+
+		found, err := repo.FindAPIKeyByID(ctx, id)
+		require.NoError(t, err)
+		require.NotNil(t, found.LastUsedAt)
+		assert.True(t, usedAt.Equal(*found.LastUsedAt))
+	})
+
+	t.Run("TenantIsolation", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx, other := tenantContexts()
+
+		key := fixtureAPIKey("grafana", 0)
+		id, err := repo.InsertAPIKey(ctx, &key)
+		require.NoError(t, err)
+
+		found, err := repo.FindAPIKeyByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, tenant, found.Tenant)
+
+		_, err = repo.FindAPIKeyByID(other, id)
+		require.Error(t, err)
+		assert.Equal(t, "api key not found", err.Error())
+
+		keys, err := repo.FindAPIKeys(other)
+		require.NoError(t, err)
+		assert.Empty(t, keys)
+
+		err = repo.RevokeAPIKey(other, id, baseTime)
+		require.Error(t, err)
+		assert.Equal(t, "api key not found", err.Error())
+
+		// Keys are authenticated before their tenant is known
+		found, err = repo.FindAPIKeyByHash(other, key.Hash)
+		require.NoError(t, err)
+		assert.Equal(t, tenant, found.Tenant)
+	})
+}
+
+// fixtureAPIKey returns an API key named name, created minutes after baseTime, with a hash unique to both
+func fixtureAPIKey(name string, minutes int) models.APIKey {
+	return models.APIKey{
+		Name:      name,
+		Prefix:    "wr_" + name,
+		Hash:      fmt.Sprintf("%s-%d", name, minutes),
+		CreatedAt: baseTime.Add(time.Duration(minutes) * time.Minute),
+	}
+}
@@ -0,0 +1,171 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// AuditLogRepositoryFactory returns an empty audit log repository for a single test
+type AuditLogRepositoryFactory func(t *testing.T) repository.IAuditLogRepository
+
+// RunAuditLogRepositoryTests runs the IAuditLogRepository conformance suite against newRepo
+func RunAuditLogRepositoryTests(t *testing.T, newRepo AuditLogRepositoryFactory) {
+	// insertEntries appends entries a minute apart from baseTime and returns their IDs in insertion order
+	insertEntries := func(t *testing.T, repo repository.IAuditLogRepository, ctx context.Context, entries ...models.AuditEntry) []string {
+		ids := make([]string, len(entries))
+		for i := range entries {
+			entries[i].Time = baseTime.Add(time.Duration(i) * time.Minute)
+			id, err := repo.InsertAuditEntry(ctx, &entries[i])
+			require.NoError(t, err)
+			ids[i] = id
+		}
+		return ids
+	}
+
+	entryIDs := func(entries []models.AuditEntry) []string {
+		ids := make([]string, len(entries))
+		for i := range entries {
+			ids[i] = entries[i].ID
+		}
+		return ids
+	}
+
+	t.Run("InsertAndFind", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		ids := insertEntries(t, repo, ctx,
+			models.AuditEntry{
+				Actor:      &models.AuditActor{Method: models.AuthMethodAPIKey, Subject: "key1", Name: "grafana", Role: models.RoleOperator},
+				ClientIP:   "10.0.0.1",
+				Method:     "PATCH",
+				Route:      "/api/reports/{id}",
+				Path:       "/api/reports/r1",
+				PathParams: map[string]string{"id": "r1"},
+				Body:       `{"notes":"checked"}`,
+				BodySize:   19,
+				Status:     200,
+				ResourceID: "r1",
+				LatencyMs:  12,
+			},
+			models.AuditEntry{Method: "POST", Route: "/api/reports", Path: "/api/reports", Status: 201},
+		)
+		require.NotEqual(t, ids[0], ids[1])
+
+		entries, err := repo.FindAuditEntries(ctx, &request.AuditLogRequest{}, 10)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, []string{ids[1], ids[0]}, entryIDs(entries), "newest first")
+
+		found := entries[1]
+		assert.True(t, baseTime.Equal(found.Time))
+		assert.Equal(t, &models.AuditActor{Method: models.AuthMethodAPIKey, Subject: "key1", Name: "grafana", Role: models.RoleOperator}, found.Actor)
+		assert.Equal(t, "10.0.0.1", found.ClientIP)
+		assert.Equal(t, "/api/reports/{id}", found.Route)
+		assert.Equal(t, map[string]string{"id": "r1"}, found.PathParams)
+		assert.Equal(t, `{"notes":"checked"}`, found.Body)
+		assert.Equal(t, int64(19), found.BodySize)
+		assert.Equal(t, 200, found.Status)
+		assert.Equal(t, "r1", found.ResourceID)
+		assert.Equal(t, int64(12), found.LatencyMs)
+		assert.Nil(t, entries[0].Actor)
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		key1 := &models.AuditActor{Method: models.AuthMethodAPIKey, Subject: "key1"}
+		key2 := &models.AuditActor{Method: models.AuthMethodAPIKey, Subject: "key2"}
+		ids := insertEntries(t, repo, ctx,
+			models.AuditEntry{Actor: key1, Method: "POST", Route: "/api/reports", Status: 201, ResourceID: "r1"},
+			models.AuditEntry{Actor: key2, Method: "DELETE", Route: "/api/reports/{id}", Status: 403},
+			models.AuditEntry{Actor: key1, Method: "DELETE", Route: "/api/reports/{id}", Status: 204, ResourceID: "r1"},
+			models.AuditEntry{Method: "PUT", Route: "/api/thresholds/{metric}", Status: 200},
+		)
+
+		for name, tc := range map[string]struct {
+			req  request.AuditLogRequest
+			want []string
+		}{
+			"Actor":      {req: request.AuditLogRequest{Actor: "key1"}, want: []string{ids[2], ids[0]}},
+			"Method":     {req: request.AuditLogRequest{Method: "DELETE"}, want: []string{ids[2], ids[1]}},
+			"Route":      {req: request.AuditLogRequest{Route: "/api/thresholds/{metric}"}, want: []string{ids[3]}},
+			"ResourceID": {req: request.AuditLogRequest{ResourceID: "r1"}, want: []string{ids[2], ids[0]}},
+			"Success":    {req: request.AuditLogRequest{Outcome: request.AuditOutcomeSuccess}, want: []string{ids[3], ids[2], ids[0]}},
+			"Failure":    {req: request.AuditLogRequest{Outcome: request.AuditOutcomeFailure}, want: []string{ids[1]}},
+			"TimeRange": {
+				req:  request.AuditLogRequest{From: baseTime.Add(time.Minute), To: baseTime.Add(2 * time.Minute)},
+				want: []string{ids[2], ids[1]},
+			},
+			"Combined": {req: request.AuditLogRequest{Actor: "key1", Method: "DELETE"}, want: []string{ids[2]}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				entries, err := repo.FindAuditEntries(ctx, &tc.req, 10)
+				require.NoError(t, err)
+				assert.Equal(t, tc.want, entryIDs(entries))
+			})
+		}
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		ids := insertEntries(t, repo, ctx,
+			models.AuditEntry{Method: "POST", Status: 201},
+			models.AuditEntry{Method: "POST", Status: 201},
+			models.AuditEntry{Method: "POST", Status: 201},
+		)
+
+		page, err := repo.FindAuditEntries(ctx, &request.AuditLogRequest{}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[2], ids[1]}, entryIDs(page))
+
+		page, err = repo.FindAuditEntries(ctx, &request.AuditLogRequest{Cursor: ids[1]}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[0]}, entryIDs(page))
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		ids := insertEntries(t, repo, ctx,
+			models.AuditEntry{Method: "POST", Status: 201},
+			models.AuditEntry{Method: "PATCH", Status: 200},
+			models.AuditEntry{Method: "POST", Status: 400},
+		)
+
+		var streamed []string
+		err := repo.StreamAuditEntries(ctx, &request.AuditLogRequest{Method: "POST"}, func(entry *models.AuditEntry) error {
+			streamed = append(streamed, entry.ID)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[2], ids[0]}, streamed)
+	})
+
+	t.Run("TenantIsolation", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx, other := tenantContexts()
+
+		insertEntries(t, repo, ctx, models.AuditEntry{Method: "POST", Status: 201})
+
+		entries, err := repo.FindAuditEntries(ctx, &request.AuditLogRequest{}, 10)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, tenant, entries[0].Tenant)
+
+		entries, err = repo.FindAuditEntries(other, &request.AuditLogRequest{}, 10)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
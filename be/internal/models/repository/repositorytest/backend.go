@@ -0,0 +1,63 @@
+package repositorytest
+
+import (
+	"testing"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+)
+
+// Repositories holds a repository of every kind, all backed by the same empty store
+type Repositories struct {
+	Reports         repository.IReportRepository
+	WeatherCaches   repository.IWeatherCacheRepository
+	Comparisons     repository.IComparisonRepository
+	Thresholds      repository.IThresholdRepository
+	APIKeys         repository.IAPIKeyRepository
+	RoleAssignments repository.IRoleAssignmentRepository
+	RateLimits      repository.IRateLimitRepository
+	AuditLogs       repository.IAuditLogRepository
+}
+
+// Backend opens an empty store of a repository implementation for a single test
+type Backend func(t *testing.T) Repositories
+
+// suites are the conformance suites of every repository kind. A new repository adds its
+// suite here and its field to Repositories; each backend then only constructs it.
+var suites = []struct {
+	name string
+	run  func(t *testing.T, open Backend)
+}{
+	{"ReportRepository", func(t *testing.T, open Backend) {
+		RunReportRepositoryTests(t, func(t *testing.T) repository.IReportRepository { return open(t).Reports })
+	}},
+	{"WeatherCacheRepository", func(t *testing.T, open Backend) {
+		RunWeatherCacheRepositoryTests(t, func(t *testing.T) repository.IWeatherCacheRepository { return open(t).WeatherCaches })
+	}},
+	{"ComparisonRepository", func(t *testing.T, open Backend) {
+		RunComparisonRepositoryTests(t, func(t *testing.T) repository.IComparisonRepository { return open(t).Comparisons })
+	}},
+	{"ThresholdRepository", func(t *testing.T, open Backend) {
+		RunThresholdRepositoryTests(t, func(t *testing.T) repository.IThresholdRepository { return open(t).Thresholds })
+	}},
+	{"APIKeyRepository", func(t *testing.T, open Backend) {
+		RunAPIKeyRepositoryTests(t, func(t *testing.T) repository.IAPIKeyRepository { return open(t).APIKeys })
+	}},
+	{"RoleAssignmentRepository", func(t *testing.T, open Backend) {
+		RunRoleAssignmentRepositoryTests(t, func(t *testing.T) repository.IRoleAssignmentRepository { return open(t).RoleAssignments })
+	}},
+	{"RateLimitRepository", func(t *testing.T, open Backend) {
+		RunRateLimitRepositoryTests(t, func(t *testing.T) repository.IRateLimitRepository { return open(t).RateLimits })
+	}},
+	{"AuditLogRepository", func(t *testing.T, open Backend) {
+		RunAuditLogRepositoryTests(t, func(t *testing.T) repository.IAuditLogRepository { return open(t).AuditLogs })
+	}},
+}
+
+// RunBackendTests runs the conformance suite of every repository kind against the backend
+func RunBackendTests(t *testing.T, open Backend) {
+	for _, suite := range suites {
+		t.Run(suite.name, func(t *testing.T) {
+			suite.run(t, open)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ComparisonRepositoryFactory returns an empty comparison repository for a single test
+type ComparisonRepositoryFactory func(t *testing.T) repository.IComparisonRepository
+
+// RunComparisonRepositoryTests runs the IComparisonRepository conformance suite against newRepo
+func RunComparisonRepositoryTests(t *testing.T, newRepo ComparisonRepositoryFactory) {
+	t.Run("InsertAndFindByID", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		comparison := fixtureComparison("Morning vs evening", 0)
+		id, err := repo.InsertComparison(ctx, &comparison)
+		require.NoError(t, err)
+		assert.NotEmpty(t, id)
+
+		found, err := repo.FindComparisonByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, id, found.ID)
+		assert.Equal(t, comparison.Title, found.Title)
+		assert.Equal(t, comparison.Notes, found.Notes)
+		assert.Equal(t, comparison.Report1.ID, found.Report1.ID)
+		assert.Equal(t, comparison.Report2.Temperature, found.Report2.Temperature)
+		assert.True(t, comparison.Report1.Timestamp.Equal(found.Report1.Timestamp))
+		assert.True(t, comparison.CreatedAt.Equal(found.CreatedAt))
+	})
+
+	t.Run("FindByIDNotFound", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.FindComparisonByID(context.Background(), "000000000000000000000000")
+		require.Error(t, err)
+		assert.Equal(t, "comparison not found", err.Error())
+	})
+
+	t.Run("ListNewestFirstWithPaging", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		ids := make([]string, 3)
+		for i := range ids {
+			comparison := fixtureComparison("Comparison", i)
+			id, err := repo.InsertComparison(ctx, &comparison)
+			require.NoError(t, err)
+			ids[i] = id
+		}
+
+		comparisons, err := repo.FindComparisons(ctx, 2, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[2], ids[1]}, comparisonIDs(comparisons))
+
+		comparisons, err = repo.FindComparisons(ctx, 2, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[0]}, comparisonIDs(comparisons))
+
+		comparisons, err = repo.FindComparisons(ctx, 2, 5)
+		require.NoError(t, err)
+		assert.Empty(t, comparisons)
+
+		count, err := repo.CountComparisons(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		comparison := fixtureComparison("To delete", 0)
+		id, err := repo.InsertComparison(ctx, &comparison)
+		require.NoError(t, err)
+
+		require.NoError(t, repo.DeleteComparison(ctx, id))
+
+		_, err = repo.FindComparisonByID(ctx, id)
+		require.Error(t, err)
+		assert.Equal(t, "comparison not found", err.Error())
+
+		err = repo.DeleteComparison(ctx, id)
+		require.Error(t, err)
+		assert.Equal(t, "comparison not found", err.Error())
+	})
+
+	t.Run("TenantIsolation", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx, other := tenantContexts()
+
+		comparison := fixtureComparison("Morning vs evening", 0)
+		id, err := repo.InsertComparison(ctx, &comparison)
+		require.NoError(t, err)
+
+		found, err := repo.FindComparisonByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, tenant, found.Tenant)
+
+		_, err = repo.FindComparisonByID(other, id)
+		require.Error(t, err)
+		assert.Equal(t, "comparison not found", err.Error())
+
+		comparisons, err := repo.FindComparisons(other, 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, comparisons)
+
+		count, err := repo.CountComparisons(other)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+
+		err = repo.DeleteComparison(other, id)
+		require.Error(t, err)
+		assert.Equal(t, "comparison not found", err.Error())
+	})
+}
+
+// fixtureComparison returns a comparison of two report snapshots, created minutes after baseTime
+func fixtureComparison(title string, minutes int) models.Comparison {
+	report1 := fixtureReport(0, 25)
+	report1.ID = "000000000000000000000001"
+	report2 := fixtureReport(1, 27)
+	report2.ID = "000000000000000000000002"
+
+	return models.Comparison{
+		Title:     title,
+		Notes:     "Fixture notes",
+		Report1:   report1,
+		Report2:   report2,
+		CreatedAt: baseTime.Add(time.Duration(minutes) * time.Minute),
+	}
+}
+
+// comparisonIDs returns the IDs of comparisons in order
+func comparisonIDs(comparisons []models.Comparison) []string {
+	ids := make([]string, len(comparisons))
+	for i := range comparisons {
+		ids[i] = comparisons[i].ID
+	}
+	return ids
+}
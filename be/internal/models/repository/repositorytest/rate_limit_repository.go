@@ -0,0 +1,90 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RateLimitRepositoryFactory returns a rate limit repository whose buckets are all full for a single test
+type RateLimitRepositoryFactory func(t *testing.T) repository.IRateLimitRepository
+
+// RunRateLimitRepositoryTests runs the IRateLimitRepository conformance suite against newRepo
+func RunRateLimitRepositoryTests(t *testing.T, newRepo RateLimitRepositoryFactory) {
+	limit := models.RateLimit{Requests: 3, Period: time.Minute}
+
+	t.Run("BurstThenDenied", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		for remaining := 2; remaining >= 0; remaining-- {
+			result, err := repo.TakeToken(ctx, "ip:10.0.0.1", limit, baseTime)
+			require.NoError(t, err)
+			assert.True(t, result.Allowed)
+			assert.Equal(t, remaining, result.Remaining)
+			assert.Equal(t, time.Duration(3-remaining)*20*time.Second, result.Reset)
+		}
+
+		result, err := repo.TakeToken(ctx, "ip:10.0.0.1", limit, baseTime.Add(5*time.Second))
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, 0, result.Remaining)
+		assert.Equal(t, 15*time.Second, result.RetryAfter)
+		assert.Equal(t, 55*time.Second, result.Reset)
+	})
+
+	t.Run("Refills", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		for i := 0; i < 3; i++ {
+			_, err := repo.TakeToken(ctx, "api_key:k1", limit, baseTime)
+			require.NoError(t, err)
+		}
+
+		// One token is back after an interval, and the denied request in between does not use one
+		result, err := repo.TakeToken(ctx, "api_key:k1", limit, baseTime.Add(19*time.Second))
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+
+		result, err = repo.TakeToken(ctx, "api_key:k1", limit, baseTime.Add(20*time.Second))
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 0, result.Remaining)
+
+		// A bucket left alone is full again
+		result, err = repo.TakeToken(ctx, "api_key:k1", limit, baseTime.Add(time.Hour))
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 2, result.Remaining)
+	})
+
+	t.Run("KeysAreSeparate", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		for i := 0; i < 3; i++ {
+			_, err := repo.TakeToken(ctx, "ip:10.0.0.1 POST /api/reports", limit, baseTime)
+			require.NoError(t, err)
+		}
+
+		result, err := repo.TakeToken(ctx, "ip:10.0.0.2 POST /api/reports", limit, baseTime)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+
+		result, err = repo.TakeToken(ctx, "ip:10.0.0.1 *", limit, baseTime)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+
+		// Buckets are shared by every tenant
+		other := models.ContextWithTenant(ctx, tenant)
+		result, err = repo.TakeToken(other, "ip:10.0.0.1 POST /api/reports", limit, baseTime)
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+	})
+}
@@ -0,0 +1,912 @@
+// Package repositorytest provides conformance suites that every repository backend must pass.
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ReportRepositoryFactory returns an empty report repository for a single test
+type ReportRepositoryFactory func(t *testing.T) repository.IReportRepository
+
+// baseTime is the reference timestamp for all fixtures, at millisecond precision like BSON datetimes
+var baseTime = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// Tenants of the TenantIsolation tests
+const (
+	tenant      = "changi-ops"
+	otherTenant = "seletar-ops"
+)
+
+// tenantContexts returns the contexts of tenant and otherTenant
+func tenantContexts() (context.Context, context.Context) {
+	return models.ContextWithTenant(context.Background(), tenant), models.ContextWithTenant(context.Background(), otherTenant)
+}
+
+// RunReportRepositoryTests runs the IReportRepository conformance suite against newRepo
+func RunReportRepositoryTests(t *testing.T, newRepo ReportRepositoryFactory) {
+	t.Run("InsertAndFindByID", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		report := fixtureReport(0, 25.5)
+		id, err := repo.InsertReport(ctx, &report)
+		require.NoError(t, err)
+		assert.NotEmpty(t, id)
+
+		found, err := repo.FindReportByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, id, found.ID)
+		assert.True(t, report.Timestamp.Equal(found.Timestamp))
+		assert.Equal(t, report.Temperature, found.Temperature)
+		assert.Equal(t, report.Pressure, found.Pressure)
+		assert.Equal(t, report.Humidity, found.Humidity)
+		assert.Equal(t, report.CloudCover, found.CloudCover)
+	})
+
+	t.Run("FindByIDNotFound", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		_, err := repo.FindReportByID(ctx, "000000000000000000000000")
+		require.Error(t, err)
+		assert.Equal(t, "report not found", err.Error())
+
+		_, err = repo.FindReportByID(ctx, "not-an-object-id")
+		require.Error(t, err)
+		assert.Equal(t, "report not found", err.Error())
+	})
+
+	t.Run("FindByIDs", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		ids := seedReports(t, repo, 4)
+
+		reports, err := repo.FindReportsByIDs(ctx, []string{ids[3], ids[1], "000000000000000000000000", "not-an-object-id"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{ids[1], ids[3]}, reportIDs(reports))
+
+		reports, err = repo.FindReportsByIDs(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, reports)
+	})
+
+	t.Run("InsertReportsInBatch", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		ids, err := repo.InsertReports(ctx, []models.WeatherReport{fixtureReport(0, 25), fixtureReport(1, 26)})
+		require.NoError(t, err)
+		require.Len(t, ids, 2)
+
+		found, err := repo.FindReportByID(ctx, ids[1])
+		require.NoError(t, err)
+		assert.Equal(t, 26.0, found.Temperature)
+
+		ids, err = repo.InsertReports(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, ids)
+	})
+
+	t.Run("FindExistingTimestampsIncludesDeleted", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		ids := seedReports(t, repo, 3)
+		require.NoError(t, repo.DeleteReport(ctx, ids[2], baseTime))
+
+		existing, err := repo.FindExistingTimestamps(ctx, []time.Time{
+			baseTime.Add(time.Hour),
+			baseTime.Add(2 * time.Hour),
+			baseTime.Add(5 * time.Hour),
+		})
+		require.NoError(t, err)
+		require.Len(t, existing, 2)
+		assert.True(t, existing[0].Equal(baseTime.Add(time.Hour)) || existing[1].Equal(baseTime.Add(time.Hour)))
+		assert.True(t, existing[0].Equal(baseTime.Add(2*time.Hour)) || existing[1].Equal(baseTime.Add(2*time.Hour)))
+
+		existing, err = repo.FindExistingTimestamps(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, existing)
+	})
+
+	t.Run("AggregateTimeRangeInclusive", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		seedReports(t, repo, 5) // temperatures 25..29, one per hour from baseTime
+
+		stats, err := repo.AggregateReports(ctx, baseTime.Add(time.Hour), baseTime.Add(3*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 3, stats.Count)
+
+		temperature := stats.Metrics[models.MetricTemperature]
+		assert.InDelta(t, 27.0, temperature.Mean, 1e-9)
+		assert.Equal(t, 26.0, temperature.Min)
+		assert.Equal(t, 28.0, temperature.Max)
+		assert.Equal(t, 2.0, temperature.Range)
+		assert.InDelta(t, 0.8165, temperature.StdDev, 1e-4)
+		assert.InDelta(t, 1012.0, stats.Metrics[models.MetricPressure].Mean, 1e-9)
+	})
+
+	t.Run("AggregateEmptyRange", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		seedReports(t, repo, 2)
+
+		stats, err := repo.AggregateReports(ctx, baseTime.Add(-2*time.Hour), baseTime.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 0, stats.Count)
+		assert.Empty(t, stats.Metrics)
+	})
+
+	t.Run("AggregateBucketsOmitsEmptyBuckets", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		seedReports(t, repo, 5) // temperatures 25..29, one per hour from baseTime
+
+		from, to := baseTime.Add(-2*time.Hour), baseTime.Add(4*time.Hour)
+		buckets, err := repo.AggregateReportBuckets(ctx, from, to, 2*time.Hour)
+		require.NoError(t, err)
+		require.Len(t, buckets, 3, "the bucket before the first report is omitted")
+
+		assert.True(t, buckets[0].From.Equal(baseTime))
+		assert.True(t, buckets[0].To.Equal(baseTime.Add(2*time.Hour)))
+		assert.Equal(t, 2, buckets[0].Count)
+		assert.Equal(t, 25.0, buckets[0].Metrics[models.MetricTemperature].Min)
+		assert.Equal(t, 26.0, buckets[0].Metrics[models.MetricTemperature].Max)
+		assert.InDelta(t, 25.5, buckets[0].Metrics[models.MetricTemperature].Mean, 1e-9)
+
+		assert.Equal(t, 2, buckets[1].Count)
+		assert.InDelta(t, 27.5, buckets[1].Metrics[models.MetricTemperature].Mean, 1e-9)
+
+		assert.True(t, buckets[2].From.Equal(to))
+		assert.True(t, buckets[2].To.Equal(to), "the last bucket is capped at to")
+		assert.Equal(t, 1, buckets[2].Count)
+		assert.Equal(t, 29.0, buckets[2].Metrics[models.MetricTemperature].Mean)
+	})
+
+	t.Run("FindAllNewestFirst", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		ids := seedReports(t, repo, 3)
+
+		reports, err := repo.FindAllReports(ctx)
+		require.NoError(t, err)
+		require.Len(t, reports, 3)
+		assert.Equal(t, []string{ids[2], ids[1], ids[0]}, reportIDs(reports))
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		count, err := repo.CountReports(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+
+		seedReports(t, repo, 4)
+		count, err = repo.CountReports(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), count)
+	})
+
+	t.Run("PaginatedDefaults", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		ids := seedReports(t, repo, 12)
+
+		page, err := repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, 12, page.TotalCount)
+		require.Len(t, page.Reports, 10)
+		assert.Equal(t, ids[11], page.Reports[0].ID)
+		assert.Equal(t, ids[2], page.Reports[9].ID)
+	})
+
+	t.Run("PaginatedOffsetAndLimit", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		ids := seedReports(t, repo, 7)
+
+		page, err := repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{Limit: 3, Offset: 3})
+		require.NoError(t, err)
+		assert.Equal(t, 7, page.TotalCount)
+		assert.Equal(t, []string{ids[3], ids[2], ids[1]}, reportIDs(page.Reports))
+
+		page, err = repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{Limit: 3, Offset: 6})
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[0]}, reportIDs(page.Reports))
+	})
+
+	t.Run("PaginatedOffsetBeyondEnd", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		seedReports(t, repo, 2)
+
+		page, err := repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{Offset: 5})
+		require.NoError(t, err)
+		assert.NotNil(t, page.Reports)
+		assert.Empty(t, page.Reports)
+		assert.Equal(t, 2, page.TotalCount)
+	})
+
+	t.Run("PaginatedAscending", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		ids := seedReports(t, repo, 3)
+
+		page, err := repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{SortOrder: request.SortOrderAsc})
+		require.NoError(t, err)
+		assert.Equal(t, ids, reportIDs(page.Reports))
+	})
+
+	t.Run("PaginatedSortByMetric", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		temperatures := []float64{27, 21, 33}
+		ids := make([]string, len(temperatures))
+		for i, temperature := range temperatures {
+			report := fixtureReport(i, temperature)
+			id, err := repo.InsertReport(ctx, &report)
+			require.NoError(t, err)
+			ids[i] = id
+		}
+
+		page, err := repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{SortBy: "temperature"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[2], ids[0], ids[1]}, reportIDs(page.Reports))
+
+		page, err = repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{SortBy: "temperature", SortOrder: request.SortOrderAsc})
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[1], ids[0], ids[2]}, reportIDs(page.Reports))
+	})
+
+	t.Run("PaginatedTimeRangeInclusive", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		ids := seedReports(t, repo, 6)
+
+		page, err := repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{
+			FromTime:   baseTime.Add(1 * time.Hour),
+			ToTime:     baseTime.Add(3 * time.Hour),
+			IsFiltered: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, page.TotalCount)
+		assert.Equal(t, []string{ids[3], ids[2], ids[1]}, reportIDs(page.Reports))
+
+		page, err = repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{
+			FromTime:   baseTime.Add(4 * time.Hour),
+			IsFiltered: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[5], ids[4]}, reportIDs(page.Reports))
+	})
+
+	t.Run("CursorWalkForwardAndBack", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		ids := seedReports(t, repo, 5)
+
+		first, err := repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[4], ids[3]}, reportIDs(first.Reports))
+		assert.True(t, first.HasMore)
+		assert.Empty(t, first.PrevCursor)
+		require.NotEmpty(t, first.NextCursor)
+
+		second, err := repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{Limit: 2, Cursor: decodeCursor(t, first.NextCursor)})
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[2], ids[1]}, reportIDs(second.Reports))
+		assert.True(t, second.HasMore)
+		assert.Equal(t, 5, second.TotalCount)
+
+		last, err := repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{Limit: 2, Cursor: decodeCursor(t, second.NextCursor)})
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[0]}, reportIDs(last.Reports))
+		assert.False(t, last.HasMore)
+		assert.Empty(t, last.NextCursor)
+		require.NotEmpty(t, last.PrevCursor)
+
+		back, err := repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{Limit: 2, Cursor: decodeCursor(t, last.PrevCursor)})
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[2], ids[1]}, reportIDs(back.Reports))
+		assert.True(t, back.HasMore)
+
+		front, err := repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{Limit: 2, Cursor: decodeCursor(t, back.PrevCursor)})
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[4], ids[3]}, reportIDs(front.Reports))
+		assert.Empty(t, front.PrevCursor)
+	})
+
+	t.Run("CursorAscendingWithTimeRange", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		ids := seedReports(t, repo, 6)
+
+		req := &request.PaginatedReportsRequest{
+			Limit:      2,
+			SortOrder:  request.SortOrderAsc,
+			FromTime:   baseTime.Add(1 * time.Hour),
+			ToTime:     baseTime.Add(4 * time.Hour),
+			IsFiltered: true,
+		}
+		first, err := repo.FindPaginatedReports(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[1], ids[2]}, reportIDs(first.Reports))
+
+		next := *req
+		next.Cursor = decodeCursor(t, first.NextCursor)
+		second, err := repo.FindPaginatedReports(ctx, &next)
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[3], ids[4]}, reportIDs(second.Reports))
+		assert.False(t, second.HasMore)
+		assert.Equal(t, 4, second.TotalCount)
+	})
+
+	t.Run("CursorTieBreaksOnID", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		var ids []string
+		for i := 0; i < 3; i++ {
+			report := fixtureReport(0, 25)
+			id, err := repo.InsertReport(ctx, &report)
+			require.NoError(t, err)
+			ids = append(ids, id)
+		}
+
+		seen := map[string]bool{}
+		req := &request.PaginatedReportsRequest{Limit: 1}
+		for i := 0; i < 3; i++ {
+			page, err := repo.FindPaginatedReports(ctx, req)
+			require.NoError(t, err)
+			require.Len(t, page.Reports, 1)
+			seen[page.Reports[0].ID] = true
+			if i < 2 {
+				require.True(t, page.HasMore)
+				req = &request.PaginatedReportsRequest{Limit: 1, Cursor: decodeCursor(t, page.NextCursor)}
+			} else {
+				assert.False(t, page.HasMore)
+			}
+		}
+		assert.Len(t, seen, 3)
+	})
+
+	t.Run("OffsetReportsHasMore", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		seedReports(t, repo, 3)
+
+		page, err := repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{Limit: 2, Offset: 0})
+		require.NoError(t, err)
+		assert.True(t, page.HasMore)
+
+		page, err = repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{Limit: 2, Offset: 1})
+		require.NoError(t, err)
+		assert.False(t, page.HasMore)
+		assert.NotEmpty(t, page.PrevCursor)
+	})
+
+	t.Run("SkipTotalCount", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		seedReports(t, repo, 3)
+
+		page, err := repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{SkipTotalCount: true})
+		require.NoError(t, err)
+		assert.Equal(t, -1, page.TotalCount)
+		assert.Len(t, page.Reports, 3)
+	})
+
+	t.Run("CursorSortMismatch", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		seedReports(t, repo, 3)
+
+		first, err := repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{Limit: 1})
+		require.NoError(t, err)
+
+		_, err = repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{
+			Limit:  1,
+			Sort:   []request.SortKey{{Field: "temperature", Order: request.SortOrderAsc}},
+			Cursor: decodeCursor(t, first.NextCursor),
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid cursor")
+	})
+
+	t.Run("UnsupportedSortField", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.FindPaginatedReports(context.Background(), &request.PaginatedReportsRequest{SortBy: "windSpeed"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid sort")
+	})
+
+	t.Run("MultiKeySortWithCursor", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		// Three reports share a temperature, so timestamp decides among them
+		temperatures := []float64{30, 28, 30, 28, 30}
+		ids := make([]string, len(temperatures))
+		for i, temperature := range temperatures {
+			report := fixtureReport(i, temperature)
+			id, err := repo.InsertReport(ctx, &report)
+			require.NoError(t, err)
+			ids[i] = id
+		}
+		expected := []string{ids[0], ids[2], ids[4], ids[1], ids[3]}
+
+		keys, err := request.ParseSortSpec("-temperature,timestamp")
+		require.NoError(t, err)
+
+		var walked []string
+		req := &request.PaginatedReportsRequest{Limit: 2, Sort: keys}
+		for {
+			page, err := repo.FindPaginatedReports(ctx, req)
+			require.NoError(t, err)
+			walked = append(walked, reportIDs(page.Reports)...)
+			if !page.HasMore {
+				break
+			}
+			req = &request.PaginatedReportsRequest{Limit: 2, Sort: keys, Cursor: decodeCursor(t, page.NextCursor)}
+		}
+		assert.Equal(t, expected, walked)
+
+		// Walk back from the last page
+		back, err := repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{Limit: 2, Sort: keys, Cursor: decodeCursor(t, mustPrevCursor(t, repo, req))})
+		require.NoError(t, err)
+		assert.Equal(t, expected[2:4], reportIDs(back.Reports))
+	})
+
+	t.Run("SortTieBreakIsStable", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		for i := 0; i < 4; i++ {
+			report := fixtureReport(i, 25)
+			_, err := repo.InsertReport(ctx, &report)
+			require.NoError(t, err)
+		}
+
+		keys := []request.SortKey{{Field: "temperature", Order: request.SortOrderDesc}}
+		first, err := repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{Sort: keys})
+		require.NoError(t, err)
+		second, err := repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{Sort: keys})
+		require.NoError(t, err)
+		assert.Equal(t, reportIDs(first.Reports), reportIDs(second.Reports))
+		assert.Greater(t, first.Reports[0].ID, first.Reports[1].ID)
+	})
+
+	t.Run("FilterMetricConditionsAnd", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		ids := seedReports(t, repo, 6) // temperatures 25..30, humidity 60..65
+
+		page, err := repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{
+			Conditions: []request.FilterCondition{
+				{Metric: models.MetricTemperature, Operator: request.OpGreaterThanEqual, Value: 27},
+				{Metric: models.MetricHumidity, Operator: request.OpLessThan, Value: 64},
+			},
+			IsFiltered: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, page.TotalCount)
+		assert.Equal(t, []string{ids[3], ids[2]}, reportIDs(page.Reports))
+	})
+
+	t.Run("FilterMetricConditionsOr", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		ids := seedReports(t, repo, 6)
+
+		page, err := repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{
+			Conditions: []request.FilterCondition{
+				{Metric: models.MetricTemperature, Operator: request.OpLessThanEqual, Value: 25},
+				{Metric: models.MetricCloudCover, Operator: request.OpGreaterThan, Value: 24},
+				{Metric: models.MetricPressure, Operator: request.OpEqual, Value: 1012},
+			},
+			Logic:      request.LogicOr,
+			IsFiltered: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[5], ids[2], ids[0]}, reportIDs(page.Reports))
+
+		page, err = repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{
+			Conditions: []request.FilterCondition{
+				{Metric: models.MetricTemperature, Operator: request.OpNotEqual, Value: 25},
+			},
+			IsFiltered: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 5, page.TotalCount)
+	})
+
+	t.Run("FilterLocationSourceAndType", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		ids := seedReports(t, repo, 3)
+
+		other := fixtureReport(3, 30)
+		other.Location = "WSAP"
+		other.Type = models.ReportTypeCurrent
+		otherID, err := repo.InsertReport(ctx, &other)
+		require.NoError(t, err)
+
+		page, err := repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{Locations: []string{"WSAP"}, IsFiltered: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{otherID}, reportIDs(page.Reports))
+
+		page, err = repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{
+			Locations:  []string{models.LocationChangi, "WSAP"},
+			Sources:    []string{models.SourceOpenWeather},
+			Types:      []string{models.ReportTypeHistorical},
+			IsFiltered: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[2], ids[1], ids[0]}, reportIDs(page.Reports))
+
+		// Category filters are always ANDed with OR-combined metric conditions
+		page, err = repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{
+			Types: []string{models.ReportTypeCurrent},
+			Conditions: []request.FilterCondition{
+				{Metric: models.MetricTemperature, Operator: request.OpLessThan, Value: 26},
+				{Metric: models.MetricTemperature, Operator: request.OpGreaterThan, Value: 29},
+			},
+			Logic:      request.LogicOr,
+			IsFiltered: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{otherID}, reportIDs(page.Reports))
+	})
+
+	t.Run("StreamReportsFiltersAndSorts", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		ids := seedReports(t, repo, 5) // temperatures 25..29
+		require.NoError(t, repo.DeleteReport(ctx, ids[4], baseTime))
+
+		req := &request.PaginatedReportsRequest{
+			Limit: 1, // Ignored when streaming
+			Conditions: []request.FilterCondition{
+				{Metric: models.MetricTemperature, Operator: request.OpGreaterThanEqual, Value: 26},
+			},
+			Sort:       []request.SortKey{{Field: "temperature", Order: request.SortOrderAsc}},
+			IsFiltered: true,
+		}
+
+		var streamed []models.WeatherReport
+		err := repo.StreamReports(ctx, req, func(report *models.WeatherReport) error {
+			streamed = append(streamed, *report)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[1], ids[2], ids[3]}, reportIDs(streamed))
+	})
+
+	t.Run("StreamReportsStopsOnError", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		seedReports(t, repo, 3)
+
+		stop := errors.New("stop")
+		calls := 0
+		err := repo.StreamReports(ctx, &request.PaginatedReportsRequest{}, func(*models.WeatherReport) error {
+			calls++
+			return stop
+		})
+		assert.Equal(t, stop, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("UpdateTagsAndNotes", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		ids := seedReports(t, repo, 1)
+
+		tags := []string{"runway closure", "sensor check"}
+		notes := "Runway 02L closed for maintenance"
+		require.NoError(t, repo.UpdateReport(ctx, ids[0], &request.ReportUpdateRequest{Tags: &tags, Notes: &notes}))
+
+		found, err := repo.FindReportByID(ctx, ids[0])
+		require.NoError(t, err)
+		assert.Equal(t, tags, found.Tags)
+		assert.Equal(t, notes, found.Notes)
+
+		// Nil fields are left unchanged, empty ones are cleared
+		noTags := []string{}
+		require.NoError(t, repo.UpdateReport(ctx, ids[0], &request.ReportUpdateRequest{Tags: &noTags}))
+
+		found, err = repo.FindReportByID(ctx, ids[0])
+		require.NoError(t, err)
+		assert.Empty(t, found.Tags)
+		assert.Equal(t, notes, found.Notes)
+		assert.Equal(t, 25.0, found.Temperature)
+	})
+
+	t.Run("UpdateNotFound", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		ids := seedReports(t, repo, 1)
+		notes := "sensor check"
+
+		err := repo.UpdateReport(ctx, "000000000000000000000000", &request.ReportUpdateRequest{Notes: &notes})
+		require.Error(t, err)
+		assert.Equal(t, "report not found", err.Error())
+
+		require.NoError(t, repo.DeleteReport(ctx, ids[0], baseTime))
+		err = repo.UpdateReport(ctx, ids[0], &request.ReportUpdateRequest{Notes: &notes})
+		require.Error(t, err)
+		assert.Equal(t, "report not found", err.Error())
+	})
+
+	t.Run("FilterAndCountTags", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		ids := seedReports(t, repo, 4)
+
+		runway := []string{"runway closure"}
+		both := []string{"sensor check", "runway closure"}
+		sensor := []string{"sensor check"}
+		require.NoError(t, repo.UpdateReport(ctx, ids[0], &request.ReportUpdateRequest{Tags: &runway}))
+		require.NoError(t, repo.UpdateReport(ctx, ids[1], &request.ReportUpdateRequest{Tags: &both}))
+		require.NoError(t, repo.UpdateReport(ctx, ids[2], &request.ReportUpdateRequest{Tags: &sensor}))
+		require.NoError(t, repo.UpdateReport(ctx, ids[3], &request.ReportUpdateRequest{Tags: &sensor}))
+		require.NoError(t, repo.DeleteReport(ctx, ids[3], baseTime))
+
+		page, err := repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{Tags: []string{"runway closure"}, IsFiltered: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[1], ids[0]}, reportIDs(page.Reports))
+		assert.Equal(t, 2, page.TotalCount)
+
+		// Reports with any of the tags match
+		page, err = repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{Tags: []string{"runway closure", "sensor check"}, IsFiltered: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[2], ids[1], ids[0]}, reportIDs(page.Reports))
+
+		counts, err := repo.FindTagCounts(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []response.TagCount{
+			{Tag: "runway closure", Count: 2},
+			{Tag: "sensor check", Count: 2},
+		}, counts)
+	})
+
+	t.Run("SoftDeleteHidesReportFromReads", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		ids := seedReports(t, repo, 3)
+
+		require.NoError(t, repo.DeleteReport(ctx, ids[1], baseTime.Add(24*time.Hour)))
+
+		_, err := repo.FindReportByID(ctx, ids[1])
+		require.Error(t, err)
+		assert.Equal(t, "report not found", err.Error())
+
+		reports, err := repo.FindAllReports(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[2], ids[0]}, reportIDs(reports))
+
+		reports, err = repo.FindReportsByIDs(ctx, ids)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{ids[0], ids[2]}, reportIDs(reports))
+
+		page, err := repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[2], ids[0]}, reportIDs(page.Reports))
+		assert.Equal(t, 2, page.TotalCount)
+
+		stats, err := repo.AggregateReports(ctx, baseTime, baseTime.Add(2*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 2, stats.Count)
+
+		count, err := repo.CountReports(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		// Deleted reports can still be listed on request
+		page, err = repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{IncludeDeleted: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[2], ids[1], ids[0]}, reportIDs(page.Reports))
+		require.NotNil(t, page.Reports[1].DeletedAt)
+		assert.True(t, baseTime.Add(24*time.Hour).Equal(*page.Reports[1].DeletedAt))
+	})
+
+	t.Run("DeleteNotFound", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		ids := seedReports(t, repo, 1)
+
+		err := repo.DeleteReport(ctx, "000000000000000000000000", baseTime)
+		require.Error(t, err)
+		assert.Equal(t, "report not found", err.Error())
+
+		// Deleting twice does not move the tombstone
+		require.NoError(t, repo.DeleteReport(ctx, ids[0], baseTime))
+		err = repo.DeleteReport(ctx, ids[0], baseTime.Add(time.Hour))
+		require.Error(t, err)
+		assert.Equal(t, "report not found", err.Error())
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		ids := seedReports(t, repo, 2)
+
+		// Only deleted reports can be restored
+		err := repo.RestoreReport(ctx, ids[0])
+		require.Error(t, err)
+		assert.Equal(t, "deleted report not found", err.Error())
+
+		require.NoError(t, repo.DeleteReport(ctx, ids[0], baseTime))
+		require.NoError(t, repo.RestoreReport(ctx, ids[0]))
+
+		found, err := repo.FindReportByID(ctx, ids[0])
+		require.NoError(t, err)
+		assert.Nil(t, found.DeletedAt)
+
+		count, err := repo.CountReports(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("PurgeDeletedReports", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		ids := seedReports(t, repo, 4)
+
+		require.NoError(t, repo.DeleteReport(ctx, ids[0], baseTime))
+		require.NoError(t, repo.DeleteReport(ctx, ids[1], baseTime.Add(time.Hour)))
+		require.NoError(t, repo.DeleteReport(ctx, ids[2], baseTime.Add(2*time.Hour)))
+
+		// The cutoff is inclusive and leaves newer tombstones and live reports alone
+		purged, err := repo.PurgeDeletedReports(ctx, baseTime.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), purged)
+
+		page, err := repo.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{IncludeDeleted: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[3], ids[2]}, reportIDs(page.Reports))
+
+		err = repo.RestoreReport(ctx, ids[0])
+		require.Error(t, err)
+		assert.Equal(t, "deleted report not found", err.Error())
+	})
+
+	t.Run("TenantIsolation", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx, other := tenantContexts()
+
+		report := fixtureReport(0, 25)
+		report.Tags = []string{"storm"}
+		id, err := repo.InsertReport(ctx, &report)
+		require.NoError(t, err)
+		ids, err := repo.InsertReports(ctx, []models.WeatherReport{fixtureReport(1, 26)})
+		require.NoError(t, err)
+
+		found, err := repo.FindReportByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, tenant, found.Tenant)
+
+		// Another tenant sees none of it, not even by ID
+		_, err = repo.FindReportByID(other, id)
+		require.Error(t, err)
+		assert.Equal(t, "report not found", err.Error())
+
+		all, err := repo.FindAllReports(other)
+		require.NoError(t, err)
+		assert.Empty(t, all)
+
+		page, err := repo.FindPaginatedReports(other, &request.PaginatedReportsRequest{IncludeDeleted: true})
+		require.NoError(t, err)
+		assert.Empty(t, page.Reports)
+		assert.Equal(t, 0, page.TotalCount)
+
+		byIDs, err := repo.FindReportsByIDs(other, []string{id, ids[0]})
+		require.NoError(t, err)
+		assert.Empty(t, byIDs)
+
+		existing, err := repo.FindExistingTimestamps(other, []time.Time{report.Timestamp})
+		require.NoError(t, err)
+		assert.Empty(t, existing, "tenants may have reports for the same timestamp")
+
+		stats, err := repo.AggregateReports(other, baseTime, baseTime.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 0, stats.Count)
+
+		count, err := repo.CountReports(other)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+
+		tags, err := repo.FindTagCounts(other)
+		require.NoError(t, err)
+		assert.Empty(t, tags)
+
+		notes := "not yours"
+		err = repo.UpdateReport(other, id, &request.ReportUpdateRequest{Notes: &notes})
+		require.Error(t, err)
+		assert.Equal(t, "report not found", err.Error())
+
+		err = repo.DeleteReport(other, id, baseTime)
+		require.Error(t, err)
+		assert.Equal(t, "report not found", err.Error())
+
+		require.NoError(t, repo.DeleteReport(ctx, id, baseTime))
+		err = repo.RestoreReport(other, id)
+		require.Error(t, err)
+		assert.Equal(t, "deleted report not found", err.Error())
+
+		count, err = repo.CountReports(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+}
+
+// mustPrevCursor fetches the page for req and returns its prev cursor
+func mustPrevCursor(t *testing.T, repo repository.IReportRepository, req *request.PaginatedReportsRequest) string {
+	t.Helper()
+
+	page, err := repo.FindPaginatedReports(context.Background(), req)
+	require.NoError(t, err)
+	require.NotEmpty(t, page.PrevCursor)
+	return page.PrevCursor
+}
+
+// decodeCursor decodes a cursor returned by a repository
+func decodeCursor(t *testing.T, value string) *request.ReportCursor {
+	t.Helper()
+
+	cursor, err := request.DecodeReportCursor(value)
+	require.NoError(t, err)
+	return cursor
+}
+
+// fixtureReport builds a report hourIndex hours after baseTime
+func fixtureReport(hourIndex int, temperature float64) models.WeatherReport {
+	timestamp := baseTime.Add(time.Duration(hourIndex) * time.Hour)
+	return models.WeatherReport{
+		Timestamp:   timestamp,
+		Temperature: temperature,
+		Pressure:    1010 + float64(hourIndex),
+		Humidity:    60 + float64(hourIndex),
+		CloudCover:  20 + float64(hourIndex),
+		Location:    models.LocationChangi,
+		Source:      models.SourceOpenWeather,
+		Type:        models.ReportTypeHistorical,
+		CreatedAt:   timestamp,
+	}
+}
+
+// seedReports inserts n hourly reports and returns their IDs oldest first
+func seedReports(t *testing.T, repo repository.IReportRepository, n int) []string {
+	t.Helper()
+
+	ids := make([]string, n)
+	for i := 0; i < n; i++ {
+		report := fixtureReport(i, 25+float64(i))
+		id, err := repo.InsertReport(context.Background(), &report)
+		require.NoError(t, err)
+		ids[i] = id
+	}
+	return ids
+}
+
+// reportIDs returns the IDs of reports in order
+func reportIDs(reports []models.WeatherReport) []string {
+	ids := make([]string, len(reports))
+	for i, report := range reports {
+		ids[i] = report.ID
+	}
+	return ids
+}
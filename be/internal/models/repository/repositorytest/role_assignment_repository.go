@@ -0,0 +1,121 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RoleAssignmentRepositoryFactory returns an empty role assignment repository for a single test
+type RoleAssignmentRepositoryFactory func(t *testing.T) repository.IRoleAssignmentRepository
+
+// RunRoleAssignmentRepositoryTests runs the IRoleAssignmentRepository conformance suite against newRepo
+func RunRoleAssignmentRepositoryTests(t *testing.T, newRepo RoleAssignmentRepositoryFactory) {
+	t.Run("UpsertAndFind", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		assignment := models.RoleAssignment{
+			Method: models.AuthMethodToken, Subject: "auth0|user/1", Role: models.RoleAnalyst,
+			UpdatedAt: baseTime, UpdatedBy: "admin",
+		}
+		require.NoError(t, repo.UpsertRoleAssignment(ctx, &assignment))
+
+		found, err := repo.FindRoleAssignment(ctx, models.AuthMethodToken, "auth0|user/1")
+		require.NoError(t, err)
+		assert.Equal(t, models.RoleAssignmentID(models.DefaultTenant, models.AuthMethodToken, "auth0|user/1"), found.ID)
+		assert.Equal(t, models.AuthMethodToken, found.Method)
+		assert.Equal(t, "auth0|user/1", found.Subject)
+		assert.Equal(t, models.RoleAnalyst, found.Role)
+		assert.True(t, baseTime.Equal(found.UpdatedAt))
+		assert.Equal(t, "admin", found.UpdatedBy)
+
+		_, err = repo.FindRoleAssignment(ctx, models.AuthMethodAPIKey, "auth0|user/1")
+		require.Error(t, err)
+		assert.Equal(t, "role assignment not found", err.Error(), "the method is part of the key")
+	})
+
+	t.Run("UpsertReplaces", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		for _, role := range []models.Role{models.RoleViewer, models.RoleAdmin} {
+			assignment := models.RoleAssignment{Method: models.AuthMethodAPIKey, Subject: "key1", Role: role, UpdatedAt: baseTime}
+			require.NoError(t, repo.UpsertRoleAssignment(ctx, &assignment))
+		}
+
+		assignments, err := repo.FindRoleAssignments(ctx)
+		require.NoError(t, err)
+		require.Len(t, assignments, 1)
+		assert.Equal(t, models.RoleAdmin, assignments[0].Role)
+	})
+
+	t.Run("ListOrdered", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		for _, assignment := range []models.RoleAssignment{
+			{Method: models.AuthMethodToken, Subject: "a"},
+			{Method: models.AuthMethodAPIKey, Subject: "b"},
+			{Method: models.AuthMethodAPIKey, Subject: "a"},
+		} {
+			assignment.Role = models.RoleViewer
+			assignment.UpdatedAt = baseTime.Add(time.Minute)
+			require.NoError(t, repo.UpsertRoleAssignment(ctx, &assignment))
+		}
+
+		assignments, err := repo.FindRoleAssignments(ctx)
+		require.NoError(t, err)
+		require.Len(t, assignments, 3)
+		assert.Equal(t, []string{"default:api_key:a", "default:api_key:b", "default:token:a"},
+			[]string{assignments[0].ID, assignments[1].ID, assignments[2].ID})
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		assignment := models.RoleAssignment{Method: models.AuthMethodAPIKey, Subject: "key1", Role: models.RoleOperator, UpdatedAt: baseTime}
+		require.NoError(t, repo.UpsertRoleAssignment(ctx, &assignment))
+
+		require.NoError(t, repo.DeleteRoleAssignment(ctx, models.AuthMethodAPIKey, "key1"))
+
+		_, err := repo.FindRoleAssignment(ctx, models.AuthMethodAPIKey, "key1")
+		require.Error(t, err)
+		assert.Equal(t, "role assignment not found", err.Error())
+
+		err = repo.DeleteRoleAssignment(ctx, models.AuthMethodAPIKey, "key1")
+		require.Error(t, err)
+		assert.Equal(t, "role assignment not found", err.Error())
+	})
+
+	t.Run("TenantIsolation", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx, other := tenantContexts()
+
+		assignment := models.RoleAssignment{Method: models.AuthMethodAPIKey, Subject: "key1", Role: models.RoleAdmin, UpdatedAt: baseTime}
+		require.NoError(t, repo.UpsertRoleAssignment(ctx, &assignment))
+
+		found, err := repo.FindRoleAssignment(ctx, models.AuthMethodAPIKey, "key1")
+		require.NoError(t, err)
+		assert.Equal(t, tenant+":api_key:key1", found.ID)
+		assert.Equal(t, tenant, found.Tenant)
+
+		_, err = repo.FindRoleAssignment(other, models.AuthMethodAPIKey, "key1")
+		require.Error(t, err)
+		assert.Equal(t, "role assignment not found", err.Error())
+
+		assignments, err := repo.FindRoleAssignments(other)
+		require.NoError(t, err)
+		assert.Empty(t, assignments)
+
+		err = repo.DeleteRoleAssignment(other, models.AuthMethodAPIKey, "key1")
+		require.Error(t, err)
+		assert.Equal(t, "role assignment not found", err.Error())
+	})
+}
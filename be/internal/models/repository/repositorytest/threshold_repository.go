@@ -0,0 +1,85 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ThresholdRepositoryFactory returns an empty threshold repository for a single test
+type ThresholdRepositoryFactory func(t *testing.T) repository.IThresholdRepository
+
+// RunThresholdRepositoryTests runs the IThresholdRepository conformance suite against newRepo
+func RunThresholdRepositoryTests(t *testing.T, newRepo ThresholdRepositoryFactory) {
+	t.Run("EmptyByDefault", func(t *testing.T) {
+		repo := newRepo(t)
+
+		thresholds, err := repo.FindThresholds(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, thresholds)
+	})
+
+	t.Run("UpsertReplacesPerMetric", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		updatedAt := baseTime
+		require.NoError(t, repo.UpsertThreshold(ctx, &models.Threshold{Metric: models.MetricTemperature, Notable: 1, Major: 3, UpdatedAt: &updatedAt}))
+		require.NoError(t, repo.UpsertThreshold(ctx, &models.Threshold{Metric: models.MetricHumidity, Notable: 5, Major: 15, UpdatedAt: &updatedAt}))
+		require.NoError(t, repo.UpsertThreshold(ctx, &models.Threshold{Metric: models.MetricTemperature, Notable: 1.5, Major: 4, UpdatedAt: &updatedAt}))
+
+		thresholds, err := repo.FindThresholds(ctx)
+		require.NoError(t, err)
+		require.Len(t, thresholds, 2)
+
+		byMetric := make(map[models.Metric]models.Threshold)
+		for _, threshold := range thresholds {
+			byMetric[threshold.Metric] = threshold
+		}
+		assert.Equal(t, 1.5, byMetric[models.MetricTemperature].Notable)
+		assert.Equal(t, 4.0, byMetric[models.MetricTemperature].Major)
+		assert.False(t, byMetric[models.MetricTemperature].IsDefault)
+		require.NotNil(t, byMetric[models.MetricTemperature].UpdatedAt)
+		assert.True(t, baseTime.Equal(*byMetric[models.MetricTemperature].UpdatedAt))
+		assert.Equal(t, 15.0, byMetric[models.MetricHumidity].Major)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		require.NoError(t, repo.UpsertThreshold(ctx, &models.Threshold{Metric: models.MetricPressure, Notable: 2, Major: 6}))
+		require.NoError(t, repo.DeleteThreshold(ctx, models.MetricPressure))
+
+		thresholds, err := repo.FindThresholds(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, thresholds)
+
+		// Deleting a threshold that is not configured is not an error
+		require.NoError(t, repo.DeleteThreshold(ctx, models.MetricPressure))
+	})
+
+	t.Run("TenantIsolation", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx, other := tenantContexts()
+
+		require.NoError(t, repo.UpsertThreshold(ctx, &models.Threshold{Metric: models.MetricTemperature, Notable: 1, Major: 3}))
+		require.NoError(t, repo.UpsertThreshold(other, &models.Threshold{Metric: models.MetricTemperature, Notable: 2, Major: 6}))
+
+		thresholds, err := repo.FindThresholds(ctx)
+		require.NoError(t, err)
+		require.Len(t, thresholds, 1)
+		assert.Equal(t, 3.0, thresholds[0].Major, "each tenant has its own threshold per metric")
+		assert.Equal(t, tenant, thresholds[0].Tenant)
+
+		require.NoError(t, repo.DeleteThreshold(other, models.MetricTemperature))
+
+		thresholds, err = repo.FindThresholds(ctx)
+		require.NoError(t, err)
+		assert.Len(t, thresholds, 1)
+	})
+}
@@ -0,0 +1,152 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"github.com/DangVTNhan/Scanner/be/pkg/openweather"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// WeatherCacheRepositoryFactory returns an empty weather cache repository for a single test
+type WeatherCacheRepositoryFactory func(t *testing.T) repository.IWeatherCacheRepository
+
+// RunWeatherCacheRepositoryTests runs the IWeatherCacheRepository conformance suite against newRepo
+func RunWeatherCacheRepositoryTests(t *testing.T, newRepo WeatherCacheRepositoryFactory) {
+	t.Run("SaveAndFindByTimestamp", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		cache := fixtureCache(baseTime, 28.5)
+		id, err := repo.SaveWeatherCache(ctx, &cache)
+		require.NoError(t, err)
+		assert.NotEmpty(t, id)
+
+		found, err := repo.FindWeatherCacheByTimestamp(ctx, baseTime.Add(5*time.Minute))
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, id, found.ID)
+		assert.True(t, baseTime.Equal(found.Timestamp))
+		assert.Equal(t, cache.WeatherData, found.WeatherData)
+	})
+
+	t.Run("FindByTimestampOutsideWindow", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		cache := fixtureCache(baseTime, 28.5)
+		_, err := repo.SaveWeatherCache(ctx, &cache)
+		require.NoError(t, err)
+
+		found, err := repo.FindWeatherCacheByTimestamp(ctx, baseTime.Add(11*time.Minute))
+		require.NoError(t, err)
+		assert.Nil(t, found)
+
+		found, err = repo.FindWeatherCacheByTimestamp(ctx, baseTime.Add(2*time.Minute), 1)
+		require.NoError(t, err)
+		assert.Nil(t, found)
+
+		found, err = repo.FindWeatherCacheByTimestamp(ctx, baseTime.Add(-1*time.Minute), 1)
+		require.NoError(t, err)
+		assert.NotNil(t, found)
+	})
+
+	t.Run("FindByTimestampEmpty", func(t *testing.T) {
+		repo := newRepo(t)
+
+		found, err := repo.FindWeatherCacheByTimestamp(context.Background(), baseTime)
+		require.NoError(t, err)
+		assert.Nil(t, found)
+	})
+
+	t.Run("EntriesWithoutExpiryAreKept", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		cache := fixtureCache(baseTime, 28.5)
+		_, err := repo.SaveWeatherCache(ctx, &cache)
+		require.NoError(t, err)
+
+		require.NoError(t, repo.DeleteExpiredCaches(ctx))
+
+		found, err := repo.FindWeatherCacheByTimestamp(ctx, baseTime)
+		require.NoError(t, err)
+		assert.NotNil(t, found)
+	})
+
+	t.Run("StreamInTimeRange", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		// Saved out of order to check that entries are streamed by timestamp
+		for _, hours := range []int{3, 0, 2, 1} {
+			cache := fixtureCache(baseTime.Add(time.Duration(hours)*time.Hour), 25+float64(hours))
+			_, err := repo.SaveWeatherCache(ctx, &cache)
+			require.NoError(t, err)
+		}
+
+		var temperatures []float64
+		err := repo.StreamWeatherCaches(ctx, baseTime.Add(time.Hour), baseTime.Add(2*time.Hour), func(cache *models.WeatherCache) error {
+			temperatures = append(temperatures, cache.WeatherData.Temperature)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []float64{26, 27}, temperatures)
+
+		temperatures = nil
+		err = repo.StreamWeatherCaches(ctx, time.Time{}, time.Time{}, func(cache *models.WeatherCache) error {
+			temperatures = append(temperatures, cache.WeatherData.Temperature)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []float64{25, 26, 27, 28}, temperatures)
+	})
+
+	t.Run("TenantIsolation", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx, other := tenantContexts()
+
+		cache := fixtureCache(baseTime, 28.5)
+		_, err := repo.SaveWeatherCache(ctx, &cache)
+		require.NoError(t, err)
+
+		found, err := repo.FindWeatherCacheByTimestamp(ctx, baseTime)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, tenant, found.Tenant)
+
+		found, err = repo.FindWeatherCacheByTimestamp(other, baseTime)
+		require.NoError(t, err)
+		assert.Nil(t, found)
+
+		latest, err := repo.FindLatestWeatherCache(other)
+		require.NoError(t, err)
+		assert.Nil(t, latest)
+
+		var streamed int
+		err = repo.StreamWeatherCaches(other, time.Time{}, time.Time{}, func(*models.WeatherCache) error {
+			streamed++
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Zero(t, streamed)
+	})
+}
+
+// fixtureCache builds a cache entry for the given timestamp
+func fixtureCache(timestamp time.Time, temperature float64) models.WeatherCache {
+	return models.WeatherCache{
+		Timestamp: timestamp,
+		WeatherData: openweather.WeatherData{
+			Temperature: temperature,
+			Pressure:    1012,
+			Humidity:    70,
+			CloudCover:  40,
+		},
+		CreatedAt: timestamp,
+	}
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+)
+
+// IRoleAssignmentRepository defines the interface for role assignment data access.
+// Every method only sees the assignments of the tenant of ctx, see models.TenantFromContext, and saved
+// assignments are stamped with it.
+type IRoleAssignmentRepository interface {
+	// FindRoleAssignment retrieves the role assignment of a caller.
+	// It returns "role assignment not found" if the caller has none.
+	FindRoleAssignment(ctx context.Context, method models.AuthMethod, subject string) (*models.RoleAssignment, error)
+
+	// FindRoleAssignments retrieves all role assignments, ordered by method and subject
+	FindRoleAssignments(ctx context.Context) ([]models.RoleAssignment, error)
+
+	// UpsertRoleAssignment creates or replaces the role assignment of a caller
+	UpsertRoleAssignment(ctx context.Context, assignment *models.RoleAssignment) error
+
+	// DeleteRoleAssignment removes the role assignment of a caller.
+	// It returns "role assignment not found" if the caller has none.
+	DeleteRoleAssignment(ctx context.Context, method models.AuthMethod, subject string) error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+)
+
+// IThresholdRepository defines the interface for deviation threshold data access.
+// Every method only sees the thresholds of the tenant of ctx, see models.TenantFromContext, and saved
+// thresholds are stamped with it.
+type IThresholdRepository interface {
+	// FindThresholds retrieves all configured thresholds
+	FindThresholds(ctx context.Context) ([]models.Threshold, error)
+
+	// UpsertThreshold creates or replaces the threshold of a metric
+	UpsertThreshold(ctx context.Context, threshold *models.Threshold) error
+
+	// DeleteThreshold removes the configured threshold of a metric. Deleting a missing threshold is not an error.
+	DeleteThreshold(ctx context.Context, metric models.Metric) error
+}
@@ -7,7 +7,9 @@ import (
 	"github.com/DangVTNhan/Scanner/be/internal/models"
 )
 
-// IWeatherCacheRepository defines the interface for weather cache data access
+// IWeatherCacheRepository defines the interface for weather cache data access.
+// Every method only sees the entries of the tenant of ctx, see models.TenantFromContext, and saved
+// entries are stamped with it.
 type IWeatherCacheRepository interface {
 	// SaveWeatherCache saves a weather data cache entry
 	SaveWeatherCache(ctx context.Context, cache *models.WeatherCache) (string, error)
@@ -20,4 +22,8 @@ type IWeatherCacheRepository interface {
 
 	// DeleteExpiredCaches removes expired cache entries
 	DeleteExpiredCaches(ctx context.Context) error
+
+	// StreamWeatherCaches calls fn for every cache entry with a timestamp between from and to
+	// (inclusive, zero means unbounded) in timestamp order, stopping at the first error fn returns
+	StreamWeatherCaches(ctx context.Context, from, to time.Time, fn func(*models.WeatherCache) error) error
 }
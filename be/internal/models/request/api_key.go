@@ -0,0 +1,9 @@
+package request
+
+// MaxAPIKeyNameLength is the maximum length of the name of an API key
+const MaxAPIKeyNameLength = 100
+
+// APIKeyRequest represents a request to create an API key
+type APIKeyRequest struct {
+	Name string `json:"name"` // Who or what uses the key, e.g. "grafana"
+}
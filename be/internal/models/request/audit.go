@@ -0,0 +1,42 @@
+package request
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultAuditPageLimit = 50   // Entries returned when a request sets no limit
+	MaxAuditPageLimit     = 1000 // Most entries returned in one page
+)
+
+// AuditOutcome selects audit entries by how their request ended
+type AuditOutcome string
+
+const (
+	AuditOutcomeSuccess AuditOutcome = "success" // Status below 400
+	AuditOutcomeFailure AuditOutcome = "failure" // Status 400 or above, e.g. requests without permission
+)
+
+// ParseAuditOutcome parses success or failure (case-insensitive); empty means both
+func ParseAuditOutcome(value string) (AuditOutcome, error) {
+	switch outcome := AuditOutcome(strings.ToLower(strings.TrimSpace(value))); outcome {
+	case "", AuditOutcomeSuccess, AuditOutcomeFailure:
+		return outcome, nil
+	}
+	return "", fmt.Errorf("invalid outcome %q, expected success or failure", value)
+}
+
+// AuditLogRequest selects audit entries. Entries are returned newest first; empty fields match any entry.
+type AuditLogRequest struct {
+	Actor      string       // Subject of the caller: ID of the API key or "sub" claim of the token
+	Method     string       // HTTP method, e.g. DELETE
+	Route      string       // Path template, e.g. /api/reports/{id}
+	ResourceID string       // ID of the created or changed item
+	Outcome    AuditOutcome // success or failure
+	From       time.Time    // Entries from this time on
+	To         time.Time    // Entries up to this time
+	Limit      int          // Page size, DefaultAuditPageLimit if zero
+	Cursor     string       // ID of the last entry of the previous page
+}
@@ -0,0 +1,50 @@
+package request
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+)
+
+// ChartStyle is how a chart draws the values of a metric
+type ChartStyle string
+
+const (
+	ChartStyleLine ChartStyle = "line" // A line through the values (default)
+	ChartStyleArea ChartStyle = "area" // A line with the area below it filled
+)
+
+// ParseChartStyle parses line or area (case-insensitive); empty means line
+func ParseChartStyle(value string) (ChartStyle, error) {
+	switch style := ChartStyle(strings.ToLower(strings.TrimSpace(value))); style {
+	case "":
+		return ChartStyleLine, nil
+	case ChartStyleLine, ChartStyleArea:
+		return style, nil
+	}
+	return "", fmt.Errorf("invalid style %q, expected line or area", value)
+}
+
+// Default and allowed sizes of a chart in pixels
+const (
+	DefaultChartWidth  = 800
+	DefaultChartHeight = 320
+	MinChartWidth      = 240
+	MinChartHeight     = 160
+	MaxChartWidth      = 4000
+	MaxChartHeight     = 2000
+)
+
+// MetricChartRequest represents a request for a chart of the history of a metric
+type MetricChartRequest struct {
+	Metric models.Metric
+	From   time.Time     // Start of the chart, inclusive
+	To     time.Time     // End of the chart, inclusive
+	Bucket time.Duration // Plot the mean of the reports per bucket; zero plots every report
+	Band   bool          // Draw the min and max of every bucket as a band behind the line
+	Style  ChartStyle
+	Width  int
+	Height int
+}
@@ -0,0 +1,35 @@
+package request
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseChartStyle(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected ChartStyle
+		wantErr  bool
+	}{
+		{"", ChartStyleLine, false},
+		{"line", ChartStyleLine, false},
+		{" Area ", ChartStyleArea, false},
+		{"bar", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			// Act
+			style, err := ParseChartStyle(tt.value)
+
+			// Assert
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, style)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package request
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+)
+
+// CursorDirection tells which way a cursor pages through the results
+type CursorDirection string
+
+const (
+	CursorNext CursorDirection = "next" // Items after the cursor position in sort order
+	CursorPrev CursorDirection = "prev" // Items before the cursor position in sort order
+)
+
+// ReportCursor is the decoded form of an opaque pagination cursor.
+// It records the sort key values of the report at the page boundary, plus its _id tie-break.
+type ReportCursor struct {
+	Sort      string                    `json:"s"`            // Canonical sort specification the cursor was issued for
+	ID        string                    `json:"id"`           // _id of the boundary report
+	Timestamp time.Time                 `json:"ts,omitempty"` // Set when sorting by timestamp
+	CreatedAt time.Time                 `json:"ca,omitempty"` // Set when sorting by createdAt
+	Metrics   map[models.Metric]float64 `json:"m,omitempty"`  // Metric values used by the sort
+	Direction CursorDirection           `json:"d"`
+}
+
+// NewReportCursor returns a cursor positioned at report for the given sort keys
+func NewReportCursor(report models.WeatherReport, keys []SortKey, direction CursorDirection) ReportCursor {
+	cursor := ReportCursor{Sort: FormatSortSpec(keys), ID: report.ID, Direction: direction}
+	for _, key := range keys {
+		switch metric := models.Metric(key.Field); {
+		case key.Field == "timestamp":
+			cursor.Timestamp = report.Timestamp
+		case key.Field == "createdAt":
+			cursor.CreatedAt = report.CreatedAt
+		case metric.IsValid():
+			if cursor.Metrics == nil {
+				cursor.Metrics = make(map[models.Metric]float64)
+			}
+			cursor.Metrics[metric] = report.Value(metric)
+		}
+	}
+	return cursor
+}
+
+// WithDirection returns a copy of the cursor pointing the other way from the same position
+func (c ReportCursor) WithDirection(direction CursorDirection) ReportCursor {
+	c.Direction = direction
+	return c
+}
+
+// Boundary returns a report holding the cursor's key values, for comparing against stored reports
+func (c ReportCursor) Boundary() models.WeatherReport {
+	return models.WeatherReport{
+		ID:          c.ID,
+		Timestamp:   c.Timestamp,
+		CreatedAt:   c.CreatedAt,
+		Temperature: c.Metrics[models.MetricTemperature],
+		Pressure:    c.Metrics[models.MetricPressure],
+		Humidity:    c.Metrics[models.MetricHumidity],
+		CloudCover:  c.Metrics[models.MetricCloudCover],
+	}
+}
+
+// Encode returns the opaque string form of the cursor
+func (c ReportCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeReportCursor parses a cursor previously returned by Encode
+func DecodeReportCursor(value string) (*ReportCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	var cursor ReportCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	if cursor.ID == "" || cursor.Sort == "" {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	if cursor.Direction != CursorNext && cursor.Direction != CursorPrev {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return &cursor, nil
+}
@@ -0,0 +1,49 @@
+package request
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExportFormat is the file format of a report export
+type ExportFormat string
+
+const (
+	ExportFormatCSV    ExportFormat = "csv"    // One row per report with a header row (default)
+	ExportFormatNDJSON ExportFormat = "ndjson" // One JSON object per line
+	ExportFormatJSON   ExportFormat = "json"   // A single JSON array
+
+	ExportFormatParquet ExportFormat = "parquet" // Apache Parquet file with a typed schema
+	ExportFormatArrow   ExportFormat = "arrow"   // Apache Arrow IPC file with a typed schema
+)
+
+// ContentType returns the MIME type of the format
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportFormatNDJSON:
+		return "application/x-ndjson"
+	case ExportFormatJSON:
+		return "application/json"
+	case ExportFormatParquet:
+		return "application/vnd.apache.parquet"
+	case ExportFormatArrow:
+		return "application/vnd.apache.arrow.file"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// IsColumnar reports whether the format is a binary columnar format
+func (f ExportFormat) IsColumnar() bool {
+	return f == ExportFormatParquet || f == ExportFormatArrow
+}
+
+// ParseExportFormat parses csv, ndjson, json, parquet or arrow (case-insensitive); empty means csv
+func ParseExportFormat(value string) (ExportFormat, error) {
+	switch format := ExportFormat(strings.ToLower(strings.TrimSpace(value))); format {
+	case "":
+		return ExportFormatCSV, nil
+	case ExportFormatCSV, ExportFormatNDJSON, ExportFormatJSON, ExportFormatParquet, ExportFormatArrow:
+		return format, nil
+	}
+	return "", fmt.Errorf("invalid format %q, expected csv, ndjson, json, parquet or arrow", value)
+}
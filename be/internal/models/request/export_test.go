@@ -0,0 +1,38 @@
+package request
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExportFormat(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected ExportFormat
+		wantErr  bool
+	}{
+		{"", ExportFormatCSV, false},
+		{"csv", ExportFormatCSV, false},
+		{" NDJSON ", ExportFormatNDJSON, false},
+		{"json", ExportFormatJSON, false},
+		{"Parquet", ExportFormatParquet, false},
+		{"arrow", ExportFormatArrow, false},
+		{"xlsx", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			// Act
+			format, err := ParseExportFormat(tt.value)
+
+			// Assert
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, format)
+		})
+	}
+}
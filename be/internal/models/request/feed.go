@@ -0,0 +1,29 @@
+package request
+
+import (
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+)
+
+// FeedFormat is the syndication format of a feed of reports
+type FeedFormat string
+
+const (
+	FeedFormatAtom FeedFormat = "atom" // Atom 1.0
+	FeedFormatRSS  FeedFormat = "rss"  // RSS 2.0
+)
+
+// Number of reports in a feed
+const (
+	DefaultFeedLimit = 20
+	MaxFeedLimit     = 100
+)
+
+// AlertFeedRequest represents a request for the alert events of a time range.
+// An alert event is a report whose deviation from the report before it reaches MinSeverity.
+type AlertFeedRequest struct {
+	From        time.Time       // Start of the range, inclusive
+	To          time.Time       // End of the range, inclusive
+	MinSeverity models.Severity // notable (default) or major
+}
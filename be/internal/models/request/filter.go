@@ -0,0 +1,119 @@
+package request
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+)
+
+// MaxFilterConditions is the maximum number of metric conditions in a single request
+const MaxFilterConditions = 20
+
+// ComparisonOperator is the operator of a metric filter condition
+type ComparisonOperator string
+
+const (
+	OpGreaterThan      ComparisonOperator = ">"
+	OpGreaterThanEqual ComparisonOperator = ">="
+	OpLessThan         ComparisonOperator = "<"
+	OpLessThanEqual    ComparisonOperator = "<="
+	OpEqual            ComparisonOperator = "="
+	OpNotEqual         ComparisonOperator = "!="
+)
+
+// comparisonOperators lists the operators with two-character ones first so they match before their prefixes
+var comparisonOperators = []ComparisonOperator{
+	OpGreaterThanEqual, OpLessThanEqual, OpNotEqual, OpGreaterThan, OpLessThan, OpEqual,
+}
+
+// LogicalOperator combines metric filter conditions
+type LogicalOperator string
+
+const (
+	LogicAnd LogicalOperator = "and" // All conditions must match (default)
+	LogicOr  LogicalOperator = "or"  // At least one condition must match
+)
+
+// FilterCondition is a range condition on a metric, e.g. temperature>=30
+type FilterCondition struct {
+	Metric   models.Metric      `json:"metric"`
+	Operator ComparisonOperator `json:"operator"`
+	Value    float64            `json:"value"`
+}
+
+// String returns the condition in its query string form
+func (c FilterCondition) String() string {
+	return fmt.Sprintf("%s%s%s", c.Metric, c.Operator, strconv.FormatFloat(c.Value, 'f', -1, 64))
+}
+
+// Matches reports whether value satisfies the condition
+func (c FilterCondition) Matches(value float64) bool {
+	switch c.Operator {
+	case OpGreaterThan:
+		return value > c.Value
+	case OpGreaterThanEqual:
+		return value >= c.Value
+	case OpLessThan:
+		return value < c.Value
+	case OpLessThanEqual:
+		return value <= c.Value
+	case OpEqual:
+		return value == c.Value
+	case OpNotEqual:
+		return value != c.Value
+	}
+	return false
+}
+
+// ParseFilterCondition parses an expression such as "temperature>=30" or "humidity<80"
+func ParseFilterCondition(expr string) (*FilterCondition, error) {
+	expr = strings.TrimSpace(expr)
+
+	for _, op := range comparisonOperators {
+		i := strings.Index(expr, string(op))
+		if i < 0 {
+			continue
+		}
+
+		metric := models.Metric(strings.TrimSpace(expr[:i]))
+		if !metric.IsValid() {
+			return nil, fmt.Errorf("unknown metric %q in filter %q", metric, expr)
+		}
+
+		valueStr := strings.TrimSpace(expr[i+len(op):])
+		value, err := strconv.ParseFloat(valueStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q in filter %q", valueStr, expr)
+		}
+
+		return &FilterCondition{Metric: metric, Operator: op, Value: value}, nil
+	}
+
+	return nil, fmt.Errorf("missing comparison operator in filter %q", expr)
+}
+
+// ParseLogicalOperator parses "and" or "or" (case-insensitive); empty means "and"
+func ParseLogicalOperator(value string) (LogicalOperator, error) {
+	switch LogicalOperator(strings.ToLower(strings.TrimSpace(value))) {
+	case "", LogicAnd:
+		return LogicAnd, nil
+	case LogicOr:
+		return LogicOr, nil
+	}
+	return "", fmt.Errorf("invalid logic %q, expected and or or", value)
+}
+
+// ParseListValues splits comma-separated values from one or more query parameters, dropping blanks
+func ParseListValues(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package request
+
+import (
+	"testing"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilterCondition(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected FilterCondition
+	}{
+		{"temperature>=30", FilterCondition{Metric: models.MetricTemperature, Operator: OpGreaterThanEqual, Value: 30}},
+		{"humidity<80", FilterCondition{Metric: models.MetricHumidity, Operator: OpLessThan, Value: 80}},
+		{" pressure <= 1009.5 ", FilterCondition{Metric: models.MetricPressure, Operator: OpLessThanEqual, Value: 1009.5}},
+		{"cloudCover>50", FilterCondition{Metric: models.MetricCloudCover, Operator: OpGreaterThan, Value: 50}},
+		{"temperature=-2", FilterCondition{Metric: models.MetricTemperature, Operator: OpEqual, Value: -2}},
+		{"humidity!=100", FilterCondition{Metric: models.MetricHumidity, Operator: OpNotEqual, Value: 100}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			// Act
+			condition, err := ParseFilterCondition(tt.expr)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, *condition)
+		})
+	}
+}
+
+func TestParseFilterCondition_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "temperature", "windSpeed>3", "temperature>=warm", "_id=1", ">=30"} {
+		t.Run(expr, func(t *testing.T) {
+			// Act
+			condition, err := ParseFilterCondition(expr)
+
+			// Assert
+			assert.Error(t, err)
+			assert.Nil(t, condition)
+		})
+	}
+}
+
+func TestFilterCondition_Matches(t *testing.T) {
+	condition := FilterCondition{Metric: models.MetricTemperature, Operator: OpGreaterThanEqual, Value: 30}
+
+	assert.True(t, condition.Matches(30))
+	assert.True(t, condition.Matches(31.5))
+	assert.False(t, condition.Matches(29.9))
+	assert.Equal(t, "temperature>=30", condition.String())
+}
+
+func TestParseLogicalOperator(t *testing.T) {
+	logic, err := ParseLogicalOperator("")
+	assert.NoError(t, err)
+	assert.Equal(t, LogicAnd, logic)
+
+	logic, err = ParseLogicalOperator("OR")
+	assert.NoError(t, err)
+	assert.Equal(t, LogicOr, logic)
+
+	_, err = ParseLogicalOperator("xor")
+	assert.Error(t, err)
+}
+
+func TestParseListValues(t *testing.T) {
+	assert.Equal(t, []string{"WSSS", "WSAP", "WMKK"}, ParseListValues([]string{"WSSS, WSAP", "", "WMKK,"}))
+	assert.Nil(t, ParseListValues(nil))
+}
@@ -0,0 +1,25 @@
+package request
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ImportFormat is the file format of a report import
+type ImportFormat string
+
+const (
+	ImportFormatCSV    ImportFormat = "csv"    // A header row, then one row per report (default)
+	ImportFormatNDJSON ImportFormat = "ndjson" // One JSON object per line
+)
+
+// ParseImportFormat parses csv or ndjson (case-insensitive); empty means csv
+func ParseImportFormat(value string) (ImportFormat, error) {
+	switch format := ImportFormat(strings.ToLower(strings.TrimSpace(value))); format {
+	case "":
+		return ImportFormatCSV, nil
+	case ImportFormatCSV, ImportFormatNDJSON:
+		return format, nil
+	}
+	return "", fmt.Errorf("invalid format %q, expected csv or ndjson", value)
+}
@@ -0,0 +1,35 @@
+package request
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseImportFormat(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected ImportFormat
+		wantErr  bool
+	}{
+		{"", ImportFormatCSV, false},
+		{"CSV", ImportFormatCSV, false},
+		{" ndjson ", ImportFormatNDJSON, false},
+		{"json", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			// Act
+			format, err := ParseImportFormat(tt.value)
+
+			// Assert
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, format)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package request
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RenderFormat is the document format of a printable report
+type RenderFormat string
+
+const (
+	RenderFormatHTML RenderFormat = "html" // A standalone HTML page with print styles (default)
+	RenderFormatPDF  RenderFormat = "pdf"  // An A4 PDF document
+)
+
+// ContentType returns the MIME type of the format
+func (f RenderFormat) ContentType() string {
+	if f == RenderFormatPDF {
+		return "application/pdf"
+	}
+	return "text/html; charset=utf-8"
+}
+
+// ParseRenderFormat parses html or pdf (case-insensitive); empty means html
+func ParseRenderFormat(value string) (RenderFormat, error) {
+	switch format := RenderFormat(strings.ToLower(strings.TrimSpace(value))); format {
+	case "":
+		return RenderFormatHTML, nil
+	case RenderFormatHTML, RenderFormatPDF:
+		return format, nil
+	}
+	return "", fmt.Errorf("invalid format %q, expected html or pdf", value)
+}
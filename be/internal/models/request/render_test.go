@@ -0,0 +1,35 @@
+package request
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRenderFormat(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected RenderFormat
+		wantErr  bool
+	}{
+		{"", RenderFormatHTML, false},
+		{"html", RenderFormatHTML, false},
+		{" PDF ", RenderFormatPDF, false},
+		{"docx", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			// Act
+			format, err := ParseRenderFormat(tt.value)
+
+			// Assert
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, format)
+		})
+	}
+}
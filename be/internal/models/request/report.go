@@ -1,16 +1,65 @@
 package request
 
-import "time"
+import (
+	"fmt"
+	"slices"
+	"time"
+)
 
 // ReportRequest represents a request to generate a weather report
 type ReportRequest struct {
 	Timestamp *time.Time `json:"timestamp"` // Optional: if not provided, current time will be used
 }
 
+// MaxBatchReports is the maximum number of timestamps in a batch report request
+const MaxBatchReports = 100
+
+// BatchReportRequest represents a request to generate a weather report for each of several timestamps
+type BatchReportRequest struct {
+	Timestamps []time.Time `json:"timestamps"` // Equal timestamps are generated once
+}
+
 // ComparisonRequest represents a request to compare two reports
 type ComparisonRequest struct {
 	ReportID1 string `json:"reportId1"`
 	ReportID2 string `json:"reportId2"`
+	Save      bool   `json:"save,omitempty"`  // Optional: persist the comparison so it can be retrieved later
+	Title     string `json:"title,omitempty"` // Title of the saved comparison, required when save is true
+	Notes     string `json:"notes,omitempty"` // Optional notes of the saved comparison
+}
+
+// ReportUpdateRequest represents a partial update of a report's annotations.
+// Fields that are null or missing are left unchanged; an empty value clears the field.
+type ReportUpdateRequest struct {
+	Tags  *[]string `json:"tags,omitempty"`  // Replaces all tags of the report
+	Notes *string   `json:"notes,omitempty"` // Replaces the notes of the report
+}
+
+// MaxComparisonReports is the maximum number of reports in a multi-report comparison
+const MaxComparisonReports = 50
+
+// MultiComparisonRequest represents a request to compare several reports against a baseline
+type MultiComparisonRequest struct {
+	ReportIDs  []string `json:"reportIds"`  // Reports to compare, at least two
+	BaselineID string   `json:"baselineId"` // Optional: report the others are compared to, defaults to the first report
+}
+
+// TimeRange is an inclusive range of report timestamps
+type TimeRange struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// PeriodComparisonRequest represents a request to compare the reports of two time periods
+type PeriodComparisonRequest struct {
+	Period1 TimeRange `json:"period1"` // The period compared against, e.g. the same week last year
+	Period2 TimeRange `json:"period2"` // The period of interest, e.g. this week
+}
+
+// ThresholdRequest represents a request to configure the deviation threshold of a metric
+type ThresholdRequest struct {
+	Notable float64 `json:"notable"` // Deviations of at least this much are notable, must be positive
+	Major   float64 `json:"major"`   // Deviations of at least this much are major, must not be below notable
 }
 
 // SortOrder represents the sort order (ascending or descending)
@@ -28,6 +77,42 @@ type PaginatedReportsRequest struct {
 	FromTime   time.Time `json:"fromTime,omitempty"`   // Filter reports from this time
 	ToTime     time.Time `json:"toTime,omitempty"`     // Filter reports until this time
 	IsFiltered bool      `json:"isFiltered,omitempty"` // Whether filtering is applied
-	SortBy     string    `json:"sortBy,omitempty"`     // Field to sort by (default: "timestamp")
-	SortOrder  SortOrder `json:"sortOrder,omitempty"`  // Sort order (default: "desc")
+	SortBy     string    `json:"sortBy,omitempty"`     // Field to sort by (default: "timestamp"); legacy, superseded by Sort
+	SortOrder  SortOrder `json:"sortOrder,omitempty"`  // Sort order (default: "desc"); legacy, superseded by Sort
+	Sort       []SortKey `json:"sort,omitempty"`       // Sort keys in priority order; takes precedence over SortBy/SortOrder
+
+	Conditions []FilterCondition `json:"conditions,omitempty"` // Metric range conditions, combined with Logic
+	Logic      LogicalOperator   `json:"logic,omitempty"`      // How Conditions are combined (default: "and")
+	Locations  []string          `json:"locations,omitempty"`  // Only reports from these locations
+	Sources    []string          `json:"sources,omitempty"`    // Only reports from these providers
+	Types      []string          `json:"types,omitempty"`      // Only reports of these types
+	Tags       []string          `json:"tags,omitempty"`       // Only reports with at least one of these tags
+
+	Cursor         *ReportCursor `json:"cursor,omitempty"`         // Keyset position from a previous page; replaces Offset when set
+	SkipTotalCount bool          `json:"skipTotalCount,omitempty"` // Skip counting all matching reports (TotalCount is -1)
+	IncludeDeleted bool          `json:"includeDeleted,omitempty"` // Also return soft-deleted reports
+}
+
+// SortKeys returns the requested sort keys, falling back to the legacy SortBy/SortOrder fields
+func (r *PaginatedReportsRequest) SortKeys() ([]SortKey, error) {
+	if len(r.Sort) > 0 {
+		return r.Sort, nil
+	}
+	return LegacySortKeys(r.SortBy, r.SortOrder)
+}
+
+// EffectiveSort returns the sort keys followed by the _id tie-break, which takes the direction of the last key
+func (r *PaginatedReportsRequest) EffectiveSort() ([]SortKey, error) {
+	keys, err := r.SortKeys()
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		if !slices.Contains(SortableFields, key.Field) {
+			return nil, fmt.Errorf("unsupported sort field %q", key.Field)
+		}
+	}
+
+	effective := append([]SortKey{}, keys...)
+	return append(effective, SortKey{Field: TieBreakField, Order: keys[len(keys)-1].Order}), nil
 }
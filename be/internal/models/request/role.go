@@ -0,0 +1,10 @@
+package request
+
+import (
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+)
+
+// RoleAssignmentRequest represents a request to assign a role to a caller
+type RoleAssignmentRequest struct {
+	Role models.Role `json:"role"` // viewer, analyst, operator or admin
+}
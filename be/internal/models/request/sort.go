@@ -0,0 +1,99 @@
+package request
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MaxSortKeys is the maximum number of keys in a sort specification
+const MaxSortKeys = 4
+
+// SortableFields lists the report fields that can be sorted on
+var SortableFields = []string{"timestamp", "createdAt", "temperature", "pressure", "humidity", "cloudCover"}
+
+// TieBreakField is appended to every sort so that the order is total and stable across pages
+const TieBreakField = "_id"
+
+// SortKey is a single field of a sort specification
+type SortKey struct {
+	Field string    `json:"field"`
+	Order SortOrder `json:"order"`
+}
+
+// Direction returns 1 for ascending and -1 for descending, as used by MongoDB
+func (k SortKey) Direction() int {
+	if k.Order == SortOrderAsc {
+		return 1
+	}
+	return -1
+}
+
+// ParseSortSpec parses a sort specification such as "-temperature,timestamp".
+// A leading "-" sorts descending, a leading "+" or none sorts ascending.
+func ParseSortSpec(spec string) ([]SortKey, error) {
+	var keys []SortKey
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("empty sort key in %q", spec)
+		}
+
+		key := SortKey{Field: part, Order: SortOrderAsc}
+		switch part[0] {
+		case '-':
+			key = SortKey{Field: part[1:], Order: SortOrderDesc}
+		case '+':
+			key.Field = part[1:]
+		}
+
+		if !slices.Contains(SortableFields, key.Field) {
+			return nil, fmt.Errorf("unsupported sort field %q, expected one of %s", key.Field, strings.Join(SortableFields, ", "))
+		}
+		if seen[key.Field] {
+			return nil, fmt.Errorf("duplicate sort field %q", key.Field)
+		}
+		seen[key.Field] = true
+		keys = append(keys, key)
+	}
+
+	if len(keys) > MaxSortKeys {
+		return nil, fmt.Errorf("too many sort keys (max %d)", MaxSortKeys)
+	}
+	return keys, nil
+}
+
+// LegacySortKeys validates the legacy sortBy/sortOrder parameters and converts them to sort keys
+func LegacySortKeys(sortBy string, sortOrder SortOrder) ([]SortKey, error) {
+	if sortBy == "" {
+		sortBy = "timestamp"
+	}
+	if !slices.Contains(SortableFields, sortBy) {
+		return nil, fmt.Errorf("unsupported sort field %q, expected one of %s", sortBy, strings.Join(SortableFields, ", "))
+	}
+
+	switch sortOrder {
+	case "":
+		sortOrder = SortOrderDesc
+	case SortOrderAsc, SortOrderDesc:
+	default:
+		return nil, fmt.Errorf("invalid sort order %q, expected asc or desc", sortOrder)
+	}
+
+	return []SortKey{{Field: sortBy, Order: sortOrder}}, nil
+}
+
+// FormatSortSpec returns the canonical string form of sort keys, the inverse of ParseSortSpec
+func FormatSortSpec(keys []SortKey) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		if key.Order == SortOrderDesc {
+			parts[i] = "-" + key.Field
+		} else {
+			parts[i] = key.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,77 @@
+package request
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSortSpec(t *testing.T) {
+	// Act
+	keys, err := ParseSortSpec("-temperature, +timestamp,humidity")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []SortKey{
+		{Field: "temperature", Order: SortOrderDesc},
+		{Field: "timestamp", Order: SortOrderAsc},
+		{Field: "humidity", Order: SortOrderAsc},
+	}, keys)
+	assert.Equal(t, "-temperature,timestamp,humidity", FormatSortSpec(keys))
+}
+
+func TestParseSortSpec_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "-", "windSpeed", "_id", "timestamp,,pressure", "timestamp,-timestamp", "timestamp,createdAt,temperature,pressure,humidity"} {
+		t.Run(spec, func(t *testing.T) {
+			// Act
+			keys, err := ParseSortSpec(spec)
+
+			// Assert
+			assert.Error(t, err)
+			assert.Nil(t, keys)
+		})
+	}
+}
+
+func TestLegacySortKeys(t *testing.T) {
+	keys, err := LegacySortKeys("", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []SortKey{{Field: "timestamp", Order: SortOrderDesc}}, keys)
+
+	keys, err = LegacySortKeys("pressure", SortOrderAsc)
+	assert.NoError(t, err)
+	assert.Equal(t, []SortKey{{Field: "pressure", Order: SortOrderAsc}}, keys)
+
+	_, err = LegacySortKeys("windSpeed", SortOrderAsc)
+	assert.Error(t, err)
+
+	_, err = LegacySortKeys("timestamp", "sideways")
+	assert.Error(t, err)
+}
+
+func TestEffectiveSort_AppendsTieBreak(t *testing.T) {
+	// Arrange
+	req := &PaginatedReportsRequest{Sort: []SortKey{
+		{Field: "temperature", Order: SortOrderDesc},
+		{Field: "timestamp", Order: SortOrderAsc},
+	}}
+
+	// Act
+	keys, err := req.EffectiveSort()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "-temperature,timestamp,_id", FormatSortSpec(keys))
+}
+
+func TestEffectiveSort_LegacyFields(t *testing.T) {
+	// Arrange
+	req := &PaginatedReportsRequest{SortBy: "humidity", SortOrder: SortOrderDesc}
+
+	// Act
+	keys, err := req.EffectiveSort()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "-humidity,-_id", FormatSortSpec(keys))
+}
@@ -0,0 +1,11 @@
+package response
+
+import (
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+)
+
+// CreatedAPIKey is a newly created API key. The key itself is only returned here and cannot be retrieved later.
+type CreatedAPIKey struct {
+	APIKey models.APIKey `json:"apiKey"`
+	Key    string        `json:"key"` // Send in the X-API-Key header
+}
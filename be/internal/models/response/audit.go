@@ -0,0 +1,12 @@
+package response
+
+import (
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+)
+
+// AuditLogPage is a page of audit entries, newest first
+type AuditLogPage struct {
+	Entries    []models.AuditEntry `json:"entries"`
+	HasMore    bool                `json:"hasMore"`
+	NextCursor string              `json:"nextCursor,omitempty"` // Pass as cursor to get the next, older page
+}
@@ -1,24 +1,194 @@
 package response
 
-import "github.com/DangVTNhan/Scanner/be/internal/models"
+import (
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+)
 
 // ComparisonResult represents the result of comparing two reports
 type ComparisonResult struct {
+	ID        string               `json:"id,omitempty"` // ID of the saved comparison, only set when saving was requested
 	Report1   models.WeatherReport `json:"report1"`
 	Report2   models.WeatherReport `json:"report2"`
 	Deviation Deviation            `json:"deviation"`
 }
 
-// Deviation represents the differences between two reports
+// SavedComparison represents a saved comparison of two report snapshots
+type SavedComparison struct {
+	ID         string                             `json:"id"`
+	Title      string                             `json:"title"`
+	Notes      string                             `json:"notes"`
+	CreatedAt  time.Time                          `json:"createdAt"`
+	Report1    models.WeatherReport               `json:"report1"`
+	Report2    models.WeatherReport               `json:"report2"`
+	Deviation  Deviation                          `json:"deviation"`  // Calculated from the snapshots
+	Thresholds map[models.Metric]models.Threshold `json:"thresholds"` // In effect when the comparison was saved
+}
+
+// SavedComparisonsResponse represents a page of saved comparisons
+type SavedComparisonsResponse struct {
+	Comparisons []SavedComparison `json:"comparisons"` // Saved comparisons, newest first
+	TotalCount  int               `json:"totalCount"`  // Total number of saved comparisons
+}
+
+// Deviation represents the differences between two reports.
+// The top-level metric fields are absolute differences; Metrics carries the signed details.
 type Deviation struct {
-	Temperature float64 `json:"temperature"`
-	Pressure    float64 `json:"pressure"`
-	Humidity    float64 `json:"humidity"`
-	CloudCover  float64 `json:"cloudCover"`
+	Temperature  float64                           `json:"temperature"`
+	Pressure     float64                           `json:"pressure"`
+	Humidity     float64                           `json:"humidity"`
+	CloudCover   float64                           `json:"cloudCover"`
+	HoursElapsed float64                           `json:"hoursElapsed"` // Hours from the first report's timestamp to the second's, negative if the second is earlier
+	Metrics      map[models.Metric]MetricDeviation `json:"metrics"`      // Signed and relative change of every metric
+	Verdict      models.Severity                   `json:"verdict"`      // Highest severity of any metric
+}
+
+// Direction indicates whether a metric went up or down between two reports
+type Direction string
+
+const (
+	DirectionIncrease  Direction = "increase"
+	DirectionDecrease  Direction = "decrease"
+	DirectionUnchanged Direction = "unchanged"
+)
+
+// MetricDeviation describes the change of a single metric from one report to another
+type MetricDeviation struct {
+	Delta         float64         `json:"delta"`         // Second value minus first value
+	PercentChange *float64        `json:"percentChange"` // Delta relative to the first value in %, null if the first value is zero
+	RatePerHour   *float64        `json:"ratePerHour"`   // Delta per hour between the timestamps, null if they are equal
+	Direction     Direction       `json:"direction"`
+	Severity      models.Severity `json:"severity"` // Classification of the absolute delta by the metric's threshold
+}
+
+// MultiComparisonResult represents the result of comparing several reports against a baseline
+type MultiComparisonResult struct {
+	Baseline   models.WeatherReport               `json:"baseline"`   // The report the others are compared to
+	Reports    []ReportDeviation                  `json:"reports"`    // The other reports in request order
+	Statistics map[models.Metric]MetricStatistics `json:"statistics"` // Spread of each metric across all reports, baseline included
+}
+
+// ReportDeviation represents a report and its deviation from the baseline
+type ReportDeviation struct {
+	Report    models.WeatherReport `json:"report"`
+	Deviation Deviation            `json:"deviation"`
+}
+
+// MetricStatistics describes the spread of a metric across a set of reports
+type MetricStatistics struct {
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stdDev"` // Population standard deviation
+	Range  float64 `json:"range"`  // Max - Min
+}
+
+// PeriodStatistics summarizes the reports in a time range
+type PeriodStatistics struct {
+	From    time.Time                          `json:"from"`
+	To      time.Time                          `json:"to"`
+	Count   int                                `json:"count"`   // Number of reports in the range
+	Metrics map[models.Metric]MetricStatistics `json:"metrics"` // Spread of every metric, empty if there are no reports
+}
+
+// Significance is a hint whether the difference between two periods is more than noise
+type Significance string
+
+const (
+	SignificanceSignificant      Significance = "significant"       // |t| of Welch's t-test is at least 2, roughly p < 0.05
+	SignificanceNotSignificant   Significance = "not_significant"   // The difference is within the variation of the periods
+	SignificanceInsufficientData Significance = "insufficient_data" // A period has fewer than two reports
+)
+
+// PeriodMetricComparison compares the statistics of a metric between two periods
+type PeriodMetricComparison struct {
+	MeanDelta    float64      `json:"meanDelta"`    // Mean of the second period minus mean of the first
+	MinDelta     float64      `json:"minDelta"`     // Min of the second period minus min of the first
+	MaxDelta     float64      `json:"maxDelta"`     // Max of the second period minus max of the first
+	TStatistic   *float64     `json:"tStatistic"`   // Welch's t statistic, null if it cannot be calculated
+	Significance Significance `json:"significance"` // Hint based on TStatistic
+}
+
+// PeriodComparisonResult represents the result of comparing two time periods
+type PeriodComparisonResult struct {
+	Period1   PeriodStatistics                         `json:"period1"`
+	Period2   PeriodStatistics                         `json:"period2"`
+	Deviation Deviation                                `json:"deviation"` // Deviation of the period means; hoursElapsed is between the period midpoints
+	Metrics   map[models.Metric]PeriodMetricComparison `json:"metrics"`
 }
 
 // PaginatedReportsResponse represents a paginated response of weather reports
 type PaginatedReportsResponse struct {
-	Reports    []models.WeatherReport `json:"reports"`    // List of reports for the current page
-	TotalCount int                    `json:"totalCount"` // Total number of reports (for calculating total pages)
+	Reports    []models.WeatherReport `json:"reports"`              // List of reports for the current page
+	TotalCount int                    `json:"totalCount"`           // Total number of reports (for calculating total pages), -1 if not counted
+	HasMore    bool                   `json:"hasMore"`              // Whether more reports follow this page
+	NextCursor string                 `json:"nextCursor,omitempty"` // Cursor for the page after this one
+	PrevCursor string                 `json:"prevCursor,omitempty"` // Cursor for the page before this one
+}
+
+// TagCount is a tag and the number of reports that have it
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// BatchReportItem is the outcome of generating the report of one timestamp in a batch
+type BatchReportItem struct {
+	Timestamp time.Time             `json:"timestamp"`
+	Status    string                `json:"status"`            // success or error, like BaseResponse
+	ErrorCode string                `json:"errorCode"`         // Error code (empty if status is success)
+	Message   string                `json:"message,omitempty"` // Error message (empty if status is success)
+	Report    *models.WeatherReport `json:"report,omitempty"`
+	Err       error                 `json:"-"` // Error that caused the failure, mapped to ErrorCode by the handler
+}
+
+// BatchReportResult represents the outcome of a batch report request, one item per distinct timestamp
+type BatchReportResult struct {
+	Items     []BatchReportItem `json:"items"`     // In the order the timestamps were first requested
+	Succeeded int               `json:"succeeded"` // Number of reports generated
+	Failed    int               `json:"failed"`    // Number of timestamps that failed
+}
+
+// ImportLineError describes why a line of an imported file was rejected
+type ImportLineError struct {
+	Line    int    `json:"line"`            // 1-based line number in the file; the CSV header is line 1
+	Field   string `json:"field,omitempty"` // Field the error is about, if any
+	Message string `json:"message"`
+}
+
+// ImportResult represents the outcome of importing a file of reports
+type ImportResult struct {
+	Rows            int               `json:"rows"`            // Number of data rows read
+	Imported        int               `json:"imported"`        // Number of reports inserted, or that would be inserted in a dry run
+	Duplicates      int               `json:"duplicates"`      // Rows skipped because a report with the timestamp exists or appears earlier in the file
+	Failed          int               `json:"failed"`          // Rows rejected as invalid
+	Errors          []ImportLineError `json:"errors"`          // Why rows were rejected, in file order
+	ErrorsTruncated bool              `json:"errorsTruncated"` // Whether more rows failed than are listed in errors
+	DryRun          bool              `json:"dryRun"`          // Whether the rows were only validated, not inserted
+}
+
+// SeriesPoint is a report, or the aggregate of the reports in a bucket, in the history of a metric
+type SeriesPoint struct {
+	Timestamp time.Time `json:"timestamp"` // Time of the report, or the middle of the bucket
+	Value     float64   `json:"value"`     // Value of the report, or mean of the bucket
+	Min       float64   `json:"min"`       // Lowest value in the bucket; the value for reports
+	Max       float64   `json:"max"`       // Highest value in the bucket; the value for reports
+	Count     int       `json:"count"`     // Number of reports
+}
+
+// MetricSeries is the history of a metric over a time range
+type MetricSeries struct {
+	Metric models.Metric `json:"metric"`
+	From   time.Time     `json:"from"`
+	To     time.Time     `json:"to"`
+	Bucket int64         `json:"bucket"` // Length of the buckets the reports are aggregated in, in seconds; zero if points are single reports
+	Points []SeriesPoint `json:"points"` // In time order; empty buckets are omitted
+}
+
+// AlertEvent is a report whose deviation from the report before it reached an alerting severity
+type AlertEvent struct {
+	Previous  models.WeatherReport `json:"previous"` // The report before it, which the deviation is calculated from
+	Report    models.WeatherReport `json:"report"`
+	Deviation Deviation            `json:"deviation"`
 }
@@ -0,0 +1,23 @@
+package response
+
+import (
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+)
+
+// RoleDefinition lists the permissions of a role
+type RoleDefinition struct {
+	Role        models.Role         `json:"role"`
+	Permissions []models.Permission `json:"permissions"`
+}
+
+// RolesResponse lists the roles from least to most privileged, and the role of callers without an assignment
+type RolesResponse struct {
+	Roles       []RoleDefinition `json:"roles"`
+	DefaultRole models.Role      `json:"defaultRole"`
+}
+
+// CurrentIdentity is the caller of a request with the permissions of its role
+type CurrentIdentity struct {
+	Identity    models.Identity     `json:"identity"`
+	Permissions []models.Permission `json:"permissions"`
+}
@@ -0,0 +1,8 @@
+package response
+
+// TenantUsage is how many reports the caller's tenant stores, out of how many it may store
+type TenantUsage struct {
+	Tenant      string `json:"tenant"`
+	Reports     int64  `json:"reports"`     // Stored reports, excluding soft-deleted ones
+	ReportQuota int64  `json:"reportQuota"` // Reports the tenant may store, 0 for no limit
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+)
+
+// Role grants a set of permissions. Every role has the permissions of the roles below it.
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"   // Reads reports, comparisons, charts and feeds
+	RoleAnalyst  Role = "analyst"  // Also annotates reports and compares them
+	RoleOperator Role = "operator" // Also generates, imports and deletes reports and sets thresholds
+	RoleAdmin    Role = "admin"    // Also manages API keys and role assignments and reads the audit log
+)
+
+// Roles lists the roles from least to most privileged
+var Roles = []Role{RoleViewer, RoleAnalyst, RoleOperator, RoleAdmin}
+
+// Permission allows a group of operations
+type Permission string
+
+const (
+	PermissionReadReports      Permission = "reports:read"
+	PermissionAnnotateReports  Permission = "reports:annotate"
+	PermissionCompareReports   Permission = "reports:compare"
+	PermissionGenerateReports  Permission = "reports:generate" // Calls OpenWeather, which is billed
+	PermissionImportReports    Permission = "reports:import"
+	PermissionDeleteReports    Permission = "reports:delete"
+	PermissionManageThresholds Permission = "thresholds:manage"
+	PermissionManageAPIKeys    Permission = "apikeys:manage"
+	PermissionManageRoles      Permission = "roles:manage"
+	PermissionReadAuditLog     Permission = "audit:read"
+)
+
+// roleGrants are the permissions that each role adds to those of the roles below it
+var roleGrants = map[Role][]Permission{
+	RoleViewer:   {PermissionReadReports},
+	RoleAnalyst:  {PermissionAnnotateReports, PermissionCompareReports},
+	RoleOperator: {PermissionGenerateReports, PermissionImportReports, PermissionDeleteReports, PermissionManageThresholds},
+	RoleAdmin:    {PermissionManageAPIKeys, PermissionManageRoles, PermissionReadAuditLog},
+}
+
+// IsValid returns true if r is a known role
+func (r Role) IsValid() bool {
+	_, ok := roleGrants[r]
+	return ok
+}
+
+// Permissions returns all permissions of the role
+func (r Role) Permissions() []Permission {
+	var permissions []Permission
+	for _, role := range Roles {
+		permissions = append(permissions, roleGrants[role]...)
+		if role == r {
+			return permissions
+		}
+	}
+	return nil
+}
+
+// Can returns true if the role has the permission
+func (r Role) Can(permission Permission) bool {
+	for _, p := range r.Permissions() {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleAssignment gives the caller with an identity a role. Callers without an assignment get the default role.
+type RoleAssignment struct {
+	ID        string     `json:"-" bson:"_id"`    // "<tenant>:<method>:<subject>", so each caller has at most one assignment
+	Tenant    string     `json:"-" bson:"tenant"` // Set by the repository from the context
+	Method    AuthMethod `json:"method" bson:"method"`
+	Subject   string     `json:"subject" bson:"subject"` // ID of the API key, or the "sub" claim of the token
+	Role      Role       `json:"role" bson:"role"`
+	UpdatedAt time.Time  `json:"updatedAt" bson:"updatedAt"`
+	UpdatedBy string     `json:"updatedBy,omitempty" bson:"updatedBy,omitempty"` // Name of the admin who assigned the role
+}
+
+// RoleAssignmentID returns the ID of the role assignment of a caller of a tenant
+func RoleAssignmentID(tenant string, method AuthMethod, subject string) string {
+	return tenant + ":" + string(method) + ":" + subject
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"context"
+	"regexp"
+)
+
+// DefaultTenant owns the data of callers that do not belong to a tenant, such as CLI commands, tokens without a
+// tenant claim and every request while authentication is disabled. Data from before tenants existed belongs to it.
+const DefaultTenant = "default"
+
+// tenantPattern matches valid tenant IDs: lower-case letters, digits and dashes, starting with a letter or digit
+var tenantPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// IsValidTenant returns true if tenant is a valid tenant ID, e.g. "changi-ops"
+func IsValidTenant(tenant string) bool {
+	return tenantPattern.MatchString(tenant)
+}
+
+// tenantContextKey is the context key of the tenant of a request
+type tenantContextKey struct{}
+
+// ContextWithTenant returns a copy of ctx whose repository calls only see and create data of tenant
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant of ctx, or DefaultTenant if it has none
+func TenantFromContext(ctx context.Context) string {
+	if tenant, ok := ctx.Value(tenantContextKey{}).(string); ok && tenant != "" {
+		return tenant
+	}
+	return DefaultTenant
+}
+
+// ReportQuota limits how many reports a tenant may store. Soft-deleted reports do not count.
+type ReportQuota struct {
+	Default int64            // Limit of tenants without their own, 0 for no limit
+	Tenants map[string]int64 // Limits of individual tenants, 0 for no limit
+}
+
+// Limit returns the number of reports tenant may store, or 0 if it may store any number
+func (q ReportQuota) Limit(tenant string) int64 {
+	if limit, ok := q.Tenants[tenant]; ok {
+		return limit
+	}
+	return q.Default
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// Severity classifies how significant a deviation is
+type Severity string
+
+const (
+	SeverityNormal  Severity = "normal"
+	SeverityNotable Severity = "notable"
+	SeverityMajor   Severity = "major"
+)
+
+// Rank orders severities from normal (0) to major (2)
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityNotable:
+		return 1
+	case SeverityMajor:
+		return 2
+	}
+	return 0
+}
+
+// Threshold defines from which absolute deviation a change of a metric is notable or major
+type Threshold struct {
+	ID        string     `json:"-" bson:"_id"`    // "<tenant>:<metric>", so each tenant has at most one per metric
+	Tenant    string     `json:"-" bson:"tenant"` // Set by the repository from the context
+	Metric    Metric     `json:"metric" bson:"metric"`
+	Notable   float64    `json:"notable" bson:"notable"` // Deviations of at least this much are notable
+	Major     float64    `json:"major" bson:"major"`     // Deviations of at least this much are major
+	IsDefault bool       `json:"isDefault" bson:"-"`     // Whether the built-in default applies
+	UpdatedAt *time.Time `json:"updatedAt,omitempty" bson:"updatedAt"`
+}
+
+// ThresholdID returns the ID of the threshold of a metric of a tenant
+func ThresholdID(tenant string, metric Metric) string {
+	return tenant + ":" + string(metric)
+}
+
+// DefaultThresholds are used for every metric without a configured threshold
+var DefaultThresholds = map[Metric]Threshold{
+	MetricTemperature: {Metric: MetricTemperature, Notable: 2, Major: 5},
+	MetricPressure:    {Metric: MetricPressure, Notable: 3, Major: 8},
+	MetricHumidity:    {Metric: MetricHumidity, Notable: 10, Major: 25},
+	MetricCloudCover:  {Metric: MetricCloudCover, Notable: 20, Major: 50},
+}
+
+// Classify returns the severity of a deviation of delta, whose sign is ignored
+func (t Threshold) Classify(delta float64) Severity {
+	switch abs := math.Abs(delta); {
+	case abs >= t.Major:
+		return SeverityMajor
+	case abs >= t.Notable:
+		return SeverityNotable
+	}
+	return SeverityNormal
+}
@@ -9,6 +9,7 @@ import (
 // WeatherCache represents a cached weather data entry
 type WeatherCache struct {
 	ID          string                  `json:"id" bson:"_id,omitempty"`
+	Tenant      string                  `json:"-" bson:"tenant"` // Set by the repository from the context
 	Timestamp   time.Time               `json:"timestamp" bson:"timestamp"`
 	WeatherData openweather.WeatherData `json:"weatherData" bson:"weatherData"`
 	CreatedAt   time.Time               `json:"createdAt" bson:"createdAt"`
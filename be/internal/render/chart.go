@@ -0,0 +1,74 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+	"github.com/DangVTNhan/Scanner/be/pkg/chart"
+)
+
+// MetricChart writes the history of a metric as a branded SVG chart. With band set, the min and max of every
+// bucket are drawn behind the line; series of single reports have no band.
+func MetricChart(w io.Writer, series *response.MetricSeries, style request.ChartStyle, band bool, width, height int) error {
+	c := &chart.TimeSeries{
+		Title:   metricLabel(series.Metric),
+		Caption: Brand,
+		Width:   width,
+		Height:  height,
+		Color:   brandColor,
+		Points:  make([]chart.Point, len(series.Points)),
+		From:    series.From,
+		To:      series.To,
+	}
+	if style == request.ChartStyleArea {
+		c.Style = chart.Area
+	}
+
+	c.Subtitle = fmt.Sprintf("%s – %s", formatTime(series.From), formatTime(series.To))
+	if series.Bucket > 0 {
+		c.Subtitle += " · mean per " + formatBucket(time.Duration(series.Bucket)*time.Second)
+		if band {
+			c.Subtitle += ", band shows min – max"
+		}
+	} else if len(series.Points) == 1 {
+		c.Subtitle += " · 1 report"
+	} else {
+		c.Subtitle += fmt.Sprintf(" · %d reports", len(series.Points))
+	}
+
+	for i, point := range series.Points {
+		c.Points[i] = chart.Point{Time: point.Timestamp, Value: point.Value}
+		if band && series.Bucket > 0 {
+			c.Band = append(c.Band, chart.Range{Time: point.Timestamp, Low: point.Min, High: point.Max})
+		}
+	}
+
+	// Bucket midpoints can lie just outside the requested range
+	if len(c.Points) > 0 {
+		if first := c.Points[0].Time; first.Before(c.From) {
+			c.From = first
+		}
+		if last := c.Points[len(c.Points)-1].Time; last.After(c.To) {
+			c.To = last
+		}
+	}
+	return c.WriteSVG(w)
+}
+
+// formatBucket describes a bucket length, e.g. "3 hours" or "day"
+func formatBucket(d time.Duration) string {
+	unit, size := "minute", time.Minute
+	switch {
+	case d%(24*time.Hour) == 0:
+		unit, size = "day", 24*time.Hour
+	case d%time.Hour == 0:
+		unit, size = "hour", time.Hour
+	}
+	if n := d / size; n != 1 {
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+	return unit
+}
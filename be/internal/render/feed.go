@@ -0,0 +1,108 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+	"github.com/DangVTNhan/Scanner/be/pkg/feed"
+)
+
+// alertRefreshInterval is how often calendar clients are asked to poll the alert feed
+const alertRefreshInterval = 15 * time.Minute
+
+// ReportFeed writes reports as an Atom or RSS feed, in the given order. apiURL is the absolute URL of the API,
+// e.g. "https://example.com/api"; entries link to the printable page of their report. updated is the last time
+// any of the reports changed.
+func ReportFeed(w io.Writer, format request.FeedFormat, reports []models.WeatherReport, apiURL string, updated time.Time) error {
+	f := &feed.Feed{
+		ID:       apiURL + "/feeds/reports.atom",
+		Title:    Brand,
+		Subtitle: "Latest weather reports",
+		Link:     fmt.Sprintf("%s/feeds/reports.%s", apiURL, format),
+		HomeLink: apiURL + "/reports",
+		Author:   Brand,
+		Updated:  updated,
+		Entries:  make([]feed.Entry, len(reports)),
+	}
+
+	for i, report := range reports {
+		lines := make([]string, 0, len(models.Metrics)+2)
+		for _, metric := range models.Metrics {
+			lines = append(lines, fmt.Sprintf("%s: %s", metricLabel(metric), formatNumber(report.Value(metric))))
+		}
+		lines = append(lines, fmt.Sprintf("Source: %s (%s)", report.Source, report.Type))
+		if report.Notes != "" {
+			lines = append(lines, "", report.Notes)
+		}
+
+		f.Entries[i] = feed.Entry{
+			ID: apiURL + "/reports/" + url.PathEscape(report.ID),
+			Title: fmt.Sprintf("%s · %s · %s %s", report.Location, formatTime(report.Timestamp),
+				formatNumber(report.Temperature), models.MetricTemperature.Unit()),
+			Link:       apiURL + "/reports/" + url.PathEscape(report.ID) + "/render",
+			Summary:    strings.Join(lines, "\n"),
+			Categories: report.Tags,
+			Published:  report.CreatedAt,
+			Updated:    report.CreatedAt,
+		}
+	}
+
+	if format == request.FeedFormatRSS {
+		return f.WriteRSS(w)
+	}
+	return f.WriteAtom(w)
+}
+
+// AlertCalendar writes alert events as an iCalendar feed. Every event spans the time between the two reports
+// it compares and links to the printable comparison of them.
+func AlertCalendar(w io.Writer, events []response.AlertEvent, apiURL string) error {
+	host := "localhost"
+	if u, err := url.Parse(apiURL); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+
+	c := &feed.Calendar{
+		ProdID:          fmt.Sprintf("-//%s//Alerts//EN", Brand),
+		Name:            Brand + " Alerts",
+		Description:     "Changes between consecutive weather reports that reach the deviation thresholds",
+		RefreshInterval: alertRefreshInterval,
+		Events:          make([]feed.Event, len(events)),
+	}
+
+	for i, event := range events {
+		previous, report := &event.Previous, &event.Report
+
+		var changes, lines []string
+		for _, metric := range models.Metrics {
+			deviation := event.Deviation.Metrics[metric]
+			if deviation.Severity != models.SeverityNormal {
+				changes = append(changes, fmt.Sprintf("%s %s %s", metricLabels[metric], formatSigned(deviation.Delta), metric.Unit()))
+			}
+			lines = append(lines, fmt.Sprintf("%s: %s → %s (%s, %s)", metricLabel(metric),
+				formatNumber(previous.Value(metric)), formatNumber(report.Value(metric)), formatSigned(deviation.Delta), deviation.Severity))
+		}
+		lines = append(lines, "",
+			fmt.Sprintf("From report %s at %s", previous.ID, formatTime(previous.Timestamp)),
+			fmt.Sprintf("To report %s at %s", report.ID, formatTime(report.Timestamp)))
+
+		verdict := string(event.Deviation.Verdict)
+		c.Events[i] = feed.Event{
+			UID:         fmt.Sprintf("%s-%s@%s", previous.ID, report.ID, host),
+			Stamp:       report.CreatedAt,
+			Start:       previous.Timestamp,
+			End:         report.Timestamp,
+			Summary:     fmt.Sprintf("%s change: %s", strings.ToUpper(verdict[:1])+verdict[1:], strings.Join(changes, ", ")),
+			Description: strings.Join(lines, "\n"),
+			URL: fmt.Sprintf("%s/reports/compare/render?reportId1=%s&reportId2=%s",
+				apiURL, url.QueryEscape(previous.ID), url.QueryEscape(report.ID)),
+			Categories: []string{verdict},
+		}
+	}
+	return c.WriteICS(w)
+}
@@ -0,0 +1,46 @@
+package render
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"image/color"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+)
+
+//go:embed templates/*.html
+var templateFiles embed.FS
+
+// Every document extends layout.html, which defines the page and its print styles
+var (
+	reportTemplate     = parseTemplate("report.html")
+	comparisonTemplate = parseTemplate("comparison.html")
+)
+
+// templateFuncs are available to every template
+var templateFuncs = template.FuncMap{
+	// theme returns the colors of the theme as CSS values by name, to declare them as custom properties
+	"theme": func() map[string]template.CSS {
+		return map[string]template.CSS{
+			"brand":   cssColor(brandColor),
+			"text":    cssColor(textColor),
+			"muted":   cssColor(mutedColor),
+			"border":  cssColor(borderColor),
+			"header":  cssColor(headerColor),
+			"normal":  cssColor(severityText[models.SeverityNormal]),
+			"notable": cssColor(severityText[models.SeverityNotable]),
+			"major":   cssColor(severityText[models.SeverityMajor]),
+		}
+	},
+}
+
+// parseTemplate parses the layout and the named document template
+func parseTemplate(name string) *template.Template {
+	return template.Must(template.New("layout.html").Funcs(templateFuncs).ParseFS(templateFiles, "templates/layout.html", "templates/"+name))
+}
+
+// cssColor formats a color as a CSS hex color
+func cssColor(c color.RGBA) template.CSS {
+	return template.CSS(fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B))
+}
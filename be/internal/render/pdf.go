@@ -0,0 +1,294 @@
+package render
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Page geometry of PDF documents in points
+const (
+	pdfMargin      = 50
+	pdfBandHeight  = 56 // Height of the brand header on the first page
+	pdfFooterSpace = 60 // Space kept free at the bottom of every page for the footer
+	pdfRowHeight   = 20
+	pdfLineHeight  = 14
+	pdfCellPadding = 5
+	pdfTableSize   = 9
+	pdfTextSize    = 10
+)
+
+// Styles of the Helvetica core font, which every PDF reader provides so nothing needs to be embedded
+const (
+	pdfRegular = ""
+	pdfBold    = "B"
+)
+
+// pdfColumn is a column of a PDF table
+type pdfColumn struct {
+	title string
+	width float64
+	right bool // Right-align the values, for numbers
+}
+
+// pdfCell is a value of a PDF table
+type pdfCell struct {
+	text  string
+	color color.Color // Defaults to the text color
+	bold  bool
+}
+
+// pdfLayout places content from the top of the page downwards, starting a new page when it is full
+type pdfLayout struct {
+	doc    *fpdf.Fpdf
+	encode func(string) string // Converts UTF-8 to the Windows-1252 encoding of the core fonts
+	y      float64             // Position of the next content
+}
+
+// newPDFLayout returns a layout of an empty A4 document
+func newPDFLayout() *pdfLayout {
+	doc := fpdf.New("P", "pt", "A4", "")
+	// Pages are broken by the layout, which keeps space for the footer and repeats table headers
+	doc.SetAutoPageBreak(false, 0)
+	return &pdfLayout{doc: doc, encode: doc.UnicodeTranslatorFromDescriptor("")}
+}
+
+// layoutPDF lays out v as an A4 document
+func layoutPDF(v *view) *fpdf.Fpdf {
+	l := newPDFLayout()
+	l.doc.SetTitle(fmt.Sprintf("%s – %s", v.Title, v.Brand), true)
+	l.doc.AddPage()
+
+	pageWidth, _ := l.doc.GetPageSize()
+	l.fillRect(0, 0, pageWidth, pdfBandHeight, brandColor)
+	l.text(pdfMargin, 35, pdfBold, 16, color.White, v.Brand)
+	l.text(pdfMargin, 96, pdfBold, 20, textColor, v.Title)
+	l.text(pdfMargin, 114, pdfRegular, 11, mutedColor, v.Subtitle)
+	l.y = 140
+
+	for _, d := range v.Details {
+		l.ensure(pdfLineHeight + 2)
+		l.text(pdfMargin, l.y, pdfRegular, pdfTextSize, mutedColor, d.Label)
+		l.text(pdfMargin+110, l.y, pdfRegular, pdfTextSize, textColor, l.fit(pdfRegular, pdfTextSize, d.Value, l.width()-110))
+		l.y += pdfLineHeight + 2
+	}
+	l.y += 12
+
+	if v.Metrics != nil {
+		rows := make([][]pdfCell, len(v.Metrics))
+		for i, metric := range v.Metrics {
+			rows[i] = []pdfCell{{text: metric.Label}, {text: metric.Value}}
+		}
+		l.table([]pdfColumn{{"Parameter", 330, false}, {"Value", 165, true}}, rows)
+	}
+
+	if v.Deviations != nil {
+		l.ensure(pdfLineHeight + 8)
+		l.text(pdfMargin, l.y, pdfRegular, pdfTextSize, textColor, "Overall:")
+		l.text(pdfMargin+45, l.y, pdfBold, pdfTextSize, severityText[v.Verdict], capitalize(string(v.Verdict)))
+		l.y += pdfLineHeight
+
+		rows := make([][]pdfCell, len(v.Deviations))
+		for i, deviation := range v.Deviations {
+			delta := deviation.Delta
+			if deviation.Change != "" {
+				delta += " (" + deviation.Change + ")"
+			}
+			severity := pdfCell{text: "–", color: mutedColor}
+			if deviation.Severity != "" {
+				severity = pdfCell{text: capitalize(string(deviation.Severity)), color: severityText[deviation.Severity], bold: true}
+			}
+			rows[i] = []pdfCell{{text: deviation.Label}, {text: deviation.Value1}, {text: deviation.Value2}, {text: delta}, severity}
+		}
+		l.table([]pdfColumn{
+			{"Parameter", 105, false},
+			{"Report 1", 110, false},
+			{"Report 2", 110, false},
+			{"Deviation", 110, false},
+			{"Severity", 60, false},
+		}, rows)
+	}
+
+	if v.Notes != "" {
+		l.ensure(pdfLineHeight * 2)
+		l.text(pdfMargin, l.y, pdfBold, 12, textColor, "Notes")
+		l.y += pdfLineHeight + 4
+		for _, line := range l.wrap(pdfRegular, pdfTextSize, v.Notes, l.width()) {
+			l.ensure(pdfLineHeight)
+			l.text(pdfMargin, l.y, pdfRegular, pdfTextSize, textColor, line)
+			l.y += pdfLineHeight
+		}
+	}
+
+	l.footers(fmt.Sprintf("Generated %s by %s", v.GeneratedAt, v.Brand))
+	return l.doc
+}
+
+// width returns the width available for content
+func (l *pdfLayout) width() float64 {
+	pageWidth, _ := l.doc.GetPageSize()
+	return pageWidth - 2*pdfMargin
+}
+
+// height returns the page height
+func (l *pdfLayout) height() float64 {
+	_, pageHeight := l.doc.GetPageSize()
+	return pageHeight
+}
+
+// ensure starts a new page unless height fits above the footer
+func (l *pdfLayout) ensure(height float64) {
+	if l.y+height <= l.height()-pdfFooterSpace {
+		return
+	}
+	l.doc.AddPage()
+	l.y = pdfMargin + pdfLineHeight
+}
+
+// table draws a table with a shaded header row, repeating the header after a page break
+func (l *pdfLayout) table(columns []pdfColumn, rows [][]pdfCell) {
+	header := func() {
+		l.fillRect(pdfMargin, l.y, l.width(), pdfRowHeight, headerColor)
+		cells := make([]pdfCell, len(columns))
+		for i, column := range columns {
+			cells[i] = pdfCell{text: column.title, bold: true}
+		}
+		l.row(columns, cells)
+	}
+
+	l.ensure(2 * pdfRowHeight)
+	l.line(pdfMargin, l.y, pdfMargin+l.width(), l.y, 0.75, borderColor)
+	header()
+	for _, cells := range rows {
+		if l.y+pdfRowHeight > l.height()-pdfFooterSpace {
+			l.ensure(2 * pdfRowHeight)
+			l.line(pdfMargin, l.y, pdfMargin+l.width(), l.y, 0.75, borderColor)
+			header()
+		}
+		l.row(columns, cells)
+	}
+	l.y += 16
+}
+
+// row draws the cells of a table row followed by its bottom border
+func (l *pdfLayout) row(columns []pdfColumn, cells []pdfCell) {
+	baseline := l.y + pdfRowHeight/2 + pdfTableSize*0.35
+	x := float64(pdfMargin)
+	for i, column := range columns {
+		cell := cells[i]
+		style := pdfRegular
+		if cell.bold {
+			style = pdfBold
+		}
+		var c color.Color = textColor
+		if cell.color != nil {
+			c = cell.color
+		}
+
+		text := l.fit(style, pdfTableSize, cell.text, column.width-2*pdfCellPadding)
+		if column.right {
+			l.textRight(x+column.width-pdfCellPadding, baseline, style, pdfTableSize, c, text)
+		} else {
+			l.text(x+pdfCellPadding, baseline, style, pdfTableSize, c, text)
+		}
+		x += column.width
+	}
+	l.y += pdfRowHeight
+	l.line(pdfMargin, l.y, pdfMargin+l.width(), l.y, 0.75, borderColor)
+}
+
+// footers draws the footer with the page number on every page
+func (l *pdfLayout) footers(text string) {
+	pages := l.doc.PageCount()
+	for i := 1; i <= pages; i++ {
+		l.doc.SetPage(i)
+		y := l.height() - 40
+		l.line(pdfMargin, y, pdfMargin+l.width(), y, 0.75, borderColor)
+		l.text(pdfMargin, y+14, pdfRegular, 8, mutedColor, text)
+		l.textRight(pdfMargin+l.width(), y+14, pdfRegular, 8, mutedColor, fmt.Sprintf("Page %d of %d", i, pages))
+	}
+}
+
+// text draws s with its baseline starting at (x, y)
+func (l *pdfLayout) text(x, y float64, style string, size float64, c color.Color, s string) {
+	l.doc.SetFont("Helvetica", style, size)
+	l.doc.SetTextColor(rgb(c))
+	l.doc.Text(x, y, l.encode(s))
+}
+
+// textRight draws s ending at x, to right-align numbers
+func (l *pdfLayout) textRight(x, y float64, style string, size float64, c color.Color, s string) {
+	l.text(x-l.textWidth(style, size, s), y, style, size, c, s)
+}
+
+// fillRect fills the rectangle whose top-left corner is (x, y)
+func (l *pdfLayout) fillRect(x, y, width, height float64, c color.Color) {
+	l.doc.SetFillColor(rgb(c))
+	l.doc.Rect(x, y, width, height, "F")
+}
+
+// line draws a straight line of the given width from (x1, y1) to (x2, y2)
+func (l *pdfLayout) line(x1, y1, x2, y2, width float64, c color.Color) {
+	l.doc.SetLineWidth(width)
+	l.doc.SetDrawColor(rgb(c))
+	l.doc.Line(x1, y1, x2, y2)
+}
+
+// textWidth returns the width of s in points
+func (l *pdfLayout) textWidth(style string, size float64, s string) float64 {
+	l.doc.SetFont("Helvetica", style, size)
+	return l.doc.GetStringWidth(l.encode(s))
+}
+
+// fit shortens text with an ellipsis until it is at most width wide
+func (l *pdfLayout) fit(style string, size float64, text string, width float64) string {
+	if l.textWidth(style, size, text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && l.textWidth(style, size, string(runes)+"…") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// wrap breaks text into lines at most width wide, keeping its line breaks.
+// Words wider than a line are shortened.
+func (l *pdfLayout) wrap(style string, size float64, text string, width float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if l.textWidth(style, size, candidate) <= width {
+				line = candidate
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+			line = l.fit(style, size, word, width)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// rgb returns the 8-bit components of a color
+func rgb(c color.Color) (int, int, int) {
+	r, g, b, _ := c.RGBA()
+	return int(r >> 8), int(g >> 8), int(b >> 8)
+}
+
+// capitalize upper-cases the first letter of s
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
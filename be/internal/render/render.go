@@ -0,0 +1,192 @@
+// Package render produces branded, printable documents of reports and comparisons as HTML or PDF,
+// and SVG charts of metric history. Both document formats are generated from the same view, so they
+// show the same values.
+package render
+
+import (
+	"fmt"
+	"html/template"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+)
+
+// Brand is printed in the header of every document
+const Brand = "Changi Airport Weather Reports"
+
+// timeLayout formats timestamps in documents; times are shown in UTC
+const timeLayout = "02 Jan 2006 15:04 MST"
+
+// Colors of the document theme, shared by the HTML styles and the PDF layout
+var (
+	brandColor   = color.RGBA{R: 0x5b, G: 0x21, B: 0x82, A: 0xff} // Header band
+	textColor    = color.RGBA{R: 0x1e, G: 0x29, B: 0x3b, A: 0xff}
+	mutedColor   = color.RGBA{R: 0x64, G: 0x74, B: 0x8b, A: 0xff}
+	borderColor  = color.RGBA{R: 0xe2, G: 0xe8, B: 0xf0, A: 0xff}
+	headerColor  = color.RGBA{R: 0xf8, G: 0xfa, B: 0xfc, A: 0xff} // Table header background
+	severityText = map[models.Severity]color.RGBA{
+		models.SeverityNormal:  {R: 0x15, G: 0x80, B: 0x3d, A: 0xff},
+		models.SeverityNotable: {R: 0xb4, G: 0x53, B: 0x09, A: 0xff},
+		models.SeverityMajor:   {R: 0xb9, G: 0x1c, B: 0x1c, A: 0xff},
+	}
+)
+
+// metricLabels are the display names of the metrics, as on the comparison page
+var metricLabels = map[models.Metric]string{
+	models.MetricTemperature: "Temperature",
+	models.MetricPressure:    "Pressure",
+	models.MetricHumidity:    "Humidity",
+	models.MetricCloudCover:  "Cloud Cover",
+}
+
+// detail is a labelled value in the summary of a document
+type detail struct {
+	Label string
+	Value string
+}
+
+// metricRow is a row of the metric table of a report
+type metricRow struct {
+	Label string // Metric name with its unit, e.g. "Temperature (°C)"
+	Value string
+}
+
+// deviationRow is a row of the metric table of a comparison
+type deviationRow struct {
+	Label     string
+	Value1    string
+	Value2    string
+	Delta     string // Signed change from the first report to the second
+	Change    string // Relative change in %, empty if it is undefined
+	Direction response.Direction
+	Severity  models.Severity // Empty for rows that are not classified, such as the timestamp
+}
+
+// view is the content of a rendered document
+type view struct {
+	Brand       string
+	Title       string
+	Subtitle    string
+	GeneratedAt string
+	Details     []detail
+	Metrics     []metricRow    // Set for reports
+	Deviations  []deviationRow // Set for comparisons
+	Verdict     models.Severity
+	Notes       string
+}
+
+// Report writes a printable document of a weather report in the given format
+func Report(w io.Writer, format request.RenderFormat, report *models.WeatherReport, generatedAt time.Time) error {
+	v := &view{
+		Brand:       Brand,
+		Title:       "Weather Report",
+		Subtitle:    fmt.Sprintf("%s · %s", report.Location, formatTime(report.Timestamp)),
+		GeneratedAt: formatTime(generatedAt),
+		Details: []detail{
+			{"Report ID", report.ID},
+			{"Observed at", formatTime(report.Timestamp)},
+			{"Location", report.Location},
+			{"Source", report.Source},
+			{"Type", report.Type},
+			{"Generated at", formatTime(report.CreatedAt)},
+		},
+		Notes: report.Notes,
+	}
+	if len(report.Tags) > 0 {
+		v.Details = append(v.Details, detail{"Tags", strings.Join(report.Tags, ", ")})
+	}
+	for _, metric := range models.Metrics {
+		v.Metrics = append(v.Metrics, metricRow{Label: metricLabel(metric), Value: formatNumber(report.Value(metric))})
+	}
+	return write(w, format, reportTemplate, v)
+}
+
+// Comparison writes a printable document comparing two reports in the given format.
+// The title and notes are those of a saved comparison; an unsaved one has neither.
+func Comparison(w io.Writer, format request.RenderFormat, comparison *response.SavedComparison, generatedAt time.Time) error {
+	report1, report2 := &comparison.Report1, &comparison.Report2
+	v := &view{
+		Brand:       Brand,
+		Title:       comparison.Title,
+		Subtitle:    fmt.Sprintf("%s – %s", formatTime(report1.Timestamp), formatTime(report2.Timestamp)),
+		GeneratedAt: formatTime(generatedAt),
+		Details: []detail{
+			{"Report 1", fmt.Sprintf("%s · %s · %s", report1.ID, report1.Location, report1.Source)},
+			{"Report 2", fmt.Sprintf("%s · %s · %s", report2.ID, report2.Location, report2.Source)},
+		},
+		Verdict: comparison.Deviation.Verdict,
+		Notes:   comparison.Notes,
+	}
+	if v.Title == "" {
+		v.Title = "Report Comparison"
+	}
+	if !comparison.CreatedAt.IsZero() {
+		v.Details = append(v.Details, detail{"Saved at", formatTime(comparison.CreatedAt)})
+	}
+
+	v.Deviations = append(v.Deviations, deviationRow{
+		Label:  "Timestamp",
+		Value1: formatTime(report1.Timestamp),
+		Value2: formatTime(report2.Timestamp),
+		Delta:  formatSigned(comparison.Deviation.HoursElapsed) + " h",
+	})
+	for _, metric := range models.Metrics {
+		deviation := comparison.Deviation.Metrics[metric]
+		row := deviationRow{
+			Label:     metricLabel(metric),
+			Value1:    formatNumber(report1.Value(metric)),
+			Value2:    formatNumber(report2.Value(metric)),
+			Delta:     formatSigned(deviation.Delta),
+			Direction: deviation.Direction,
+			Severity:  deviation.Severity,
+		}
+		if deviation.PercentChange != nil {
+			row.Change = formatSigned(*deviation.PercentChange) + "%"
+		}
+		v.Deviations = append(v.Deviations, row)
+	}
+	return write(w, format, comparisonTemplate, v)
+}
+
+// write renders v as HTML with the template, or as PDF
+func write(w io.Writer, format request.RenderFormat, tmpl *template.Template, v *view) error {
+	if format == request.RenderFormatPDF {
+		return layoutPDF(v).Output(w)
+	}
+	return tmpl.Execute(w, v)
+}
+
+// metricLabel returns the display name of a metric with its unit
+func metricLabel(metric models.Metric) string {
+	return fmt.Sprintf("%s (%s)", metricLabels[metric], metric.Unit())
+}
+
+// formatTime formats a timestamp in UTC
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// formatNumber formats a measurement with at most two decimals
+func formatNumber(value float64) string {
+	s := strconv.FormatFloat(value, 'f', 2, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// formatSigned formats a change with two decimals and an explicit sign
+func formatSigned(value float64) string {
+	if math.Abs(value) < 0.005 {
+		return "0.00"
+	}
+	return fmt.Sprintf("%+.2f", value)
+}
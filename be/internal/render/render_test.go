@@ -0,0 +1,278 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var generatedAt = time.Date(2024, 3, 2, 8, 30, 0, 0, time.UTC)
+
+func testReport() *models.WeatherReport {
+	timestamp := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	return &models.WeatherReport{
+		ID: "report1", Timestamp: timestamp, Temperature: 25.5, Pressure: 1013.2, Humidity: 60, CloudCover: 30,
+		Location: models.LocationChangi, Source: models.SourceOpenWeather, Type: models.ReportTypeHistorical, CreatedAt: timestamp,
+		Tags: []string{"runway closure"}, Notes: "Closed <02L> & 02C",
+	}
+}
+
+func testComparison() *response.SavedComparison {
+	report2 := *testReport()
+	report2.ID = "report2"
+	report2.Timestamp = report2.Timestamp.Add(3 * time.Hour)
+	report2.Temperature = 26.7
+	percent := 4.705882
+	return &response.SavedComparison{
+		Title:   "Afternoon warming",
+		Report1: *testReport(),
+		Report2: report2,
+		Deviation: response.Deviation{
+			HoursElapsed: 3,
+			Metrics: map[models.Metric]response.MetricDeviation{
+				models.MetricTemperature: {Delta: 1.2, PercentChange: &percent, Direction: response.DirectionIncrease, Severity: models.SeverityNormal},
+				models.MetricPressure:    {Delta: -8.5, Direction: response.DirectionDecrease, Severity: models.SeverityMajor},
+				models.MetricHumidity:    {Direction: response.DirectionUnchanged, Severity: models.SeverityNormal},
+				models.MetricCloudCover:  {Direction: response.DirectionUnchanged, Severity: models.SeverityNormal},
+			},
+			Verdict: models.SeverityMajor,
+		},
+	}
+}
+
+func TestReport_HTML(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+
+	// Act
+	err := Report(&buf, request.RenderFormatHTML, testReport(), generatedAt)
+
+	// Assert
+	require.NoError(t, err)
+	html := buf.String()
+	assert.True(t, strings.HasPrefix(html, "<!DOCTYPE html>"))
+	assert.Contains(t, html, "--brand: #5b2182;")
+	assert.Contains(t, html, "<h1>Weather Report</h1>")
+	assert.Contains(t, html, "<p class=\"subtitle\">WSSS · 01 Mar 2024 12:00 UTC</p>")
+	assert.Contains(t, html, "<dt>Tags</dt>\n      <dd>runway closure</dd>")
+	assert.Contains(t, html, `<tr><td>Temperature (°C)</td><td class="number">25.5</td></tr>`)
+	assert.Contains(t, html, `<tr><td>Pressure (hPa)</td><td class="number">1013.2</td></tr>`)
+	assert.Contains(t, html, "Closed &lt;02L&gt; &amp; 02C", "notes are escaped")
+	assert.Contains(t, html, "Generated 02 Mar 2024 08:30 UTC")
+}
+
+func TestComparison_HTML(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+
+	// Act
+	err := Comparison(&buf, request.RenderFormatHTML, testComparison(), generatedAt)
+
+	// Assert
+	require.NoError(t, err)
+	html := buf.String()
+	assert.Contains(t, html, "<h1>Afternoon warming</h1>")
+	assert.Contains(t, html, `Overall: <span class="severity severity-major">major</span>`)
+	assert.Contains(t, html, "<td>Timestamp</td>\n          <td>01 Mar 2024 12:00 UTC</td>\n          <td>01 Mar 2024 15:00 UTC</td>\n          <td>&#43;3.00 h</td>\n          <td>–</td>")
+	assert.Contains(t, html, "<td>▲ &#43;1.20 <span class=\"change\">(&#43;4.71%)</span></td>")
+	assert.Contains(t, html, "<td>▼ -8.50</td>")
+	assert.Contains(t, html, "<td>0.00</td>")
+	assert.Contains(t, html, `<span class="severity severity-major">major</span></td>`)
+}
+
+func TestComparison_DefaultTitle(t *testing.T) {
+	// Arrange
+	comparison := testComparison()
+	comparison.Title = ""
+	var buf bytes.Buffer
+
+	// Act
+	err := Comparison(&buf, request.RenderFormatHTML, comparison, generatedAt)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "<h1>Report Comparison</h1>")
+}
+
+func TestRender_PDF(t *testing.T) {
+	for name, render := range map[string]func(*bytes.Buffer) error{
+		"Report": func(buf *bytes.Buffer) error { return Report(buf, request.RenderFormatPDF, testReport(), generatedAt) },
+		"Comparison": func(buf *bytes.Buffer) error {
+			return Comparison(buf, request.RenderFormatPDF, testComparison(), generatedAt)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			var buf bytes.Buffer
+
+			// Act
+			err := render(&buf)
+
+			// Assert
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(buf.String(), "%PDF-"))
+			assert.True(t, strings.HasSuffix(buf.String(), "%%EOF\n"))
+			assert.Contains(t, buf.String(), "/Count 1")
+		})
+	}
+}
+
+func TestLayoutPDF_LongNotesBreakPages(t *testing.T) {
+	// Arrange
+	report := testReport()
+	report.Notes = strings.Repeat("Runway 02L closed for resurfacing.\n", 60)
+	v := &view{Title: "Weather Report", Notes: report.Notes}
+
+	// Act
+	doc := layoutPDF(v)
+
+	// Assert
+	assert.Equal(t, 2, doc.PageCount())
+	assert.NoError(t, doc.Error())
+}
+
+func TestFit(t *testing.T) {
+	l := newPDFLayout()
+	assert.Equal(t, "short", l.fit(pdfRegular, 10, "short", 100))
+
+	fitted := l.fit(pdfRegular, 10, "a value that is far too long for the column", 80)
+	assert.True(t, strings.HasSuffix(fitted, "…"))
+	assert.LessOrEqual(t, l.textWidth(pdfRegular, 10, fitted), 80.0)
+}
+
+func TestWrap(t *testing.T) {
+	// Arrange
+	l := newPDFLayout()
+
+	// Act
+	lines := l.wrap(pdfRegular, 10, "one two three four\n\nfive", l.textWidth(pdfRegular, 10, "one two three"))
+
+	// Assert
+	assert.Equal(t, []string{"one two three", "four", "", "five"}, lines)
+}
+
+func TestMetricChart(t *testing.T) {
+	// Arrange
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	series := &response.MetricSeries{
+		Metric: models.MetricTemperature,
+		From:   from,
+		To:     from.Add(9 * time.Hour),
+		Bucket: 3 * 60 * 60,
+		Points: []response.SeriesPoint{
+			{Timestamp: from.Add(90 * time.Minute), Value: 26, Min: 25, Max: 27, Count: 3},
+			{Timestamp: from.Add(270 * time.Minute), Value: 28, Min: 27.5, Max: 29, Count: 3},
+		},
+	}
+	var buf bytes.Buffer
+
+	// Act
+	err := MetricChart(&buf, series, request.ChartStyleLine, true, 600, 300)
+
+	// Assert
+	require.NoError(t, err)
+	svg := buf.String()
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="600" height="300"`))
+	assert.Contains(t, svg, "<title>Temperature (°C)</title>")
+	assert.Contains(t, svg, "01 Mar 2024 00:00 UTC – 01 Mar 2024 09:00 UTC · mean per 3 hours, band shows min – max")
+	assert.Contains(t, svg, `fill="#5b2182" fill-opacity="0.18"`, "band")
+	assert.NotContains(t, svg, `fill-opacity="0.25"`, "no area")
+	assert.Contains(t, svg, ">"+Brand+"</text>")
+}
+
+func TestMetricChart_Reports(t *testing.T) {
+	// Arrange
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	series := &response.MetricSeries{
+		Metric: models.MetricHumidity,
+		From:   from,
+		To:     from.Add(2 * time.Hour),
+		Points: []response.SeriesPoint{{Timestamp: from, Value: 60, Min: 60, Max: 60, Count: 1}},
+	}
+	var buf bytes.Buffer
+
+	// Act
+	err := MetricChart(&buf, series, request.ChartStyleArea, true, 600, 300)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "· 1 report<")
+	assert.NotContains(t, buf.String(), `fill-opacity="0.18"`, "single reports have no band")
+	assert.Contains(t, buf.String(), `fill-opacity="0.25"`, "area")
+}
+
+func TestFormatBucket(t *testing.T) {
+	assert.Equal(t, "minute", formatBucket(time.Minute))
+	assert.Equal(t, "30 minutes", formatBucket(30*time.Minute))
+	assert.Equal(t, "90 minutes", formatBucket(90*time.Minute))
+	assert.Equal(t, "hour", formatBucket(time.Hour))
+	assert.Equal(t, "3 hours", formatBucket(3*time.Hour))
+	assert.Equal(t, "day", formatBucket(24*time.Hour))
+	assert.Equal(t, "7 days", formatBucket(7*24*time.Hour))
+}
+
+func TestReportFeed_Atom(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+
+	// Act
+	err := ReportFeed(&buf, request.FeedFormatAtom, []models.WeatherReport{*testReport()}, "https://example.com/api", generatedAt)
+
+	// Assert
+	require.NoError(t, err)
+	atom := buf.String()
+	assert.Contains(t, atom, `<feed xmlns="http://www.w3.org/2005/Atom">`)
+	assert.Contains(t, atom, `<link rel="self" type="application/atom+xml" href="https://example.com/api/feeds/reports.atom"></link>`)
+	assert.Contains(t, atom, "<updated>2024-03-02T08:30:00Z</updated>")
+	assert.Contains(t, atom, "<id>https://example.com/api/reports/report1</id>")
+	assert.Contains(t, atom, "<title>WSSS · 01 Mar 2024 12:00 UTC · 25.5 °C</title>")
+	assert.Contains(t, atom, `<link rel="alternate" href="https://example.com/api/reports/report1/render"></link>`)
+	assert.Contains(t, atom, "Pressure (hPa): 1013.2&#xA;Humidity (%): 60", "line breaks are escaped")
+	assert.Contains(t, atom, "Closed &lt;02L&gt; &amp; 02C")
+	assert.Contains(t, atom, `<category term="runway closure"></category>`)
+}
+
+func TestReportFeed_RSS(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+
+	// Act
+	err := ReportFeed(&buf, request.FeedFormatRSS, []models.WeatherReport{*testReport()}, "https://example.com/api", generatedAt)
+
+	// Assert
+	require.NoError(t, err)
+	rss := buf.String()
+	assert.Contains(t, rss, `<rss version="2.0"`)
+	assert.Contains(t, rss, `href="https://example.com/api/feeds/reports.rss"`)
+	assert.Contains(t, rss, `<guid isPermaLink="false">https://example.com/api/reports/report1</guid>`)
+	assert.Contains(t, rss, "<pubDate>Fri, 01 Mar 2024 12:00:00 +0000</pubDate>")
+}
+
+func TestAlertCalendar(t *testing.T) {
+	// Arrange
+	comparison := testComparison()
+	comparison.Report2.CreatedAt = generatedAt
+	events := []response.AlertEvent{{Previous: comparison.Report1, Report: comparison.Report2, Deviation: comparison.Deviation}}
+	var buf bytes.Buffer
+
+	// Act
+	err := AlertCalendar(&buf, events, "https://example.com/api")
+
+	// Assert
+	require.NoError(t, err)
+	ics := strings.ReplaceAll(buf.String(), "\r\n ", "") // Unfold long lines
+	assert.Contains(t, ics, "UID:report1-report2@example.com\r\n")
+	assert.Contains(t, ics, "DTSTAMP:20240302T083000Z\r\n")
+	assert.Contains(t, ics, "DTSTART:20240301T120000Z\r\nDTEND:20240301T150000Z\r\n")
+	assert.Contains(t, ics, "SUMMARY:Major change: Pressure -8.50 hPa\r\n")
+	assert.Contains(t, ics, `DESCRIPTION:Temperature (°C): 25.5 → 26.7 (+1.20\, normal)\nPressure (hPa): 1`)
+	assert.Contains(t, ics, "URL;VALUE=URI:https://example.com/api/reports/compare/render?reportId1=report1&reportId2=report2\r\n")
+	assert.Contains(t, ics, "CATEGORIES:major\r\n")
+}
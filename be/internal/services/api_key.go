@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+)
+
+const (
+	// APIKeyPrefix starts every API key, so leaked keys are easy to recognize
+	APIKeyPrefix = "wr_"
+
+	// APIKeyUsageResolution is how often the last use of a key is recorded at most
+	APIKeyUsageResolution = time.Minute
+)
+
+// apiKeyBytes is the number of random bytes in a key
+const apiKeyBytes = 32
+
+// apiKeyPrefixLength is how many characters of a key are stored to recognize it
+const apiKeyPrefixLength = len(APIKeyPrefix) + 6
+
+// APIKeyService handles the creation, revocation and checking of API keys
+type APIKeyService struct {
+	apiKeyRepository repository.IAPIKeyRepository
+}
+
+// NewAPIKeyService creates a new instance of APIKeyService
+func NewAPIKeyService(apiKeyRepository repository.IAPIKeyRepository) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepository: apiKeyRepository,
+	}
+}
+
+// CreateAPIKey generates a new random API key and stores its hash. The key is only returned here.
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, req *request.APIKeyRequest) (*response.CreatedAPIKey, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("invalid api key: name is required")
+	}
+	if len(name) > request.MaxAPIKeyNameLength {
+		return nil, fmt.Errorf("invalid api key: name must be at most %d characters", request.MaxAPIKeyNameLength)
+	}
+
+	secret := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+	key := APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	apiKey := models.APIKey{
+		Name:      name,
+		Prefix:    key[:apiKeyPrefixLength],
+		Hash:      hashAPIKey(key),
+		CreatedAt: time.Now().UTC(),
+	}
+	id, err := s.apiKeyRepository.InsertAPIKey(ctx, &apiKey)
+	if err != nil {
+		return nil, err
+	}
+	apiKey.ID = id
+
+	return &response.CreatedAPIKey{APIKey: apiKey, Key: key}, nil
+}
+
+// ListAPIKeys retrieves all API keys, revoked ones included, newest first
+func (s *APIKeyService) ListAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	return s.apiKeyRepository.FindAPIKeys(ctx)
+}
+
+// RevokeAPIKey revokes an API key so it no longer authenticates, and returns it
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, id string) (*models.APIKey, error) {
+	if err := s.apiKeyRepository.RevokeAPIKey(ctx, id, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	return s.apiKeyRepository.FindAPIKeyByID(ctx, id)
+}
+
+// Authenticate returns the API key matching key, of any tenant. It returns "invalid api key" for unknown and revoked keys.
+// The last use of the key is recorded at most once per APIKeyUsageResolution; failing to record it is
+// logged but does not fail the authentication.
+func (s *APIKeyService) Authenticate(ctx context.Context, key string) (*models.APIKey, error) {
+	if !strings.HasPrefix(key, APIKeyPrefix) {
+		return nil, fmt.Errorf("invalid api key")
+	}
+
+	apiKey, err := s.apiKeyRepository.FindAPIKeyByHash(ctx, hashAPIKey(key))
+	if err != nil {
+		if err.Error() == "api key not found" {
+			return nil, fmt.Errorf("invalid api key")
+		}
+		return nil, err
+	}
+	if apiKey.IsRevoked() {
+		return nil, fmt.Errorf("invalid api key")
+	}
+
+	now := time.Now().UTC()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= APIKeyUsageResolution {
+		// Keys are looked up across tenants, but only the tenant of the key can update it
+		keyCtx := models.ContextWithTenant(ctx, apiKey.Tenant)
+		if err := s.apiKeyRepository.UpdateAPIKeyLastUsed(keyCtx, apiKey.ID, now); err != nil {
+			log.Printf("Failed to record use of API key %s: %v", apiKey.ID, err)
+		} else {
+			apiKey.LastUsedAt = &now
+		}
+	}
+	return apiKey, nil
+}
+
+// hashAPIKey returns the hex SHA-256 of a key. Keys are long and random, so a fast unsalted hash is enough.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
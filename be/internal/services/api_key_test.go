@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository/memory"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAPIKeyRepository is a mock implementation of IAPIKeyRepository
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) InsertAPIKey(ctx context.Context, key *models.APIKey) (string, error) {
+	args := m.Called(ctx, key)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) FindAPIKeyByID(ctx context.Context, id string) (*models.APIKey, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) FindAPIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	args := m.Called(ctx, hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) FindAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error {
+	args := m.Called(ctx, id, revokedAt)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) UpdateAPIKeyLastUsed(ctx context.Context, id string, usedAt time.Time) error {
+	args := m.Called(ctx, id, usedAt)
+	return args.Error(0)
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	// Arrange
+	service := NewAPIKeyService(memory.NewAPIKeyRepository())
+
+	// Act
+	created, err := service.CreateAPIKey(context.Background(), &request.APIKeyRequest{Name: "  grafana "})
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.Key, APIKeyPrefix))
+	assert.Len(t, created.Key, len(APIKeyPrefix)+43, "32 random bytes in unpadded base64")
+	assert.NotEmpty(t, created.APIKey.ID)
+	assert.Equal(t, "grafana", created.APIKey.Name)
+	assert.Equal(t, created.Key[:len(created.APIKey.Prefix)], created.APIKey.Prefix)
+	assert.NotContains(t, created.APIKey.Hash, created.Key, "only a hash of the key is stored")
+
+	keys, err := service.ListAPIKeys(context.Background())
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, hashAPIKey(created.Key), keys[0].Hash)
+}
+
+func TestCreateAPIKey_InvalidName(t *testing.T) {
+	service := NewAPIKeyService(memory.NewAPIKeyRepository())
+
+	_, err := service.CreateAPIKey(context.Background(), &request.APIKeyRequest{Name: " "})
+	assert.EqualError(t, err, "invalid api key: name is required")
+
+	_, err = service.CreateAPIKey(context.Background(), &request.APIKeyRequest{Name: strings.Repeat("x", request.MaxAPIKeyNameLength+1)})
+	assert.EqualError(t, err, "invalid api key: name must be at most 100 characters")
+}
+
+func TestAuthenticate(t *testing.T) {
+	// Arrange
+	service := NewAPIKeyService(memory.NewAPIKeyRepository())
+	created, err := service.CreateAPIKey(context.Background(), &request.APIKeyRequest{Name: "ci"})
+	require.NoError(t, err)
+
+	// Act
+	apiKey, err := service.Authenticate(context.Background(), created.Key)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, created.APIKey.ID, apiKey.ID)
+	require.NotNil(t, apiKey.LastUsedAt)
+	assert.WithinDuration(t, time.Now(), *apiKey.LastUsedAt, time.Minute)
+
+	keys, err := service.ListAPIKeys(context.Background())
+	require.NoError(t, err)
+	assert.NotNil(t, keys[0].LastUsedAt, "the last use is stored")
+}
+
+func TestAuthenticate_InvalidKeys(t *testing.T) {
+	// Arrange
+	service := NewAPIKeyService(memory.NewAPIKeyRepository())
+	revoked, err := service.CreateAPIKey(context.Background(), &request.APIKeyRequest{Name: "old"})
+	require.NoError(t, err)
+	_, err = service.RevokeAPIKey(context.Background(), revoked.APIKey.ID)
+	require.NoError(t, err)
+
+	for name, key := range map[string]string{
+		"Empty":     "",
+		"NoPrefix":  "secret",
+		"Unknown":   APIKeyPrefix + "unknown",
+		"Revoked":   revoked.Key,
+		"Truncated": revoked.Key[:len(revoked.Key)-1],
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			apiKey, err := service.Authenticate(context.Background(), key)
+
+			// Assert
+			assert.Nil(t, apiKey)
+			assert.EqualError(t, err, "invalid api key")
+		})
+	}
+}
+
+func TestAuthenticate_RecordsUseOncePerResolution(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockAPIKeyRepository)
+	recently := time.Now().UTC().Add(-APIKeyUsageResolution / 2)
+	mockRepo.On("FindAPIKeyByHash", mock.Anything, hashAPIKey(APIKeyPrefix+"key")).
+		Return(&models.APIKey{ID: "key1", LastUsedAt: &recently}, nil)
+	service := NewAPIKeyService(mockRepo)
+
+	// Act
+	apiKey, err := service.Authenticate(context.Background(), APIKeyPrefix+"key")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "key1", apiKey.ID)
+	mockRepo.AssertNotCalled(t, "UpdateAPIKeyLastUsed", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthenticate_RepositoryError(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockAPIKeyRepository)
+	mockRepo.On("FindAPIKeyByHash", mock.Anything, mock.Anything).
+		Return(nil, errors.New("failed to retrieve api key: database error"))
+	service := NewAPIKeyService(mockRepo)
+
+	// Act
+	apiKey, err := service.Authenticate(context.Background(), APIKeyPrefix+"key")
+
+	// Assert
+	assert.Nil(t, apiKey)
+	assert.EqualError(t, err, "failed to retrieve api key: database error")
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	// Arrange
+	service := NewAPIKeyService(memory.NewAPIKeyRepository())
+	created, err := service.CreateAPIKey(context.Background(), &request.APIKeyRequest{Name: "ci"})
+	require.NoError(t, err)
+
+	// Act
+	revoked, err := service.RevokeAPIKey(context.Background(), created.APIKey.ID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, revoked.IsRevoked())
+
+	_, err = service.RevokeAPIKey(context.Background(), "000000000000000000000000")
+	assert.EqualError(t, err, "api key not found")
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+)
+
+// auditCSVHeader lists the columns of a CSV export of the audit log
+var auditCSVHeader = []string{
+	"id", "time", "actorMethod", "actorSubject", "actorName", "actorRole", "clientIp", "method", "route",
+	"path", "query", "body", "bodySize", "status", "errorCode", "error", "resourceId", "latencyMs",
+}
+
+// AuditService records mutating requests and lets admins search and export them
+type AuditService struct {
+	auditLogRepository repository.IAuditLogRepository
+}
+
+// NewAuditService creates a new instance of AuditService
+func NewAuditService(auditLogRepository repository.IAuditLogRepository) *AuditService {
+	return &AuditService{
+		auditLogRepository: auditLogRepository,
+	}
+}
+
+// RecordEntry appends an entry to the audit log of the tenant of ctx
+func (s *AuditService) RecordEntry(ctx context.Context, entry *models.AuditEntry) error {
+	if _, err := s.auditLogRepository.InsertAuditEntry(ctx, entry); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ListAuditEntries returns a page of the entries matching req, newest first
+func (s *AuditService) ListAuditEntries(ctx context.Context, req *request.AuditLogRequest) (*response.AuditLogPage, error) {
+	if err := validateAuditRequest(req); err != nil {
+		return nil, err
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = request.DefaultAuditPageLimit
+	}
+
+	// Fetch one more than a page to tell whether there are older entries
+	entries, err := s.auditLogRepository.FindAuditEntries(ctx, req, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &response.AuditLogPage{Entries: entries}
+	if len(entries) > limit {
+		page.Entries = entries[:limit]
+		page.HasMore = true
+		page.NextCursor = page.Entries[limit-1].ID
+	}
+	return page, nil
+}
+
+// ExportAuditEntries writes every entry matching the filters of req to w as CSV, NDJSON or a JSON array,
+// streaming them from the repository. Nothing is written before the first entry has been read.
+func (s *AuditService) ExportAuditEntries(ctx context.Context, req *request.AuditLogRequest, format request.ExportFormat, w io.Writer) error {
+	if format.IsColumnar() {
+		return fmt.Errorf("invalid export: the audit log can only be exported as csv, ndjson or json")
+	}
+	if err := validateAuditRequest(req); err != nil {
+		return err
+	}
+
+	writer := newAuditWriter(format, w)
+	if err := s.auditLogRepository.StreamAuditEntries(ctx, req, writer.Write); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// validateAuditRequest checks the filters of req and normalizes its method
+func validateAuditRequest(req *request.AuditLogRequest) error {
+	if req.Limit < 0 || req.Limit > request.MaxAuditPageLimit {
+		return fmt.Errorf("invalid audit query: limit must be between 1 and %d", request.MaxAuditPageLimit)
+	}
+	if !req.From.IsZero() && !req.To.IsZero() && req.To.Before(req.From) {
+		return fmt.Errorf("invalid audit query: to is before from")
+	}
+	if req.Cursor != "" {
+		// Cursors are entry IDs, which are ObjectIDs
+		if _, err := hex.DecodeString(req.Cursor); err != nil || len(req.Cursor) != 24 {
+			return fmt.Errorf("invalid audit query: invalid cursor")
+		}
+	}
+	req.Method = strings.ToUpper(req.Method)
+	return nil
+}
+
+// auditWriter encodes a stream of audit entries in an export format. In CSV, text from callers is escaped
+// with escapeCSVText, so that a spreadsheet does not run it as a formula.
+type auditWriter struct {
+	format  request.ExportFormat
+	w       io.Writer
+	csv     *csv.Writer
+	encoder *json.Encoder
+	count   int
+}
+
+// newAuditWriter returns the writer of a CSV, NDJSON or JSON export
+func newAuditWriter(format request.ExportFormat, w io.Writer) *auditWriter {
+	return &auditWriter{format: format, w: w, csv: csv.NewWriter(w), encoder: json.NewEncoder(w)}
+}
+
+// Write encodes a single entry
+func (a *auditWriter) Write(entry *models.AuditEntry) error {
+	a.count++
+	switch a.format {
+	case request.ExportFormatNDJSON:
+		return a.encoder.Encode(entry)
+	case request.ExportFormatJSON:
+		separator := ",\n"
+		if a.count == 1 {
+			separator = "[\n"
+		}
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to encode audit entry: %w", err)
+		}
+		if _, err := io.WriteString(a.w, separator); err != nil {
+			return err
+		}
+		_, err = a.w.Write(data)
+		return err
+	}
+
+	if a.count == 1 {
+		if err := a.csv.Write(auditCSVHeader); err != nil {
+			return err
+		}
+	}
+	var actor models.AuditActor
+	if entry.Actor != nil {
+		actor = *entry.Actor
+	}
+	return a.csv.Write([]string{
+		entry.ID,
+		entry.Time.Format(time.RFC3339Nano),
+		string(actor.Method),
+		escapeCSVText(actor.Subject),
+		escapeCSVText(actor.Name),
+		string(actor.Role),
+		entry.ClientIP,
+		entry.Method,
+		entry.Route,
+		escapeCSVText(entry.Path),
+		escapeCSVText(entry.Query),
+		escapeCSVText(entry.Body),
+		strconv.FormatInt(entry.BodySize, 10),
+		strconv.Itoa(entry.Status),
+		entry.ErrorCode,
+		escapeCSVText(entry.Error),
+		escapeCSVText(entry.ResourceID),
+		strconv.FormatInt(entry.LatencyMs, 10),
+	})
+}
+
+// Close finishes the export and flushes buffered output
+func (a *auditWriter) Close() error {
+	switch a.format {
+	case request.ExportFormatNDJSON:
+		return nil
+	case request.ExportFormatJSON:
+		closing := "\n]\n"
+		if a.count == 0 {
+			closing = "[]\n"
+		}
+		_, err := io.WriteString(a.w, closing)
+		return err
+	}
+
+	if a.count == 0 {
+		if err := a.csv.Write(auditCSVHeader); err != nil {
+			return err
+		}
+	}
+	a.csv.Flush()
+	return a.csv.Error()
+}
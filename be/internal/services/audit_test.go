@@ -0,0 +1,132 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository/memory"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAuditServiceWithEntries returns an audit service whose log holds n entries a minute apart, the last one a failure
+func newAuditServiceWithEntries(t *testing.T, n int) *AuditService {
+	service := NewAuditService(memory.NewAuditLogRepository())
+	start := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		entry := &models.AuditEntry{
+			Time:      start.Add(time.Duration(i) * time.Minute),
+			Actor:     &models.AuditActor{Method: models.AuthMethodAPIKey, Subject: "key1", Name: "ops", Role: models.RoleOperator},
+			Method:    "POST",
+			Route:     "/api/reports",
+			Path:      "/api/reports",
+			Body:      `{"timestamp":"2026-10-01T08:00:00Z"}`,
+			Status:    201,
+			LatencyMs: 40,
+		}
+		if i == n-1 {
+			entry.Status = 403
+			entry.ErrorCode = "ERR4004"
+			entry.Error = "quota exceeded"
+		}
+		require.NoError(t, service.RecordEntry(context.Background(), entry))
+	}
+	return service
+}
+
+func TestListAuditEntries_Pagination(t *testing.T) {
+	// Arrange
+	service := newAuditServiceWithEntries(t, 5)
+	ctx := context.Background()
+
+	// Act
+	first, err := service.ListAuditEntries(ctx, &request.AuditLogRequest{Limit: 3})
+	require.NoError(t, err)
+	second, err := service.ListAuditEntries(ctx, &request.AuditLogRequest{Limit: 3, Cursor: first.NextCursor})
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, first.Entries, 3)
+	assert.True(t, first.HasMore)
+	assert.Equal(t, first.Entries[2].ID, first.NextCursor)
+	assert.Equal(t, 403, first.Entries[0].Status, "newest first")
+
+	require.Len(t, second.Entries, 2)
+	assert.False(t, second.HasMore)
+	assert.Empty(t, second.NextCursor)
+	assert.True(t, second.Entries[0].Time.Before(first.Entries[2].Time))
+}
+
+func TestListAuditEntries_Filters(t *testing.T) {
+	// Arrange
+	service := newAuditServiceWithEntries(t, 3)
+
+	// Act
+	page, err := service.ListAuditEntries(context.Background(), &request.AuditLogRequest{Method: "post", Outcome: request.AuditOutcomeFailure})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 1, "methods match regardless of case")
+	assert.Equal(t, "ERR4004", page.Entries[0].ErrorCode)
+}
+
+func TestListAuditEntries_Invalid(t *testing.T) {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	for name, tc := range map[string]struct {
+		req     request.AuditLogRequest
+		wantErr string
+	}{
+		"LimitTooLarge": {req: request.AuditLogRequest{Limit: 1001}, wantErr: "invalid audit query: limit must be between 1 and 1000"},
+		"ToBeforeFrom":  {req: request.AuditLogRequest{From: from, To: from.Add(-time.Hour)}, wantErr: "invalid audit query: to is before from"},
+		"Cursor":        {req: request.AuditLogRequest{Cursor: "not-an-id"}, wantErr: "invalid audit query: invalid cursor"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			service := NewAuditService(memory.NewAuditLogRepository())
+
+			// Act
+			page, err := service.ListAuditEntries(context.Background(), &tc.req)
+
+			// Assert
+			assert.Nil(t, page)
+			assert.EqualError(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestExportAuditEntries(t *testing.T) {
+	// Arrange
+	service := newAuditServiceWithEntries(t, 2)
+	ctx := context.Background()
+
+	// Act
+	var csvOut, ndjsonOut, jsonOut, emptyOut bytes.Buffer
+	require.NoError(t, service.ExportAuditEntries(ctx, &request.AuditLogRequest{}, request.ExportFormatCSV, &csvOut))
+	require.NoError(t, service.ExportAuditEntries(ctx, &request.AuditLogRequest{}, request.ExportFormatNDJSON, &ndjsonOut))
+	require.NoError(t, service.ExportAuditEntries(ctx, &request.AuditLogRequest{}, request.ExportFormatJSON, &jsonOut))
+	require.NoError(t, service.ExportAuditEntries(ctx, &request.AuditLogRequest{Actor: "nobody"}, request.ExportFormatJSON, &emptyOut))
+	err := service.ExportAuditEntries(ctx, &request.AuditLogRequest{}, request.ExportFormatParquet, &bytes.Buffer{})
+
+	// Assert
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, strings.Join(auditCSVHeader, ","), lines[0])
+	assert.Contains(t, lines[1], ",2026-10-01T08:01:00Z,api_key,key1,ops,operator,,POST,/api/reports,/api/reports,,")
+	assert.Contains(t, lines[1], ",403,ERR4004,quota exceeded,,40")
+
+	assert.Len(t, strings.Split(strings.TrimSpace(ndjsonOut.String()), "\n"), 2)
+
+	var entries []models.AuditEntry
+	require.NoError(t, json.Unmarshal(jsonOut.Bytes(), &entries))
+	require.Len(t, entries, 2)
+	assert.Equal(t, "key1", entries[0].Actor.Subject)
+	assert.Equal(t, "[]\n", emptyOut.String())
+
+	assert.EqualError(t, err, "invalid export: the audit log can only be exported as csv, ndjson or json")
+}
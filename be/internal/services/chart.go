@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+)
+
+const (
+	// MaxChartPoints is the most points a chart plots. Longer histories of single reports are aggregated instead.
+	MaxChartPoints = 1000
+
+	// DefaultChartRange is the time range of a chart without from and to
+	DefaultChartRange = 7 * 24 * time.Hour
+
+	// MinChartBucket is the shortest bucket reports can be aggregated in
+	MinChartBucket = time.Minute
+)
+
+// chartBuckets are the bucket lengths chosen when a chart needs aggregation but no bucket is given,
+// in increasing order
+var chartBuckets = []time.Duration{
+	5 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+	time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour,
+	24 * time.Hour, 7 * 24 * time.Hour,
+}
+
+// targetChartBuckets is how many buckets a chosen bucket length aims for at most
+const targetChartBuckets = 120
+
+// errTooManyPoints stops streaming single reports once a chart would have more than MaxChartPoints
+var errTooManyPoints = errors.New("too many points")
+
+// GetMetricSeries returns the history of a metric for a chart. Without a bucket every report is a point,
+// unless there are more than MaxChartPoints or a band is requested; the reports are then aggregated in
+// buckets of a suitable length. Buckets are aligned to multiples of their length in UTC, so the first
+// one may start before from. A missing to defaults to now and a missing from to DefaultChartRange before to.
+func (s *ReportService) GetMetricSeries(ctx context.Context, req *request.MetricChartRequest) (*response.MetricSeries, error) {
+	if !req.Metric.IsValid() {
+		return nil, fmt.Errorf("invalid chart: unknown metric %q", req.Metric)
+	}
+
+	series := &response.MetricSeries{Metric: req.Metric, From: req.From, To: req.To}
+	if series.To.IsZero() {
+		series.To = time.Now().UTC()
+	}
+	if series.From.IsZero() {
+		series.From = series.To.Add(-DefaultChartRange)
+	}
+	if !series.To.After(series.From) {
+		return nil, fmt.Errorf("invalid chart: to must be after from")
+	}
+
+	span := series.To.Sub(series.From)
+	var bucket time.Duration
+	if req.Bucket != 0 {
+		if req.Bucket < MinChartBucket {
+			return nil, fmt.Errorf("invalid chart: bucket must be at least %s", MinChartBucket)
+		}
+		if span/req.Bucket >= MaxChartPoints {
+			return nil, fmt.Errorf("invalid chart: a bucket of %s gives more than %d points, use a longer bucket", req.Bucket, MaxChartPoints)
+		}
+		bucket = req.Bucket
+	} else if req.Band {
+		bucket = chartBucket(span)
+	} else {
+		points, err := s.reportPoints(ctx, req, series.From, series.To)
+		if err == nil {
+			series.Points = points
+			return series, nil
+		}
+		if !errors.Is(err, errTooManyPoints) {
+			return nil, err
+		}
+		bucket = chartBucket(span)
+	}
+	series.Bucket = int64(bucket / time.Second)
+
+	buckets, err := s.reportRepository.AggregateReportBuckets(ctx, series.From.Truncate(bucket), series.To, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	series.Points = make([]response.SeriesPoint, len(buckets))
+	for i, bucket := range buckets {
+		stats := bucket.Metrics[req.Metric]
+		series.Points[i] = response.SeriesPoint{
+			Timestamp: bucket.From.Add(bucket.To.Sub(bucket.From) / 2),
+			Value:     stats.Mean,
+			Min:       stats.Min,
+			Max:       stats.Max,
+			Count:     bucket.Count,
+		}
+	}
+	return series, nil
+}
+
+// reportPoints returns a point for every report between from and to, in time order.
+// It returns errTooManyPoints as soon as there are more than MaxChartPoints.
+func (s *ReportService) reportPoints(ctx context.Context, req *request.MetricChartRequest, from, to time.Time) ([]response.SeriesPoint, error) {
+	filter := &request.PaginatedReportsRequest{
+		FromTime:   from,
+		ToTime:     to,
+		IsFiltered: true,
+		Sort:       []request.SortKey{{Field: "timestamp", Order: request.SortOrderAsc}},
+	}
+
+	points := []response.SeriesPoint{}
+	err := s.reportRepository.StreamReports(ctx, filter, func(report *models.WeatherReport) error {
+		if len(points) == MaxChartPoints {
+			return errTooManyPoints
+		}
+		value := report.Value(req.Metric)
+		points = append(points, response.SeriesPoint{Timestamp: report.Timestamp, Value: value, Min: value, Max: value, Count: 1})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// chartBucket returns the shortest bucket length that splits span into at most targetChartBuckets buckets
+func chartBucket(span time.Duration) time.Duration {
+	for _, bucket := range chartBuckets {
+		if span/bucket <= targetChartBuckets {
+			return bucket
+		}
+	}
+	days := (span/targetChartBuckets + 24*time.Hour - 1) / (24 * time.Hour)
+	return days * 24 * time.Hour
+}
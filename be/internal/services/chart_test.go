@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository/memory"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var chartStart = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+// newChartTestService returns a service whose repository has count reports, one every interval from chartStart,
+// with temperatures 20, 21, 22, ...
+func newChartTestService(t *testing.T, count int, interval time.Duration) *ReportService {
+	reportRepo := memory.NewReportRepository()
+	reports := make([]models.WeatherReport, count)
+	for i := range reports {
+		reports[i] = models.WeatherReport{Timestamp: chartStart.Add(time.Duration(i) * interval), Temperature: float64(20 + i), Pressure: 1010}
+	}
+	_, err := reportRepo.InsertReports(context.Background(), reports)
+	require.NoError(t, err)
+	return NewReportService(reportRepo, nil, nil, nil)
+}
+
+func TestGetMetricSeries_Reports(t *testing.T) {
+	// Arrange
+	service := newChartTestService(t, 5, time.Hour)
+	req := &request.MetricChartRequest{Metric: models.MetricTemperature, From: chartStart.Add(time.Hour), To: chartStart.Add(3 * time.Hour)}
+
+	// Act
+	series, err := service.GetMetricSeries(context.Background(), req)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), series.Bucket)
+	require.Len(t, series.Points, 3)
+	for i, point := range series.Points {
+		assert.True(t, point.Timestamp.Equal(chartStart.Add(time.Duration(i+1)*time.Hour)))
+		assert.Equal(t, float64(21+i), point.Value)
+		assert.Equal(t, point.Value, point.Min)
+		assert.Equal(t, point.Value, point.Max)
+		assert.Equal(t, 1, point.Count)
+	}
+}
+
+func TestGetMetricSeries_Band(t *testing.T) {
+	// Arrange
+	service := newChartTestService(t, 12, time.Hour)
+	req := &request.MetricChartRequest{Metric: models.MetricTemperature, From: chartStart, To: chartStart.Add(DefaultChartRange), Band: true}
+
+	// Act
+	series, err := service.GetMetricSeries(context.Background(), req)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(3*time.Hour/time.Second), series.Bucket, "a week is split into 3 hour buckets")
+	require.Len(t, series.Points, 4)
+	assert.True(t, series.Points[0].Timestamp.Equal(chartStart.Add(90*time.Minute)), "points are at the middle of their bucket")
+	assert.Equal(t, 21.0, series.Points[0].Value)
+	assert.Equal(t, 20.0, series.Points[0].Min)
+	assert.Equal(t, 22.0, series.Points[0].Max)
+	assert.Equal(t, 3, series.Points[0].Count)
+	assert.Equal(t, 30.0, series.Points[3].Value)
+}
+
+func TestGetMetricSeries_BucketsAlignedToUTC(t *testing.T) {
+	// Arrange
+	service := newChartTestService(t, 6, time.Hour)
+	req := &request.MetricChartRequest{Metric: models.MetricTemperature, From: chartStart.Add(30 * time.Minute), To: chartStart.Add(6 * time.Hour), Bucket: 2 * time.Hour}
+
+	// Act
+	series, err := service.GetMetricSeries(context.Background(), req)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(2*time.Hour/time.Second), series.Bucket)
+	require.Len(t, series.Points, 3)
+	assert.True(t, series.Points[0].Timestamp.Equal(chartStart.Add(time.Hour)), "the first bucket starts at midnight")
+	assert.Equal(t, 2, series.Points[0].Count)
+	assert.Equal(t, 24.5, series.Points[2].Value)
+}
+
+func TestGetMetricSeries_TooManyReportsAreAggregated(t *testing.T) {
+	// Arrange
+	service := newChartTestService(t, MaxChartPoints+1, time.Minute)
+	req := &request.MetricChartRequest{Metric: models.MetricPressure, From: chartStart, To: chartStart.Add(MaxChartPoints * time.Minute)}
+
+	// Act
+	series, err := service.GetMetricSeries(context.Background(), req)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(15*time.Minute/time.Second), series.Bucket)
+	total := 0
+	for _, point := range series.Points {
+		total += point.Count
+		assert.Equal(t, 1010.0, point.Value)
+	}
+	assert.Equal(t, MaxChartPoints+1, total)
+}
+
+func TestGetMetricSeries_DefaultRange(t *testing.T) {
+	// Arrange
+	service := newChartTestService(t, 0, time.Hour)
+
+	// Act
+	series, err := service.GetMetricSeries(context.Background(), &request.MetricChartRequest{Metric: models.MetricHumidity})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, DefaultChartRange, series.To.Sub(series.From))
+	assert.WithinDuration(t, time.Now(), series.To, time.Minute)
+	assert.Empty(t, series.Points)
+}
+
+func TestGetMetricSeries_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     request.MetricChartRequest
+		wantErr string
+	}{
+		{"Unknown metric", request.MetricChartRequest{Metric: "visibility"}, `invalid chart: unknown metric "visibility"`},
+		{"Empty range", request.MetricChartRequest{Metric: models.MetricTemperature, From: chartStart, To: chartStart}, "invalid chart: to must be after from"},
+		{"Short bucket", request.MetricChartRequest{Metric: models.MetricTemperature, Bucket: time.Second}, "invalid chart: bucket must be at least 1m0s"},
+		{"Too many buckets", request.MetricChartRequest{Metric: models.MetricTemperature, Bucket: 5 * time.Minute}, "invalid chart: a bucket of 5m0s gives more than 1000 points, use a longer bucket"},
+	}
+
+	service := newChartTestService(t, 0, time.Hour)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.GetMetricSeries(context.Background(), &tt.req)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestGetMetricSeries_RepositoryError(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	mockReportRepo.On("AggregateReportBuckets", mock.Anything, mock.Anything, mock.Anything, time.Hour).
+		Return(nil, errors.New("failed to aggregate reports: database error"))
+	service := NewReportService(mockReportRepo, nil, nil, nil)
+
+	// Act
+	series, err := service.GetMetricSeries(context.Background(), &request.MetricChartRequest{Metric: models.MetricTemperature, Bucket: time.Hour})
+
+	// Assert
+	assert.Nil(t, series)
+	assert.EqualError(t, err, "failed to aggregate reports: database error")
+	mockReportRepo.AssertExpectations(t)
+}
+
+func TestChartBucket(t *testing.T) {
+	tests := []struct {
+		span time.Duration
+		want time.Duration
+	}{
+		{time.Hour, 5 * time.Minute},
+		{24 * time.Hour, 15 * time.Minute},
+		{DefaultChartRange, 3 * time.Hour},
+		{90 * 24 * time.Hour, 24 * time.Hour},
+		{365 * 24 * time.Hour, 7 * 24 * time.Hour},
+		{5 * 365 * 24 * time.Hour, 16 * 24 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.span.String(), func(t *testing.T) {
+			assert.Equal(t, tt.want, chartBucket(tt.span))
+		})
+	}
+}
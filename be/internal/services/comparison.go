@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/DangVTNhan/Scanner/be/internal/interfaces"
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+)
+
+// Limits of saved comparison fields, in characters
+const (
+	MaxComparisonTitleLength = 200
+	MaxComparisonNotesLength = 2000
+)
+
+// ComparisonService handles business logic for saved comparisons
+type ComparisonService struct {
+	comparisonRepository repository.IComparisonRepository
+	thresholdService     interfaces.IThresholdService
+}
+
+// NewComparisonService creates a new instance of ComparisonService
+func NewComparisonService(comparisonRepository repository.IComparisonRepository, thresholdService interfaces.IThresholdService) *ComparisonService {
+	return &ComparisonService{
+		comparisonRepository: comparisonRepository,
+		thresholdService:     thresholdService,
+	}
+}
+
+// SaveComparison saves the result of a comparison with a title and notes.
+// The compared reports and the thresholds in effect are stored as snapshots.
+func (s *ComparisonService) SaveComparison(ctx context.Context, title, notes string, result *response.ComparisonResult) (*response.SavedComparison, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, fmt.Errorf("invalid comparison: title is required when saving")
+	}
+	if utf8.RuneCountInString(title) > MaxComparisonTitleLength {
+		return nil, fmt.Errorf("invalid comparison: title is longer than %d characters", MaxComparisonTitleLength)
+	}
+	if utf8.RuneCountInString(notes) > MaxComparisonNotesLength {
+		return nil, fmt.Errorf("invalid comparison: notes are longer than %d characters", MaxComparisonNotesLength)
+	}
+
+	thresholds, err := s.thresholdService.GetEffectiveThresholds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load thresholds: %w", err)
+	}
+
+	comparison := &models.Comparison{
+		Title:      title,
+		Notes:      notes,
+		Report1:    result.Report1,
+		Report2:    result.Report2,
+		CreatedAt:  time.Now(),
+		Thresholds: thresholds,
+	}
+
+	id, err := s.comparisonRepository.InsertComparison(ctx, comparison)
+	if err != nil {
+		return nil, err
+	}
+	comparison.ID = id
+
+	return toSavedComparison(comparison), nil
+}
+
+// GetComparison retrieves a saved comparison by ID
+func (s *ComparisonService) GetComparison(ctx context.Context, id string) (*response.SavedComparison, error) {
+	comparison, err := s.comparisonRepository.FindComparisonByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toSavedComparison(comparison), nil
+}
+
+// ListComparisons retrieves a page of saved comparisons, newest first
+func (s *ComparisonService) ListComparisons(ctx context.Context, limit, offset int) (*response.SavedComparisonsResponse, error) {
+	if limit <= 0 {
+		limit = repository.DefaultPageLimit
+	}
+
+	comparisons, err := s.comparisonRepository.FindComparisons(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	totalCount, err := s.comparisonRepository.CountComparisons(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &response.SavedComparisonsResponse{
+		Comparisons: make([]response.SavedComparison, len(comparisons)),
+		TotalCount:  int(totalCount),
+	}
+	for i := range comparisons {
+		result.Comparisons[i] = *toSavedComparison(&comparisons[i])
+	}
+
+	return result, nil
+}
+
+// DeleteComparison deletes a saved comparison by ID
+func (s *ComparisonService) DeleteComparison(ctx context.Context, id string) error {
+	return s.comparisonRepository.DeleteComparison(ctx, id)
+}
+
+// toSavedComparison converts a stored comparison to its response, recalculating the deviation from the snapshots
+// of the reports and thresholds
+func toSavedComparison(comparison *models.Comparison) *response.SavedComparison {
+	return &response.SavedComparison{
+		ID:         comparison.ID,
+		Title:      comparison.Title,
+		Notes:      comparison.Notes,
+		CreatedAt:  comparison.CreatedAt,
+		Report1:    comparison.Report1,
+		Report2:    comparison.Report2,
+		Deviation:  calculateDeviation(&comparison.Report1, &comparison.Report2, comparison.Thresholds),
+		Thresholds: comparison.Thresholds,
+	}
+}
+
+// calculateDeviation calculates the deviation of report from base and classifies it by thresholds
+func calculateDeviation(base, report *models.WeatherReport, thresholds map[models.Metric]models.Threshold) response.Deviation {
+	hours := report.Timestamp.Sub(base.Timestamp).Hours()
+
+	verdict := models.SeverityNormal
+	metrics := make(map[models.Metric]response.MetricDeviation, len(models.Metrics))
+	for _, metric := range models.Metrics {
+		deviation := metricDeviation(base.Value(metric), report.Value(metric), hours)
+		deviation.Severity = thresholds[metric].Classify(deviation.Delta)
+		if deviation.Severity.Rank() > verdict.Rank() {
+			verdict = deviation.Severity
+		}
+		metrics[metric] = deviation
+	}
+
+	return response.Deviation{
+		Temperature:  math.Abs(report.Temperature - base.Temperature),
+		Pressure:     math.Abs(report.Pressure - base.Pressure),
+		Humidity:     math.Abs(report.Humidity - base.Humidity),
+		CloudCover:   math.Abs(report.CloudCover - base.CloudCover),
+		HoursElapsed: hours,
+		Metrics:      metrics,
+		Verdict:      verdict,
+	}
+}
+
+// metricDeviation calculates the signed change from one value to another over the given hours
+func metricDeviation(from, to, hours float64) response.MetricDeviation {
+	deviation := response.MetricDeviation{
+		Delta:     to - from,
+		Direction: response.DirectionUnchanged,
+	}
+
+	switch {
+	case deviation.Delta > 0:
+		deviation.Direction = response.DirectionIncrease
+	case deviation.Delta < 0:
+		deviation.Direction = response.DirectionDecrease
+	}
+
+	if from != 0 {
+		percent := deviation.Delta / math.Abs(from) * 100
+		deviation.PercentChange = &percent
+	}
+	if hours != 0 {
+		rate := deviation.Delta / hours
+		deviation.RatePerHour = &rate
+	}
+
+	return deviation
+}
+
+// significanceThreshold is the |t| from which a difference between periods is flagged as significant.
+// For all but very small periods it corresponds to a two-sided p-value of about 0.05.
+const significanceThreshold = 2.0
+
+// periodMeans returns a report holding the mean of every metric of a period, timestamped at the period's midpoint
+func periodMeans(stats *response.PeriodStatistics) models.WeatherReport {
+	return models.WeatherReport{
+		Timestamp:   stats.From.Add(stats.To.Sub(stats.From) / 2),
+		Temperature: stats.Metrics[models.MetricTemperature].Mean,
+		Pressure:    stats.Metrics[models.MetricPressure].Mean,
+		Humidity:    stats.Metrics[models.MetricHumidity].Mean,
+		CloudCover:  stats.Metrics[models.MetricCloudCover].Mean,
+	}
+}
+
+// comparePeriodMetric compares the statistics of a metric over two periods with n1 and n2 reports
+func comparePeriodMetric(n1 int, stats1 response.MetricStatistics, n2 int, stats2 response.MetricStatistics) response.PeriodMetricComparison {
+	comparison := response.PeriodMetricComparison{
+		MeanDelta: stats2.Mean - stats1.Mean,
+		MinDelta:  stats2.Min - stats1.Min,
+		MaxDelta:  stats2.Max - stats1.Max,
+	}
+	comparison.TStatistic, comparison.Significance = welchT(n1, stats1, n2, stats2)
+	return comparison
+}
+
+// welchT calculates Welch's t statistic for the difference of the means of two samples and the resulting hint.
+// The statistics carry population standard deviations, which are converted to sample variances.
+func welchT(n1 int, stats1 response.MetricStatistics, n2 int, stats2 response.MetricStatistics) (*float64, response.Significance) {
+	if n1 < 2 || n2 < 2 {
+		return nil, response.SignificanceInsufficientData
+	}
+
+	variance1 := stats1.StdDev * stats1.StdDev * float64(n1) / float64(n1-1)
+	variance2 := stats2.StdDev * stats2.StdDev * float64(n2) / float64(n2-1)
+	standardError := math.Sqrt(variance1/float64(n1) + variance2/float64(n2))
+	delta := stats2.Mean - stats1.Mean
+
+	// Without any variation every difference is real, and no difference is none
+	if standardError == 0 {
+		if delta == 0 {
+			return nil, response.SignificanceNotSignificant
+		}
+		return nil, response.SignificanceSignificant
+	}
+
+	t := delta / standardError
+	if math.Abs(t) >= significanceThreshold {
+		return &t, response.SignificanceSignificant
+	}
+	return &t, response.SignificanceNotSignificant
+}
+
+// calculateStatistics calculates the spread of every metric across reports, which must not be empty
+func calculateStatistics(reports []models.WeatherReport) map[models.Metric]response.MetricStatistics {
+	statistics := make(map[models.Metric]response.MetricStatistics, len(models.Metrics))
+	for _, metric := range models.Metrics {
+		values := make([]float64, len(reports))
+		for i := range reports {
+			values[i] = reports[i].Value(metric)
+		}
+		statistics[metric] = metricStatistics(values)
+	}
+	return statistics
+}
+
+// metricStatistics calculates min, max, mean, population standard deviation and range of values
+func metricStatistics(values []float64) response.MetricStatistics {
+	stats := response.MetricStatistics{Min: values[0], Max: values[0]}
+
+	var sum float64
+	for _, value := range values {
+		stats.Min = math.Min(stats.Min, value)
+		stats.Max = math.Max(stats.Max, value)
+		sum += value
+	}
+	stats.Mean = sum / float64(len(values))
+
+	var squares float64
+	for _, value := range values {
+		squares += (value - stats.Mean) * (value - stats.Mean)
+	}
+	stats.StdDev = math.Sqrt(squares / float64(len(values)))
+	stats.Range = stats.Max - stats.Min
+
+	return stats
+}
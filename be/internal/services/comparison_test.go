@@ -0,0 +1,361 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockComparisonRepository is a mock implementation of IComparisonRepository
+type MockComparisonRepository struct {
+	mock.Mock
+}
+
+func (m *MockComparisonRepository) InsertComparison(ctx context.Context, comparison *models.Comparison) (string, error) {
+	args := m.Called(ctx, comparison)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockComparisonRepository) FindComparisonByID(ctx context.Context, id string) (*models.Comparison, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Comparison), args.Error(1)
+}
+
+func (m *MockComparisonRepository) FindComparisons(ctx context.Context, limit, offset int) ([]models.Comparison, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Comparison), args.Error(1)
+}
+
+func (m *MockComparisonRepository) CountComparisons(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockComparisonRepository) DeleteComparison(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockThresholdService is a mock implementation of IThresholdService
+type MockThresholdService struct {
+	mock.Mock
+}
+
+func (m *MockThresholdService) GetThresholds(ctx context.Context) ([]models.Threshold, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Threshold), args.Error(1)
+}
+
+func (m *MockThresholdService) GetEffectiveThresholds(ctx context.Context) (map[models.Metric]models.Threshold, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[models.Metric]models.Threshold), args.Error(1)
+}
+
+func (m *MockThresholdService) SetThreshold(ctx context.Context, metric models.Metric, req *request.ThresholdRequest) (*models.Threshold, error) {
+	args := m.Called(ctx, metric, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Threshold), args.Error(1)
+}
+
+func (m *MockThresholdService) ResetThreshold(ctx context.Context, metric models.Metric) (*models.Threshold, error) {
+	args := m.Called(ctx, metric)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Threshold), args.Error(1)
+}
+
+// defaultThresholds returns the built-in thresholds keyed by metric
+func defaultThresholds() map[models.Metric]models.Threshold {
+	thresholds := make(map[models.Metric]models.Threshold, len(models.DefaultThresholds))
+	for metric, threshold := range models.DefaultThresholds {
+		thresholds[metric] = threshold
+	}
+	return thresholds
+}
+
+func TestCalculateDeviation_Signed(t *testing.T) {
+	// Arrange
+	base := &models.WeatherReport{
+		Timestamp:   time.Date(2023, 1, 1, 18, 0, 0, 0, time.UTC),
+		Temperature: 30.0,
+		Pressure:    1010.0,
+		Humidity:    0.0,
+		CloudCover:  40.0,
+	}
+	report := &models.WeatherReport{
+		Timestamp:   time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+		Temperature: 27.0,
+		Pressure:    1010.0,
+		Humidity:    50.0,
+		CloudCover:  40.0,
+	}
+
+	// Act
+	deviation := calculateDeviation(base, report, defaultThresholds())
+
+	// Assert
+	assert.Equal(t, 3.0, deviation.Temperature)
+	assert.Equal(t, -6.0, deviation.HoursElapsed)
+
+	temperature := deviation.Metrics[models.MetricTemperature]
+	assert.Equal(t, -3.0, temperature.Delta)
+	assert.Equal(t, response.DirectionDecrease, temperature.Direction)
+	assert.Equal(t, -10.0, *temperature.PercentChange)
+	assert.Equal(t, 0.5, *temperature.RatePerHour)
+
+	pressure := deviation.Metrics[models.MetricPressure]
+	assert.Equal(t, 0.0, pressure.Delta)
+	assert.Equal(t, response.DirectionUnchanged, pressure.Direction)
+
+	// A change from zero has no meaningful percentage
+	humidity := deviation.Metrics[models.MetricHumidity]
+	assert.Equal(t, response.DirectionIncrease, humidity.Direction)
+	assert.Nil(t, humidity.PercentChange)
+}
+
+func TestCalculateDeviation_SameTimestamp(t *testing.T) {
+	// Arrange
+	timestamp := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	base := &models.WeatherReport{Timestamp: timestamp, Temperature: 25.0}
+	report := &models.WeatherReport{Timestamp: timestamp, Temperature: 26.0}
+
+	// Act
+	deviation := calculateDeviation(base, report, defaultThresholds())
+
+	// Assert
+	assert.Equal(t, 0.0, deviation.HoursElapsed)
+	assert.Nil(t, deviation.Metrics[models.MetricTemperature].RatePerHour)
+	assert.Equal(t, 4.0, *deviation.Metrics[models.MetricTemperature].PercentChange)
+}
+
+func TestWelchT(t *testing.T) {
+	// Arrange
+	stats1 := response.MetricStatistics{Mean: 27.0, StdDev: 1.0}
+	stats2 := response.MetricStatistics{Mean: 27.5, StdDev: 1.0}
+
+	// Act
+	tStatistic, significance := welchT(10, stats1, 10, stats2)
+
+	// Assert: sample variances are 10/9, so t = 0.5 / sqrt(2/9)
+	assert.InDelta(t, 1.0607, *tStatistic, 1e-4)
+	assert.Equal(t, response.SignificanceNotSignificant, significance)
+
+	tStatistic, significance = welchT(40, stats1, 40, stats2)
+	assert.InDelta(t, 2.2079, *tStatistic, 1e-4)
+	assert.Equal(t, response.SignificanceSignificant, significance)
+}
+
+func TestWelchT_EdgeCases(t *testing.T) {
+	constant := response.MetricStatistics{Mean: 1010.0}
+	shifted := response.MetricStatistics{Mean: 1012.0}
+
+	tStatistic, significance := welchT(1, constant, 10, shifted)
+	assert.Nil(t, tStatistic)
+	assert.Equal(t, response.SignificanceInsufficientData, significance)
+
+	tStatistic, significance = welchT(5, constant, 5, shifted)
+	assert.Nil(t, tStatistic)
+	assert.Equal(t, response.SignificanceSignificant, significance)
+
+	tStatistic, significance = welchT(5, constant, 5, constant)
+	assert.Nil(t, tStatistic)
+	assert.Equal(t, response.SignificanceNotSignificant, significance)
+}
+
+func TestSaveComparison(t *testing.T) {
+	// Arrange
+	mockComparisonRepo := new(MockComparisonRepository)
+	service := NewComparisonService(mockComparisonRepo, newTestThresholdService())
+
+	ctx := context.Background()
+	result := &response.ComparisonResult{
+		Report1: models.WeatherReport{ID: "report1", Temperature: 25.0},
+		Report2: models.WeatherReport{ID: "report2", Temperature: 27.0},
+	}
+
+	mockComparisonRepo.On("InsertComparison", ctx, mock.MatchedBy(func(c *models.Comparison) bool {
+		return c.Title == "Before and after the storm" && c.Report1.ID == "report1" && c.Report2.ID == "report2"
+	})).Return("comparison1", nil)
+
+	// Act
+	saved, err := service.SaveComparison(ctx, "  Before and after the storm ", "Notes", result)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "comparison1", saved.ID)
+	assert.Equal(t, "Before and after the storm", saved.Title)
+	assert.Equal(t, "Notes", saved.Notes)
+	assert.Equal(t, 2.0, saved.Deviation.Temperature)
+	assert.False(t, saved.CreatedAt.IsZero())
+	mockComparisonRepo.AssertExpectations(t)
+}
+
+func TestSaveComparison_ThresholdsUnavailable(t *testing.T) {
+	// Arrange
+	mockComparisonRepo := new(MockComparisonRepository)
+	mockThresholdService := new(MockThresholdService)
+	service := NewComparisonService(mockComparisonRepo, mockThresholdService)
+
+	ctx := context.Background()
+	mockThresholdService.On("GetEffectiveThresholds", ctx).Return(nil, errors.New("database unavailable"))
+
+	// Act
+	saved, err := service.SaveComparison(ctx, "Title", "", &response.ComparisonResult{})
+
+	// Assert
+	assert.Nil(t, saved)
+	assert.EqualError(t, err, "failed to load thresholds: database unavailable")
+	mockThresholdService.AssertExpectations(t)
+	mockComparisonRepo.AssertNotCalled(t, "InsertComparison", mock.Anything, mock.Anything)
+}
+
+func TestSaveComparison_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		notes string
+	}{
+		{"missing title", " ", ""},
+		{"title too long", strings.Repeat("a", MaxComparisonTitleLength+1), ""},
+		{"notes too long", "Title", strings.Repeat("a", MaxComparisonNotesLength+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockComparisonRepo := new(MockComparisonRepository)
+			service := NewComparisonService(mockComparisonRepo, newTestThresholdService())
+
+			// Act
+			saved, err := service.SaveComparison(context.Background(), tt.title, tt.notes, &response.ComparisonResult{})
+
+			// Assert
+			assert.Error(t, err)
+			assert.Nil(t, saved)
+			assert.Contains(t, err.Error(), "invalid comparison")
+			mockComparisonRepo.AssertNotCalled(t, "InsertComparison", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetComparison_UsesSnapshots(t *testing.T) {
+	// Arrange
+	mockComparisonRepo := new(MockComparisonRepository)
+	service := NewComparisonService(mockComparisonRepo, newTestThresholdService())
+
+	ctx := context.Background()
+	comparison := &models.Comparison{
+		ID:         "comparison1",
+		Title:      "Title",
+		Report1:    models.WeatherReport{ID: "report1", Humidity: 60.0},
+		Report2:    models.WeatherReport{ID: "report2", Humidity: 75.0},
+		Thresholds: defaultThresholds(),
+	}
+	mockComparisonRepo.On("FindComparisonByID", ctx, "comparison1").Return(comparison, nil)
+
+	// Act
+	saved, err := service.GetComparison(ctx, "comparison1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, comparison.Report1, saved.Report1)
+	assert.Equal(t, 15.0, saved.Deviation.Humidity)
+	assert.Equal(t, models.SeverityNotable, saved.Deviation.Verdict)
+	mockComparisonRepo.AssertExpectations(t)
+}
+
+func TestGetComparison_KeepsThresholdsOfSave(t *testing.T) {
+	// Arrange
+	mockComparisonRepo := new(MockComparisonRepository)
+	thresholdService := newTestThresholdService()
+	service := NewComparisonService(mockComparisonRepo, thresholdService)
+
+	ctx := context.Background()
+	result := &response.ComparisonResult{
+		Report1: models.WeatherReport{ID: "report1", Temperature: 25.0},
+		Report2: models.WeatherReport{ID: "report2", Temperature: 28.0},
+	}
+	var stored *models.Comparison
+	mockComparisonRepo.On("InsertComparison", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*models.Comparison)
+	}).Return("comparison1", nil)
+
+	_, err := service.SaveComparison(ctx, "Title", "", result)
+	assert.NoError(t, err)
+	mockComparisonRepo.On("FindComparisonByID", ctx, "comparison1").Return(stored, nil)
+	_, err = thresholdService.SetThreshold(ctx, models.MetricTemperature, &request.ThresholdRequest{Notable: 4, Major: 10})
+	assert.NoError(t, err)
+
+	// Act
+	saved, err := service.GetComparison(ctx, "comparison1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, models.SeverityNotable, saved.Deviation.Metrics[models.MetricTemperature].Severity)
+	assert.Equal(t, models.SeverityNotable, saved.Deviation.Verdict)
+	assert.Equal(t, 2.0, saved.Thresholds[models.MetricTemperature].Notable)
+	mockComparisonRepo.AssertExpectations(t)
+}
+
+func TestListComparisons(t *testing.T) {
+	// Arrange
+	mockComparisonRepo := new(MockComparisonRepository)
+	service := NewComparisonService(mockComparisonRepo, newTestThresholdService())
+
+	ctx := context.Background()
+	comparisons := []models.Comparison{
+		{ID: "comparison2", Thresholds: defaultThresholds()},
+		{ID: "comparison1", Thresholds: defaultThresholds()},
+	}
+	mockComparisonRepo.On("FindComparisons", ctx, 10, 0).Return(comparisons, nil)
+	mockComparisonRepo.On("CountComparisons", ctx).Return(int64(2), nil)
+
+	// Act
+	result, err := service.ListComparisons(ctx, 0, 0)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.TotalCount)
+	assert.Len(t, result.Comparisons, 2)
+	assert.Equal(t, "comparison2", result.Comparisons[0].ID)
+	mockComparisonRepo.AssertExpectations(t)
+}
+
+func TestDeleteComparison_NotFound(t *testing.T) {
+	// Arrange
+	mockComparisonRepo := new(MockComparisonRepository)
+	service := NewComparisonService(mockComparisonRepo, newTestThresholdService())
+
+	ctx := context.Background()
+	mockComparisonRepo.On("DeleteComparison", ctx, "missing").Return(errors.New("comparison not found"))
+
+	// Act
+	err := service.DeleteComparison(ctx, "missing")
+
+	// Assert
+	assert.EqualError(t, err, "comparison not found")
+	mockComparisonRepo.AssertExpectations(t)
+}
@@ -0,0 +1,335 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/pkg/columnar"
+)
+
+// csvHeader lists the columns of a CSV export
+var csvHeader = []string{
+	"id", "timestamp", "temperature", "pressure", "humidity", "cloudCover",
+	"location", "source", "type", "tags", "notes", "createdAt", "deletedAt",
+}
+
+// ExportReports writes every report matching the filters and sort of req to w, streaming them from
+// the repository one at a time. Nothing is written before the first report has been read, so callers
+// can still report an error if the query itself fails.
+func (s *ReportService) ExportReports(ctx context.Context, req *request.PaginatedReportsRequest, format request.ExportFormat, w io.Writer) error {
+	writer, err := newReportWriter(format, w, reportSchema(req, time.Now()))
+	if err != nil {
+		return err
+	}
+	if err := s.reportRepository.StreamReports(ctx, req, writer.Write); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// ExportWeatherCaches writes the raw cache entries with a timestamp between from and to (zero means
+// unbounded) to w as a Parquet or Arrow file, streaming them from the repository
+func (s *ReportService) ExportWeatherCaches(ctx context.Context, from, to time.Time, format request.ExportFormat, w io.Writer) error {
+	if !format.IsColumnar() {
+		return fmt.Errorf("invalid export: weather cache entries can only be exported as parquet or arrow")
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return fmt.Errorf("invalid export: toTime is before fromTime")
+	}
+
+	writer, err := newColumnarWriter(format, w, weatherCacheSchema(from, to, time.Now()))
+	if err != nil {
+		return err
+	}
+
+	err = s.weatherCacheRepo.StreamWeatherCaches(ctx, from, to, func(cache *models.WeatherCache) error {
+		return writer.WriteRow(
+			cache.ID,
+			cache.Timestamp,
+			cache.WeatherData.Temperature,
+			cache.WeatherData.Pressure,
+			cache.WeatherData.Humidity,
+			cache.WeatherData.CloudCover,
+			models.LocationChangi, // The weather service only fetches data for Changi
+			models.SourceOpenWeather,
+			cache.CreatedAt,
+		)
+	})
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// reportWriter encodes a stream of reports in an export format
+type reportWriter interface {
+	// Write encodes a single report
+	Write(report *models.WeatherReport) error
+
+	// Close finishes the export and flushes buffered output
+	Close() error
+}
+
+// newReportWriter returns the writer of the given format. The schema is only used by columnar formats.
+func newReportWriter(format request.ExportFormat, w io.Writer, schema columnar.Schema) (reportWriter, error) {
+	switch format {
+	case request.ExportFormatNDJSON:
+		return &ndjsonReportWriter{encoder: json.NewEncoder(w)}, nil
+	case request.ExportFormatJSON:
+		return &jsonReportWriter{w: w}, nil
+	case request.ExportFormatParquet, request.ExportFormatArrow:
+		writer, err := newColumnarWriter(format, w, schema)
+		if err != nil {
+			return nil, err
+		}
+		return &columnarReportWriter{writer: writer}, nil
+	}
+	return &csvReportWriter{writer: csv.NewWriter(w)}, nil
+}
+
+// newColumnarWriter returns a Parquet or Arrow writer with the default row group size
+func newColumnarWriter(format request.ExportFormat, w io.Writer, schema columnar.Schema) (columnar.Writer, error) {
+	if format == request.ExportFormatArrow {
+		return columnar.NewArrowWriter(w, schema, columnar.DefaultBatchSize)
+	}
+	return columnar.NewParquetWriter(w, schema, columnar.DefaultBatchSize)
+}
+
+// csvFormulaPrefixes are the first characters that make a spreadsheet read a cell as a formula
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// isCSVFormula returns true if a spreadsheet would read s as a formula, ignoring leading apostrophes
+func isCSVFormula(s string) bool {
+	s = strings.TrimLeft(s, "'")
+	return s != "" && strings.IndexByte(csvFormulaPrefixes, s[0]) >= 0
+}
+
+// escapeCSVText prefixes user text that a spreadsheet would run as a formula, e.g. "=HYPERLINK(...)",
+// with an apostrophe, which makes spreadsheets show it as text. Text that already starts with apostrophes
+// before such a character gets one more, so that unescapeCSVText restores every value.
+func escapeCSVText(s string) string {
+	if isCSVFormula(s) {
+		return "'" + s
+	}
+	return s
+}
+
+// unescapeCSVText removes the apostrophe added by escapeCSVText
+func unescapeCSVText(s string) string {
+	if strings.HasPrefix(s, "'") && isCSVFormula(s) {
+		return s[1:]
+	}
+	return s
+}
+
+// csvReportWriter writes reports as CSV rows. Tags are joined with semicolons, and tags and notes that
+// a spreadsheet would run as a formula are escaped with escapeCSVText.
+type csvReportWriter struct {
+	writer  *csv.Writer
+	started bool
+}
+
+func (c *csvReportWriter) begin() error {
+	if c.started {
+		return nil
+	}
+	c.started = true
+	return c.writer.Write(csvHeader)
+}
+
+func (c *csvReportWriter) Write(report *models.WeatherReport) error {
+	if err := c.begin(); err != nil {
+		return err
+	}
+
+	deletedAt := ""
+	if report.DeletedAt != nil {
+		deletedAt = report.DeletedAt.Format(time.RFC3339)
+	}
+	return c.writer.Write([]string{
+		report.ID,
+		report.Timestamp.Format(time.RFC3339),
+		formatFloat(report.Temperature),
+		formatFloat(report.Pressure),
+		formatFloat(report.Humidity),
+		formatFloat(report.CloudCover),
+		report.Location,
+		report.Source,
+		report.Type,
+		escapeCSVText(strings.Join(report.Tags, ";")),
+		escapeCSVText(report.Notes),
+		report.CreatedAt.Format(time.RFC3339),
+		deletedAt,
+	})
+}
+
+func (c *csvReportWriter) Close() error {
+	if err := c.begin(); err != nil {
+		return err
+	}
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+// ndjsonReportWriter writes one JSON object per line
+type ndjsonReportWriter struct {
+	encoder *json.Encoder
+}
+
+func (n *ndjsonReportWriter) Write(report *models.WeatherReport) error {
+	return n.encoder.Encode(report)
+}
+
+func (n *ndjsonReportWriter) Close() error {
+	return nil
+}
+
+// jsonReportWriter writes a JSON array one element at a time
+type jsonReportWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (j *jsonReportWriter) Write(report *models.WeatherReport) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+
+	separator := ",\n"
+	if j.count == 0 {
+		separator = "[\n"
+	}
+	j.count++
+
+	if _, err := io.WriteString(j.w, separator); err != nil {
+		return err
+	}
+	_, err = j.w.Write(data)
+	return err
+}
+
+func (j *jsonReportWriter) Close() error {
+	closing := "\n]\n"
+	if j.count == 0 {
+		closing = "[]\n"
+	}
+	_, err := io.WriteString(j.w, closing)
+	return err
+}
+
+// columnarReportWriter writes reports as rows of a Parquet or Arrow file, see reportSchema
+type columnarReportWriter struct {
+	writer columnar.Writer
+}
+
+func (c *columnarReportWriter) Write(report *models.WeatherReport) error {
+	var tags, notes, deletedAt any
+	if len(report.Tags) > 0 {
+		tags = strings.Join(report.Tags, ";")
+	}
+	if report.Notes != "" {
+		notes = report.Notes
+	}
+	if report.DeletedAt != nil {
+		deletedAt = *report.DeletedAt
+	}
+
+	return c.writer.WriteRow(
+		report.ID,
+		report.Timestamp,
+		report.Temperature,
+		report.Pressure,
+		report.Humidity,
+		report.CloudCover,
+		report.Location,
+		report.Source,
+		report.Type,
+		tags,
+		notes,
+		report.CreatedAt,
+		deletedAt,
+	)
+}
+
+func (c *columnarReportWriter) Close() error {
+	return c.writer.Close()
+}
+
+// reportSchema returns the columnar schema of a report export. The file metadata records what was
+// exported and when, so files loaded into other systems can be traced back to their query.
+func reportSchema(req *request.PaginatedReportsRequest, exportedAt time.Time) columnar.Schema {
+	fields := []columnar.Field{
+		{Name: "id", Type: columnar.TypeString},
+		{Name: "timestamp", Type: columnar.TypeTimestamp, Metadata: map[string]string{"description": "Time the weather data applies to"}},
+	}
+	fields = append(fields, metricFields()...)
+	fields = append(fields,
+		columnar.Field{Name: "location", Type: columnar.TypeString, Metadata: map[string]string{"description": "ICAO code of the airport"}},
+		columnar.Field{Name: "source", Type: columnar.TypeString, Metadata: map[string]string{"description": "Provider of the weather data"}},
+		columnar.Field{Name: "type", Type: columnar.TypeString, Metadata: map[string]string{"description": "current or historical"}},
+		columnar.Field{Name: "tags", Type: columnar.TypeString, Nullable: true, Metadata: map[string]string{"delimiter": ";"}},
+		columnar.Field{Name: "notes", Type: columnar.TypeString, Nullable: true},
+		columnar.Field{Name: "createdAt", Type: columnar.TypeTimestamp, Metadata: map[string]string{"description": "Time the report was generated"}},
+		columnar.Field{Name: "deletedAt", Type: columnar.TypeTimestamp, Nullable: true, Metadata: map[string]string{"description": "Time the report was soft-deleted"}},
+	)
+
+	metadata := exportMetadata("reports", req.FromTime, req.ToTime, exportedAt)
+	if keys, err := req.SortKeys(); err == nil {
+		metadata["sort"] = request.FormatSortSpec(keys)
+	}
+	return columnar.Schema{Fields: fields, Metadata: metadata}
+}
+
+// weatherCacheSchema returns the columnar schema of a weather cache export
+func weatherCacheSchema(from, to, exportedAt time.Time) columnar.Schema {
+	fields := []columnar.Field{
+		{Name: "id", Type: columnar.TypeString},
+		{Name: "timestamp", Type: columnar.TypeTimestamp, Metadata: map[string]string{"description": "Time the weather data applies to"}},
+	}
+	fields = append(fields, metricFields()...)
+	fields = append(fields,
+		columnar.Field{Name: "location", Type: columnar.TypeString, Metadata: map[string]string{"description": "ICAO code of the airport"}},
+		columnar.Field{Name: "source", Type: columnar.TypeString, Metadata: map[string]string{"description": "Provider of the weather data"}},
+		columnar.Field{Name: "fetchedAt", Type: columnar.TypeTimestamp, Metadata: map[string]string{"description": "Time the data was fetched from the provider"}},
+	)
+
+	return columnar.Schema{Fields: fields, Metadata: exportMetadata("weather_cache", from, to, exportedAt)}
+}
+
+// metricFields returns a field per metric in the order of models.Metrics, with its unit
+func metricFields() []columnar.Field {
+	fields := make([]columnar.Field, len(models.Metrics))
+	for i, metric := range models.Metrics {
+		fields[i] = columnar.Field{Name: string(metric), Type: columnar.TypeFloat64, Metadata: map[string]string{"unit": metric.Unit()}}
+	}
+	return fields
+}
+
+// exportMetadata returns the provenance metadata of a columnar export
+func exportMetadata(dataset string, from, to, exportedAt time.Time) map[string]string {
+	metadata := map[string]string{
+		"dataset":    dataset,
+		"exportedAt": exportedAt.UTC().Format(time.RFC3339),
+	}
+	if !from.IsZero() {
+		metadata["fromTime"] = from.UTC().Format(time.RFC3339)
+	}
+	if !to.IsZero() {
+		metadata["toTime"] = to.UTC().Format(time.RFC3339)
+	}
+	return metadata
+}
+
+// formatFloat formats a metric value with as many digits as necessary
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
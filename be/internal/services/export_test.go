@@ -0,0 +1,294 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/pkg/openweather"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// exportFixtures returns two reports, the second one annotated and deleted
+func exportFixtures() []models.WeatherReport {
+	timestamp := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	deletedAt := timestamp.Add(48 * time.Hour)
+	return []models.WeatherReport{
+		{
+			ID: "report1", Timestamp: timestamp, Temperature: 25.5, Pressure: 1013.2, Humidity: 60, CloudCover: 30,
+			Location: models.LocationChangi, Source: models.SourceOpenWeather, Type: models.ReportTypeHistorical, CreatedAt: timestamp,
+		},
+		{
+			ID: "report2", Timestamp: timestamp.Add(time.Hour), Temperature: 26, Pressure: 1012, Humidity: 65, CloudCover: 40,
+			Location: models.LocationChangi, Source: models.SourceOpenWeather, Type: models.ReportTypeHistorical, CreatedAt: timestamp,
+			Tags: []string{"runway closure", "sensor check"}, Notes: "Closed, \"02L\"", DeletedAt: &deletedAt,
+		},
+	}
+}
+
+func TestExportReports_CSV(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	service := NewReportService(mockReportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), newTestThresholdService())
+
+	ctx := context.Background()
+	req := &request.PaginatedReportsRequest{IncludeDeleted: true}
+	mockReportRepo.On("StreamReports", ctx, req).Return(exportFixtures(), nil)
+
+	var out bytes.Buffer
+
+	// Act
+	err := service.ExportReports(ctx, req, request.ExportFormatCSV, &out)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"id,timestamp,temperature,pressure,humidity,cloudCover,location,source,type,tags,notes,createdAt,deletedAt",
+		"report1,2024-03-01T12:00:00Z,25.5,1013.2,60,30,WSSS,openweather,historical,,,2024-03-01T12:00:00Z,",
+		`report2,2024-03-01T13:00:00Z,26,1012,65,40,WSSS,openweather,historical,runway closure;sensor check,"Closed, ""02L""",2024-03-01T12:00:00Z,2024-03-03T12:00:00Z`,
+		"",
+	}, "\n"), out.String())
+	mockReportRepo.AssertExpectations(t)
+}
+
+func TestExportReports_CSVEscapesFormulas(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	service := NewReportService(mockReportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), newTestThresholdService())
+
+	ctx := context.Background()
+	req := &request.PaginatedReportsRequest{}
+	timestamp := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	reports := []models.WeatherReport{
+		{ID: "report1", Timestamp: timestamp, Temperature: -5, Pressure: 1013, Location: models.LocationChangi, CreatedAt: timestamp,
+			Tags: []string{"@sum(a1)", "runway"}, Notes: `=HYPERLINK("https://evil.example.com","Details")`},
+		{ID: "report2", Timestamp: timestamp.Add(time.Hour), Temperature: 25, Pressure: 1013, Location: models.LocationChangi, CreatedAt: timestamp,
+			Notes: "'=already quoted"},
+	}
+	mockReportRepo.On("StreamReports", ctx, req).Return(reports, nil)
+
+	var out bytes.Buffer
+
+	// Act
+	err := service.ExportReports(ctx, req, request.ExportFormatCSV, &out)
+
+	// Assert
+	assert.NoError(t, err)
+	lines := strings.Split(out.String(), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, `report1,2024-03-01T12:00:00Z,-5,1013,0,0,WSSS,,,'@sum(a1);runway,"'=HYPERLINK(""https://evil.example.com"",""Details"")",2024-03-01T12:00:00Z,`, lines[1])
+	assert.Equal(t, "report2,2024-03-01T13:00:00Z,25,1013,0,0,WSSS,,,,''=already quoted,2024-03-01T12:00:00Z,", lines[2])
+
+	// The escaped notes and tags are restored when the export is imported again
+	importService, reportRepo := newImportTestService()
+	result, err := importService.ImportReports(ctx, request.ImportFormatCSV, &out, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Imported)
+	imported, err := reportRepo.FindAllReports(ctx)
+	require.NoError(t, err)
+	require.Len(t, imported, 2)
+	assert.Equal(t, []string{"@sum(a1)", "runway"}, imported[1].Tags)
+	assert.Equal(t, reports[0].Notes, imported[1].Notes)
+	assert.Equal(t, reports[1].Notes, imported[0].Notes)
+}
+
+func TestEscapeCSVText(t *testing.T) {
+	for value, want := range map[string]string{
+		"":               "",
+		"runway closure": "runway closure",
+		"=1+1":           "'=1+1",
+		"+1":             "'+1",
+		"-1":             "'-1",
+		"@sum(a1)":       "'@sum(a1)",
+		"\tindented":     "'\tindented",
+		"\rline":         "'\rline",
+		"'quoted":        "'quoted",
+		"'=1+1":          "''=1+1",
+		"a=1":            "a=1",
+	} {
+		t.Run(value, func(t *testing.T) {
+			// Act
+			escaped := escapeCSVText(value)
+
+			// Assert
+			assert.Equal(t, want, escaped)
+			assert.Equal(t, value, unescapeCSVText(escaped))
+		})
+	}
+}
+
+func TestExportReports_NDJSON(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	service := NewReportService(mockReportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), newTestThresholdService())
+
+	ctx := context.Background()
+	req := &request.PaginatedReportsRequest{}
+	mockReportRepo.On("StreamReports", ctx, req).Return(exportFixtures(), nil)
+
+	var out bytes.Buffer
+
+	// Act
+	err := service.ExportReports(ctx, req, request.ExportFormatNDJSON, &out)
+
+	// Assert
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+
+	var report models.WeatherReport
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &report))
+	assert.Equal(t, exportFixtures()[1].Tags, report.Tags)
+}
+
+func TestExportReports_JSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		reports []models.WeatherReport
+	}{
+		{"reports", exportFixtures()},
+		{"no reports", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockReportRepo := new(MockReportRepository)
+			service := NewReportService(mockReportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), newTestThresholdService())
+
+			ctx := context.Background()
+			req := &request.PaginatedReportsRequest{}
+			mockReportRepo.On("StreamReports", ctx, req).Return(tt.reports, nil)
+
+			var out bytes.Buffer
+
+			// Act
+			err := service.ExportReports(ctx, req, request.ExportFormatJSON, &out)
+
+			// Assert
+			assert.NoError(t, err)
+			var reports []models.WeatherReport
+			require.NoError(t, json.Unmarshal(out.Bytes(), &reports))
+			assert.Len(t, reports, len(tt.reports))
+		})
+	}
+}
+
+func TestExportReports_QueryErrorWritesNothing(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	service := NewReportService(mockReportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), newTestThresholdService())
+
+	ctx := context.Background()
+	req := &request.PaginatedReportsRequest{}
+	mockReportRepo.On("StreamReports", ctx, req).Return(nil, errors.New("failed to retrieve reports: database error"))
+
+	var out bytes.Buffer
+
+	// Act
+	err := service.ExportReports(ctx, req, request.ExportFormatCSV, &out)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to retrieve reports")
+	assert.Empty(t, out.String())
+}
+
+func TestExportReports_Columnar(t *testing.T) {
+	tests := []struct {
+		format request.ExportFormat
+		magic  string
+	}{
+		{request.ExportFormatParquet, "PAR1"},
+		{request.ExportFormatArrow, "ARROW1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			// Arrange
+			mockReportRepo := new(MockReportRepository)
+			service := NewReportService(mockReportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), newTestThresholdService())
+
+			ctx := context.Background()
+			req := &request.PaginatedReportsRequest{IncludeDeleted: true}
+			mockReportRepo.On("StreamReports", ctx, req).Return(exportFixtures(), nil)
+
+			var out bytes.Buffer
+
+			// Act
+			err := service.ExportReports(ctx, req, tt.format, &out)
+
+			// Assert
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(out.String(), tt.magic))
+			assert.True(t, strings.HasSuffix(out.String(), tt.magic))
+			assert.Contains(t, out.String(), "runway closure;sensor check")
+			assert.Contains(t, out.String(), "°C", "units are part of the schema")
+			mockReportRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExportWeatherCaches(t *testing.T) {
+	// Arrange
+	mockWeatherCacheRepo := new(MockWeatherCacheRepository)
+	service := NewReportService(new(MockReportRepository), mockWeatherCacheRepo, new(MockWeatherService), newTestThresholdService())
+
+	ctx := context.Background()
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	caches := []models.WeatherCache{
+		{ID: "cache1", Timestamp: from.Add(time.Hour), WeatherData: openweather.WeatherData{Temperature: 25.5, Pressure: 1013.2, Humidity: 60, CloudCover: 30}, CreatedAt: from},
+	}
+	mockWeatherCacheRepo.On("StreamWeatherCaches", ctx, from, to).Return(caches, nil)
+
+	var out bytes.Buffer
+
+	// Act
+	err := service.ExportWeatherCaches(ctx, from, to, request.ExportFormatParquet, &out)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out.String(), "PAR1"))
+	assert.Contains(t, out.String(), "weather_cache")
+	assert.Contains(t, out.String(), "2024-03-01T00:00:00Z", "the time range is recorded")
+	mockWeatherCacheRepo.AssertExpectations(t)
+}
+
+func TestExportWeatherCaches_InvalidRequest(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		from, to time.Time
+		format   request.ExportFormat
+	}{
+		{"RowFormat", time.Time{}, time.Time{}, request.ExportFormatCSV},
+		{"ReversedRange", from, from.Add(-time.Hour), request.ExportFormatArrow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockWeatherCacheRepo := new(MockWeatherCacheRepository)
+			service := NewReportService(new(MockReportRepository), mockWeatherCacheRepo, new(MockWeatherService), newTestThresholdService())
+
+			var out bytes.Buffer
+
+			// Act
+			err := service.ExportWeatherCaches(context.Background(), tt.from, tt.to, tt.format, &out)
+
+			// Assert
+			assert.ErrorContains(t, err, "invalid export")
+			assert.Zero(t, out.Len())
+			mockWeatherCacheRepo.AssertNotCalled(t, "StreamWeatherCaches", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
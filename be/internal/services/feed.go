@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+)
+
+const (
+	// DefaultAlertRange is the time range of the alert feed without from and to
+	DefaultAlertRange = 30 * 24 * time.Hour
+
+	// MaxAlertEvents is the most alert events returned; older events in the range are dropped
+	MaxAlertEvents = 500
+)
+
+// GetLatestReports returns the limit reports with the most recent timestamps, newest first
+func (s *ReportService) GetLatestReports(ctx context.Context, limit int) ([]models.WeatherReport, error) {
+	if limit < 1 || limit > request.MaxFeedLimit {
+		return nil, fmt.Errorf("invalid feed: limit must be between 1 and %d", request.MaxFeedLimit)
+	}
+
+	page, err := s.reportRepository.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{
+		Limit:          limit,
+		Sort:           []request.SortKey{{Field: "timestamp", Order: request.SortOrderDesc}},
+		SkipTotalCount: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return page.Reports, nil
+}
+
+// GetAlertEvents returns the alert events in a time range in time order: every report in the range whose
+// deviation from the report before it, which may lie before the range, reaches the minimum severity under the
+// configured thresholds. Only the most recent MaxAlertEvents are returned. A missing to defaults to now and a
+// missing from to DefaultAlertRange before to.
+func (s *ReportService) GetAlertEvents(ctx context.Context, req *request.AlertFeedRequest) ([]response.AlertEvent, error) {
+	minSeverity := req.MinSeverity
+	switch minSeverity {
+	case "":
+		minSeverity = models.SeverityNotable
+	case models.SeverityNotable, models.SeverityMajor:
+	default:
+		return nil, fmt.Errorf("invalid feed: severity must be notable or major")
+	}
+
+	to := req.To
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	from := req.From
+	if from.IsZero() {
+		from = to.Add(-DefaultAlertRange)
+	}
+	if !to.After(from) {
+		return nil, fmt.Errorf("invalid feed: to must be after from")
+	}
+
+	thresholds, err := s.thresholdService.GetEffectiveThresholds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load thresholds: %w", err)
+	}
+
+	// The first report in the range is compared with the last one before it. The lookup includes from,
+	// so up to two reports are needed to find one strictly before it.
+	before, err := s.reportRepository.FindPaginatedReports(ctx, &request.PaginatedReportsRequest{
+		Limit:          2,
+		ToTime:         from,
+		IsFiltered:     true,
+		Sort:           []request.SortKey{{Field: "timestamp", Order: request.SortOrderDesc}},
+		SkipTotalCount: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var previous *models.WeatherReport
+	for i := range before.Reports {
+		if before.Reports[i].Timestamp.Before(from) {
+			previous = &before.Reports[i]
+			break
+		}
+	}
+
+	filter := &request.PaginatedReportsRequest{
+		FromTime:   from,
+		ToTime:     to,
+		IsFiltered: true,
+		Sort:       []request.SortKey{{Field: "timestamp", Order: request.SortOrderAsc}},
+	}
+	events := []response.AlertEvent{}
+	err = s.reportRepository.StreamReports(ctx, filter, func(report *models.WeatherReport) error {
+		current := *report
+		if previous != nil {
+			deviation := calculateDeviation(previous, &current, thresholds)
+			if deviation.Verdict.Rank() >= minSeverity.Rank() {
+				events = append(events, response.AlertEvent{Previous: *previous, Report: current, Deviation: deviation})
+				if len(events) > MaxAlertEvents {
+					events = events[1:]
+				}
+			}
+		}
+		previous = &current
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
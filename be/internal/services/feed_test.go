@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository/memory"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var feedStart = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+// newFeedTestService returns a service whose repository has a report every hour from feedStart with the given
+// temperatures, and the default thresholds (notable from 2 °C, major from 5 °C)
+func newFeedTestService(t *testing.T, temperatures ...float64) *ReportService {
+	reportRepo := memory.NewReportRepository()
+	reports := make([]models.WeatherReport, len(temperatures))
+	for i, temperature := range temperatures {
+		reports[i] = models.WeatherReport{Timestamp: feedStart.Add(time.Duration(i) * time.Hour), Temperature: temperature, Pressure: 1010}
+	}
+	_, err := reportRepo.InsertReports(context.Background(), reports)
+	require.NoError(t, err)
+	return NewReportService(reportRepo, nil, nil, newTestThresholdService())
+}
+
+func TestGetLatestReports(t *testing.T) {
+	// Arrange
+	service := newFeedTestService(t, 20, 21, 22, 23)
+
+	// Act
+	reports, err := service.GetLatestReports(context.Background(), 3)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, reports, 3)
+	assert.Equal(t, 23.0, reports[0].Temperature, "the newest report comes first")
+	assert.Equal(t, 21.0, reports[2].Temperature)
+}
+
+func TestGetLatestReports_InvalidLimit(t *testing.T) {
+	service := newFeedTestService(t)
+	for _, limit := range []int{0, request.MaxFeedLimit + 1} {
+		_, err := service.GetLatestReports(context.Background(), limit)
+		assert.EqualError(t, err, "invalid feed: limit must be between 1 and 100")
+	}
+}
+
+func TestGetAlertEvents(t *testing.T) {
+	// Arrange
+	service := newFeedTestService(t, 20, 21, 24, 24.5, 30, 29)
+	req := &request.AlertFeedRequest{From: feedStart, To: feedStart.Add(24 * time.Hour)}
+
+	// Act
+	events, err := service.GetAlertEvents(context.Background(), req)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, 21.0, events[0].Previous.Temperature)
+	assert.Equal(t, 24.0, events[0].Report.Temperature)
+	assert.Equal(t, models.SeverityNotable, events[0].Deviation.Verdict)
+	assert.Equal(t, 24.5, events[1].Previous.Temperature)
+	assert.Equal(t, models.SeverityMajor, events[1].Deviation.Verdict)
+}
+
+func TestGetAlertEvents_MajorOnly(t *testing.T) {
+	// Arrange
+	service := newFeedTestService(t, 20, 21, 24, 24.5, 30, 29)
+	req := &request.AlertFeedRequest{From: feedStart, To: feedStart.Add(24 * time.Hour), MinSeverity: models.SeverityMajor}
+
+	// Act
+	events, err := service.GetAlertEvents(context.Background(), req)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, 30.0, events[0].Report.Temperature)
+}
+
+func TestGetAlertEvents_ComparesWithReportBeforeRange(t *testing.T) {
+	// Arrange
+	service := newFeedTestService(t, 20, 21, 30, 30)
+	req := &request.AlertFeedRequest{From: feedStart.Add(2 * time.Hour), To: feedStart.Add(24 * time.Hour)}
+
+	// Act
+	events, err := service.GetAlertEvents(context.Background(), req)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, 21.0, events[0].Previous.Temperature, "the report at from is compared with the one before it")
+	assert.Equal(t, 30.0, events[0].Report.Temperature)
+}
+
+func TestGetAlertEvents_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     request.AlertFeedRequest
+		wantErr string
+	}{
+		{"Unknown severity", request.AlertFeedRequest{MinSeverity: "normal"}, "invalid feed: severity must be notable or major"},
+		{"Empty range", request.AlertFeedRequest{From: feedStart, To: feedStart}, "invalid feed: to must be after from"},
+	}
+
+	service := newFeedTestService(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.GetAlertEvents(context.Background(), &tt.req)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
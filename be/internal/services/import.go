@@ -0,0 +1,422 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+)
+
+const (
+	ImportBatchSize   = 1000    // Rows checked for duplicates and inserted per database round trip
+	MaxImportErrors   = 100     // Line errors listed in an import result; further failures are only counted
+	MaxImportLineSize = 1 << 20 // Bytes of a single NDJSON line
+)
+
+// requiredImportColumns must be present in the header of a CSV import
+var requiredImportColumns = []string{"timestamp", "temperature", "pressure", "humidity", "cloudCover"}
+
+// icaoCode matches a four-letter ICAO airport code
+var icaoCode = regexp.MustCompile(`^[A-Z]{4}$`)
+
+// ImportReports reads historical reports from a CSV or NDJSON file and inserts them in batches.
+// Every row is validated against the report fields and the physical bounds of each metric; invalid rows
+// are reported by line without stopping the import. Rows whose timestamp already has a report, deleted
+// or not, or appears earlier in the file are skipped as duplicates. Exported files can be imported as is:
+// their id, createdAt and deletedAt fields are ignored. With dryRun nothing is inserted.
+// If reading the file or saving a batch fails, or a batch would exceed the report quota, the result so far
+// is returned along with the error; batches inserted before the failure are kept.
+func (s *ReportService) ImportReports(ctx context.Context, format request.ImportFormat, r io.Reader, dryRun bool) (*response.ImportResult, error) {
+	importer := &reportImporter{
+		repository: s.reportRepository,
+		reserve:    s.reserveReports,
+		dryRun:     dryRun,
+		createdAt:  time.Now().UTC(),
+		seen:       make(map[int64]bool),
+		result:     &response.ImportResult{Errors: []response.ImportLineError{}, DryRun: dryRun},
+	}
+
+	var err error
+	if format == request.ImportFormatNDJSON {
+		err = importer.readNDJSON(ctx, r)
+	} else {
+		err = importer.readCSV(ctx, r)
+	}
+	if err == nil {
+		err = importer.flush(ctx)
+	}
+	return importer.result, err
+}
+
+// reportImporter validates imported rows and inserts them a batch at a time
+type reportImporter struct {
+	repository repository.IReportRepository
+	reserve    func(ctx context.Context, n int) (release func(), err error)
+	dryRun     bool
+	createdAt  time.Time
+	seen       map[int64]bool // Timestamps of the valid rows so far, in Unix milliseconds
+	batch      []models.WeatherReport
+	result     *response.ImportResult
+}
+
+// add validates the row read from line and queues it for insertion
+func (i *reportImporter) add(ctx context.Context, line int, record *importRecord) error {
+	i.result.Rows++
+	report, lineErr := record.report(i.createdAt)
+	if lineErr != nil {
+		i.fail(line, lineErr.Field, lineErr.Message)
+		return nil
+	}
+
+	key := report.Timestamp.UnixMilli()
+	if i.seen[key] {
+		i.result.Duplicates++
+		return nil
+	}
+	i.seen[key] = true
+
+	i.batch = append(i.batch, *report)
+	if len(i.batch) >= ImportBatchSize {
+		return i.flush(ctx)
+	}
+	return nil
+}
+
+// fail records an invalid row
+func (i *reportImporter) fail(line int, field, message string) {
+	i.result.Failed++
+	if len(i.result.Errors) >= MaxImportErrors {
+		i.result.ErrorsTruncated = true
+		return
+	}
+	i.result.Errors = append(i.result.Errors, response.ImportLineError{Line: line, Field: field, Message: message})
+}
+
+// flush drops the queued reports whose timestamp already has a report and inserts the others
+func (i *reportImporter) flush(ctx context.Context) error {
+	if len(i.batch) == 0 {
+		return nil
+	}
+
+	timestamps := make([]time.Time, len(i.batch))
+	for j, report := range i.batch {
+		timestamps[j] = report.Timestamp
+	}
+	existing, err := i.repository.FindExistingTimestamps(ctx, timestamps)
+	if err != nil {
+		return err
+	}
+	taken := make(map[int64]bool, len(existing))
+	for _, timestamp := range existing {
+		taken[timestamp.UnixMilli()] = true
+	}
+
+	reports := make([]models.WeatherReport, 0, len(i.batch))
+	for _, report := range i.batch {
+		if !taken[report.Timestamp.UnixMilli()] {
+			reports = append(reports, report)
+		}
+	}
+	i.result.Duplicates += len(i.batch) - len(reports)
+	i.batch = i.batch[:0]
+
+	// A dry run inserts nothing, so the reports it would have imported so far count towards the quota
+	pending := len(reports)
+	if i.dryRun {
+		pending += i.result.Imported
+	}
+	release, err := i.reserve(ctx, pending)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if !i.dryRun && len(reports) > 0 {
+		if _, err := i.repository.InsertReports(ctx, reports); err != nil {
+			return err
+		}
+	}
+	i.result.Imported += len(reports)
+	return nil
+}
+
+// readCSV imports a CSV file whose header names the columns, as in a CSV export
+func (i *reportImporter) readCSV(ctx context.Context, r io.Reader) error {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return fmt.Errorf("invalid import: the file is empty")
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return fmt.Errorf("invalid import: header: %w", parseErr.Err)
+	} else if err != nil {
+		return fmt.Errorf("failed to read import: %w", err)
+	}
+	columns, err := importColumns(header)
+	if err != nil {
+		return err
+	}
+
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if errors.As(err, &parseErr) {
+			i.result.Rows++
+			i.fail(parseErr.StartLine, "", parseErr.Err.Error())
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to read import: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		record, lineErr := parseCSVRow(columns, row)
+		if lineErr != nil {
+			i.result.Rows++
+			i.fail(line, lineErr.Field, lineErr.Message)
+			continue
+		}
+		if err := i.add(ctx, line, record); err != nil {
+			return err
+		}
+	}
+}
+
+// importColumns maps a CSV header to field names, which are matched case-insensitively
+func importColumns(header []string) ([]string, error) {
+	columns := make([]string, len(header))
+	for j, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")) // Spreadsheets may start the file with a BOM
+		index := slices.IndexFunc(csvHeader, func(column string) bool { return strings.EqualFold(column, name) })
+		if index < 0 {
+			return nil, fmt.Errorf("invalid import: unknown column %q", name)
+		}
+		if slices.Contains(columns, csvHeader[index]) {
+			return nil, fmt.Errorf("invalid import: duplicate column %q", name)
+		}
+		columns[j] = csvHeader[index]
+	}
+
+	for _, column := range requiredImportColumns {
+		if !slices.Contains(columns, column) {
+			return nil, fmt.Errorf("invalid import: missing column %q", column)
+		}
+	}
+	return columns, nil
+}
+
+// parseCSVRow parses the cells of a CSV row. Empty cells are missing values; tags are separated by semicolons.
+// Tags and notes escaped as in a CSV export are unescaped.
+func parseCSVRow(columns, row []string) (*importRecord, *response.ImportLineError) {
+	record := &importRecord{}
+	for j, column := range columns {
+		cell := strings.TrimSpace(row[j])
+		if cell == "" {
+			continue
+		}
+
+		switch column {
+		case "timestamp":
+			timestamp, err := time.Parse(time.RFC3339, cell)
+			if err != nil {
+				return nil, &response.ImportLineError{Field: column, Message: fmt.Sprintf("timestamp %q is not an RFC 3339 time", cell)}
+			}
+			record.Timestamp = &timestamp
+		case "temperature", "pressure", "humidity", "cloudCover":
+			value, err := strconv.ParseFloat(cell, 64)
+			if err != nil {
+				return nil, &response.ImportLineError{Field: column, Message: fmt.Sprintf("%s %q is not a number", column, cell)}
+			}
+			*record.metric(models.Metric(column)) = &value
+		case "location":
+			record.Location = cell
+		case "source":
+			record.Source = cell
+		case "type":
+			record.Type = cell
+		case "tags":
+			for _, tag := range strings.Split(unescapeCSVText(cell), ";") {
+				if strings.TrimSpace(tag) != "" {
+					record.Tags = append(record.Tags, tag)
+				}
+			}
+		case "notes":
+			record.Notes = unescapeCSVText(row[j]) // Keep the notes as written
+		}
+	}
+	return record, nil
+}
+
+// readNDJSON imports a file with one JSON report per line, as in an NDJSON export. Blank lines are skipped.
+func (i *reportImporter) readNDJSON(ctx context.Context, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxImportLineSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		record, lineErr := parseJSONLine(data)
+		if lineErr != nil {
+			i.result.Rows++
+			i.fail(line, lineErr.Field, lineErr.Message)
+			continue
+		}
+		if err := i.add(ctx, line, record); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return fmt.Errorf("invalid import: line %d is longer than %d bytes", line+1, MaxImportLineSize)
+		}
+		return fmt.Errorf("failed to read import: %w", err)
+	}
+	return nil
+}
+
+// parseJSONLine decodes a single JSON object, rejecting unknown fields
+func parseJSONLine(data []byte) (*importRecord, *response.ImportLineError) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	record := &importRecord{}
+	if err := decoder.Decode(record); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return nil, &response.ImportLineError{Field: typeErr.Field, Message: fmt.Sprintf("%s must not be a JSON %s", typeErr.Field, typeErr.Value)}
+		}
+		return nil, &response.ImportLineError{Message: "invalid JSON: " + strings.TrimPrefix(err.Error(), "json: ")}
+	}
+	if decoder.More() {
+		return nil, &response.ImportLineError{Message: "invalid JSON: more than one value on the line"}
+	}
+	return record, nil
+}
+
+// importRecord holds the fields of an imported row before validation. Nil fields are missing.
+type importRecord struct {
+	Timestamp   *time.Time `json:"timestamp"`
+	Temperature *float64   `json:"temperature"`
+	Pressure    *float64   `json:"pressure"`
+	Humidity    *float64   `json:"humidity"`
+	CloudCover  *float64   `json:"cloudCover"`
+	Location    string     `json:"location"`
+	Source      string     `json:"source"`
+	Type        string     `json:"type"`
+	Tags        []string   `json:"tags"`
+	Notes       string     `json:"notes"`
+
+	// Written by exports and ignored
+	ID        json.RawMessage `json:"id"`
+	CreatedAt json.RawMessage `json:"createdAt"`
+	DeletedAt json.RawMessage `json:"deletedAt"`
+}
+
+// metric returns the field holding metric m
+func (rec *importRecord) metric(m models.Metric) **float64 {
+	switch m {
+	case models.MetricTemperature:
+		return &rec.Temperature
+	case models.MetricPressure:
+		return &rec.Pressure
+	case models.MetricHumidity:
+		return &rec.Humidity
+	}
+	return &rec.CloudCover
+}
+
+// report validates the record and returns the report to insert. The location defaults to Changi,
+// the source to import and the type to historical. Timestamps are truncated to milliseconds like BSON datetimes.
+func (rec *importRecord) report(createdAt time.Time) (*models.WeatherReport, *response.ImportLineError) {
+	if rec.Timestamp == nil {
+		return nil, &response.ImportLineError{Field: "timestamp", Message: "timestamp is required"}
+	}
+	timestamp := rec.Timestamp.UTC().Truncate(time.Millisecond)
+	if timestamp.After(createdAt) {
+		return nil, &response.ImportLineError{Field: "timestamp", Message: "timestamp must not be in the future"}
+	}
+
+	report := &models.WeatherReport{
+		Timestamp: timestamp,
+		Location:  strings.ToUpper(strings.TrimSpace(rec.Location)),
+		Source:    strings.ToLower(strings.TrimSpace(rec.Source)),
+		Type:      strings.ToLower(strings.TrimSpace(rec.Type)),
+		Notes:     rec.Notes,
+		CreatedAt: createdAt,
+	}
+
+	for _, metric := range models.Metrics {
+		value := *rec.metric(metric)
+		field := string(metric)
+		if value == nil {
+			return nil, &response.ImportLineError{Field: field, Message: field + " is required"}
+		}
+		if math.IsNaN(*value) || math.IsInf(*value, 0) {
+			return nil, &response.ImportLineError{Field: field, Message: field + " must be a finite number"}
+		}
+		if min, max := metric.PhysicalBounds(); *value < min || *value > max {
+			return nil, &response.ImportLineError{
+				Field:   field,
+				Message: fmt.Sprintf("%s %s is outside the physical bounds [%s, %s] %s", field, formatFloat(*value), formatFloat(min), formatFloat(max), metric.Unit()),
+			}
+		}
+	}
+	report.Temperature = *rec.Temperature
+	report.Pressure = *rec.Pressure
+	report.Humidity = *rec.Humidity
+	report.CloudCover = *rec.CloudCover
+
+	if report.Location == "" {
+		report.Location = models.LocationChangi
+	} else if !icaoCode.MatchString(report.Location) {
+		return nil, &response.ImportLineError{Field: "location", Message: fmt.Sprintf("location %q is not a four-letter ICAO code", rec.Location)}
+	}
+	if report.Source == "" {
+		report.Source = models.SourceImport
+	} else if !slices.Contains(models.ReportSources, report.Source) {
+		return nil, &response.ImportLineError{Field: "source", Message: fmt.Sprintf("unknown source %q", rec.Source)}
+	}
+	if report.Type == "" {
+		report.Type = models.ReportTypeHistorical
+	} else if !slices.Contains(models.ReportTypes, report.Type) {
+		return nil, &response.ImportLineError{Field: "type", Message: fmt.Sprintf("unknown type %q", rec.Type)}
+	}
+
+	if len(rec.Tags) > 0 {
+		tags, err := normalizeTags(rec.Tags)
+		if err != nil {
+			return nil, &response.ImportLineError{Field: "tags", Message: err.Error()}
+		}
+		report.Tags = tags
+	}
+	if utf8.RuneCountInString(rec.Notes) > MaxReportNotesLength {
+		return nil, &response.ImportLineError{Field: "notes", Message: fmt.Sprintf("notes are longer than %d characters", MaxReportNotesLength)}
+	}
+	return report, nil
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository/memory"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newImportTestService() (*ReportService, repository.IReportRepository) {
+	reportRepo := memory.NewReportRepository()
+	return NewReportService(reportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), newTestThresholdService()), reportRepo
+}
+
+func TestImportReports_CSV(t *testing.T) {
+	// Arrange
+	service, reportRepo := newImportTestService()
+	ctx := context.Background()
+
+	existing := models.WeatherReport{Timestamp: time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC), Temperature: 27}
+	_, err := reportRepo.InsertReport(ctx, &existing)
+	require.NoError(t, err)
+
+	file := strings.Join([]string{
+		"Timestamp,temperature,pressure,humidity,cloudCover,tags,notes",
+		`2024-03-01T12:00:00Z,25.5,1013.2,60,30,Runway Closure;;sensor check,"Closed, ""02L"""`,
+		"2024-03-01T13:00:00+08:00,26,1012,65,40,,",
+		"2024-03-01T12:00:00Z,25.5,1013.2,60,30,,", // Duplicate within the file
+		"2024-03-01T14:00:00Z,27,1011,70,50,,",     // Duplicate of an existing report
+		"2024-03-01T15:00:00Z,75,1011,70,50,,",
+		"2024-03-01T16:00:00Z,abc,1011,70,50,,",
+		"2024-03-01T17:00:00Z,27,1011,70",
+		"",
+	}, "\n")
+
+	// Act
+	result, err := service.ImportReports(ctx, request.ImportFormatCSV, strings.NewReader(file), false)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 7, result.Rows)
+	assert.Equal(t, 2, result.Imported)
+	assert.Equal(t, 2, result.Duplicates)
+	assert.Equal(t, 3, result.Failed)
+	assert.Equal(t, []response.ImportLineError{
+		{Line: 6, Field: "temperature", Message: "temperature 75 is outside the physical bounds [-90, 60] °C"},
+		{Line: 7, Field: "temperature", Message: `temperature "abc" is not a number`},
+		{Line: 8, Message: "wrong number of fields"},
+	}, result.Errors)
+
+	reports, err := reportRepo.FindAllReports(ctx)
+	require.NoError(t, err)
+	require.Len(t, reports, 3)
+	imported := reports[1] // Newest first, after the existing report
+	assert.Equal(t, 25.5, imported.Temperature)
+	assert.Equal(t, models.LocationChangi, imported.Location)
+	assert.Equal(t, models.SourceImport, imported.Source)
+	assert.Equal(t, models.ReportTypeHistorical, imported.Type)
+	assert.Equal(t, []string{"runway closure", "sensor check"}, imported.Tags)
+	assert.Equal(t, `Closed, "02L"`, imported.Notes)
+	assert.True(t, reports[2].Timestamp.Equal(time.Date(2024, 3, 1, 5, 0, 0, 0, time.UTC)))
+}
+
+func TestImportReports_NDJSON(t *testing.T) {
+	// Arrange
+	service, reportRepo := newImportTestService()
+	ctx := context.Background()
+
+	file := strings.Join([]string{
+		// An exported report, whose id, createdAt and deletedAt are ignored
+		`{"id":"report1","timestamp":"2024-03-01T12:00:00Z","temperature":25.5,"pressure":1013.2,"humidity":60,"cloudCover":30,"location":"wsss","source":"openweather","type":"current","createdAt":"2024-03-01T12:00:00Z","deletedAt":"2024-03-03T12:00:00Z"}`,
+		"",
+		`{"timestamp":"2024-03-01T13:00:00Z","temperature":26,"pressure":1012,"humidity":65}`,
+		`{"timestamp":"2024-03-01T14:00:00Z","temperature":"26","pressure":1012,"humidity":65,"cloudCover":40}`,
+		`{"timestamp":"2024-03-01T15:00:00Z","temperature":26,"pressure":1012,"humidity":65,"cloudCover":40,"wind":3}`,
+		`{"timestamp":"2024-03-01T16:00:00Z","temperature":26,"pressure":1012,"humidity":65,"cloudCover":40,"location":"Changi"}`,
+		`{"timestamp":"2999-01-01T00:00:00Z","temperature":26,"pressure":1012,"humidity":65,"cloudCover":40}`,
+		`not json`,
+	}, "\n")
+
+	// Act
+	result, err := service.ImportReports(ctx, request.ImportFormatNDJSON, strings.NewReader(file), false)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 7, result.Rows)
+	assert.Equal(t, 1, result.Imported)
+	assert.Equal(t, 6, result.Failed)
+	assert.Equal(t, []response.ImportLineError{
+		{Line: 3, Field: "cloudCover", Message: "cloudCover is required"},
+		{Line: 4, Field: "temperature", Message: "temperature must not be a JSON string"},
+		{Line: 5, Message: `invalid JSON: unknown field "wind"`},
+		{Line: 6, Field: "location", Message: `location "Changi" is not a four-letter ICAO code`},
+		{Line: 7, Field: "timestamp", Message: "timestamp must not be in the future"},
+		{Line: 8, Message: "invalid JSON: invalid character 'o' in literal null (expecting 'u')"},
+	}, result.Errors)
+
+	reports, err := reportRepo.FindAllReports(ctx)
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.NotEqual(t, "report1", reports[0].ID)
+	assert.Equal(t, models.LocationChangi, reports[0].Location)
+	assert.Equal(t, models.SourceOpenWeather, reports[0].Source)
+	assert.Equal(t, models.ReportTypeCurrent, reports[0].Type)
+	assert.Nil(t, reports[0].DeletedAt)
+	assert.True(t, reports[0].CreatedAt.After(reports[0].Timestamp))
+}
+
+func TestImportReports_DryRun(t *testing.T) {
+	// Arrange
+	service, reportRepo := newImportTestService()
+	file := "timestamp,temperature,pressure,humidity,cloudCover\n2024-03-01T12:00:00Z,25.5,1013.2,60,30\n"
+
+	// Act
+	result, err := service.ImportReports(context.Background(), request.ImportFormatCSV, strings.NewReader(file), true)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 1, result.Imported)
+	count, err := reportRepo.CountReports(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestImportReports_QuotaExceeded(t *testing.T) {
+	for _, dryRun := range []bool{false, true} {
+		t.Run(fmt.Sprintf("DryRun=%t", dryRun), func(t *testing.T) {
+			// Arrange
+			service, reportRepo := newImportTestService()
+			service.SetReportQuota(models.ReportQuota{Tenants: map[string]int64{"changi-ops": ImportBatchSize + 1}})
+			ctx := models.ContextWithTenant(context.Background(), "changi-ops")
+
+			var file strings.Builder
+			file.WriteString("timestamp,temperature,pressure,humidity,cloudCover\n")
+			for i := 0; i < 2*ImportBatchSize; i++ {
+				timestamp := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(i) * time.Hour)
+				fmt.Fprintf(&file, "%s,25.5,1013.2,60,30\n", timestamp.Format(time.RFC3339))
+			}
+
+			// Act
+			result, err := service.ImportReports(ctx, request.ImportFormatCSV, strings.NewReader(file.String()), dryRun)
+
+			// Assert
+			require.Error(t, err)
+			assert.Equal(t, fmt.Sprintf("quota exceeded: tenant changi-ops may store at most %d reports", ImportBatchSize+1), err.Error())
+			assert.Equal(t, ImportBatchSize, result.Imported, "the first batch fits")
+
+			// Other tenants are not limited
+			result, err = service.ImportReports(context.Background(), request.ImportFormatCSV, strings.NewReader(file.String()), dryRun)
+			require.NoError(t, err)
+			assert.Equal(t, 2*ImportBatchSize, result.Imported)
+
+			if !dryRun {
+				count, err := reportRepo.CountReports(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, int64(ImportBatchSize), count)
+			}
+		})
+	}
+}
+
+func TestImportReports_InvalidHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		expected string
+	}{
+		{"Empty", "", "invalid import: the file is empty"},
+		{"UnknownColumn", "timestamp,temperature,pressure,humidity,cloudCover,wind\n", `invalid import: unknown column "wind"`},
+		{"DuplicateColumn", "timestamp,temperature,pressure,humidity,cloudCover,Pressure\n", `invalid import: duplicate column "Pressure"`},
+		{"MissingColumn", "timestamp,temperature,pressure,humidity\n", `invalid import: missing column "cloudCover"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service, _ := newImportTestService()
+
+			// Act
+			_, err := service.ImportReports(context.Background(), request.ImportFormatCSV, strings.NewReader(tt.file), false)
+
+			// Assert
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func TestImportReports_ErrorsTruncated(t *testing.T) {
+	// Arrange
+	service, _ := newImportTestService()
+	var file strings.Builder
+	file.WriteString("timestamp,temperature,pressure,humidity,cloudCover\n")
+	for i := 0; i < MaxImportErrors+5; i++ {
+		fmt.Fprintf(&file, "2024-03-01T12:%02d:00Z,25,2000,60,30\n", i%60)
+	}
+
+	// Act
+	result, err := service.ImportReports(context.Background(), request.ImportFormatCSV, strings.NewReader(file.String()), false)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, MaxImportErrors+5, result.Failed)
+	assert.Len(t, result.Errors, MaxImportErrors)
+	assert.True(t, result.ErrorsTruncated)
+}
+
+func TestImportReports_InsertsInBatches(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	service := NewReportService(mockReportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), newTestThresholdService())
+	ctx := context.Background()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var file strings.Builder
+	file.WriteString("timestamp,temperature,pressure,humidity,cloudCover\n")
+	for i := 0; i < ImportBatchSize+1; i++ {
+		fmt.Fprintf(&file, "%s,25,1010,60,30\n", start.Add(time.Duration(i)*time.Minute).Format(time.RFC3339))
+	}
+
+	mockReportRepo.On("FindExistingTimestamps", ctx, mock.Anything).Return([]time.Time{start}, nil).Once()
+	mockReportRepo.On("InsertReports", ctx, mock.MatchedBy(func(reports []models.WeatherReport) bool {
+		return len(reports) == ImportBatchSize-1
+	})).Return(make([]string, ImportBatchSize-1), nil).Once()
+	mockReportRepo.On("FindExistingTimestamps", ctx, mock.Anything).Return([]time.Time{}, nil).Once()
+	mockReportRepo.On("InsertReports", ctx, mock.Anything).Return(nil, errors.New("failed to save reports: database error")).Once()
+
+	// Act
+	result, err := service.ImportReports(ctx, request.ImportFormatCSV, strings.NewReader(file.String()), false)
+
+	// Assert: the first batch stays imported when the second one fails
+	assert.EqualError(t, err, "failed to save reports: database error")
+	assert.Equal(t, ImportBatchSize-1, result.Imported)
+	assert.Equal(t, 1, result.Duplicates)
+	mockReportRepo.AssertExpectations(t)
+}
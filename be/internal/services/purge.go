@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// PurgeJob periodically removes soft-deleted reports once their retention window has passed
+type PurgeJob struct {
+	reportService *ReportService
+	retention     time.Duration
+	interval      time.Duration
+}
+
+// NewPurgeJob creates a new PurgeJob that purges every interval the reports deleted longer than retention ago
+func NewPurgeJob(reportService *ReportService, retention, interval time.Duration) *PurgeJob {
+	return &PurgeJob{
+		reportService: reportService,
+		retention:     retention,
+		interval:      interval,
+	}
+}
+
+// Run purges once immediately and then on every interval until ctx is cancelled
+func (j *PurgeJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := j.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to purge deleted reports: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce purges the expired deleted reports and returns how many were removed
+func (j *PurgeJob) RunOnce(ctx context.Context) (int64, error) {
+	purged, err := j.reportService.PurgeDeletedReports(ctx, j.retention)
+	if err != nil {
+		return 0, err
+	}
+	if purged > 0 {
+		log.Printf("Purged %d deleted reports older than %s", purged, j.retention)
+	}
+	return purged, nil
+}
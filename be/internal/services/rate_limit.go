@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+)
+
+// defaultRateLimitBucket names the bucket a client shares between all routes without their own limit
+const defaultRateLimitBucket = "*"
+
+// RateLimitService decides whether clients may make another request to a route
+type RateLimitService struct {
+	rateLimitRepository repository.IRateLimitRepository
+	defaultLimit        models.RateLimit
+	routeLimits         map[string]models.RateLimit // Keyed by method and path template, e.g. "POST /api/reports"
+}
+
+// NewRateLimitService creates a new instance of RateLimitService. Routes in routeLimits have their own limit
+// and bucket per client; the others share a bucket with defaultLimit.
+func NewRateLimitService(rateLimitRepository repository.IRateLimitRepository, defaultLimit models.RateLimit,
+	routeLimits map[string]models.RateLimit) *RateLimitService {
+	return &RateLimitService{
+		rateLimitRepository: rateLimitRepository,
+		defaultLimit:        defaultLimit,
+		routeLimits:         routeLimits,
+	}
+}
+
+// Take takes a token from the bucket of client for route, e.g. "POST /api/reports". Clients are identified by
+// their credential or address, e.g. "api_key:<id>" or "ip:10.0.0.1". The result has a zero limit and allows
+// the request if the route is not limited.
+func (s *RateLimitService) Take(ctx context.Context, client, route string) (*models.RateLimitResult, error) {
+	limit, ok := s.routeLimits[route]
+	bucket := route
+	if !ok {
+		limit = s.defaultLimit
+		bucket = defaultRateLimitBucket
+	}
+	if limit.IsUnlimited() {
+		return &models.RateLimitResult{Allowed: true}, nil
+	}
+
+	result, err := s.rateLimitRepository.TakeToken(ctx, client+" "+bucket, limit, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to check rate limit: %w", err)
+	}
+	return result, nil
+}
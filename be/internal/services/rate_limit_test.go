@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRateLimitRepository is a mock implementation of IRateLimitRepository
+type MockRateLimitRepository struct {
+	mock.Mock
+}
+
+func (m *MockRateLimitRepository) TakeToken(ctx context.Context, key string, limit models.RateLimit, now time.Time) (*models.RateLimitResult, error) {
+	args := m.Called(ctx, key, limit, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RateLimitResult), args.Error(1)
+}
+
+func TestRateLimitTake(t *testing.T) {
+	// Arrange
+	service := NewRateLimitService(memory.NewRateLimitRepository(), models.RateLimit{Requests: 5, Period: time.Minute},
+		map[string]models.RateLimit{
+			"POST /api/reports":       {Requests: 1, Period: time.Minute},
+			"POST /api/reports/batch": {}, // Not limited
+		})
+	ctx := context.Background()
+
+	// Act
+	first, err := service.Take(ctx, "api_key:k1", "POST /api/reports")
+	require.NoError(t, err)
+	second, err := service.Take(ctx, "api_key:k1", "POST /api/reports")
+	require.NoError(t, err)
+	otherClient, err := service.Take(ctx, "api_key:k2", "POST /api/reports")
+	require.NoError(t, err)
+	defaultRoute, err := service.Take(ctx, "api_key:k1", "GET /api/reports")
+	require.NoError(t, err)
+	sharedDefault, err := service.Take(ctx, "api_key:k1", "GET /api/tags")
+	require.NoError(t, err)
+	unlimited, err := service.Take(ctx, "api_key:k1", "POST /api/reports/batch")
+	require.NoError(t, err)
+
+	// Assert
+	assert.True(t, first.Allowed)
+	assert.False(t, second.Allowed, "the route has its own limit")
+	assert.True(t, otherClient.Allowed, "clients have their own buckets")
+	assert.True(t, defaultRoute.Allowed)
+	assert.Equal(t, 4, defaultRoute.Remaining)
+	assert.Equal(t, 3, sharedDefault.Remaining, "routes without a limit share the default bucket")
+	assert.True(t, unlimited.Allowed)
+	assert.True(t, unlimited.Limit.IsUnlimited())
+}
+
+func TestRateLimitTake_Error(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRateLimitRepository)
+	limit := models.RateLimit{Requests: 5, Period: time.Minute}
+	service := NewRateLimitService(mockRepo, limit, nil)
+
+	ctx := context.Background()
+	mockRepo.On("TakeToken", ctx, "ip:10.0.0.1 *", limit, mock.AnythingOfType("time.Time")).
+		Return(nil, errors.New("failed to update rate limit: connection refused"))
+
+	// Act
+	result, err := service.Take(ctx, "ip:10.0.0.1", "GET /api/reports")
+
+	// Assert
+	assert.Nil(t, result)
+	require.Error(t, err)
+	assert.Equal(t, "failed to check rate limit: failed to update rate limit: connection refused", err.Error())
+	mockRepo.AssertExpectations(t)
+}
@@ -5,12 +5,27 @@ import (
 	"fmt"
 	"github.com/DangVTNhan/Scanner/be/internal/models/request"
 	"github.com/DangVTNhan/Scanner/be/internal/models/response"
-	"math"
+	"slices"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
+	"github.com/DangVTNhan/Scanner/be/internal/interfaces"
 	"github.com/DangVTNhan/Scanner/be/internal/models"
 	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
 	"github.com/DangVTNhan/Scanner/be/pkg/openweather"
+	"golang.org/x/sync/errgroup"
+)
+
+// BatchConcurrency is the maximum number of reports of a batch that are generated at the same time
+const BatchConcurrency = 4
+
+// Limits of report annotations
+const (
+	MaxReportTags        = 20   // Tags per report
+	MaxTagLength         = 50   // Characters per tag
+	MaxReportNotesLength = 2000 // Characters of report notes
 )
 
 // ReportService handles business logic for weather reports
@@ -18,22 +33,115 @@ type ReportService struct {
 	reportRepository repository.IReportRepository
 	weatherCacheRepo repository.IWeatherCacheRepository
 	weatherService   openweather.IWeatherService
+	thresholdService interfaces.IThresholdService
+	reportQuota      models.ReportQuota
+	reservations     reportReservations
 }
 
 // NewReportService creates a new instance of ReportService
 func NewReportService(
 	reportRepository repository.IReportRepository,
 	weatherCacheRepo repository.IWeatherCacheRepository,
-	weatherService openweather.IWeatherService) *ReportService {
+	weatherService openweather.IWeatherService,
+	thresholdService interfaces.IThresholdService) *ReportService {
 	return &ReportService{
 		reportRepository: reportRepository,
 		weatherCacheRepo: weatherCacheRepo,
 		weatherService:   weatherService,
+		thresholdService: thresholdService,
+	}
+}
+
+// SetReportQuota limits how many reports each tenant may store. Without a quota tenants may store any number.
+func (s *ReportService) SetReportQuota(quota models.ReportQuota) {
+	s.reportQuota = quota
+}
+
+// reportReservations are the reports that each tenant is about to insert
+type reportReservations struct {
+	mu      sync.Mutex
+	tenants map[string]*tenantReservations
+}
+
+// tenantReservations are the reports that a tenant is about to insert, which count towards its quota
+type tenantReservations struct {
+	mu      sync.Mutex // Held while the tenant's reports are counted and reserved
+	pending int64
+}
+
+// forTenant returns the reservations of a tenant
+func (r *reportReservations) forTenant(tenant string) *tenantReservations {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.tenants == nil {
+		r.tenants = make(map[string]*tenantReservations)
 	}
+	if r.tenants[tenant] == nil {
+		r.tenants[tenant] = &tenantReservations{}
+	}
+	return r.tenants[tenant]
+}
+
+// reserveReports reserves n reports of the quota of the tenant of ctx until release is called, which the caller
+// must do exactly once after inserting them or giving up. It returns an error starting with "quota exceeded" if the stored
+// and reserved reports of the tenant leave no room for n more. Counting and reserving happen under a lock of the
+// tenant, so concurrent requests cannot all pass the check and insert beyond the quota. The lock is not shared
+// between instances; see the README on running several replicas.
+func (s *ReportService) reserveReports(ctx context.Context, n int) (release func(), err error) {
+	tenant := models.TenantFromContext(ctx)
+	limit := s.reportQuota.Limit(tenant)
+	if limit == 0 || n <= 0 {
+		return func() {}, nil
+	}
+
+	reservations := s.reservations.forTenant(tenant)
+	reservations.mu.Lock()
+	defer reservations.mu.Unlock()
+
+	count, err := s.reportRepository.CountReports(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve report count: %w", err)
+	}
+	if count+reservations.pending+int64(n) > limit {
+		return nil, fmt.Errorf("quota exceeded: tenant %s may store at most %d reports", tenant, limit)
+	}
+
+	reservations.pending += int64(n)
+	return func() {
+		reservations.mu.Lock()
+		reservations.pending -= int64(n)
+		reservations.mu.Unlock()
+	}, nil
+}
+
+// GetTenantUsage returns how many reports the tenant of ctx stores and its report quota
+func (s *ReportService) GetTenantUsage(ctx context.Context) (*response.TenantUsage, error) {
+	count, err := s.reportRepository.CountReports(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve report count: %w", err)
+	}
+
+	tenant := models.TenantFromContext(ctx)
+	return &response.TenantUsage{
+		Tenant:      tenant,
+		Reports:     count,
+		ReportQuota: s.reportQuota.Limit(tenant),
+	}, nil
 }
 
 // GenerateReport creates a new weather report
 func (s *ReportService) GenerateReport(ctx context.Context, req *request.ReportRequest) (*models.WeatherReport, error) {
+	release, err := s.reserveReports(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return s.generateReport(ctx, req)
+}
+
+// generateReport creates a new weather report, which the caller has reserved in the quota
+func (s *ReportService) generateReport(ctx context.Context, req *request.ReportRequest) (*models.WeatherReport, error) {
 	var timestamp time.Time
 	if req.Timestamp != nil {
 		timestamp = *req.Timestamp
@@ -44,24 +152,22 @@ func (s *ReportService) GenerateReport(ctx context.Context, req *request.ReportR
 	var weatherData *openweather.WeatherData
 	var err error
 
-	// Check if a valid weather cache exists
-	cache, err := s.weatherCacheRepo.FindWeatherCacheByTimestamp(ctx, timestamp, 1)
-	if err == nil && cache != nil {
-		return &models.WeatherReport{
-			Timestamp:   timestamp,
-			Temperature: cache.WeatherData.Temperature,
-			Pressure:    cache.WeatherData.Pressure,
-			Humidity:    cache.WeatherData.Humidity,
-			CloudCover:  cache.WeatherData.CloudCover,
-			CreatedAt:   timestamp,
-			ID:          cache.ID,
-		}, nil
-	}
-	// If timestamp is within the last hour, get current weather
+	// If timestamp is within the last few minutes, get current weather
 	// Otherwise, get historical weather
+	reportType := models.ReportTypeHistorical
 	if time.Since(timestamp) < 10*time.Minute {
+		reportType = models.ReportTypeCurrent
+	}
+
+	// Reuse the weather data of a valid cache entry; the report is stored either way
+	cache, err := s.weatherCacheRepo.FindWeatherCacheByTimestamp(ctx, timestamp, 1)
+	cached := err == nil && cache != nil
+	switch {
+	case cached:
+		weatherData = &cache.WeatherData
+	case reportType == models.ReportTypeCurrent:
 		weatherData, err = s.weatherService.GetCurrentWeather()
-	} else {
+	default:
 		weatherData, err = s.weatherService.GetHistoricalWeather(timestamp)
 	}
 
@@ -75,6 +181,10 @@ func (s *ReportService) GenerateReport(ctx context.Context, req *request.ReportR
 		Pressure:    weatherData.Pressure,
 		Humidity:    weatherData.Humidity,
 		CloudCover:  weatherData.CloudCover,
+		Location:    models.LocationChangi,
+		Source:      models.SourceOpenWeather,
+		Type:        reportType,
+		Tenant:      models.TenantFromContext(ctx),
 		CreatedAt:   time.Now(),
 	}
 
@@ -82,6 +192,10 @@ func (s *ReportService) GenerateReport(ctx context.Context, req *request.ReportR
 	if err != nil {
 		return nil, fmt.Errorf("failed to save report: %w", err)
 	}
+	report.ID = insertedID
+	if cached {
+		return report, nil
+	}
 
 	// Save the weather data to cache
 	now := time.Now()
@@ -96,10 +210,68 @@ func (s *ReportService) GenerateReport(ctx context.Context, req *request.ReportR
 		// TODO: Handle error (e.g., log it)
 	}
 
-	report.ID = insertedID
 	return report, nil
 }
 
+// GenerateReports generates a weather report for each distinct timestamp of a batch, at most
+// BatchConcurrency at a time. A failing timestamp does not stop the others; its error is
+// reported on its item instead. Batches that could exceed the report quota are rejected as a whole.
+func (s *ReportService) GenerateReports(ctx context.Context, req *request.BatchReportRequest) (*response.BatchReportResult, error) {
+	if len(req.Timestamps) == 0 {
+		return nil, fmt.Errorf("invalid batch request: at least one timestamp is required")
+	}
+	if len(req.Timestamps) > request.MaxBatchReports {
+		return nil, fmt.Errorf("invalid batch request: too many timestamps (max %d)", request.MaxBatchReports)
+	}
+
+	// Equal instants are generated once, even if they are written in different time zones
+	var timestamps []time.Time
+	seen := make(map[int64]bool, len(req.Timestamps))
+	for i, timestamp := range req.Timestamps {
+		if timestamp.IsZero() {
+			return nil, fmt.Errorf("invalid batch request: timestamp %d is missing", i)
+		}
+		if !seen[timestamp.UnixNano()] {
+			seen[timestamp.UnixNano()] = true
+			timestamps = append(timestamps, timestamp)
+		}
+	}
+	release, err := s.reserveReports(ctx, len(timestamps))
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	items := make([]response.BatchReportItem, len(timestamps))
+	var group errgroup.Group
+	group.SetLimit(BatchConcurrency)
+	for i, timestamp := range timestamps {
+		group.Go(func() error {
+			items[i] = response.BatchReportItem{Timestamp: timestamp, Status: "success"}
+			report, err := s.generateReport(ctx, &request.ReportRequest{Timestamp: &timestamp})
+			if err != nil {
+				items[i].Status = "error"
+				items[i].Message = err.Error()
+				items[i].Err = err
+				return nil // Keep generating the other reports
+			}
+			items[i].Report = report
+			return nil
+		})
+	}
+	group.Wait() // Items carry their own errors
+
+	result := &response.BatchReportResult{Items: items}
+	for _, item := range items {
+		if item.Err != nil {
+			result.Failed++
+		} else {
+			result.Succeeded++
+		}
+	}
+	return result, nil
+}
+
 // GetAllReports retrieves all weather reports (legacy method, kept for backward compatibility)
 func (s *ReportService) GetAllReports(ctx context.Context) ([]models.WeatherReport, error) {
 	return s.reportRepository.FindAllReports(ctx)
@@ -115,6 +287,85 @@ func (s *ReportService) GetReportByID(ctx context.Context, id string) (*models.W
 	return s.reportRepository.FindReportByID(ctx, id)
 }
 
+// UpdateReport updates the tags and notes of a weather report and returns the updated report.
+// Tags are normalized to lower case and deduplicated.
+func (s *ReportService) UpdateReport(ctx context.Context, id string, req *request.ReportUpdateRequest) (*models.WeatherReport, error) {
+	if req.Tags == nil && req.Notes == nil {
+		return nil, fmt.Errorf("invalid report update: tags or notes are required")
+	}
+
+	update := &request.ReportUpdateRequest{Notes: req.Notes}
+	if req.Tags != nil {
+		tags, err := normalizeTags(*req.Tags)
+		if err != nil {
+			return nil, fmt.Errorf("invalid report update: %w", err)
+		}
+		update.Tags = &tags
+	}
+	if req.Notes != nil && utf8.RuneCountInString(*req.Notes) > MaxReportNotesLength {
+		return nil, fmt.Errorf("invalid report update: notes are longer than %d characters", MaxReportNotesLength)
+	}
+
+	if err := s.reportRepository.UpdateReport(ctx, id, update); err != nil {
+		return nil, err
+	}
+	return s.GetReportByID(ctx, id)
+}
+
+// GetTags returns every tag in use with the number of reports that have it
+func (s *ReportService) GetTags(ctx context.Context) ([]response.TagCount, error) {
+	return s.reportRepository.FindTagCounts(ctx)
+}
+
+// normalizeTags validates tags and returns their normalized form without duplicates, in the given order
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = models.NormalizeTag(tag)
+		if tag == "" {
+			return nil, fmt.Errorf("tags must not be empty")
+		}
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return nil, fmt.Errorf("tag %q is longer than %d characters", tag, MaxTagLength)
+		}
+		if !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+
+	if len(normalized) > MaxReportTags {
+		return nil, fmt.Errorf("too many tags (max %d)", MaxReportTags)
+	}
+	return normalized, nil
+}
+
+// DeleteReport soft-deletes a weather report. It can be restored until it is purged.
+func (s *ReportService) DeleteReport(ctx context.Context, id string) error {
+	return s.reportRepository.DeleteReport(ctx, id, time.Now())
+}
+
+// RestoreReport restores a soft-deleted weather report and returns it
+func (s *ReportService) RestoreReport(ctx context.Context, id string) (*models.WeatherReport, error) {
+	release, err := s.reserveReports(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if err := s.reportRepository.RestoreReport(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.GetReportByID(ctx, id)
+}
+
+// PurgeDeletedReports permanently removes the reports that were deleted longer than retention ago
+func (s *ReportService) PurgeDeletedReports(ctx context.Context, retention time.Duration) (int64, error) {
+	if retention < 0 {
+		return 0, fmt.Errorf("invalid retention: must not be negative")
+	}
+	return s.reportRepository.PurgeDeletedReports(ctx, time.Now().Add(-retention))
+}
+
 // CompareReports compares two weather reports
 func (s *ReportService) CompareReports(ctx context.Context, req *request.ComparisonRequest) (*response.ComparisonResult, error) {
 	report1, err := s.GetReportByID(ctx, req.ReportID1)
@@ -127,14 +378,13 @@ func (s *ReportService) CompareReports(ctx context.Context, req *request.Compari
 		return nil, fmt.Errorf("failed to retrieve second report: %w", err)
 	}
 
-	// Calculate deviations
-	deviation := response.Deviation{
-		Temperature: math.Abs(report2.Temperature - report1.Temperature),
-		Pressure:    math.Abs(report2.Pressure - report1.Pressure),
-		Humidity:    math.Abs(report2.Humidity - report1.Humidity),
-		CloudCover:  math.Abs(report2.CloudCover - report1.CloudCover),
+	thresholds, err := s.thresholdService.GetEffectiveThresholds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load thresholds: %w", err)
 	}
 
+	deviation := calculateDeviation(report1, report2, thresholds)
+
 	result := &response.ComparisonResult{
 		Report1:   *report1,
 		Report2:   *report2,
@@ -143,3 +393,127 @@ func (s *ReportService) CompareReports(ctx context.Context, req *request.Compari
 
 	return result, nil
 }
+
+// CompareMultipleReports compares several weather reports against a baseline.
+// All reports are fetched in a single query.
+func (s *ReportService) CompareMultipleReports(ctx context.Context, req *request.MultiComparisonRequest) (*response.MultiComparisonResult, error) {
+	if len(req.ReportIDs) < 2 {
+		return nil, fmt.Errorf("invalid comparison request: at least two report IDs are required")
+	}
+	if len(req.ReportIDs) > request.MaxComparisonReports {
+		return nil, fmt.Errorf("invalid comparison request: too many reports (max %d)", request.MaxComparisonReports)
+	}
+
+	seen := make(map[string]bool, len(req.ReportIDs))
+	for _, id := range req.ReportIDs {
+		if id == "" {
+			return nil, fmt.Errorf("invalid comparison request: report ID must not be empty")
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("invalid comparison request: duplicate report ID %s", id)
+		}
+		seen[id] = true
+	}
+
+	baselineID := req.BaselineID
+	if baselineID == "" {
+		baselineID = req.ReportIDs[0]
+	} else if !seen[baselineID] {
+		return nil, fmt.Errorf("invalid comparison request: baseline %s is not one of the compared reports", baselineID)
+	}
+
+	found, err := s.reportRepository.FindReportsByIDs(ctx, req.ReportIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve reports: %w", err)
+	}
+
+	byID := make(map[string]models.WeatherReport, len(found))
+	for _, report := range found {
+		byID[report.ID] = report
+	}
+
+	// Restore request order and report every missing ID at once
+	reports := make([]models.WeatherReport, 0, len(req.ReportIDs))
+	var missing []string
+	for _, id := range req.ReportIDs {
+		report, ok := byID[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		reports = append(reports, report)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("report not found: %s", strings.Join(missing, ", "))
+	}
+
+	thresholds, err := s.thresholdService.GetEffectiveThresholds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load thresholds: %w", err)
+	}
+
+	baseline := byID[baselineID]
+	result := &response.MultiComparisonResult{
+		Baseline:   baseline,
+		Reports:    make([]response.ReportDeviation, 0, len(reports)-1),
+		Statistics: calculateStatistics(reports),
+	}
+	for i := range reports {
+		if reports[i].ID == baselineID {
+			continue
+		}
+		result.Reports = append(result.Reports, response.ReportDeviation{
+			Report:    reports[i],
+			Deviation: calculateDeviation(&baseline, &reports[i], thresholds),
+		})
+	}
+
+	return result, nil
+}
+
+// ComparePeriods compares the reports of two time periods by the statistics of every metric.
+// Each period is aggregated by the repository in a single query.
+func (s *ReportService) ComparePeriods(ctx context.Context, req *request.PeriodComparisonRequest) (*response.PeriodComparisonResult, error) {
+	for i, period := range []request.TimeRange{req.Period1, req.Period2} {
+		if period.From.IsZero() || period.To.IsZero() {
+			return nil, fmt.Errorf("invalid period comparison: period%d requires from and to", i+1)
+		}
+		if period.To.Before(period.From) {
+			return nil, fmt.Errorf("invalid period comparison: period%d ends before it starts", i+1)
+		}
+	}
+
+	stats1, err := s.reportRepository.AggregateReports(ctx, req.Period1.From, req.Period1.To)
+	if err != nil {
+		return nil, err
+	}
+	if stats1.Count == 0 {
+		return nil, fmt.Errorf("no reports found in period1")
+	}
+
+	stats2, err := s.reportRepository.AggregateReports(ctx, req.Period2.From, req.Period2.To)
+	if err != nil {
+		return nil, err
+	}
+	if stats2.Count == 0 {
+		return nil, fmt.Errorf("no reports found in period2")
+	}
+
+	thresholds, err := s.thresholdService.GetEffectiveThresholds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load thresholds: %w", err)
+	}
+
+	means1, means2 := periodMeans(stats1), periodMeans(stats2)
+	result := &response.PeriodComparisonResult{
+		Period1:   *stats1,
+		Period2:   *stats2,
+		Deviation: calculateDeviation(&means1, &means2, thresholds),
+		Metrics:   make(map[models.Metric]response.PeriodMetricComparison, len(models.Metrics)),
+	}
+	for _, metric := range models.Metrics {
+		result.Metrics[metric] = comparePeriodMetric(stats1.Count, stats1.Metrics[metric], stats2.Count, stats2.Metrics[metric])
+	}
+
+	return result, nil
+}
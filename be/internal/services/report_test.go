@@ -3,15 +3,20 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository/memory"
 	"github.com/DangVTNhan/Scanner/be/internal/models/request"
 	"github.com/DangVTNhan/Scanner/be/internal/models/response"
 	"github.com/DangVTNhan/Scanner/be/pkg/openweather"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // Mock implementations of the dependencies
@@ -26,6 +31,14 @@ func (m *MockReportRepository) InsertReport(ctx context.Context, report *models.
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockReportRepository) InsertReports(ctx context.Context, reports []models.WeatherReport) ([]string, error) {
+	args := m.Called(ctx, reports)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func (m *MockReportRepository) FindAllReports(ctx context.Context) ([]models.WeatherReport, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]models.WeatherReport), args.Error(1)
@@ -39,6 +52,19 @@ func (m *MockReportRepository) FindPaginatedReports(ctx context.Context, req *re
 	return args.Get(0).(*response.PaginatedReportsResponse), args.Error(1)
 }
 
+// StreamReports passes the mocked reports to fn, then returns the mocked error
+func (m *MockReportRepository) StreamReports(ctx context.Context, req *request.PaginatedReportsRequest, fn func(*models.WeatherReport) error) error {
+	args := m.Called(ctx, req)
+	if reports, ok := args.Get(0).([]models.WeatherReport); ok {
+		for i := range reports {
+			if err := fn(&reports[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
 func (m *MockReportRepository) FindReportByID(ctx context.Context, id string) (*models.WeatherReport, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -47,11 +73,76 @@ func (m *MockReportRepository) FindReportByID(ctx context.Context, id string) (*
 	return args.Get(0).(*models.WeatherReport), args.Error(1)
 }
 
+func (m *MockReportRepository) FindReportsByIDs(ctx context.Context, ids []string) ([]models.WeatherReport, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WeatherReport), args.Error(1)
+}
+
+func (m *MockReportRepository) FindExistingTimestamps(ctx context.Context, timestamps []time.Time) ([]time.Time, error) {
+	args := m.Called(ctx, timestamps)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]time.Time), args.Error(1)
+}
+
+func (m *MockReportRepository) AggregateReports(ctx context.Context, from, to time.Time) (*response.PeriodStatistics, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.PeriodStatistics), args.Error(1)
+}
+
+func (m *MockReportRepository) AggregateReportBuckets(ctx context.Context, from, to time.Time, bucket time.Duration) ([]response.PeriodStatistics, error) {
+	args := m.Called(ctx, from, to, bucket)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]response.PeriodStatistics), args.Error(1)
+}
+
 func (m *MockReportRepository) CountReports(ctx context.Context) (int64, error) {
 	args := m.Called(ctx)
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockReportRepository) UpdateReport(ctx context.Context, id string, update *request.ReportUpdateRequest) error {
+	args := m.Called(ctx, id, update)
+	return args.Error(0)
+}
+
+func (m *MockReportRepository) FindTagCounts(ctx context.Context) ([]response.TagCount, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]response.TagCount), args.Error(1)
+}
+
+func (m *MockReportRepository) DeleteReport(ctx context.Context, id string, deletedAt time.Time) error {
+	args := m.Called(ctx, id, deletedAt)
+	return args.Error(0)
+}
+
+func (m *MockReportRepository) RestoreReport(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockReportRepository) PurgeDeletedReports(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// newTestThresholdService returns a ThresholdService without configured thresholds, so the defaults apply
+func newTestThresholdService() *ThresholdService {
+	return NewThresholdService(memory.NewThresholdRepository())
+}
+
 // MockWeatherCacheRepository is a mock implementation of IWeatherCacheRepository
 type MockWeatherCacheRepository struct {
 	mock.Mock
@@ -83,6 +174,19 @@ func (m *MockWeatherCacheRepository) DeleteExpiredCaches(ctx context.Context) er
 	return args.Error(0)
 }
 
+// StreamWeatherCaches passes the mocked cache entries to fn, then returns the mocked error
+func (m *MockWeatherCacheRepository) StreamWeatherCaches(ctx context.Context, from, to time.Time, fn func(*models.WeatherCache) error) error {
+	args := m.Called(ctx, from, to)
+	if caches, ok := args.Get(0).([]models.WeatherCache); ok {
+		for i := range caches {
+			if err := fn(&caches[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
 // MockWeatherService is a mock implementation of IWeatherService
 type MockWeatherService struct {
 	mock.Mock
@@ -113,10 +217,12 @@ func TestNewReportService(t *testing.T) {
 	mockWeatherService := new(MockWeatherService)
 
 	// Act
-	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService)
+	thresholdService := newTestThresholdService()
+	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService, thresholdService)
 
 	// Assert
 	assert.NotNil(t, service)
+	assert.Equal(t, thresholdService, service.thresholdService)
 	assert.Equal(t, mockReportRepo, service.reportRepository)
 	assert.Equal(t, mockWeatherCacheRepo, service.weatherCacheRepo)
 	assert.Equal(t, mockWeatherService, service.weatherService)
@@ -127,7 +233,7 @@ func TestGenerateReport_WithTimestamp(t *testing.T) {
 	mockReportRepo := new(MockReportRepository)
 	mockWeatherCacheRepo := new(MockWeatherCacheRepository)
 	mockWeatherService := new(MockWeatherService)
-	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService)
+	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService, newTestThresholdService())
 
 	ctx := context.Background()
 	timestamp := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
@@ -166,6 +272,9 @@ func TestGenerateReport_WithTimestamp(t *testing.T) {
 	assert.Equal(t, weatherData.Pressure, report.Pressure)
 	assert.Equal(t, weatherData.Humidity, report.Humidity)
 	assert.Equal(t, weatherData.CloudCover, report.CloudCover)
+	assert.Equal(t, models.LocationChangi, report.Location)
+	assert.Equal(t, models.SourceOpenWeather, report.Source)
+	assert.Equal(t, models.ReportTypeHistorical, report.Type)
 
 	mockWeatherCacheRepo.AssertExpectations(t)
 	mockWeatherService.AssertExpectations(t)
@@ -177,7 +286,7 @@ func TestGenerateReport_WithoutTimestamp(t *testing.T) {
 	mockReportRepo := new(MockReportRepository)
 	mockWeatherCacheRepo := new(MockWeatherCacheRepository)
 	mockWeatherService := new(MockWeatherService)
-	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService)
+	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService, newTestThresholdService())
 
 	ctx := context.Background()
 	req := &request.ReportRequest{
@@ -214,6 +323,7 @@ func TestGenerateReport_WithoutTimestamp(t *testing.T) {
 	assert.Equal(t, weatherData.Pressure, report.Pressure)
 	assert.Equal(t, weatherData.Humidity, report.Humidity)
 	assert.Equal(t, weatherData.CloudCover, report.CloudCover)
+	assert.Equal(t, models.ReportTypeCurrent, report.Type)
 
 	mockWeatherCacheRepo.AssertExpectations(t)
 	mockWeatherService.AssertExpectations(t)
@@ -225,9 +335,9 @@ func TestGenerateReport_WithCache(t *testing.T) {
 	mockReportRepo := new(MockReportRepository)
 	mockWeatherCacheRepo := new(MockWeatherCacheRepository)
 	mockWeatherService := new(MockWeatherService)
-	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService)
+	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService, newTestThresholdService())
 
-	ctx := context.Background()
+	ctx := models.ContextWithTenant(context.Background(), "changi-ops")
 	timestamp := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
 	req := &request.ReportRequest{
 		Timestamp: &timestamp,
@@ -248,6 +358,7 @@ func TestGenerateReport_WithCache(t *testing.T) {
 		CreatedAt:   timestamp,
 	}
 	mockWeatherCacheRepo.On("FindWeatherCacheByTimestamp", ctx, timestamp, []int{1}).Return(cache, nil)
+	mockReportRepo.On("InsertReport", ctx, mock.AnythingOfType("*models.WeatherReport")).Return("report123", nil)
 
 	// Act
 	report, err := service.GenerateReport(ctx, req)
@@ -255,7 +366,8 @@ func TestGenerateReport_WithCache(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	assert.NotNil(t, report)
-	assert.Equal(t, cacheID, report.ID)
+	assert.Equal(t, "report123", report.ID)
+	assert.Equal(t, "changi-ops", report.Tenant)
 	assert.Equal(t, timestamp, report.Timestamp)
 	assert.Equal(t, weatherData.Temperature, report.Temperature)
 	assert.Equal(t, weatherData.Pressure, report.Pressure)
@@ -263,10 +375,11 @@ func TestGenerateReport_WithCache(t *testing.T) {
 	assert.Equal(t, weatherData.CloudCover, report.CloudCover)
 
 	mockWeatherCacheRepo.AssertExpectations(t)
-	// Weather service and report repository should not be called
+	mockReportRepo.AssertExpectations(t)
+	// The weather service is not called, and the cached data is not cached again
 	mockWeatherService.AssertNotCalled(t, "GetCurrentWeather")
-	mockWeatherService.AssertNotCalled(t, "GetHistoricalWeather")
-	mockReportRepo.AssertNotCalled(t, "InsertReport")
+	mockWeatherService.AssertNotCalled(t, "GetHistoricalWeather", mock.Anything)
+	mockWeatherCacheRepo.AssertNotCalled(t, "SaveWeatherCache", mock.Anything, mock.Anything)
 }
 
 func TestGenerateReport_WeatherServiceError(t *testing.T) {
@@ -274,7 +387,7 @@ func TestGenerateReport_WeatherServiceError(t *testing.T) {
 	mockReportRepo := new(MockReportRepository)
 	mockWeatherCacheRepo := new(MockWeatherCacheRepository)
 	mockWeatherService := new(MockWeatherService)
-	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService)
+	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService, newTestThresholdService())
 
 	ctx := context.Background()
 	timestamp := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
@@ -309,7 +422,7 @@ func TestGenerateReport_ReportRepositoryError(t *testing.T) {
 	mockReportRepo := new(MockReportRepository)
 	mockWeatherCacheRepo := new(MockWeatherCacheRepository)
 	mockWeatherService := new(MockWeatherService)
-	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService)
+	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService, newTestThresholdService())
 
 	ctx := context.Background()
 	timestamp := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
@@ -347,12 +460,209 @@ func TestGenerateReport_ReportRepositoryError(t *testing.T) {
 	mockReportRepo.AssertExpectations(t)
 }
 
+func TestGenerateReport_QuotaExceeded(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	mockWeatherCacheRepo := new(MockWeatherCacheRepository)
+	mockWeatherService := new(MockWeatherService)
+	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService, newTestThresholdService())
+	service.SetReportQuota(models.ReportQuota{Default: 100, Tenants: map[string]int64{"changi-ops": 2}})
+
+	ctx := models.ContextWithTenant(context.Background(), "changi-ops")
+	timestamp := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	mockReportRepo.On("CountReports", ctx).Return(int64(2), nil)
+
+	// Act
+	report, err := service.GenerateReport(ctx, &request.ReportRequest{Timestamp: &timestamp})
+
+	// Assert
+	assert.Nil(t, report)
+	require.Error(t, err)
+	assert.Equal(t, "quota exceeded: tenant changi-ops may store at most 2 reports", err.Error())
+	mockWeatherCacheRepo.AssertNotCalled(t, "FindWeatherCacheByTimestamp", mock.Anything, mock.Anything, mock.Anything)
+	mockWeatherService.AssertNotCalled(t, "GetHistoricalWeather", mock.Anything)
+	mockReportRepo.AssertNotCalled(t, "InsertReport", mock.Anything, mock.Anything)
+}
+
+func TestGenerateReport_QuotaHoldsForConcurrentRequests(t *testing.T) {
+	// Arrange
+	reportRepo := memory.NewReportRepository()
+	mockWeatherCacheRepo := new(MockWeatherCacheRepository)
+	mockWeatherService := new(MockWeatherService)
+	service := NewReportService(reportRepo, mockWeatherCacheRepo, mockWeatherService, newTestThresholdService())
+	service.SetReportQuota(models.ReportQuota{Default: 5})
+
+	ctx := context.Background()
+	timestamp := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	weatherData := &openweather.WeatherData{Temperature: 25.5, Pressure: 1013.2, Humidity: 60.0, CloudCover: 30.0}
+	mockWeatherCacheRepo.On("FindWeatherCacheByTimestamp", ctx, timestamp, []int{1}).Return(nil, nil)
+	mockWeatherCacheRepo.On("SaveWeatherCache", ctx, mock.AnythingOfType("*models.WeatherCache")).Return("cache1", nil)
+	// Slow weather calls keep the requests in flight at the same time
+	mockWeatherService.On("GetHistoricalWeather", timestamp).Return(weatherData, nil).After(20 * time.Millisecond)
+
+	// Act
+	var wg sync.WaitGroup
+	errs := make([]error, 20)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = service.GenerateReport(ctx, &request.ReportRequest{Timestamp: &timestamp})
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		} else {
+			assert.True(t, strings.HasPrefix(err.Error(), "quota exceeded"), err.Error())
+		}
+	}
+	assert.Equal(t, 5, succeeded)
+	count, err := reportRepo.CountReports(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), count)
+}
+
+func TestGenerateReport_QuotaReleasedOnFailure(t *testing.T) {
+	// Arrange
+	reportRepo := memory.NewReportRepository()
+	mockWeatherCacheRepo := new(MockWeatherCacheRepository)
+	mockWeatherService := new(MockWeatherService)
+	service := NewReportService(reportRepo, mockWeatherCacheRepo, mockWeatherService, newTestThresholdService())
+	service.SetReportQuota(models.ReportQuota{Default: 1})
+
+	ctx := context.Background()
+	timestamp := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	weatherData := &openweather.WeatherData{Temperature: 25.5, Pressure: 1013.2, Humidity: 60.0, CloudCover: 30.0}
+	mockWeatherCacheRepo.On("FindWeatherCacheByTimestamp", ctx, timestamp, []int{1}).Return(nil, nil)
+	mockWeatherCacheRepo.On("SaveWeatherCache", ctx, mock.AnythingOfType("*models.WeatherCache")).Return("cache1", nil)
+	mockWeatherService.On("GetHistoricalWeather", timestamp).Return(nil, errors.New("weather service error")).Once()
+	mockWeatherService.On("GetHistoricalWeather", timestamp).Return(weatherData, nil).Once()
+
+	_, err := service.GenerateReport(ctx, &request.ReportRequest{Timestamp: &timestamp})
+	require.Error(t, err)
+
+	// Act
+	report, err := service.GenerateReport(ctx, &request.ReportRequest{Timestamp: &timestamp})
+
+	// Assert
+	require.NoError(t, err)
+	assert.NotEmpty(t, report.ID)
+	mockWeatherService.AssertExpectations(t)
+}
+
+func TestGenerateReports(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	mockWeatherCacheRepo := new(MockWeatherCacheRepository)
+	mockWeatherService := new(MockWeatherService)
+	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService, newTestThresholdService())
+
+	ctx := context.Background()
+	timestamp1 := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	timestamp2 := time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC)
+	req := &request.BatchReportRequest{
+		// The last timestamp is the same instant as the first in another time zone
+		Timestamps: []time.Time{timestamp1, timestamp2, timestamp1.In(time.FixedZone("SGT", 8*60*60))},
+	}
+
+	weatherData := &openweather.WeatherData{Temperature: 25.5, Pressure: 1013.2, Humidity: 60.0, CloudCover: 30.0}
+	mockWeatherCacheRepo.On("FindWeatherCacheByTimestamp", ctx, mock.AnythingOfType("time.Time"), []int{1}).Return(nil, nil)
+	mockWeatherService.On("GetHistoricalWeather", timestamp1).Return(weatherData, nil).Once()
+	mockWeatherService.On("GetHistoricalWeather", timestamp2).Return(nil, errors.New("weather service error")).Once()
+	mockReportRepo.On("InsertReport", ctx, mock.AnythingOfType("*models.WeatherReport")).Return("report1", nil).Once()
+	mockWeatherCacheRepo.On("SaveWeatherCache", ctx, mock.AnythingOfType("*models.WeatherCache")).Return("cache1", nil)
+
+	// Act
+	result, err := service.GenerateReports(ctx, req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, result.Items, 2)
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Equal(t, 1, result.Failed)
+
+	assert.Equal(t, timestamp1, result.Items[0].Timestamp)
+	assert.Equal(t, "success", result.Items[0].Status)
+	assert.Equal(t, "report1", result.Items[0].Report.ID)
+	assert.NoError(t, result.Items[0].Err)
+
+	assert.Equal(t, timestamp2, result.Items[1].Timestamp)
+	assert.Equal(t, "error", result.Items[1].Status)
+	assert.Nil(t, result.Items[1].Report)
+	assert.Contains(t, result.Items[1].Message, "failed to get weather data")
+	assert.Error(t, result.Items[1].Err)
+
+	mockWeatherService.AssertExpectations(t)
+	mockReportRepo.AssertExpectations(t)
+}
+
+func TestGenerateReports_QuotaExceeded(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	service := NewReportService(mockReportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), newTestThresholdService())
+	service.SetReportQuota(models.ReportQuota{Default: 10})
+
+	ctx := context.Background()
+	timestamps := []time.Time{
+		time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+		time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC),
+	}
+	mockReportRepo.On("CountReports", ctx).Return(int64(9), nil)
+
+	// Act
+	result, err := service.GenerateReports(ctx, &request.BatchReportRequest{Timestamps: timestamps})
+
+	// Assert
+	assert.Nil(t, result)
+	require.Error(t, err)
+	assert.Equal(t, "quota exceeded: tenant default may store at most 10 reports", err.Error())
+	mockReportRepo.AssertExpectations(t)
+}
+
+func TestGenerateReports_InvalidRequest(t *testing.T) {
+	tooMany := make([]time.Time, request.MaxBatchReports+1)
+	for i := range tooMany {
+		tooMany[i] = time.Date(2023, 1, 1, i%24, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name       string
+		timestamps []time.Time
+	}{
+		{"no timestamps", nil},
+		{"too many timestamps", tooMany},
+		{"missing timestamp", []time.Time{time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC), {}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockWeatherService := new(MockWeatherService)
+			service := NewReportService(new(MockReportRepository), new(MockWeatherCacheRepository), mockWeatherService, newTestThresholdService())
+
+			// Act
+			result, err := service.GenerateReports(context.Background(), &request.BatchReportRequest{Timestamps: tt.timestamps})
+
+			// Assert
+			assert.Nil(t, result)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid batch request")
+			mockWeatherService.AssertNotCalled(t, "GetHistoricalWeather", mock.Anything)
+		})
+	}
+}
+
 func TestGetAllReports(t *testing.T) {
 	// Arrange
 	mockReportRepo := new(MockReportRepository)
 	mockWeatherCacheRepo := new(MockWeatherCacheRepository)
 	mockWeatherService := new(MockWeatherService)
-	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService)
+	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService, newTestThresholdService())
 
 	ctx := context.Background()
 	expectedReports := []models.WeatherReport{
@@ -392,7 +702,7 @@ func TestGetPaginatedReports(t *testing.T) {
 	mockReportRepo := new(MockReportRepository)
 	mockWeatherCacheRepo := new(MockWeatherCacheRepository)
 	mockWeatherService := new(MockWeatherService)
-	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService)
+	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService, newTestThresholdService())
 
 	ctx := context.Background()
 	req := &request.PaginatedReportsRequest{
@@ -431,7 +741,7 @@ func TestGetReportByID(t *testing.T) {
 	mockReportRepo := new(MockReportRepository)
 	mockWeatherCacheRepo := new(MockWeatherCacheRepository)
 	mockWeatherService := new(MockWeatherService)
-	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService)
+	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService, newTestThresholdService())
 
 	ctx := context.Background()
 	reportID := "report1"
@@ -456,12 +766,220 @@ func TestGetReportByID(t *testing.T) {
 	mockReportRepo.AssertExpectations(t)
 }
 
+func TestUpdateReport(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	service := NewReportService(mockReportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), newTestThresholdService())
+
+	ctx := context.Background()
+	tags := []string{" Runway Closure", "sensor check", "runway closure "}
+	updatedReport := &models.WeatherReport{ID: "report1", Tags: []string{"runway closure", "sensor check"}}
+
+	// Tags are normalized and deduplicated, notes are left unchanged
+	mockReportRepo.On("UpdateReport", ctx, "report1", mock.MatchedBy(func(update *request.ReportUpdateRequest) bool {
+		return update.Notes == nil && assert.ObjectsAreEqual([]string{"runway closure", "sensor check"}, *update.Tags)
+	})).Return(nil)
+	mockReportRepo.On("FindReportByID", ctx, "report1").Return(updatedReport, nil)
+
+	// Act
+	report, err := service.UpdateReport(ctx, "report1", &request.ReportUpdateRequest{Tags: &tags})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, updatedReport, report)
+	mockReportRepo.AssertExpectations(t)
+}
+
+func TestUpdateReport_Invalid(t *testing.T) {
+	blank := []string{"ok", "  "}
+	tooLong := []string{strings.Repeat("a", MaxTagLength+1)}
+	tooMany := make([]string, MaxReportTags+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("tag%d", i)
+	}
+	longNotes := strings.Repeat("n", MaxReportNotesLength+1)
+
+	tests := []struct {
+		name string
+		req  request.ReportUpdateRequest
+	}{
+		{"nothing to update", request.ReportUpdateRequest{}},
+		{"blank tag", request.ReportUpdateRequest{Tags: &blank}},
+		{"tag too long", request.ReportUpdateRequest{Tags: &tooLong}},
+		{"too many tags", request.ReportUpdateRequest{Tags: &tooMany}},
+		{"notes too long", request.ReportUpdateRequest{Notes: &longNotes}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockReportRepo := new(MockReportRepository)
+			service := NewReportService(mockReportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), newTestThresholdService())
+
+			// Act
+			report, err := service.UpdateReport(context.Background(), "report1", &tt.req)
+
+			// Assert
+			assert.Nil(t, report)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid report update")
+			mockReportRepo.AssertNotCalled(t, "UpdateReport", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUpdateReport_NotFound(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	service := NewReportService(mockReportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), newTestThresholdService())
+
+	ctx := context.Background()
+	notes := "sensor recalibrated"
+	mockReportRepo.On("UpdateReport", ctx, "missing", mock.Anything).Return(errors.New("report not found"))
+
+	// Act
+	report, err := service.UpdateReport(ctx, "missing", &request.ReportUpdateRequest{Notes: &notes})
+
+	// Assert
+	assert.Nil(t, report)
+	assert.Error(t, err)
+	assert.Equal(t, "report not found", err.Error())
+	mockReportRepo.AssertExpectations(t)
+}
+
+func TestDeleteReport(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	service := NewReportService(mockReportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), newTestThresholdService())
+
+	ctx := context.Background()
+	mockReportRepo.On("DeleteReport", ctx, "report1", mock.MatchedBy(func(deletedAt time.Time) bool {
+		return time.Since(deletedAt) < time.Second
+	})).Return(nil)
+
+	// Act
+	err := service.DeleteReport(ctx, "report1")
+
+	// Assert
+	assert.NoError(t, err)
+	mockReportRepo.AssertExpectations(t)
+}
+
+func TestRestoreReport(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	service := NewReportService(mockReportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), newTestThresholdService())
+
+	ctx := context.Background()
+	expectedReport := &models.WeatherReport{ID: "report1", Temperature: 25.5}
+	mockReportRepo.On("RestoreReport", ctx, "report1").Return(nil)
+	mockReportRepo.On("FindReportByID", ctx, "report1").Return(expectedReport, nil)
+
+	// Act
+	report, err := service.RestoreReport(ctx, "report1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expectedReport, report)
+	mockReportRepo.AssertExpectations(t)
+}
+
+func TestRestoreReport_NotDeleted(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	service := NewReportService(mockReportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), newTestThresholdService())
+
+	ctx := context.Background()
+	mockReportRepo.On("RestoreReport", ctx, "report1").Return(errors.New("deleted report not found"))
+
+	// Act
+	report, err := service.RestoreReport(ctx, "report1")
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, report)
+	assert.Equal(t, "deleted report not found", err.Error())
+	mockReportRepo.AssertNotCalled(t, "FindReportByID", mock.Anything, mock.Anything)
+}
+
+func TestRestoreReport_QuotaExceeded(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	service := NewReportService(mockReportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), newTestThresholdService())
+	service.SetReportQuota(models.ReportQuota{Default: 5})
+
+	ctx := context.Background()
+	mockReportRepo.On("CountReports", ctx).Return(int64(5), nil)
+
+	// Act
+	report, err := service.RestoreReport(ctx, "report1")
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, report)
+	assert.True(t, strings.HasPrefix(err.Error(), "quota exceeded"), err.Error())
+	mockReportRepo.AssertNotCalled(t, "RestoreReport", mock.Anything, mock.Anything)
+}
+
+func TestGetTenantUsage(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	service := NewReportService(mockReportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), newTestThresholdService())
+	service.SetReportQuota(models.ReportQuota{Default: 1000, Tenants: map[string]int64{"seletar-ops": 0}})
+
+	ctx := models.ContextWithTenant(context.Background(), "seletar-ops")
+	mockReportRepo.On("CountReports", ctx).Return(int64(42), nil)
+
+	// Act
+	usage, err := service.GetTenantUsage(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, &response.TenantUsage{Tenant: "seletar-ops", Reports: 42, ReportQuota: 0}, usage)
+	mockReportRepo.AssertExpectations(t)
+}
+
+func TestPurgeDeletedReports(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	service := NewReportService(mockReportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), newTestThresholdService())
+
+	ctx := context.Background()
+	retention := 7 * 24 * time.Hour
+	mockReportRepo.On("PurgeDeletedReports", ctx, mock.MatchedBy(func(before time.Time) bool {
+		return time.Since(before.Add(retention)) < time.Second
+	})).Return(int64(2), nil)
+
+	// Act
+	purged, err := NewPurgeJob(service, retention, time.Hour).RunOnce(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+	mockReportRepo.AssertExpectations(t)
+}
+
+func TestPurgeDeletedReports_NegativeRetention(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	service := NewReportService(mockReportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), newTestThresholdService())
+
+	// Act
+	purged, err := service.PurgeDeletedReports(context.Background(), -time.Hour)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, int64(0), purged)
+	assert.Contains(t, err.Error(), "invalid retention")
+	mockReportRepo.AssertNotCalled(t, "PurgeDeletedReports", mock.Anything, mock.Anything)
+}
+
 func TestCompareReports(t *testing.T) {
 	// Arrange
 	mockReportRepo := new(MockReportRepository)
 	mockWeatherCacheRepo := new(MockWeatherCacheRepository)
 	mockWeatherService := new(MockWeatherService)
-	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService)
+	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService, newTestThresholdService())
 
 	ctx := context.Background()
 	req := &request.ComparisonRequest{
@@ -504,6 +1022,15 @@ func TestCompareReports(t *testing.T) {
 	assert.Equal(t, 1.0, result.Deviation.Pressure)
 	assert.Equal(t, 5.0, result.Deviation.Humidity)
 	assert.Equal(t, 5.0, result.Deviation.CloudCover)
+	assert.Equal(t, 24.0, result.Deviation.HoursElapsed)
+
+	temperature := result.Deviation.Metrics[models.MetricTemperature]
+	assert.Equal(t, 1.0, temperature.Delta)
+	assert.Equal(t, response.DirectionIncrease, temperature.Direction)
+	assert.InDelta(t, 3.922, *temperature.PercentChange, 0.001)
+	assert.InDelta(t, 0.0417, *temperature.RatePerHour, 0.0001)
+	assert.Equal(t, models.SeverityNormal, temperature.Severity)
+	assert.Equal(t, models.SeverityNormal, result.Deviation.Verdict)
 	mockReportRepo.AssertExpectations(t)
 }
 
@@ -512,7 +1039,7 @@ func TestCompareReports_FirstReportNotFound(t *testing.T) {
 	mockReportRepo := new(MockReportRepository)
 	mockWeatherCacheRepo := new(MockWeatherCacheRepository)
 	mockWeatherService := new(MockWeatherService)
-	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService)
+	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService, newTestThresholdService())
 
 	ctx := context.Background()
 	req := &request.ComparisonRequest{
@@ -539,7 +1066,7 @@ func TestCompareReports_SecondReportNotFound(t *testing.T) {
 	mockReportRepo := new(MockReportRepository)
 	mockWeatherCacheRepo := new(MockWeatherCacheRepository)
 	mockWeatherService := new(MockWeatherService)
-	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService)
+	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService, newTestThresholdService())
 
 	ctx := context.Background()
 	req := &request.ComparisonRequest{
@@ -571,3 +1098,232 @@ func TestCompareReports_SecondReportNotFound(t *testing.T) {
 	assert.Contains(t, err.Error(), expectedErr.Error())
 	mockReportRepo.AssertExpectations(t)
 }
+
+func TestCompareMultipleReports(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	mockWeatherCacheRepo := new(MockWeatherCacheRepository)
+	mockWeatherService := new(MockWeatherService)
+	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService, newTestThresholdService())
+
+	ctx := context.Background()
+	req := &request.MultiComparisonRequest{
+		ReportIDs:  []string{"report1", "report2", "report3"},
+		BaselineID: "report2",
+	}
+
+	reports := []models.WeatherReport{
+		{ID: "report3", Temperature: 30.0, Pressure: 1010.0, Humidity: 70.0, CloudCover: 10.0},
+		{ID: "report1", Temperature: 24.0, Pressure: 1014.0, Humidity: 60.0, CloudCover: 30.0},
+		{ID: "report2", Temperature: 27.0, Pressure: 1012.0, Humidity: 65.0, CloudCover: 20.0},
+	}
+	mockReportRepo.On("FindReportsByIDs", ctx, req.ReportIDs).Return(reports, nil)
+
+	// Act
+	result, err := service.CompareMultipleReports(ctx, req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "report2", result.Baseline.ID)
+	assert.Len(t, result.Reports, 2)
+	assert.Equal(t, "report1", result.Reports[0].Report.ID)
+	assert.Equal(t, 3.0, result.Reports[0].Deviation.Temperature)
+	assert.Equal(t, "report3", result.Reports[1].Report.ID)
+	assert.Equal(t, 3.0, result.Reports[1].Deviation.Temperature)
+	assert.Equal(t, 2.0, result.Reports[1].Deviation.Pressure)
+
+	temperature := result.Statistics[models.MetricTemperature]
+	assert.Equal(t, 24.0, temperature.Min)
+	assert.Equal(t, 30.0, temperature.Max)
+	assert.Equal(t, 27.0, temperature.Mean)
+	assert.Equal(t, 6.0, temperature.Range)
+	assert.InDelta(t, 2.449, temperature.StdDev, 0.001)
+	mockReportRepo.AssertExpectations(t)
+}
+
+func TestCompareMultipleReports_DefaultBaseline(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	mockWeatherCacheRepo := new(MockWeatherCacheRepository)
+	mockWeatherService := new(MockWeatherService)
+	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService, newTestThresholdService())
+
+	ctx := context.Background()
+	req := &request.MultiComparisonRequest{ReportIDs: []string{"report1", "report2"}}
+
+	reports := []models.WeatherReport{
+		{ID: "report1", Humidity: 60.0},
+		{ID: "report2", Humidity: 65.0},
+	}
+	mockReportRepo.On("FindReportsByIDs", ctx, req.ReportIDs).Return(reports, nil)
+
+	// Act
+	result, err := service.CompareMultipleReports(ctx, req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "report1", result.Baseline.ID)
+	assert.Len(t, result.Reports, 1)
+	assert.Equal(t, 5.0, result.Reports[0].Deviation.Humidity)
+	mockReportRepo.AssertExpectations(t)
+}
+
+func TestCompareMultipleReports_ReportsNotFound(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	mockWeatherCacheRepo := new(MockWeatherCacheRepository)
+	mockWeatherService := new(MockWeatherService)
+	service := NewReportService(mockReportRepo, mockWeatherCacheRepo, mockWeatherService, newTestThresholdService())
+
+	ctx := context.Background()
+	req := &request.MultiComparisonRequest{ReportIDs: []string{"report1", "report2", "report3"}}
+
+	mockReportRepo.On("FindReportsByIDs", ctx, req.ReportIDs).Return([]models.WeatherReport{{ID: "report2"}}, nil)
+
+	// Act
+	result, err := service.CompareMultipleReports(ctx, req)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, "report not found: report1, report3", err.Error())
+	mockReportRepo.AssertExpectations(t)
+}
+
+func TestCompareMultipleReports_InvalidRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		req  request.MultiComparisonRequest
+	}{
+		{"single report", request.MultiComparisonRequest{ReportIDs: []string{"report1"}}},
+		{"duplicate report", request.MultiComparisonRequest{ReportIDs: []string{"report1", "report1"}}},
+		{"empty report ID", request.MultiComparisonRequest{ReportIDs: []string{"report1", ""}}},
+		{"unknown baseline", request.MultiComparisonRequest{ReportIDs: []string{"report1", "report2"}, BaselineID: "report3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockReportRepo := new(MockReportRepository)
+			service := NewReportService(mockReportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), newTestThresholdService())
+
+			// Act
+			result, err := service.CompareMultipleReports(context.Background(), &tt.req)
+
+			// Assert
+			assert.Error(t, err)
+			assert.Nil(t, result)
+			assert.Contains(t, err.Error(), "invalid comparison request")
+			mockReportRepo.AssertNotCalled(t, "FindReportsByIDs", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestComparePeriods(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	service := NewReportService(mockReportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), newTestThresholdService())
+
+	ctx := context.Background()
+	lastYear := request.TimeRange{
+		From: time.Date(2023, 3, 4, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2023, 3, 10, 23, 59, 59, 0, time.UTC),
+	}
+	thisWeek := request.TimeRange{
+		From: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 3, 8, 23, 59, 59, 0, time.UTC),
+	}
+	req := &request.PeriodComparisonRequest{Period1: lastYear, Period2: thisWeek}
+
+	stats := func(period request.TimeRange, count int, temperature response.MetricStatistics) *response.PeriodStatistics {
+		return &response.PeriodStatistics{
+			From:  period.From,
+			To:    period.To,
+			Count: count,
+			Metrics: map[models.Metric]response.MetricStatistics{
+				models.MetricTemperature: temperature,
+				models.MetricPressure:    {Mean: 1010, Min: 1010, Max: 1010},
+				models.MetricHumidity:    {Mean: 70, Min: 60, Max: 80, StdDev: 5},
+				models.MetricCloudCover:  {Mean: 40, Min: 20, Max: 60, StdDev: 10},
+			},
+		}
+	}
+	mockReportRepo.On("AggregateReports", ctx, lastYear.From, lastYear.To).
+		Return(stats(lastYear, 50, response.MetricStatistics{Mean: 27, Min: 24, Max: 31, StdDev: 1}), nil)
+	mockReportRepo.On("AggregateReports", ctx, thisWeek.From, thisWeek.To).
+		Return(stats(thisWeek, 50, response.MetricStatistics{Mean: 28.5, Min: 25, Max: 33, StdDev: 1}), nil)
+
+	// Act
+	result, err := service.ComparePeriods(ctx, req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 50, result.Period1.Count)
+	assert.Equal(t, 1.5, result.Deviation.Temperature)
+	assert.Equal(t, response.DirectionIncrease, result.Deviation.Metrics[models.MetricTemperature].Direction)
+
+	temperature := result.Metrics[models.MetricTemperature]
+	assert.Equal(t, 1.5, temperature.MeanDelta)
+	assert.Equal(t, 1.0, temperature.MinDelta)
+	assert.Equal(t, 2.0, temperature.MaxDelta)
+	assert.Equal(t, response.SignificanceSignificant, temperature.Significance)
+
+	humidity := result.Metrics[models.MetricHumidity]
+	assert.Equal(t, 0.0, *humidity.TStatistic)
+	assert.Equal(t, response.SignificanceNotSignificant, humidity.Significance)
+	mockReportRepo.AssertExpectations(t)
+}
+
+func TestComparePeriods_NoReports(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	service := NewReportService(mockReportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), newTestThresholdService())
+
+	ctx := context.Background()
+	period := request.TimeRange{
+		From: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+	}
+	req := &request.PeriodComparisonRequest{Period1: period, Period2: period}
+
+	mockReportRepo.On("AggregateReports", ctx, period.From, period.To).
+		Return(&response.PeriodStatistics{From: period.From, To: period.To}, nil)
+
+	// Act
+	result, err := service.ComparePeriods(ctx, req)
+
+	// Assert
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "no reports found in period1")
+	mockReportRepo.AssertExpectations(t)
+}
+
+func TestComparePeriods_InvalidRequest(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	valid := request.TimeRange{From: from, To: from.Add(time.Hour)}
+
+	tests := []struct {
+		name string
+		req  request.PeriodComparisonRequest
+	}{
+		{"missing period", request.PeriodComparisonRequest{Period1: valid}},
+		{"reversed period", request.PeriodComparisonRequest{Period1: valid, Period2: request.TimeRange{From: from, To: from.Add(-time.Hour)}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockReportRepo := new(MockReportRepository)
+			service := NewReportService(mockReportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), newTestThresholdService())
+
+			// Act
+			result, err := service.ComparePeriods(context.Background(), &tt.req)
+
+			// Assert
+			assert.Nil(t, result)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid period comparison: period2")
+			mockReportRepo.AssertNotCalled(t, "AggregateReports", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
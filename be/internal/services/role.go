@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/DangVTNhan/Scanner/be/internal/models/response"
+)
+
+// RoleService handles the assignment of roles to callers
+type RoleService struct {
+	roleAssignmentRepository repository.IRoleAssignmentRepository
+	defaultRole              models.Role
+}
+
+// NewRoleService creates a new instance of RoleService. Callers without an assignment get defaultRole.
+func NewRoleService(roleAssignmentRepository repository.IRoleAssignmentRepository, defaultRole models.Role) *RoleService {
+	return &RoleService{
+		roleAssignmentRepository: roleAssignmentRepository,
+		defaultRole:              defaultRole,
+	}
+}
+
+// GetRoles returns every role with its permissions
+func (s *RoleService) GetRoles() *response.RolesResponse {
+	roles := &response.RolesResponse{DefaultRole: s.defaultRole}
+	for _, role := range models.Roles {
+		roles.Roles = append(roles.Roles, response.RoleDefinition{Role: role, Permissions: role.Permissions()})
+	}
+	return roles
+}
+
+// ResolveRole returns the role assigned to a caller, or the default role if it has none
+func (s *RoleService) ResolveRole(ctx context.Context, identity *models.Identity) (models.Role, error) {
+	assignment, err := s.roleAssignmentRepository.FindRoleAssignment(ctx, identity.Method, identity.Subject)
+	if err != nil {
+		if err.Error() == "role assignment not found" {
+			return s.defaultRole, nil
+		}
+		return "", err
+	}
+	return assignment.Role, nil
+}
+
+// ListRoleAssignments retrieves all role assignments, ordered by method and subject
+func (s *RoleService) ListRoleAssignments(ctx context.Context) ([]models.RoleAssignment, error) {
+	return s.roleAssignmentRepository.FindRoleAssignments(ctx)
+}
+
+// AssignRole gives a caller a role, replacing its current assignment. actor is the admin making the change,
+// or nil from the command line; admins cannot change their own role, so they cannot lock themselves out.
+func (s *RoleService) AssignRole(ctx context.Context, actor *models.Identity, method models.AuthMethod, subject string, req *request.RoleAssignmentRequest) (*models.RoleAssignment, error) {
+	if err := validateRoleAssignee(actor, method, subject); err != nil {
+		return nil, err
+	}
+	if !req.Role.IsValid() {
+		return nil, fmt.Errorf("invalid role assignment: role must be one of %s", joinRoles(models.Roles))
+	}
+
+	tenant := models.TenantFromContext(ctx)
+	assignment := &models.RoleAssignment{
+		ID:        models.RoleAssignmentID(tenant, method, subject),
+		Tenant:    tenant,
+		Method:    method,
+		Subject:   subject,
+		Role:      req.Role,
+		UpdatedAt: time.Now().UTC(),
+	}
+	if actor != nil {
+		assignment.UpdatedBy = actor.Name
+	}
+	if err := s.roleAssignmentRepository.UpsertRoleAssignment(ctx, assignment); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// RemoveRoleAssignment removes the role assignment of a caller, who then gets the default role
+func (s *RoleService) RemoveRoleAssignment(ctx context.Context, actor *models.Identity, method models.AuthMethod, subject string) error {
+	if err := validateRoleAssignee(actor, method, subject); err != nil {
+		return err
+	}
+	return s.roleAssignmentRepository.DeleteRoleAssignment(ctx, method, subject)
+}
+
+// validateRoleAssignee checks the caller whose role is changed by actor
+func validateRoleAssignee(actor *models.Identity, method models.AuthMethod, subject string) error {
+	if !method.IsValid() {
+		return fmt.Errorf("invalid role assignment: method must be %s or %s", models.AuthMethodAPIKey, models.AuthMethodToken)
+	}
+	if strings.TrimSpace(subject) == "" {
+		return fmt.Errorf("invalid role assignment: subject is required")
+	}
+	if actor != nil && actor.Method == method && actor.Subject == subject {
+		return fmt.Errorf("invalid role assignment: you cannot change your own role")
+	}
+	return nil
+}
+
+// joinRoles returns the roles as a comma-separated list
+func joinRoles(roles []models.Role) string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = string(role)
+	}
+	return strings.Join(names, ", ")
+}
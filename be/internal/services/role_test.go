@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository/memory"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRoleAssignmentRepository is a mock implementation of IRoleAssignmentRepository
+type MockRoleAssignmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleAssignmentRepository) FindRoleAssignment(ctx context.Context, method models.AuthMethod, subject string) (*models.RoleAssignment, error) {
+	args := m.Called(ctx, method, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RoleAssignment), args.Error(1)
+}
+
+func (m *MockRoleAssignmentRepository) FindRoleAssignments(ctx context.Context) ([]models.RoleAssignment, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RoleAssignment), args.Error(1)
+}
+
+func (m *MockRoleAssignmentRepository) UpsertRoleAssignment(ctx context.Context, assignment *models.RoleAssignment) error {
+	args := m.Called(ctx, assignment)
+	return args.Error(0)
+}
+
+func (m *MockRoleAssignmentRepository) DeleteRoleAssignment(ctx context.Context, method models.AuthMethod, subject string) error {
+	args := m.Called(ctx, method, subject)
+	return args.Error(0)
+}
+
+func TestRolePermissions(t *testing.T) {
+	assert.Equal(t, []models.Permission{models.PermissionReadReports}, models.RoleViewer.Permissions())
+
+	assert.True(t, models.RoleAnalyst.Can(models.PermissionCompareReports))
+	assert.False(t, models.RoleAnalyst.Can(models.PermissionGenerateReports))
+	assert.True(t, models.RoleOperator.Can(models.PermissionReadReports), "roles include the permissions of lower roles")
+	assert.True(t, models.RoleOperator.Can(models.PermissionDeleteReports))
+	assert.False(t, models.RoleOperator.Can(models.PermissionManageRoles))
+	assert.True(t, models.RoleAdmin.Can(models.PermissionManageRoles))
+	assert.False(t, models.Role("root").Can(models.PermissionReadReports))
+}
+
+func TestResolveRole(t *testing.T) {
+	// Arrange
+	service := NewRoleService(memory.NewRoleAssignmentRepository(), models.RoleViewer)
+	_, err := service.AssignRole(context.Background(), nil, models.AuthMethodToken, "u1", &request.RoleAssignmentRequest{Role: models.RoleOperator})
+	require.NoError(t, err)
+
+	// Act
+	assigned, assignedErr := service.ResolveRole(context.Background(), &models.Identity{Method: models.AuthMethodToken, Subject: "u1"})
+	unassigned, unassignedErr := service.ResolveRole(context.Background(), &models.Identity{Method: models.AuthMethodAPIKey, Subject: "u1"})
+
+	// Assert
+	require.NoError(t, assignedErr)
+	assert.Equal(t, models.RoleOperator, assigned)
+	require.NoError(t, unassignedErr)
+	assert.Equal(t, models.RoleViewer, unassigned, "callers without an assignment get the default role")
+}
+
+func TestResolveRole_RepositoryError(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRoleAssignmentRepository)
+	mockRepo.On("FindRoleAssignment", mock.Anything, models.AuthMethodToken, "u1").
+		Return(nil, errors.New("failed to retrieve role assignment: database error"))
+	service := NewRoleService(mockRepo, models.RoleViewer)
+
+	// Act
+	role, err := service.ResolveRole(context.Background(), &models.Identity{Method: models.AuthMethodToken, Subject: "u1"})
+
+	// Assert
+	assert.Empty(t, role)
+	assert.EqualError(t, err, "failed to retrieve role assignment: database error")
+}
+
+func TestAssignRole(t *testing.T) {
+	// Arrange
+	service := NewRoleService(memory.NewRoleAssignmentRepository(), models.RoleViewer)
+	admin := &models.Identity{Method: models.AuthMethodToken, Subject: "admin1", Name: "Jane Doe"}
+
+	// Act
+	assignment, err := service.AssignRole(context.Background(), admin, models.AuthMethodAPIKey, "key1", &request.RoleAssignmentRequest{Role: models.RoleAnalyst})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "default:api_key:key1", assignment.ID)
+	assert.Equal(t, models.RoleAnalyst, assignment.Role)
+	assert.Equal(t, "Jane Doe", assignment.UpdatedBy)
+
+	assignments, err := service.ListRoleAssignments(context.Background())
+	require.NoError(t, err)
+	require.Len(t, assignments, 1)
+	assert.Equal(t, models.RoleAnalyst, assignments[0].Role)
+}
+
+func TestAssignRole_Invalid(t *testing.T) {
+	service := NewRoleService(memory.NewRoleAssignmentRepository(), models.RoleViewer)
+	admin := &models.Identity{Method: models.AuthMethodToken, Subject: "admin1"}
+
+	for name, tc := range map[string]struct {
+		method  models.AuthMethod
+		subject string
+		role    models.Role
+		wantErr string
+	}{
+		"UnknownRole":   {method: models.AuthMethodAPIKey, subject: "key1", role: "root", wantErr: "invalid role assignment: role must be one of viewer, analyst, operator, admin"},
+		"UnknownMethod": {method: "password", subject: "key1", role: models.RoleAdmin, wantErr: "invalid role assignment: method must be api_key or token"},
+		"NoSubject":     {method: models.AuthMethodAPIKey, subject: " ", role: models.RoleAdmin, wantErr: "invalid role assignment: subject is required"},
+		"OwnRole":       {method: models.AuthMethodToken, subject: "admin1", role: models.RoleViewer, wantErr: "invalid role assignment: you cannot change your own role"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			assignment, err := service.AssignRole(context.Background(), admin, tc.method, tc.subject, &request.RoleAssignmentRequest{Role: tc.role})
+
+			// Assert
+			assert.Nil(t, assignment)
+			assert.EqualError(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestRemoveRoleAssignment(t *testing.T) {
+	// Arrange
+	service := NewRoleService(memory.NewRoleAssignmentRepository(), models.RoleViewer)
+	_, err := service.AssignRole(context.Background(), nil, models.AuthMethodAPIKey, "key1", &request.RoleAssignmentRequest{Role: models.RoleAdmin})
+	require.NoError(t, err)
+
+	// Act
+	err = service.RemoveRoleAssignment(context.Background(), nil, models.AuthMethodAPIKey, "key1")
+
+	// Assert
+	require.NoError(t, err)
+	role, err := service.ResolveRole(context.Background(), &models.Identity{Method: models.AuthMethodAPIKey, Subject: "key1"})
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleViewer, role)
+
+	err = service.RemoveRoleAssignment(context.Background(), nil, models.AuthMethodAPIKey, "key1")
+	assert.EqualError(t, err, "role assignment not found")
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/repository"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+)
+
+// ThresholdService handles business logic for deviation thresholds
+type ThresholdService struct {
+	thresholdRepository repository.IThresholdRepository
+}
+
+// NewThresholdService creates a new instance of ThresholdService
+func NewThresholdService(thresholdRepository repository.IThresholdRepository) *ThresholdService {
+	return &ThresholdService{
+		thresholdRepository: thresholdRepository,
+	}
+}
+
+// GetThresholds retrieves the effective threshold of every metric, falling back to the defaults
+func (s *ThresholdService) GetThresholds(ctx context.Context) ([]models.Threshold, error) {
+	byMetric, err := s.GetEffectiveThresholds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	thresholds := make([]models.Threshold, len(models.Metrics))
+	for i, metric := range models.Metrics {
+		thresholds[i] = byMetric[metric]
+	}
+	return thresholds, nil
+}
+
+// SetThreshold configures the threshold of a metric
+func (s *ThresholdService) SetThreshold(ctx context.Context, metric models.Metric, req *request.ThresholdRequest) (*models.Threshold, error) {
+	if !metric.IsValid() {
+		return nil, fmt.Errorf("invalid threshold: unknown metric %q", metric)
+	}
+	if req.Notable <= 0 {
+		return nil, fmt.Errorf("invalid threshold: notable must be positive")
+	}
+	if req.Major < req.Notable {
+		return nil, fmt.Errorf("invalid threshold: major must not be below notable")
+	}
+
+	now := time.Now()
+	threshold := &models.Threshold{
+		Metric:    metric,
+		Notable:   req.Notable,
+		Major:     req.Major,
+		UpdatedAt: &now,
+	}
+	if err := s.thresholdRepository.UpsertThreshold(ctx, threshold); err != nil {
+		return nil, err
+	}
+
+	return threshold, nil
+}
+
+// ResetThreshold removes the configured threshold of a metric and returns the default that applies again
+func (s *ThresholdService) ResetThreshold(ctx context.Context, metric models.Metric) (*models.Threshold, error) {
+	if !metric.IsValid() {
+		return nil, fmt.Errorf("invalid threshold: unknown metric %q", metric)
+	}
+
+	if err := s.thresholdRepository.DeleteThreshold(ctx, metric); err != nil {
+		return nil, err
+	}
+
+	threshold := defaultThreshold(metric)
+	return &threshold, nil
+}
+
+// GetEffectiveThresholds returns the threshold of every metric by metric, configured ones taking precedence over the defaults
+func (s *ThresholdService) GetEffectiveThresholds(ctx context.Context) (map[models.Metric]models.Threshold, error) {
+	configured, err := s.thresholdRepository.FindThresholds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	thresholds := make(map[models.Metric]models.Threshold, len(models.Metrics))
+	for _, metric := range models.Metrics {
+		thresholds[metric] = defaultThreshold(metric)
+	}
+	for _, threshold := range configured {
+		if threshold.Metric.IsValid() {
+			thresholds[threshold.Metric] = threshold
+		}
+	}
+	return thresholds, nil
+}
+
+// defaultThreshold returns the built-in threshold of a metric
+func defaultThreshold(metric models.Metric) models.Threshold {
+	threshold := models.DefaultThresholds[metric]
+	threshold.IsDefault = true
+	return threshold
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/internal/models/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockThresholdRepository is a mock implementation of IThresholdRepository
+type MockThresholdRepository struct {
+	mock.Mock
+}
+
+func (m *MockThresholdRepository) FindThresholds(ctx context.Context) ([]models.Threshold, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Threshold), args.Error(1)
+}
+
+func (m *MockThresholdRepository) UpsertThreshold(ctx context.Context, threshold *models.Threshold) error {
+	args := m.Called(ctx, threshold)
+	return args.Error(0)
+}
+
+func (m *MockThresholdRepository) DeleteThreshold(ctx context.Context, metric models.Metric) error {
+	args := m.Called(ctx, metric)
+	return args.Error(0)
+}
+
+func TestGetThresholds_MergesDefaults(t *testing.T) {
+	// Arrange
+	mockThresholdRepo := new(MockThresholdRepository)
+	service := NewThresholdService(mockThresholdRepo)
+
+	ctx := context.Background()
+	configured := []models.Threshold{{Metric: models.MetricPressure, Notable: 1, Major: 2}}
+	mockThresholdRepo.On("FindThresholds", ctx).Return(configured, nil)
+
+	// Act
+	thresholds, err := service.GetThresholds(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, thresholds, len(models.Metrics))
+	assert.Equal(t, models.Threshold{Metric: models.MetricTemperature, Notable: 2, Major: 5, IsDefault: true}, thresholds[0])
+	assert.Equal(t, configured[0], thresholds[1])
+	assert.True(t, thresholds[2].IsDefault)
+	mockThresholdRepo.AssertExpectations(t)
+}
+
+func TestGetThresholds_Error(t *testing.T) {
+	// Arrange
+	mockThresholdRepo := new(MockThresholdRepository)
+	service := NewThresholdService(mockThresholdRepo)
+
+	ctx := context.Background()
+	mockThresholdRepo.On("FindThresholds", ctx).Return(nil, errors.New("failed to retrieve thresholds: database error"))
+
+	// Act
+	thresholds, err := service.GetThresholds(ctx)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, thresholds)
+	mockThresholdRepo.AssertExpectations(t)
+}
+
+func TestSetThreshold(t *testing.T) {
+	// Arrange
+	mockThresholdRepo := new(MockThresholdRepository)
+	service := NewThresholdService(mockThresholdRepo)
+
+	ctx := context.Background()
+	mockThresholdRepo.On("UpsertThreshold", ctx, mock.MatchedBy(func(threshold *models.Threshold) bool {
+		return threshold.Metric == models.MetricHumidity && threshold.Notable == 5 && threshold.Major == 15
+	})).Return(nil)
+
+	// Act
+	threshold, err := service.SetThreshold(ctx, models.MetricHumidity, &request.ThresholdRequest{Notable: 5, Major: 15})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, threshold.IsDefault)
+	assert.WithinDuration(t, time.Now(), *threshold.UpdatedAt, time.Second)
+	mockThresholdRepo.AssertExpectations(t)
+}
+
+func TestSetThreshold_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		metric models.Metric
+		req    request.ThresholdRequest
+	}{
+		{"unknown metric", "windSpeed", request.ThresholdRequest{Notable: 1, Major: 2}},
+		{"zero notable", models.MetricTemperature, request.ThresholdRequest{Notable: 0, Major: 2}},
+		{"major below notable", models.MetricTemperature, request.ThresholdRequest{Notable: 3, Major: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockThresholdRepo := new(MockThresholdRepository)
+			service := NewThresholdService(mockThresholdRepo)
+
+			// Act
+			threshold, err := service.SetThreshold(context.Background(), tt.metric, &tt.req)
+
+			// Assert
+			assert.Nil(t, threshold)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid threshold")
+			mockThresholdRepo.AssertNotCalled(t, "UpsertThreshold", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestResetThreshold(t *testing.T) {
+	// Arrange
+	mockThresholdRepo := new(MockThresholdRepository)
+	service := NewThresholdService(mockThresholdRepo)
+
+	ctx := context.Background()
+	mockThresholdRepo.On("DeleteThreshold", ctx, models.MetricCloudCover).Return(nil)
+
+	// Act
+	threshold, err := service.ResetThreshold(ctx, models.MetricCloudCover)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, models.Threshold{Metric: models.MetricCloudCover, Notable: 20, Major: 50, IsDefault: true}, *threshold)
+	mockThresholdRepo.AssertExpectations(t)
+}
+
+func TestCompareReports_ClassifiesDeviation(t *testing.T) {
+	// Arrange
+	mockReportRepo := new(MockReportRepository)
+	mockThresholdRepo := new(MockThresholdRepository)
+	service := NewReportService(mockReportRepo, new(MockWeatherCacheRepository), new(MockWeatherService), NewThresholdService(mockThresholdRepo))
+
+	ctx := context.Background()
+	req := &request.ComparisonRequest{ReportID1: "report1", ReportID2: "report2"}
+
+	report1 := &models.WeatherReport{ID: "report1", Temperature: 30.0, Pressure: 1010.0, Humidity: 60.0, CloudCover: 20.0}
+	report2 := &models.WeatherReport{ID: "report2", Temperature: 24.5, Pressure: 1011.0, Humidity: 72.0, CloudCover: 20.0}
+	mockReportRepo.On("FindReportByID", ctx, "report1").Return(report1, nil)
+	mockReportRepo.On("FindReportByID", ctx, "report2").Return(report2, nil)
+
+	// Pressure changes of 1 hPa are notable for this deployment
+	mockThresholdRepo.On("FindThresholds", ctx).Return([]models.Threshold{{Metric: models.MetricPressure, Notable: 1, Major: 4}}, nil)
+
+	// Act
+	result, err := service.CompareReports(ctx, req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, models.SeverityMajor, result.Deviation.Metrics[models.MetricTemperature].Severity)
+	assert.Equal(t, models.SeverityNotable, result.Deviation.Metrics[models.MetricPressure].Severity)
+	assert.Equal(t, models.SeverityNotable, result.Deviation.Metrics[models.MetricHumidity].Severity)
+	assert.Equal(t, models.SeverityNormal, result.Deviation.Metrics[models.MetricCloudCover].Severity)
+	assert.Equal(t, models.SeverityMajor, result.Deviation.Verdict)
+	mockReportRepo.AssertExpectations(t)
+	mockThresholdRepo.AssertExpectations(t)
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/pkg/jwt"
+)
+
+// tokenNameClaims are the claims that may hold the display name of a token's user, in order of preference
+var tokenNameClaims = []string{"name", "preferred_username", "email"}
+
+// TokenService authenticates callers by the bearer tokens that the company SSO issues
+type TokenService struct {
+	verifier    *jwt.Verifier
+	tenantClaim string
+}
+
+// NewTokenService creates a new instance of TokenService. tenantClaim names the claim that holds the tenant of
+// a token's user; tokens without it belong to models.DefaultTenant.
+func NewTokenService(verifier *jwt.Verifier, tenantClaim string) *TokenService {
+	return &TokenService{
+		verifier:    verifier,
+		tenantClaim: tenantClaim,
+	}
+}
+
+// Authenticate verifies a token and returns the identity of its subject. It returns an error starting with
+// "invalid token" for tokens that do not verify, and "failed to load signing keys" when the key set is unavailable.
+func (s *TokenService) Authenticate(ctx context.Context, token string) (*models.Identity, error) {
+	claims, err := s.verifier.Verify(ctx, token)
+	if err != nil {
+		if errors.Is(err, jwt.ErrKeysUnavailable) {
+			return nil, fmt.Errorf("failed to load signing keys: %w", err)
+		}
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("invalid token: no subject")
+	}
+	tenant := models.DefaultTenant
+	if _, ok := claims.Raw[s.tenantClaim]; ok {
+		tenant = claims.String(s.tenantClaim)
+		if !models.IsValidTenant(tenant) {
+			return nil, fmt.Errorf("invalid token: invalid %s claim", s.tenantClaim)
+		}
+	}
+
+	identity := &models.Identity{
+		Method:  models.AuthMethodToken,
+		Subject: claims.Subject,
+		Name:    claims.Subject,
+		Issuer:  claims.Issuer,
+		Tenant:  tenant,
+		Claims:  claims.Raw,
+	}
+	for _, claim := range tokenNameClaims {
+		if name := claims.String(claim); name != "" {
+			identity.Name = name
+			break
+		}
+	}
+	return identity, nil
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DangVTNhan/Scanner/be/internal/models"
+	"github.com/DangVTNhan/Scanner/be/pkg/jwt"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTokenService returns a token service that trusts a generated key, and the key
+func newTestTokenService(t *testing.T) (*TokenService, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	keys, err := jwt.NewKeySet(jwt.Key{ID: "sso", Public: &key.PublicKey})
+	require.NoError(t, err)
+	return NewTokenService(&jwt.Verifier{
+		Keys:     keys,
+		Issuer:   "https://sso.example.com",
+		Audience: "weather-api",
+	}, "tenant"), key
+}
+
+func signTestToken(t *testing.T, key *ecdsa.PrivateKey, claims map[string]interface{}) string {
+	all := jwtv5.MapClaims{
+		"iss": "https://sso.example.com",
+		"aud": "weather-api",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for name, value := range claims {
+		all[name] = value
+	}
+	token := jwtv5.NewWithClaims(jwtv5.SigningMethodES256, all)
+	token.Header["kid"] = "sso"
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestTokenAuthenticate(t *testing.T) {
+	// Arrange
+	service, key := newTestTokenService(t)
+
+	for name, tc := range map[string]struct {
+		claims     map[string]interface{}
+		wantName   string
+		wantTenant string
+	}{
+		"Name":              {claims: map[string]interface{}{"sub": "u1", "name": "Jane Doe", "email": "jane@example.com"}, wantName: "Jane Doe", wantTenant: models.DefaultTenant},
+		"PreferredUsername": {claims: map[string]interface{}{"sub": "u1", "preferred_username": "jdoe"}, wantName: "jdoe", wantTenant: models.DefaultTenant},
+		"SubjectOnly":       {claims: map[string]interface{}{"sub": "u1"}, wantName: "u1", wantTenant: models.DefaultTenant},
+		"Tenant":            {claims: map[string]interface{}{"sub": "u1", "tenant": "changi-ops"}, wantName: "u1", wantTenant: "changi-ops"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			identity, err := service.Authenticate(context.Background(), signTestToken(t, key, tc.claims))
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, models.AuthMethodToken, identity.Method)
+			assert.Equal(t, "u1", identity.Subject)
+			assert.Equal(t, tc.wantName, identity.Name)
+			assert.Equal(t, tc.wantTenant, identity.Tenant)
+			assert.Equal(t, "https://sso.example.com", identity.Issuer)
+			assert.Equal(t, "u1", identity.Claims["sub"])
+		})
+	}
+}
+
+func TestTokenAuthenticate_InvalidTokens(t *testing.T) {
+	// Arrange
+	service, key := newTestTokenService(t)
+
+	for name, token := range map[string]string{
+		"Expired":   signTestToken(t, key, map[string]interface{}{"sub": "u1", "exp": time.Now().Add(-time.Hour).Unix()}),
+		"NoSubject": signTestToken(t, key, nil),
+		"BadTenant": signTestToken(t, key, map[string]interface{}{"sub": "u1", "tenant": "Changi Ops"}),
+		"NumTenant": signTestToken(t, key, map[string]interface{}{"sub": "u1", "tenant": 42}),
+		"Garbage":   "garbage",
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			identity, err := service.Authenticate(context.Background(), token)
+
+			// Assert
+			assert.Nil(t, identity)
+			require.Error(t, err)
+			assert.True(t, strings.HasPrefix(err.Error(), "invalid token: "), err.Error())
+		})
+	}
+}
+
+func TestTokenAuthenticate_KeysUnavailable(t *testing.T) {
+	// Arrange
+	_, key := newTestTokenService(t)
+	keys, err := jwt.NewKeySet()
+	require.NoError(t, err)
+	service := NewTokenService(&jwt.Verifier{Keys: keys}, "tenant")
+
+	// Act
+	identity, err := service.Authenticate(context.Background(), signTestToken(t, key, map[string]interface{}{"sub": "u1"}))
+
+	// Assert
+	assert.Nil(t, identity)
+	assert.ErrorContains(t, err, "failed to load signing keys")
+}
@@ -0,0 +1,318 @@
+// Package chart draws time series as standalone SVG images. The images use only presentation
+// attributes, no stylesheets or scripts, so they can be embedded in emails and wiki pages.
+package chart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Style is how the values of a series are drawn
+type Style int
+
+const (
+	Line Style = iota // A line through the values
+	Area              // A line with the area down to the bottom of the plot filled
+)
+
+// Point is a value at a time
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Range is a range of values at a time, drawn as a band behind the line
+type Range struct {
+	Time time.Time
+	Low  float64
+	High float64
+}
+
+// TimeSeries is a chart of values over time
+type TimeSeries struct {
+	Title    string
+	Subtitle string // Shown below the title, e.g. the time range
+	Caption  string // Shown in the bottom-right corner, e.g. the source of the data
+	Width    int    // Size of the image in pixels
+	Height   int
+	Style    Style
+	Color    color.RGBA // Color of the line, the area and the band
+	Points   []Point    // In time order
+	Band     []Range    // In time order; omitted when empty
+	From, To time.Time  // Range of the time axis; defaults to the range of the points
+}
+
+// Margins of the plot area in pixels
+const (
+	marginTop    = 56
+	marginRight  = 24
+	marginBottom = 48
+	marginLeft   = 64
+)
+
+// Colors of the chart frame
+var (
+	textColor  = color.RGBA{R: 0x1e, G: 0x29, B: 0x3b, A: 0xff}
+	mutedColor = color.RGBA{R: 0x64, G: 0x74, B: 0x8b, A: 0xff}
+	gridColor  = color.RGBA{R: 0xe2, G: 0xe8, B: 0xf0, A: 0xff}
+)
+
+// maxMarkers is the largest number of points that are also marked with a dot
+const maxMarkers = 60
+
+// WriteSVG writes the chart as an SVG image
+func (c *TimeSeries) WriteSVG(w io.Writer) error {
+	var buf bytes.Buffer
+	width, height := float64(c.Width), float64(c.Height)
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" font-family="Helvetica, Arial, sans-serif">`+"\n",
+		c.Width, c.Height, c.Width, c.Height)
+	fmt.Fprintf(&buf, "<title>%s</title>\n", escape(c.Title))
+	fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" fill="#ffffff"/>`+"\n")
+	fmt.Fprintf(&buf, `<text x="%d" y="24" font-size="16" font-weight="bold" fill="%s">%s</text>`+"\n", marginLeft, hex(textColor), escape(c.Title))
+	if c.Subtitle != "" {
+		fmt.Fprintf(&buf, `<text x="%d" y="42" font-size="12" fill="%s">%s</text>`+"\n", marginLeft, hex(mutedColor), escape(c.Subtitle))
+	}
+	if c.Caption != "" {
+		fmt.Fprintf(&buf, `<text x="%s" y="%s" font-size="10" text-anchor="end" fill="%s">%s</text>`+"\n",
+			number(width-marginRight), number(height-8), hex(mutedColor), escape(c.Caption))
+	}
+
+	p := c.plot()
+	if len(c.Points) == 0 {
+		fmt.Fprintf(&buf, `<text x="%s" y="%s" font-size="14" text-anchor="middle" fill="%s">No data</text>`+"\n",
+			number(p.left+p.width/2), number(p.top+p.height/2), hex(mutedColor))
+		buf.WriteString("</svg>\n")
+		_, err := buf.WriteTo(w)
+		return err
+	}
+
+	// Grid and axis labels
+	for _, tick := range p.valueTicks {
+		y := p.y(tick)
+		fmt.Fprintf(&buf, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s" stroke-width="1"/>`+"\n",
+			number(p.left), number(y), number(p.left+p.width), number(y), hex(gridColor))
+		fmt.Fprintf(&buf, `<text x="%s" y="%s" font-size="11" text-anchor="end" fill="%s">%s</text>`+"\n",
+			number(p.left-8), number(y+4), hex(mutedColor), strconv.FormatFloat(tick, 'f', p.valueDecimals, 64))
+	}
+	for _, tick := range p.timeTicks {
+		x := p.x(tick)
+		fmt.Fprintf(&buf, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s" stroke-width="1"/>`+"\n",
+			number(x), number(p.top+p.height), number(x), number(p.top+p.height+4), hex(mutedColor))
+		fmt.Fprintf(&buf, `<text x="%s" y="%s" font-size="11" text-anchor="middle" fill="%s">%s</text>`+"\n",
+			number(x), number(p.top+p.height+18), hex(mutedColor), escape(tick.Format(p.timeLayout)))
+	}
+	fmt.Fprintf(&buf, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s" stroke-width="1"/>`+"\n",
+		number(p.left), number(p.top+p.height), number(p.left+p.width), number(p.top+p.height), hex(mutedColor))
+
+	// Band: the highs from left to right, then the lows back
+	if len(c.Band) > 0 {
+		coords := make([]string, 0, 2*len(c.Band))
+		for _, r := range c.Band {
+			coords = append(coords, p.coord(r.Time, r.High))
+		}
+		for i := len(c.Band) - 1; i >= 0; i-- {
+			coords = append(coords, p.coord(c.Band[i].Time, c.Band[i].Low))
+		}
+		fmt.Fprintf(&buf, `<polygon points="%s" fill="%s" fill-opacity="0.18" stroke="none"/>`+"\n", strings.Join(coords, " "), hex(c.Color))
+	}
+
+	line := make([]string, len(c.Points))
+	for i, point := range c.Points {
+		line[i] = p.coord(point.Time, point.Value)
+	}
+	if c.Style == Area {
+		bottom := p.top + p.height
+		area := append([]string{number(p.x(c.Points[0].Time)) + "," + number(bottom)}, line...)
+		area = append(area, number(p.x(c.Points[len(c.Points)-1].Time))+","+number(bottom))
+		fmt.Fprintf(&buf, `<polygon points="%s" fill="%s" fill-opacity="0.25" stroke="none"/>`+"\n", strings.Join(area, " "), hex(c.Color))
+	}
+	fmt.Fprintf(&buf, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2" stroke-linejoin="round" stroke-linecap="round"/>`+"\n",
+		strings.Join(line, " "), hex(c.Color))
+	if len(c.Points) <= maxMarkers {
+		for _, point := range c.Points {
+			fmt.Fprintf(&buf, `<circle cx="%s" cy="%s" r="3" fill="%s"/>`+"\n", number(p.x(point.Time)), number(p.y(point.Value)), hex(c.Color))
+		}
+	}
+
+	buf.WriteString("</svg>\n")
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// plot maps times and values to positions in the plot area
+type plot struct {
+	left, top, width, height float64
+	from, to                 time.Time
+	low, high                float64
+
+	valueTicks    []float64
+	valueDecimals int
+	timeTicks     []time.Time
+	timeLayout    string
+}
+
+// plot computes the plot area and the axes of the chart
+func (c *TimeSeries) plot() *plot {
+	p := &plot{
+		left:   marginLeft,
+		top:    marginTop,
+		width:  math.Max(float64(c.Width-marginLeft-marginRight), 1),
+		height: math.Max(float64(c.Height-marginTop-marginBottom), 1),
+		from:   c.From,
+		to:     c.To,
+	}
+	if len(c.Points) == 0 {
+		return p
+	}
+
+	if p.from.IsZero() {
+		p.from = c.Points[0].Time
+	}
+	if p.to.IsZero() {
+		p.to = c.Points[len(c.Points)-1].Time
+	}
+	if !p.to.After(p.from) {
+		p.from, p.to = p.from.Add(-time.Hour), p.from.Add(time.Hour)
+	}
+
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, point := range c.Points {
+		low, high = math.Min(low, point.Value), math.Max(high, point.Value)
+	}
+	for _, r := range c.Band {
+		low, high = math.Min(low, r.Low), math.Max(high, r.High)
+	}
+	p.valueTicks, p.valueDecimals = valueTicks(low, high, int(p.height/40)+1)
+	p.low, p.high = p.valueTicks[0], p.valueTicks[len(p.valueTicks)-1]
+
+	p.timeTicks, p.timeLayout = timeTicks(p.from, p.to, int(p.width/90)+1)
+	return p
+}
+
+// x returns the horizontal position of t
+func (p *plot) x(t time.Time) float64 {
+	return p.left + p.width*float64(t.Sub(p.from))/float64(p.to.Sub(p.from))
+}
+
+// y returns the vertical position of value
+func (p *plot) y(value float64) float64 {
+	return p.top + p.height*(p.high-value)/(p.high-p.low)
+}
+
+// coord formats the position of a value at t as an x,y pair of a points attribute
+func (p *plot) coord(t time.Time, value float64) string {
+	return number(p.x(t)) + "," + number(p.y(value))
+}
+
+// valueTicks returns round tick values covering [low, high] with at most about maxTicks ticks,
+// and the number of decimals needed to label them
+func valueTicks(low, high float64, maxTicks int) ([]float64, int) {
+	if high-low < 1e-9 {
+		low, high = low-1, high+1
+	}
+	if maxTicks < 2 {
+		maxTicks = 2
+	}
+
+	// The step is 1, 2, 2.5 or 5 times a power of ten
+	raw := (high - low) / float64(maxTicks-1)
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	step := 10 * magnitude
+	for _, factor := range []float64{1, 2, 2.5, 5} {
+		if factor*magnitude >= raw {
+			step = factor * magnitude
+			break
+		}
+	}
+
+	decimals := 0
+	if step < 1 {
+		decimals = int(math.Ceil(-math.Log10(step)))
+		if math.Abs(step*math.Pow(10, float64(decimals))-math.Round(step*math.Pow(10, float64(decimals)))) > 1e-9 {
+			decimals++ // e.g. 0.25
+		}
+	}
+
+	var ticks []float64
+	for i := math.Floor(low / step); ; i++ {
+		tick := i * step
+		ticks = append(ticks, tick)
+		if tick >= high-step*1e-9 {
+			break
+		}
+	}
+	return ticks, decimals
+}
+
+// timeSteps are the spacings of time ticks, with the layout of their labels
+var timeSteps = []struct {
+	step   time.Duration
+	layout string
+}{
+	{5 * time.Minute, "15:04"},
+	{15 * time.Minute, "15:04"},
+	{30 * time.Minute, "15:04"},
+	{time.Hour, "15:04"},
+	{3 * time.Hour, "15:04"},
+	{6 * time.Hour, "02 Jan 15:04"},
+	{12 * time.Hour, "02 Jan 15:04"},
+	{24 * time.Hour, "02 Jan"},
+	{2 * 24 * time.Hour, "02 Jan"},
+	{7 * 24 * time.Hour, "02 Jan"},
+	{14 * 24 * time.Hour, "02 Jan"},
+	{28 * 24 * time.Hour, "02 Jan 2006"},
+	{91 * 24 * time.Hour, "Jan 2006"},
+	{364 * 24 * time.Hour, "2006"},
+}
+
+// timeTicks returns evenly spaced round times in [from, to] with at most maxTicks ticks, and the layout of their labels.
+// Ticks are aligned in UTC; daily and longer steps start at midnight, weekly steps on Mondays.
+func timeTicks(from, to time.Time, maxTicks int) ([]time.Time, string) {
+	if maxTicks < 2 {
+		maxTicks = 2
+	}
+	span := to.Sub(from)
+
+	i := 0
+	for i < len(timeSteps)-1 && span/timeSteps[i].step > time.Duration(maxTicks-1) {
+		i++
+	}
+	step := timeSteps[i]
+
+	var ticks []time.Time
+	for tick := from.UTC().Truncate(step.step); !tick.After(to); tick = tick.Add(step.step) {
+		if !tick.Before(from) {
+			ticks = append(ticks, tick)
+		}
+	}
+	return ticks, step.layout
+}
+
+// number formats a coordinate with at most one decimal
+func number(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 1, 64)
+	s = strings.TrimSuffix(s, ".0")
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// hex formats a color as an SVG hex color
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// escape escapes s for use in text content and attribute values
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
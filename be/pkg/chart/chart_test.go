@@ -0,0 +1,157 @@
+package chart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/color"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var start = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+// elements returns the names of the SVG elements in order, failing if the document is not well-formed XML
+func elements(t *testing.T, svg string) []string {
+	t.Helper()
+	var names []string
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return names
+		}
+		require.NoError(t, err)
+		if element, ok := token.(xml.StartElement); ok {
+			names = append(names, element.Name.Local)
+		}
+	}
+}
+
+func TestTimeSeriesWriteSVG(t *testing.T) {
+	// Arrange
+	chart := &TimeSeries{
+		Title:    "Temperature <°C> & more",
+		Subtitle: "01 Mar 2024",
+		Caption:  "Changi",
+		Width:    800,
+		Height:   320,
+		Style:    Area,
+		Color:    color.RGBA{R: 0x5b, G: 0x21, B: 0x82, A: 0xff},
+		Points: []Point{
+			{start, 25}, {start.Add(6 * time.Hour), 27.5}, {start.Add(12 * time.Hour), 31},
+		},
+		Band: []Range{
+			{start, 24, 26}, {start.Add(6 * time.Hour), 26, 29}, {start.Add(12 * time.Hour), 30, 32},
+		},
+		From: start,
+		To:   start.Add(12 * time.Hour),
+	}
+	var buf bytes.Buffer
+
+	// Act
+	err := chart.WriteSVG(&buf)
+
+	// Assert
+	require.NoError(t, err)
+	svg := buf.String()
+	names := elements(t, svg)
+	assert.Equal(t, "svg", names[0])
+	assert.Contains(t, svg, `width="800" height="320" viewBox="0 0 800 320"`)
+	assert.Contains(t, svg, "<title>Temperature &lt;°C&gt; &amp; more</title>")
+	assert.Contains(t, svg, `fill="#5b2182" fill-opacity="0.18"`, "band")
+	assert.Contains(t, svg, `fill="#5b2182" fill-opacity="0.25"`, "area")
+	assert.Contains(t, svg, ">00:00</text>")
+	assert.Contains(t, svg, ">12:00</text>")
+	assert.Contains(t, svg, ">24</text>")
+	assert.Contains(t, svg, ">32</text>")
+
+	// The first point is at the left edge of the plot and the last at the right edge
+	assert.Contains(t, svg, `<circle cx="64" `)
+	assert.Contains(t, svg, `<circle cx="776" `)
+	circles := strings.Count(svg, "<circle")
+	assert.Equal(t, 3, circles)
+}
+
+func TestTimeSeriesWriteSVG_NoData(t *testing.T) {
+	// Arrange
+	chart := &TimeSeries{Title: "Pressure", Width: 400, Height: 200}
+	var buf bytes.Buffer
+
+	// Act
+	err := chart.WriteSVG(&buf)
+
+	// Assert
+	require.NoError(t, err)
+	elements(t, buf.String())
+	assert.Contains(t, buf.String(), ">No data</text>")
+	assert.NotContains(t, buf.String(), "<polyline")
+}
+
+func TestTimeSeriesWriteSVG_SinglePoint(t *testing.T) {
+	// Arrange
+	chart := &TimeSeries{Title: "Humidity", Width: 400, Height: 200, Points: []Point{{start, 60}}}
+	var buf bytes.Buffer
+
+	// Act
+	err := chart.WriteSVG(&buf)
+
+	// Assert
+	require.NoError(t, err)
+	elements(t, buf.String())
+	assert.NotContains(t, buf.String(), "NaN")
+	assert.NotContains(t, buf.String(), "Inf")
+}
+
+func TestValueTicks(t *testing.T) {
+	tests := []struct {
+		name         string
+		low, high    float64
+		maxTicks     int
+		wantTicks    []float64
+		wantDecimals int
+	}{
+		{"Round steps", 24.3, 31.2, 6, []float64{24, 26, 28, 30, 32}, 0},
+		{"Decimal steps", 1.1, 2.05, 6, []float64{1, 1.2, 1.4, 1.6, 1.8, 2, 2.2}, 1},
+		{"Quarter steps", 0.1, 1.1, 5, []float64{0, 0.25, 0.5, 0.75, 1, 1.25}, 2},
+		{"Flat values", 50, 50, 5, []float64{49, 49.5, 50, 50.5, 51}, 1},
+		{"Negative values", -12, 3, 5, []float64{-15, -10, -5, 0, 5}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticks, decimals := valueTicks(tt.low, tt.high, tt.maxTicks)
+			assert.InDeltaSlice(t, tt.wantTicks, ticks, 1e-9)
+			assert.Equal(t, tt.wantDecimals, decimals)
+		})
+	}
+}
+
+func TestTimeTicks(t *testing.T) {
+	tests := []struct {
+		name       string
+		from, to   time.Time
+		maxTicks   int
+		wantFirst  time.Time
+		wantCount  int
+		wantLayout string
+	}{
+		{"Hours", start.Add(20 * time.Minute), start.Add(12 * time.Hour), 5, start.Add(3 * time.Hour), 4, "15:04"},
+		{"Days", start, start.Add(7 * 24 * time.Hour), 8, start, 8, "02 Jan"},
+		{"Weeks start on Monday", start, start.Add(60 * 24 * time.Hour), 10, time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), 9, "02 Jan"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticks, layout := timeTicks(tt.from, tt.to, tt.maxTicks)
+			require.NotEmpty(t, ticks)
+			assert.Equal(t, tt.wantFirst, ticks[0])
+			assert.Len(t, ticks, tt.wantCount)
+			assert.Equal(t, tt.wantLayout, layout)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package columnar
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+)
+
+// ArrowWriter writes rows as an Apache Arrow IPC file (also known as Feather V2), uncompressed.
+// Schema and field metadata are stored as Arrow custom metadata. Timestamps are stored in UTC.
+type ArrowWriter struct {
+	out       io.Writer
+	schema    *arrow.Schema
+	buffer    *recordBuffer
+	batchSize int
+	writer    *ipc.FileWriter // Created when the first record batch is flushed
+	closed    bool
+}
+
+// NewArrowWriter creates a writer that flushes a record batch every batchSize rows
+// (DefaultBatchSize if not positive). Nothing is written to w before the first batch is full
+// or the writer is closed.
+func NewArrowWriter(w io.Writer, schema Schema, batchSize int) (*ArrowWriter, error) {
+	if err := schema.Validate(); err != nil {
+		return nil, err
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	arrowSchema := schema.arrowSchema(schema.Metadata)
+	return &ArrowWriter{
+		out:       w,
+		schema:    arrowSchema,
+		buffer:    newRecordBuffer(&schema, arrowSchema),
+		batchSize: batchSize,
+	}, nil
+}
+
+// WriteRow appends a row, flushing a record batch when the batch is full
+func (a *ArrowWriter) WriteRow(values ...any) error {
+	if a.closed {
+		return fmt.Errorf("writer is closed")
+	}
+	if err := a.buffer.append(values); err != nil {
+		return err
+	}
+	if a.buffer.rows >= a.batchSize {
+		return a.flush()
+	}
+	return nil
+}
+
+// Close flushes buffered rows and writes the end-of-stream marker and the file footer
+func (a *ArrowWriter) Close() error {
+	if a.closed {
+		return fmt.Errorf("writer is closed")
+	}
+	a.closed = true
+	defer a.buffer.release()
+
+	if err := a.flush(); err != nil {
+		return err
+	}
+	if err := a.start(); err != nil {
+		return err
+	}
+	if err := a.writer.Close(); err != nil {
+		return fmt.Errorf("failed to write arrow footer: %w", err)
+	}
+	return nil
+}
+
+// start creates the IPC file writer once
+func (a *ArrowWriter) start() error {
+	if a.writer != nil {
+		return nil
+	}
+
+	writer, err := ipc.NewFileWriter(a.out, ipc.WithSchema(a.schema))
+	if err != nil {
+		return fmt.Errorf("failed to create arrow writer: %w", err)
+	}
+	a.writer = writer
+	return nil
+}
+
+// flush writes the buffered rows as a record batch
+func (a *ArrowWriter) flush() error {
+	if a.buffer.rows == 0 {
+		return nil
+	}
+	if err := a.start(); err != nil {
+		return err
+	}
+
+	record := a.buffer.flush()
+	defer record.Release()
+	if err := a.writer.Write(record); err != nil {
+		return fmt.Errorf("failed to write arrow record batch: %w", err)
+	}
+	return nil
+}
+
+var _ Writer = (*ArrowWriter)(nil)
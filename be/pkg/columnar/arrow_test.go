@@ -0,0 +1,118 @@
+package columnar
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertArrowSchema checks that the fields of a schema read back from a file match testSchema
+func assertArrowSchema(t *testing.T, schema *arrow.Schema) {
+	t.Helper()
+	expected := []struct {
+		name     string
+		dataType arrow.DataType
+		nullable bool
+	}{
+		{"id", arrow.BinaryTypes.String, false},
+		{"timestamp", &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}, false},
+		{"temperature", arrow.PrimitiveTypes.Float64, false},
+		{"notes", arrow.BinaryTypes.String, true},
+		{"deletedAt", &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}, true},
+	}
+	require.Equal(t, len(expected), schema.NumFields())
+	for i, field := range expected {
+		actual := schema.Field(i)
+		assert.Equal(t, field.name, actual.Name)
+		assert.True(t, arrow.TypeEqual(field.dataType, actual.Type), "%s has type %s", field.name, actual.Type)
+		assert.Equal(t, field.nullable, actual.Nullable, field.name)
+	}
+
+	unit, ok := schema.Field(2).Metadata.GetValue("unit")
+	assert.True(t, ok)
+	assert.Equal(t, "°C", unit)
+}
+
+// assertTestRows checks that the columns of records hold testRows
+func assertTestRows(t *testing.T, records []arrow.RecordBatch) {
+	t.Helper()
+	var ids, notes []string
+	var temperatures []float64
+	var timestamps []int64
+	var deletedAt []bool
+	for _, record := range records {
+		for row := 0; row < int(record.NumRows()); row++ {
+			ids = append(ids, record.Column(0).(*array.String).Value(row))
+			timestamps = append(timestamps, int64(record.Column(1).(*array.Timestamp).Value(row)))
+			temperatures = append(temperatures, record.Column(2).(*array.Float64).Value(row))
+			if record.Column(3).IsNull(row) {
+				notes = append(notes, "<null>")
+			} else {
+				notes = append(notes, record.Column(3).(*array.String).Value(row))
+			}
+			deletedAt = append(deletedAt, record.Column(4).IsValid(row))
+		}
+	}
+
+	assert.Equal(t, []string{"a", "b", "c"}, ids)
+	assert.Equal(t, testTime.UnixMicro(), timestamps[0])
+	assert.Equal(t, testTime.Add(time.Hour).UnixMicro(), timestamps[1])
+	assert.Equal(t, []float64{25.5, 26.0, -1.25}, temperatures)
+	assert.Equal(t, []string{"runway closure", "<null>", ""}, notes)
+	assert.Equal(t, []bool{false, true, false}, deletedAt)
+}
+
+func TestArrowWriter(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	writer, err := NewArrowWriter(&buf, testSchema(), 2)
+	require.NoError(t, err)
+
+	// Act
+	writeRows(t, writer, testRows)
+
+	// Assert
+	reader, err := ipc.NewFileReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	defer reader.Close()
+
+	assertArrowSchema(t, reader.Schema())
+	dataset, ok := reader.Schema().Metadata().GetValue("dataset")
+	assert.True(t, ok)
+	assert.Equal(t, "reports", dataset)
+	require.Equal(t, 2, reader.NumRecords())
+
+	var records []arrow.RecordBatch
+	for i := 0; i < reader.NumRecords(); i++ {
+		record, err := reader.RecordBatchAt(i)
+		require.NoError(t, err)
+		defer record.Release()
+		records = append(records, record)
+	}
+	assert.Equal(t, int64(2), records[0].NumRows())
+	assert.Equal(t, int64(1), records[1].NumRows())
+	assertTestRows(t, records)
+}
+
+func TestArrowWriter_Empty(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	writer, err := NewArrowWriter(&buf, testSchema(), 0)
+	require.NoError(t, err)
+
+	// Act
+	require.NoError(t, writer.Close())
+
+	// Assert
+	reader, err := ipc.NewFileReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	defer reader.Close()
+	assertArrowSchema(t, reader.Schema())
+	assert.Zero(t, reader.NumRecords())
+}
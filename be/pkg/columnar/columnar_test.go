@@ -0,0 +1,101 @@
+package columnar
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTime = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// testSchema has a column of every type, nullable and not
+func testSchema() Schema {
+	return Schema{
+		Fields: []Field{
+			{Name: "id", Type: TypeString},
+			{Name: "timestamp", Type: TypeTimestamp},
+			{Name: "temperature", Type: TypeFloat64, Metadata: map[string]string{"unit": "°C"}},
+			{Name: "notes", Type: TypeString, Nullable: true},
+			{Name: "deletedAt", Type: TypeTimestamp, Nullable: true},
+		},
+		Metadata: map[string]string{"dataset": "reports"},
+	}
+}
+
+// testRows are written in two batches of two and one rows
+var testRows = [][]any{
+	{"a", testTime, 25.5, "runway closure", nil},
+	{"b", testTime.Add(time.Hour), 26.0, nil, testTime},
+	{"c", testTime.Add(2 * time.Hour), -1.25, "", nil},
+}
+
+func writeRows(t *testing.T, writer Writer, rows [][]any) {
+	t.Helper()
+	for _, row := range rows {
+		require.NoError(t, writer.WriteRow(row...))
+	}
+	require.NoError(t, writer.Close())
+}
+
+func TestSchemaValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema Schema
+	}{
+		{"NoFields", Schema{}},
+		{"EmptyName", Schema{Fields: []Field{{Type: TypeString}}}},
+		{"DuplicateName", Schema{Fields: []Field{{Name: "id", Type: TypeString}, {Name: "id", Type: TypeFloat64}}}},
+		{"UnknownType", Schema{Fields: []Field{{Name: "id", Type: Type(42)}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := tt.schema.Validate()
+
+			// Assert
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWriteRow_InvalidValues(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	writer, err := NewParquetWriter(&buf, testSchema(), 10)
+	require.NoError(t, err)
+
+	// Act & Assert
+	assert.ErrorContains(t, writer.WriteRow("a", testTime), "row has 2 values, expected 5")
+	assert.ErrorContains(t, writer.WriteRow("a", testTime, "25.5", nil, nil), `field "temperature" expects a float64 value, got string`)
+	assert.ErrorContains(t, writer.WriteRow(nil, testTime, 25.5, nil, nil), `field "id" is not nullable`)
+
+	// Rejected rows are not buffered, so the batch stays consistent
+	require.NoError(t, writer.WriteRow(testRows[0]...))
+	assert.Equal(t, 1, writer.buffer.rows)
+	for _, builder := range writer.buffer.builder.Fields() {
+		assert.Equal(t, 1, builder.Len())
+	}
+	assert.Zero(t, buf.Len(), "nothing is written before a batch is full")
+}
+
+func TestWriters_Closed(t *testing.T) {
+	for name, newWriter := range map[string]func() (Writer, error){
+		"Parquet": func() (Writer, error) { return NewParquetWriter(&bytes.Buffer{}, testSchema(), 0) },
+		"Arrow":   func() (Writer, error) { return NewArrowWriter(&bytes.Buffer{}, testSchema(), 0) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			writer, err := newWriter()
+			require.NoError(t, err)
+			require.NoError(t, writer.Close())
+
+			// Act & Assert
+			assert.ErrorContains(t, writer.WriteRow(testRows[0]...), "writer is closed")
+			assert.ErrorContains(t, writer.Close(), "writer is closed")
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package columnar
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+)
+
+// DefaultBatchSize is the number of rows per row group or record batch when none is given
+const DefaultBatchSize = 10000
+
+// CreatedBy identifies this package in the files it writes
+const CreatedBy = "github.com/DangVTNhan/Scanner/be/pkg/columnar"
+
+// ParquetWriter writes rows as an uncompressed Apache Parquet file with one row group per batch.
+//
+// Schema metadata is stored as key-value metadata of the file and field metadata as
+// "<field>.<key>" entries. The schema is also embedded in Arrow IPC form under "ARROW:schema",
+// so Arrow based readers such as pyarrow restore field metadata and timestamp time zones.
+type ParquetWriter struct {
+	out       io.Writer
+	schema    *arrow.Schema
+	buffer    *recordBuffer
+	batchSize int
+	writer    *pqarrow.FileWriter // Created when the first row group is flushed
+	closed    bool
+}
+
+// NewParquetWriter creates a writer that flushes a row group every batchSize rows
+// (DefaultBatchSize if not positive). Nothing is written to w before the first row group is full
+// or the writer is closed.
+func NewParquetWriter(w io.Writer, schema Schema, batchSize int) (*ParquetWriter, error) {
+	if err := schema.Validate(); err != nil {
+		return nil, err
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	arrowSchema := schema.arrowSchema(parquetMetadata(&schema))
+	return &ParquetWriter{
+		out:       w,
+		schema:    arrowSchema,
+		buffer:    newRecordBuffer(&schema, arrowSchema),
+		batchSize: batchSize,
+	}, nil
+}
+
+// WriteRow appends a row, flushing a row group when the batch is full
+func (p *ParquetWriter) WriteRow(values ...any) error {
+	if p.closed {
+		return fmt.Errorf("writer is closed")
+	}
+	if err := p.buffer.append(values); err != nil {
+		return err
+	}
+	if p.buffer.rows >= p.batchSize {
+		return p.flush()
+	}
+	return nil
+}
+
+// Close flushes buffered rows and writes the file footer
+func (p *ParquetWriter) Close() error {
+	if p.closed {
+		return fmt.Errorf("writer is closed")
+	}
+	p.closed = true
+	defer p.buffer.release()
+
+	if err := p.flush(); err != nil {
+		return err
+	}
+	if err := p.start(); err != nil {
+		return err
+	}
+	if err := p.writer.Close(); err != nil {
+		return fmt.Errorf("failed to write parquet footer: %w", err)
+	}
+	return nil
+}
+
+// start creates the Parquet file writer once
+func (p *ParquetWriter) start() error {
+	if p.writer != nil {
+		return nil
+	}
+
+	props := parquet.NewWriterProperties(parquet.WithCreatedBy(CreatedBy))
+	arrowProps := pqarrow.NewArrowWriterProperties(pqarrow.WithStoreSchema())
+	writer, err := pqarrow.NewFileWriter(p.schema, nopCloser{p.out}, props, arrowProps)
+	if err != nil {
+		return fmt.Errorf("failed to create parquet writer: %w", err)
+	}
+	p.writer = writer
+	return nil
+}
+
+// flush writes the buffered rows as a row group
+func (p *ParquetWriter) flush() error {
+	if p.buffer.rows == 0 {
+		return nil
+	}
+	if err := p.start(); err != nil {
+		return err
+	}
+
+	record := p.buffer.flush()
+	defer record.Release()
+	if err := p.writer.Write(record); err != nil {
+		return fmt.Errorf("failed to write parquet row group: %w", err)
+	}
+	return nil
+}
+
+// parquetMetadata returns the schema metadata with the field metadata added as "<field>.<key>"
+// entries, for readers that do not understand the embedded Arrow schema
+func parquetMetadata(schema *Schema) map[string]string {
+	metadata := make(map[string]string, len(schema.Metadata))
+	for key, value := range schema.Metadata {
+		metadata[key] = value
+	}
+	for _, field := range schema.Fields {
+		for key, value := range field.Metadata {
+			metadata[field.Name+"."+key] = value
+		}
+	}
+	return metadata
+}
+
+// nopCloser keeps the Parquet writer from closing the underlying writer
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+var _ Writer = (*ParquetWriter)(nil)
@@ -0,0 +1,93 @@
+package columnar
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/apache/arrow-go/v18/parquet/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParquetWriter(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	writer, err := NewParquetWriter(&buf, testSchema(), 2)
+	require.NoError(t, err)
+
+	// Act
+	writeRows(t, writer, testRows)
+
+	// Assert
+	reader, err := file.NewParquetReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	defer reader.Close()
+
+	metadata := reader.MetaData()
+	assert.Equal(t, CreatedBy, metadata.GetCreatedBy())
+	assert.Equal(t, int64(3), metadata.GetNumRows())
+	require.Equal(t, 2, reader.NumRowGroups())
+	assert.Equal(t, int64(2), reader.RowGroup(0).NumRows())
+	assert.Equal(t, int64(1), reader.RowGroup(1).NumRows())
+
+	dataset := metadata.KeyValueMetadata().FindValue("dataset")
+	require.NotNil(t, dataset)
+	assert.Equal(t, "reports", *dataset)
+	unit := metadata.KeyValueMetadata().FindValue("temperature.unit")
+	require.NotNil(t, unit)
+	assert.Equal(t, "°C", *unit)
+
+	// Readers without Arrow support see plain Parquet types
+	columns := metadata.Schema
+	assert.Equal(t, parquet.Types.ByteArray, columns.Column(0).PhysicalType())
+	assert.Equal(t, parquet.Repetitions.Required, columns.Column(0).SchemaNode().RepetitionType())
+	assert.Equal(t, parquet.Types.Int64, columns.Column(1).PhysicalType())
+	timestampType, ok := columns.Column(1).LogicalType().(schema.TimestampLogicalType)
+	require.True(t, ok)
+	assert.True(t, timestampType.IsAdjustedToUTC())
+	assert.Equal(t, schema.TimeUnitMicros, timestampType.TimeUnit())
+	assert.Equal(t, parquet.Types.Double, columns.Column(2).PhysicalType())
+	assert.Equal(t, parquet.Repetitions.Optional, columns.Column(3).SchemaNode().RepetitionType())
+
+	// Arrow readers restore the schema from the embedded Arrow schema
+	table, err := pqarrow.ReadTable(context.Background(), bytes.NewReader(buf.Bytes()), nil, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	require.NoError(t, err)
+	defer table.Release()
+	assertArrowSchema(t, table.Schema())
+
+	tableReader := array.NewTableReader(table, -1)
+	defer tableReader.Release()
+	var records []arrow.RecordBatch
+	for tableReader.Next() {
+		record := tableReader.RecordBatch()
+		record.Retain()
+		defer record.Release()
+		records = append(records, record)
+	}
+	assertTestRows(t, records)
+}
+
+func TestParquetWriter_Empty(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	writer, err := NewParquetWriter(&buf, testSchema(), 0)
+	require.NoError(t, err)
+
+	// Act
+	require.NoError(t, writer.Close())
+
+	// Assert
+	reader, err := file.NewParquetReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	defer reader.Close()
+	assert.Zero(t, reader.MetaData().GetNumRows())
+	assert.Zero(t, reader.NumRowGroups())
+	assert.Equal(t, 5, reader.MetaData().Schema.NumColumns())
+}
@@ -0,0 +1,193 @@
+// Package columnar writes tabular data as Apache Parquet or Apache Arrow IPC files, using the
+// Apache Arrow Go implementation.
+//
+// Both writers buffer a bounded number of rows and flush them as a row group (Parquet) or
+// record batch (Arrow), so arbitrarily large datasets can be streamed with constant memory.
+// Only the types needed for exports are supported and data is written uncompressed.
+package columnar
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// Type is the logical type of a column
+type Type int
+
+const (
+	TypeString    Type = iota // UTF-8 string
+	TypeFloat64               // 64-bit IEEE 754 floating point number
+	TypeTimestamp             // Instant with microsecond precision, stored as UTC
+)
+
+// String returns the name of the type
+func (t Type) String() string {
+	switch t {
+	case TypeString:
+		return "string"
+	case TypeFloat64:
+		return "float64"
+	case TypeTimestamp:
+		return "timestamp"
+	}
+	return fmt.Sprintf("Type(%d)", int(t))
+}
+
+// Field describes a single column
+type Field struct {
+	Name     string
+	Type     Type
+	Nullable bool
+	Metadata map[string]string // Free-form properties such as the unit of the values
+}
+
+// Schema describes the columns of a file and properties of the file as a whole
+type Schema struct {
+	Fields   []Field
+	Metadata map[string]string
+}
+
+// Validate checks that the schema has uniquely named fields of supported types
+func (s *Schema) Validate() error {
+	if len(s.Fields) == 0 {
+		return fmt.Errorf("schema has no fields")
+	}
+
+	seen := make(map[string]bool, len(s.Fields))
+	for _, field := range s.Fields {
+		if field.Name == "" {
+			return fmt.Errorf("schema has a field without a name")
+		}
+		if seen[field.Name] {
+			return fmt.Errorf("duplicate field %q", field.Name)
+		}
+		seen[field.Name] = true
+
+		switch field.Type {
+		case TypeString, TypeFloat64, TypeTimestamp:
+		default:
+			return fmt.Errorf("field %q has unsupported type %s", field.Name, field.Type)
+		}
+	}
+	return nil
+}
+
+// Writer writes rows to a columnar file
+type Writer interface {
+	// WriteRow appends a row with one value per schema field, in schema order. Values are
+	// string, float64 or time.Time according to the field type, or nil for a null value.
+	WriteRow(values ...any) error
+
+	// Close flushes buffered rows and writes the file footer. It does not close the underlying writer.
+	Close() error
+}
+
+// arrowSchema returns the schema as an Arrow schema, with metadata as Arrow custom metadata
+func (s *Schema) arrowSchema(metadata map[string]string) *arrow.Schema {
+	fields := make([]arrow.Field, len(s.Fields))
+	for i, field := range s.Fields {
+		fields[i] = arrow.Field{
+			Name:     field.Name,
+			Type:     field.Type.arrowType(),
+			Nullable: field.Nullable,
+			Metadata: arrow.MetadataFrom(field.Metadata),
+		}
+	}
+	schemaMetadata := arrow.MetadataFrom(metadata)
+	return arrow.NewSchema(fields, &schemaMetadata)
+}
+
+// arrowType returns the Arrow data type of a column of type t
+func (t Type) arrowType() arrow.DataType {
+	switch t {
+	case TypeFloat64:
+		return arrow.PrimitiveTypes.Float64
+	case TypeTimestamp:
+		return &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}
+	}
+	return arrow.BinaryTypes.String
+}
+
+// recordBuffer buffers rows in Arrow builders until they are flushed as a record batch
+type recordBuffer struct {
+	fields  []Field
+	builder *array.RecordBuilder
+	rows    int
+}
+
+func newRecordBuffer(schema *Schema, arrowSchema *arrow.Schema) *recordBuffer {
+	return &recordBuffer{
+		fields:  schema.Fields,
+		builder: array.NewRecordBuilder(memory.DefaultAllocator, arrowSchema),
+	}
+}
+
+// append validates a row and adds it to the buffer
+func (b *recordBuffer) append(values []any) error {
+	if len(values) != len(b.fields) {
+		return fmt.Errorf("row has %d values, expected %d", len(values), len(b.fields))
+	}
+
+	// Validate the whole row first so a bad value does not leave columns of different lengths
+	for i, value := range values {
+		if err := checkValue(b.fields[i], value); err != nil {
+			return err
+		}
+	}
+	for i, value := range values {
+		appendValue(b.builder.Field(i), value)
+	}
+	b.rows++
+	return nil
+}
+
+// flush returns the buffered rows as a record batch, which the caller must release, and empties the buffer
+func (b *recordBuffer) flush() arrow.RecordBatch {
+	b.rows = 0
+	return b.builder.NewRecordBatch()
+}
+
+func (b *recordBuffer) release() {
+	b.builder.Release()
+}
+
+func checkValue(field Field, value any) error {
+	if value == nil {
+		if !field.Nullable {
+			return fmt.Errorf("field %q is not nullable", field.Name)
+		}
+		return nil
+	}
+
+	ok := false
+	switch field.Type {
+	case TypeString:
+		_, ok = value.(string)
+	case TypeFloat64:
+		_, ok = value.(float64)
+	case TypeTimestamp:
+		_, ok = value.(time.Time)
+	}
+	if !ok {
+		return fmt.Errorf("field %q expects a %s value, got %T", field.Name, field.Type, value)
+	}
+	return nil
+}
+
+// appendValue adds a checked value to the builder of its column
+func appendValue(builder array.Builder, value any) {
+	switch v := value.(type) {
+	case nil:
+		builder.AppendNull()
+	case string:
+		builder.(*array.StringBuilder).Append(v)
+	case float64:
+		builder.(*array.Float64Builder).Append(v)
+	case time.Time:
+		builder.(*array.TimestampBuilder).Append(arrow.Timestamp(v.UnixMicro()))
+	}
+}
@@ -0,0 +1,215 @@
+// Package feed writes syndication feeds for feed readers and calendar clients: Atom 1.0 and RSS 2.0 feeds
+// of entries, and iCalendar (RFC 5545) feeds of events.
+package feed
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+// Feed is a list of entries, newest first
+type Feed struct {
+	ID       string // Permanent, unique identifier of the feed, e.g. its URL
+	Title    string
+	Subtitle string    // Description of the feed
+	Link     string    // URL of the feed itself
+	HomeLink string    // URL of the site or API the feed belongs to
+	Author   string    // Name of the author of every entry
+	Updated  time.Time // Last time any entry changed
+	Entries  []Entry
+}
+
+// Entry is an item of a feed
+type Entry struct {
+	ID         string // Permanent, unique identifier of the entry, e.g. its URL
+	Title      string
+	Link       string // URL of a page showing the entry
+	Summary    string // Plain text; line breaks are kept by most readers
+	Categories []string
+	Published  time.Time
+	Updated    time.Time
+}
+
+// Content types of the feed formats
+const (
+	AtomContentType = "application/atom+xml; charset=utf-8"
+	RSSContentType  = "application/rss+xml; charset=utf-8"
+)
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Author   *atomAuthor `xml:"author,omitempty"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Link       *atomLink      `xml:"link,omitempty"`
+	Published  string         `xml:"published,omitempty"`
+	Updated    string         `xml:"updated"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// WriteAtom writes the feed as an Atom 1.0 document
+func (f *Feed) WriteAtom(w io.Writer) error {
+	doc := atomFeed{
+		ID:       f.ID,
+		Title:    f.Title,
+		Subtitle: f.Subtitle,
+		Updated:  atomTime(f.Updated),
+		Links:    []atomLink{{Rel: "self", Type: "application/atom+xml", Href: f.Link}},
+	}
+	if f.HomeLink != "" {
+		doc.Links = append(doc.Links, atomLink{Rel: "alternate", Href: f.HomeLink})
+	}
+	if f.Author != "" {
+		doc.Author = &atomAuthor{Name: f.Author}
+	}
+
+	for _, entry := range f.Entries {
+		item := atomEntry{
+			ID:      entry.ID,
+			Title:   entry.Title,
+			Updated: atomTime(entry.Updated),
+		}
+		if entry.Link != "" {
+			item.Link = &atomLink{Rel: "alternate", Href: entry.Link}
+		}
+		if !entry.Published.IsZero() {
+			item.Published = atomTime(entry.Published)
+		}
+		if entry.Summary != "" {
+			item.Summary = &atomText{Type: "text", Text: entry.Summary}
+		}
+		for _, category := range entry.Categories {
+			item.Categories = append(item.Categories, atomCategory{Term: category})
+		}
+		doc.Entries = append(doc.Entries, item)
+	}
+	return writeXML(w, doc)
+}
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link,omitempty"`
+	Description string   `xml:"description,omitempty"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+	Categories  []string `xml:"category"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// WriteRSS writes the feed as an RSS 2.0 document. RSS has no entry IDs of its own, so they become GUIDs
+// that are marked as permalinks when they equal the entry's link.
+func (f *Feed) WriteRSS(w io.Writer) error {
+	link := f.HomeLink
+	if link == "" {
+		link = f.Link
+	}
+	description := f.Subtitle
+	if description == "" {
+		description = f.Title
+	}
+
+	doc := rssDocument{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:       f.Title,
+			Link:        link,
+			Description: description,
+			AtomLink:    atomLink{Rel: "self", Type: "application/rss+xml", Href: f.Link},
+		},
+	}
+	if !f.Updated.IsZero() {
+		doc.Channel.LastBuildDate = rssTime(f.Updated)
+	}
+
+	for _, entry := range f.Entries {
+		item := rssItem{
+			Title:       entry.Title,
+			Link:        entry.Link,
+			Description: entry.Summary,
+			GUID:        rssGUID{IsPermaLink: entry.ID == entry.Link, Value: entry.ID},
+			Categories:  entry.Categories,
+		}
+		if published := entry.Published; !published.IsZero() {
+			item.PubDate = rssTime(published)
+		} else if !entry.Updated.IsZero() {
+			item.PubDate = rssTime(entry.Updated)
+		}
+		doc.Channel.Items = append(doc.Channel.Items, item)
+	}
+	return writeXML(w, doc)
+}
+
+// writeXML writes doc as an indented XML document with a declaration
+func writeXML(w io.Writer, doc any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// atomTime formats a time as RFC 3339 in UTC
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// rssTime formats a time as RFC 822 with a four-digit year, as RSS requires
+func rssTime(t time.Time) string {
+	return t.UTC().Format(time.RFC1123Z)
+}
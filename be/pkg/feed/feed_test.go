@@ -0,0 +1,192 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var published = time.Date(2024, 3, 1, 8, 30, 0, 0, time.FixedZone("SGT", 8*60*60))
+
+func testFeed() *Feed {
+	return &Feed{
+		ID:       "https://example.com/api/feeds/reports.atom",
+		Title:    "Weather <Reports> & more",
+		Subtitle: "Latest reports",
+		Link:     "https://example.com/api/feeds/reports.atom",
+		HomeLink: "https://example.com/api/reports",
+		Author:   "Changi",
+		Updated:  published,
+		Entries: []Entry{
+			{
+				ID:         "https://example.com/api/reports/1",
+				Title:      "WSSS · 01 Mar 2024",
+				Link:       "https://example.com/api/reports/1",
+				Summary:    "Temperature 31\nPressure 1008",
+				Categories: []string{"runway closure", "storm"},
+				Published:  published,
+				Updated:    published,
+			},
+			{
+				ID:      "urn:report:2",
+				Title:   "Second",
+				Link:    "https://example.com/api/reports/2",
+				Updated: published.Add(-time.Hour),
+			},
+		},
+	}
+}
+
+func TestWriteAtom(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+
+	// Act
+	err := testFeed().WriteAtom(&buf)
+
+	// Assert
+	require.NoError(t, err)
+	var doc struct {
+		XMLName xml.Name
+		Title   string `xml:"title"`
+		Updated string `xml:"updated"`
+		Links   []struct {
+			Rel  string `xml:"rel,attr"`
+			Href string `xml:"href,attr"`
+		} `xml:"link"`
+		Entries []struct {
+			ID         string `xml:"id"`
+			Published  string `xml:"published"`
+			Updated    string `xml:"updated"`
+			Summary    string `xml:"summary"`
+			Categories []struct {
+				Term string `xml:"term,attr"`
+			} `xml:"category"`
+		} `xml:"entry"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "http://www.w3.org/2005/Atom", doc.XMLName.Space)
+	assert.Equal(t, "feed", doc.XMLName.Local)
+	assert.Equal(t, "Weather <Reports> & more", doc.Title)
+	assert.Equal(t, "2024-03-01T00:30:00Z", doc.Updated, "times are in UTC")
+	require.Len(t, doc.Links, 2)
+	assert.Equal(t, "self", doc.Links[0].Rel)
+	assert.Equal(t, "alternate", doc.Links[1].Rel)
+	require.Len(t, doc.Entries, 2)
+	assert.Equal(t, "https://example.com/api/reports/1", doc.Entries[0].ID)
+	assert.Equal(t, "Temperature 31\nPressure 1008", doc.Entries[0].Summary)
+	require.Len(t, doc.Entries[0].Categories, 2)
+	assert.Equal(t, "storm", doc.Entries[0].Categories[1].Term)
+	assert.Empty(t, doc.Entries[1].Published)
+	assert.Equal(t, "2024-02-29T23:30:00Z", doc.Entries[1].Updated)
+}
+
+func TestWriteRSS(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+
+	// Act
+	err := testFeed().WriteRSS(&buf)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "<link>https://example.com/api/reports</link>")
+	assert.Contains(t, buf.String(), `<atom:link rel="self" type="application/rss+xml" href="https://example.com/api/feeds/reports.atom"></atom:link>`)
+	var doc struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Title         string `xml:"title"`
+			Description   string `xml:"description"`
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				GUID struct {
+					IsPermaLink string `xml:"isPermaLink,attr"`
+					Value       string `xml:",chardata"`
+				} `xml:"guid"`
+				PubDate    string   `xml:"pubDate"`
+				Categories []string `xml:"category"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "2.0", doc.Version)
+	assert.Equal(t, "Latest reports", doc.Channel.Description)
+	assert.Equal(t, "Fri, 01 Mar 2024 00:30:00 +0000", doc.Channel.LastBuildDate)
+	require.Len(t, doc.Channel.Items, 2)
+	assert.Equal(t, "true", doc.Channel.Items[0].GUID.IsPermaLink)
+	assert.Equal(t, []string{"runway closure", "storm"}, doc.Channel.Items[0].Categories)
+	assert.Equal(t, "false", doc.Channel.Items[1].GUID.IsPermaLink, "an ID other than the link is no permalink")
+	assert.Equal(t, "urn:report:2", doc.Channel.Items[1].GUID.Value)
+	assert.Equal(t, "Thu, 29 Feb 2024 23:30:00 +0000", doc.Channel.Items[1].PubDate, "entries without a publish time use their update time")
+}
+
+func TestWriteICS(t *testing.T) {
+	// Arrange
+	calendar := &Calendar{
+		ProdID:          "-//Example//Alerts//EN",
+		Name:            "Alerts",
+		RefreshInterval: 15 * time.Minute,
+		Events: []Event{
+			{
+				UID:         "1-2@example.com",
+				Stamp:       published,
+				Start:       published.Add(-time.Hour),
+				End:         published,
+				Summary:     "Major change; temperature, pressure",
+				Description: "Line one\nLine two \\ end",
+				URL:         "https://example.com/api/reports/2",
+				Categories:  []string{"major"},
+			},
+			{UID: "3@example.com", Stamp: published, Start: published, End: published, Summary: "Instant"},
+		},
+	}
+	var buf bytes.Buffer
+
+	// Act
+	err := calendar.WriteICS(&buf)
+
+	// Assert
+	require.NoError(t, err)
+	ics := buf.String()
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Example//Alerts//EN\r\n"))
+	assert.True(t, strings.HasSuffix(ics, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+	assert.NotContains(t, strings.ReplaceAll(ics, "\r\n", ""), "\n", "every line ends in CRLF")
+	assert.Contains(t, ics, "X-WR-CALNAME:Alerts\r\n")
+	assert.Contains(t, ics, "REFRESH-INTERVAL;VALUE=DURATION:PT15M\r\n")
+	assert.Contains(t, ics, "DTSTART:20240229T233000Z\r\nDTEND:20240301T003000Z\r\n")
+	assert.Contains(t, ics, `SUMMARY:Major change\; temperature\, pressure`+"\r\n")
+	assert.Contains(t, ics, `DESCRIPTION:Line one\nLine two \\ end`+"\r\n")
+	assert.Contains(t, ics, "CATEGORIES:major\r\n")
+	assert.Equal(t, 2, strings.Count(ics, "BEGIN:VEVENT"))
+	assert.Equal(t, 1, strings.Count(ics, "DTEND:"), "an event ending when it starts has no end")
+}
+
+func TestWriteLine_Folds(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	calendar := &Calendar{ProdID: "x", Events: []Event{{UID: "1", Summary: strings.Repeat("°", 100)}}}
+
+	// Act
+	err := calendar.WriteICS(&buf)
+
+	// Assert
+	require.NoError(t, err)
+	var summary []string
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), maxLineOctets)
+		if strings.HasPrefix(line, "SUMMARY:") || (len(summary) > 0 && strings.HasPrefix(line, " ")) {
+			summary = append(summary, line)
+		}
+	}
+	require.Greater(t, len(summary), 1)
+	unfolded := summary[0]
+	for _, line := range summary[1:] {
+		unfolded += strings.TrimPrefix(line, " ")
+	}
+	assert.Equal(t, "SUMMARY:"+strings.Repeat("°", 100), unfolded, "lines are only split between characters")
+}
@@ -0,0 +1,135 @@
+package feed
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Calendar is an iCalendar feed of events, as subscribed to by calendar clients
+type Calendar struct {
+	ProdID          string        // Identifier of the product that created the calendar, e.g. "-//Company//Product//EN"
+	Name            string        // Display name of the calendar
+	Description     string        // Optional description of the calendar
+	RefreshInterval time.Duration // How often clients should poll for changes; omitted when zero
+	Events          []Event
+}
+
+// Event is a timed event of a calendar
+type Event struct {
+	UID         string    // Globally unique, permanent identifier, e.g. "id@example.com"
+	Stamp       time.Time // Last time the event changed
+	Start       time.Time
+	End         time.Time // Omitted unless after Start, making the event end when it starts
+	Summary     string
+	Description string // Plain text; may span several lines
+	URL         string // Optional link to details of the event
+	Categories  []string
+}
+
+// ICSContentType is the content type of an iCalendar feed
+const ICSContentType = "text/calendar; charset=utf-8"
+
+// maxLineOctets is the longest content line iCalendar allows before it must be folded, excluding the line break
+const maxLineOctets = 75
+
+// icsTimeLayout formats times in UTC
+const icsTimeLayout = "20060102T150405Z"
+
+// WriteICS writes the calendar as an iCalendar document
+func (c *Calendar) WriteICS(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeLine(bw, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", escapeText(c.ProdID))
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		line("NAME", escapeText(c.Name))
+		line("X-WR-CALNAME", escapeText(c.Name))
+	}
+	if c.Description != "" {
+		line("DESCRIPTION", escapeText(c.Description))
+		line("X-WR-CALDESC", escapeText(c.Description))
+	}
+	if c.RefreshInterval > 0 {
+		line("REFRESH-INTERVAL;VALUE=DURATION", icsDuration(c.RefreshInterval))
+		line("X-PUBLISHED-TTL", icsDuration(c.RefreshInterval))
+	}
+
+	for _, event := range c.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", escapeText(event.UID))
+		line("DTSTAMP", icsTime(event.Stamp))
+		line("DTSTART", icsTime(event.Start))
+		if event.End.After(event.Start) {
+			line("DTEND", icsTime(event.End))
+		}
+		line("SUMMARY", escapeText(event.Summary))
+		if event.Description != "" {
+			line("DESCRIPTION", escapeText(event.Description))
+		}
+		if event.URL != "" {
+			line("URL;VALUE=URI", event.URL)
+		}
+		if len(event.Categories) > 0 {
+			categories := make([]string, len(event.Categories))
+			for i, category := range event.Categories {
+				categories[i] = escapeText(category)
+			}
+			line("CATEGORIES", strings.Join(categories, ","))
+		}
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+// writeLine writes a content line ending in CRLF, folding it into continuation lines that start with a space
+// so no line exceeds maxLineOctets. Lines are only split between UTF-8 characters.
+func writeLine(w *bufio.Writer, content string) {
+	limit := maxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		w.WriteString(content[:cut])
+		w.WriteString("\r\n ")
+		content = content[cut:]
+		limit = maxLineOctets - 1 // The leading space counts towards the limit
+	}
+	w.WriteString(content)
+	w.WriteString("\r\n")
+}
+
+// textEscaper escapes the characters with a special meaning in iCalendar text values
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// escapeText escapes a text value
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// icsTime formats a time in UTC
+func icsTime(t time.Time) string {
+	return t.UTC().Format(icsTimeLayout)
+}
+
+// icsDuration formats a duration as whole minutes, e.g. PT15M
+func icsDuration(d time.Duration) string {
+	minutes := int64(d / time.Minute)
+	if minutes < 1 {
+		minutes = 1
+	}
+	return fmt.Sprintf("PT%dM", minutes)
+}
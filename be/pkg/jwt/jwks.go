@@ -0,0 +1,85 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/MicahParks/jwkset"
+	"github.com/MicahParks/keyfunc/v3"
+	"golang.org/x/time/rate"
+)
+
+// MinReloadInterval limits how often tokens signed with an unknown key ID reload the key set,
+// so forged key IDs cannot be used to hammer the key source
+const MinReloadInterval = 30 * time.Second
+
+// unknownKeyWait is how long a token with an unknown key ID waits for its turn to reload the key set
+// before it is rejected
+const unknownKeyWait = time.Second
+
+// NewURLKeys loads the key set at url, such as the jwks_uri of an OpenID Connect provider, and fails if it cannot.
+// Until ctx is done, the key set is reloaded in the background every refresh interval (1h if 0), and when a token
+// names a key that it does not have, e.g. after the provider rotated its keys. Reloads fetch the key set before
+// replacing the keys, so verification never waits for a fetch it did not start. When a reload fails, the keys
+// loaded before are kept.
+func NewURLKeys(ctx context.Context, client *http.Client, url string, refresh time.Duration) (keyfunc.Keyfunc, error) {
+	failFirst := false
+	keys, err := keyfunc.NewDefaultOverrideCtx(ctx, []string{url}, keyfunc.Override{
+		Client:                    client,
+		HTTPTimeout:               client.Timeout,
+		NoErrorReturnFirstHTTPReq: &failFirst,
+		RateLimitWaitMax:          unknownKeyWait,
+		RefreshErrorHandlerFunc: func(url string) func(ctx context.Context, err error) {
+			return func(ctx context.Context, err error) {
+				log.Printf("Failed to reload key set from %s: %v", url, err)
+			}
+		},
+		RefreshInterval:   refresh,
+		RefreshUnknownKID: rate.NewLimiter(rate.Every(MinReloadInterval), 1),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load key set: %w", err)
+	}
+	return keys, nil
+}
+
+// NewFileKeys loads the key set from a file, and reloads it like NewURLKeys
+func NewFileKeys(ctx context.Context, path string, refresh time.Duration) (keyfunc.Keyfunc, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load key set: %w", err)
+	}
+	transport := &http.Transport{}
+	transport.RegisterProtocol("file", http.NewFileTransport(http.Dir("/")))
+	client := &http.Client{Transport: transport, Timeout: 10 * time.Second}
+	return NewURLKeys(ctx, client, "file://"+filepath.ToSlash(path), refresh)
+}
+
+// Key is a public key of a static key set
+type Key struct {
+	ID        string           // "kid" of the tokens it signs
+	Algorithm string           // "alg" of the tokens it signs; any of Algorithms if empty
+	Public    crypto.PublicKey // *rsa.PublicKey, *ecdsa.PublicKey or ed25519.PublicKey
+}
+
+// NewKeySet returns a static key set of the keys, e.g. for tests
+func NewKeySet(keys ...Key) (keyfunc.Keyfunc, error) {
+	storage := jwkset.NewMemoryStorage()
+	for _, key := range keys {
+		jwk, err := jwkset.NewJWKFromKey(key.Public, jwkset.JWKOptions{
+			Metadata: jwkset.JWKMetadataOptions{KID: key.ID, ALG: jwkset.ALG(key.Algorithm), USE: jwkset.UseSig},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", key.ID, err)
+		}
+		if err := storage.KeyWrite(context.Background(), jwk); err != nil {
+			return nil, err
+		}
+	}
+	return keyfunc.New(keyfunc.Options{Storage: storage})
+}
@@ -0,0 +1,147 @@
+package jwt
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keySetJSON returns a JSON Web Key Set document with testRSAKey under each key ID
+func keySetJSON(t *testing.T, kids ...string) []byte {
+	keys := make([]map[string]string, 0, len(kids))
+	for _, kid := range kids {
+		keys = append(keys, map[string]string{
+			"kty": "RSA", "kid": kid, "use": "sig", "alg": "RS256",
+			"n": base64.RawURLEncoding.EncodeToString(testRSAKey.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(testRSAKey.E)).Bytes()),
+		})
+	}
+	data, err := json.Marshal(map[string]interface{}{"keys": keys})
+	require.NoError(t, err)
+	return data
+}
+
+// keySetServer serves the key set returned by keySet and counts its requests
+func keySetServer(t *testing.T, keySet func() (int, []byte)) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		status, body := keySet()
+		w.WriteHeader(status)
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestNewFileKeys(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, keySetJSON(t, "k1"), 0o600))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Act
+	keys, err := NewFileKeys(ctx, path, time.Hour)
+	_, missingErr := NewFileKeys(ctx, path+".missing", time.Hour)
+
+	// Assert
+	require.NoError(t, err)
+	verifier := newTestVerifier(t)
+	verifier.Keys = keys
+	claims, err := verifier.Verify(context.Background(), mustSign(t, testClaims(), "RS256", "k1", testRSAKey))
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Subject)
+	assert.ErrorContains(t, missingErr, "failed to load key set")
+}
+
+func TestNewURLKeys(t *testing.T) {
+	// Arrange
+	status := http.StatusOK
+	server, _ := keySetServer(t, func() (int, []byte) { return status, keySetJSON(t, "k1") })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Act
+	keys, err := NewURLKeys(ctx, server.Client(), server.URL, time.Hour)
+	status = http.StatusInternalServerError
+	_, failedErr := NewURLKeys(ctx, server.Client(), server.URL, time.Hour)
+
+	// Assert
+	require.NoError(t, err)
+	verifier := newTestVerifier(t)
+	verifier.Keys = keys
+	_, err = verifier.Verify(context.Background(), mustSign(t, testClaims(), "RS256", "k1", testRSAKey))
+	assert.NoError(t, err)
+	assert.ErrorContains(t, failedErr, "failed to load key set")
+}
+
+func TestNewURLKeys_ReloadsForUnknownKeys(t *testing.T) {
+	// Arrange
+	kids := []string{"k1"}
+	status := http.StatusOK
+	server, requests := keySetServer(t, func() (int, []byte) { return status, keySetJSON(t, kids...) })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	keys, err := NewURLKeys(ctx, server.Client(), server.URL, time.Hour)
+	require.NoError(t, err)
+	verifier := newTestVerifier(t)
+	verifier.Keys = keys
+	verify := func(kid string) error {
+		_, err := verifier.Verify(context.Background(), mustSign(t, testClaims(), "RS256", kid, testRSAKey))
+		return err
+	}
+
+	// Act & Assert
+	kids = []string{"k1", "k2"}
+	assert.NoError(t, verify("k2"), "an unknown key ID reloads the key set")
+	assert.Equal(t, int32(2), requests.Load())
+
+	kids = []string{"k1", "k2", "k3"}
+	assert.Error(t, verify("k3"), "unknown key IDs do not reload before MinReloadInterval")
+	assert.Equal(t, int32(2), requests.Load())
+
+	status = http.StatusInternalServerError
+	assert.NoError(t, verify("k1"), "the keys are kept while reloads fail")
+}
+
+func TestNewURLKeys_VerifiesDuringReload(t *testing.T) {
+	// Arrange
+	entered := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var reloading atomic.Bool
+	server, _ := keySetServer(t, func() (int, []byte) {
+		if reloading.Load() {
+			entered <- struct{}{}
+			<-release
+		}
+		return http.StatusOK, keySetJSON(t, "k1")
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	keys, err := NewURLKeys(ctx, server.Client(), server.URL, time.Hour)
+	require.NoError(t, err)
+	verifier := newTestVerifier(t)
+	verifier.Keys = keys
+	reloading.Store(true)
+	go verifier.Verify(context.Background(), mustSign(t, testClaims(), "RS256", "rotated", testRSAKey))
+	<-entered
+
+	// Act
+	_, err = verifier.Verify(context.Background(), mustSign(t, testClaims(), "RS256", "k1", testRSAKey))
+	close(release)
+
+	// Assert
+	assert.NoError(t, err, "a reload in flight does not block tokens with known keys")
+}
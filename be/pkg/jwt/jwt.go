@@ -0,0 +1,147 @@
+// Package jwt verifies JSON Web Tokens (RFC 7519) signed with asymmetric keys from a JSON Web Key Set. Tokens are
+// parsed by github.com/golang-jwt/jwt/v5 and keys are resolved by github.com/MicahParks/keyfunc/v3.
+// Symmetric (HS*) and unsigned ("none") tokens are rejected.
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MicahParks/keyfunc/v3"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+)
+
+// ErrKeysUnavailable is returned by Verify, wrapped, while no signing keys could be loaded
+var ErrKeysUnavailable = errors.New("signing keys unavailable")
+
+// Algorithms are the accepted signing algorithms
+var Algorithms = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+// Claims are the registered claims of a verified token, and all of its claims in Raw
+type Claims struct {
+	Issuer    string
+	Subject   string
+	Audience  []string
+	ExpiresAt time.Time
+	NotBefore time.Time // Zero if the token has no "nbf" claim
+	IssuedAt  time.Time // Zero if the token has no "iat" claim
+	ID        string
+
+	Raw map[string]interface{} // Every claim as decoded from JSON, with numbers as json.Number
+}
+
+// String returns a string claim, or "" if it is missing or not a string
+func (c *Claims) String(name string) string {
+	s, _ := c.Raw[name].(string)
+	return s
+}
+
+// Strings returns a claim that is a string or an array of strings, e.g. "aud" or "groups"
+func (c *Claims) Strings(name string) []string {
+	switch value := c.Raw[name].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// Verifier checks the signature, expiry, issuer and audience of tokens
+type Verifier struct {
+	Keys     keyfunc.Keyfunc  // Keys that may sign tokens, e.g. from NewURLKeys
+	Issuer   string           // Required "iss" claim; not checked if empty
+	Audience string           // Value that the "aud" claim must contain; not checked if empty
+	Leeway   time.Duration    // Allowed clock skew when checking "exp" and "nbf"
+	Now      func() time.Time // Current time; time.Now if nil
+}
+
+// Verify checks a compact serialized token and returns its claims. Tokens must have an "exp" claim.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	options := []jwtv5.ParserOption{
+		jwtv5.WithValidMethods(Algorithms),
+		jwtv5.WithExpirationRequired(),
+		jwtv5.WithLeeway(v.Leeway),
+		jwtv5.WithJSONNumber(),
+	}
+	if v.Issuer != "" {
+		options = append(options, jwtv5.WithIssuer(v.Issuer))
+	}
+	if v.Audience != "" {
+		options = append(options, jwtv5.WithAudience(v.Audience))
+	}
+	if v.Now != nil {
+		options = append(options, jwtv5.WithTimeFunc(v.Now))
+	}
+
+	raw := jwtv5.MapClaims{}
+	if _, err := jwtv5.NewParser(options...).ParseWithClaims(token, raw, v.keyfunc(ctx)); err != nil {
+		return nil, err
+	}
+	return newClaims(raw)
+}
+
+// keyfunc resolves the key of a token, and reports ErrKeysUnavailable rather than an unknown key while the key set
+// is empty, e.g. because the identity provider could not be reached
+func (v *Verifier) keyfunc(ctx context.Context) jwtv5.Keyfunc {
+	resolve := v.Keys.KeyfuncCtx(ctx)
+	return func(token *jwtv5.Token) (interface{}, error) {
+		key, err := resolve(token)
+		if set, ok := key.(jwtv5.VerificationKeySet); err != nil || ok && len(set.Keys) == 0 {
+			if keys, readErr := v.Keys.Storage().KeyReadAll(ctx); readErr != nil || len(keys) == 0 {
+				return nil, ErrKeysUnavailable
+			}
+		}
+		return key, err
+	}
+}
+
+// newClaims reads the registered claims of a verified token
+func newClaims(raw jwtv5.MapClaims) (*Claims, error) {
+	claims := &Claims{Raw: raw}
+	var err error
+	if claims.Issuer, err = raw.GetIssuer(); err != nil {
+		return nil, err
+	}
+	if claims.Subject, err = raw.GetSubject(); err != nil {
+		return nil, err
+	}
+	if claims.Audience, err = raw.GetAudience(); err != nil {
+		return nil, err
+	}
+	for _, date := range []struct {
+		get  func() (*jwtv5.NumericDate, error)
+		into *time.Time
+	}{
+		{raw.GetExpirationTime, &claims.ExpiresAt},
+		{raw.GetNotBefore, &claims.NotBefore},
+		{raw.GetIssuedAt, &claims.IssuedAt},
+	} {
+		value, err := date.get()
+		if err != nil {
+			return nil, err
+		}
+		if value != nil {
+			*date.into = value.Time
+		}
+	}
+	if id, ok := raw["jti"]; ok {
+		if claims.ID, ok = id.(string); !ok {
+			return nil, fmt.Errorf("%w: jti is invalid", jwtv5.ErrTokenMalformed)
+		}
+	}
+	return claims, nil
+}
@@ -0,0 +1,206 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testNow is the time at which test tokens are verified
+var testNow = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// testRSAKey is generated once, since RSA key generation is slow
+var testRSAKey = mustRSAKey()
+
+func mustRSAKey() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// testClaims returns valid claims for a verifier created by newTestVerifier
+func testClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"iss":  "https://sso.example.com",
+		"sub":  "user-1",
+		"aud":  "weather-api",
+		"exp":  testNow.Add(time.Hour).Unix(),
+		"iat":  testNow.Add(-time.Minute).Unix(),
+		"name": "Jane Doe",
+	}
+}
+
+func newTestVerifier(t *testing.T, keys ...Key) *Verifier {
+	set, err := NewKeySet(keys...)
+	require.NoError(t, err)
+	return &Verifier{
+		Keys:     set,
+		Issuer:   "https://sso.example.com",
+		Audience: "weather-api",
+		Leeway:   time.Minute,
+		Now:      func() time.Time { return testNow },
+	}
+}
+
+func mustSign(t *testing.T, claims map[string]interface{}, alg, kid string, key crypto.Signer) string {
+	token := jwtv5.NewWithClaims(jwtv5.GetSigningMethod(alg), jwtv5.MapClaims(claims))
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestVerify_Algorithms(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ec384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	edPublic, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	for alg, tc := range map[string]struct {
+		kid string
+		key crypto.Signer
+	}{
+		"RS256": {kid: "rsa", key: testRSAKey},
+		"RS512": {kid: "rsa", key: testRSAKey},
+		"PS256": {kid: "rsa", key: testRSAKey},
+		"ES256": {kid: "ec", key: ecKey},
+		"ES384": {kid: "ec384", key: ec384Key},
+		"EdDSA": {kid: "ed", key: edKey},
+		"NoKid": {key: ecKey},
+	} {
+		t.Run(alg, func(t *testing.T) {
+			// Arrange
+			verifier := newTestVerifier(t,
+				Key{ID: "rsa", Public: &testRSAKey.PublicKey},
+				Key{ID: "ec", Public: &ecKey.PublicKey},
+				Key{ID: "ec384", Public: &ec384Key.PublicKey},
+				Key{ID: "ed", Public: edPublic},
+			)
+			if alg == "NoKid" {
+				alg = "ES256"
+			}
+			token := mustSign(t, testClaims(), alg, tc.kid, tc.key)
+
+			// Act
+			claims, err := verifier.Verify(context.Background(), token)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, "https://sso.example.com", claims.Issuer)
+			assert.Equal(t, "user-1", claims.Subject)
+			assert.Equal(t, []string{"weather-api"}, claims.Audience)
+			assert.True(t, testNow.Add(time.Hour).Equal(claims.ExpiresAt))
+			assert.True(t, testNow.Add(-time.Minute).Equal(claims.IssuedAt))
+			assert.True(t, claims.NotBefore.IsZero())
+			assert.Equal(t, "Jane Doe", claims.String("name"))
+		})
+	}
+}
+
+func TestVerify_Claims(t *testing.T) {
+	for name, tc := range map[string]struct {
+		claims  func(claims map[string]interface{})
+		wantErr error
+	}{
+		"AudienceArray":    {claims: func(c map[string]interface{}) { c["aud"] = []string{"other", "weather-api"} }},
+		"WithinLeeway":     {claims: func(c map[string]interface{}) { c["exp"] = testNow.Add(-30 * time.Second).Unix() }},
+		"NotBeforePassed":  {claims: func(c map[string]interface{}) { c["nbf"] = testNow.Add(-time.Hour).Unix() }},
+		"FractionalExpiry": {claims: func(c map[string]interface{}) { c["exp"] = float64(testNow.Unix()) + 3600.5 }},
+		"Expired":          {claims: func(c map[string]interface{}) { c["exp"] = testNow.Add(-2 * time.Minute).Unix() }, wantErr: jwtv5.ErrTokenExpired},
+		"NoExpiry":         {claims: func(c map[string]interface{}) { delete(c, "exp") }, wantErr: jwtv5.ErrTokenRequiredClaimMissing},
+		"NotValidYet":      {claims: func(c map[string]interface{}) { c["nbf"] = testNow.Add(5 * time.Minute).Unix() }, wantErr: jwtv5.ErrTokenNotValidYet},
+		"WrongIssuer":      {claims: func(c map[string]interface{}) { c["iss"] = "https://evil.example.com" }, wantErr: jwtv5.ErrTokenInvalidIssuer},
+		"WrongAudience":    {claims: func(c map[string]interface{}) { c["aud"] = []string{"other"} }, wantErr: jwtv5.ErrTokenInvalidAudience},
+		"NoAudience":       {claims: func(c map[string]interface{}) { delete(c, "aud") }, wantErr: jwtv5.ErrTokenRequiredClaimMissing},
+		"ExpiryNotNumber":  {claims: func(c map[string]interface{}) { c["exp"] = "tomorrow" }, wantErr: jwtv5.ErrInvalidType},
+		"SubjectNotString": {claims: func(c map[string]interface{}) { c["sub"] = 42 }, wantErr: jwtv5.ErrInvalidType},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			verifier := newTestVerifier(t, Key{Public: &testRSAKey.PublicKey})
+			claims := testClaims()
+			tc.claims(claims)
+			token := mustSign(t, claims, "RS256", "", testRSAKey)
+
+			// Act
+			_, err := verifier.Verify(context.Background(), token)
+
+			// Assert
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerify_RejectsForgedTokens(t *testing.T) {
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	verifier := newTestVerifier(t, Key{ID: "rsa", Algorithm: "RS256", Public: &testRSAKey.PublicKey})
+	token := mustSign(t, testClaims(), "RS256", "rsa", testRSAKey)
+	parts := strings.Split(token, ".")
+
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	tampered := testClaims()
+	tampered["sub"] = "admin"
+	tamperedParts := strings.Split(mustSign(t, tampered, "RS256", "rsa", testRSAKey), ".")
+
+	for name, tc := range map[string]struct {
+		token   string
+		wantErr error
+	}{
+		"AlgNone":        {token: encode(`{"alg":"none"}`) + "." + parts[1] + ".", wantErr: jwtv5.ErrTokenSignatureInvalid},
+		"HMAC":           {token: encode(`{"alg":"HS256","kid":"rsa"}`) + "." + parts[1] + "." + parts[2], wantErr: jwtv5.ErrTokenSignatureInvalid},
+		"SwappedPayload": {token: parts[0] + "." + tamperedParts[1] + "." + parts[2], wantErr: jwtv5.ErrTokenSignatureInvalid},
+		"OtherKey":       {token: mustSign(t, testClaims(), "ES256", "rsa", otherKey), wantErr: jwtv5.ErrTokenUnverifiable},
+		"AlgMismatch":    {token: mustSign(t, testClaims(), "PS256", "rsa", testRSAKey), wantErr: jwtv5.ErrTokenUnverifiable},
+		"UnknownKid":     {token: mustSign(t, testClaims(), "RS256", "rotated", testRSAKey), wantErr: jwtv5.ErrTokenUnverifiable},
+		"TwoParts":       {token: parts[0] + "." + parts[1], wantErr: jwtv5.ErrTokenMalformed},
+		"Garbage":        {token: "not.a.token", wantErr: jwtv5.ErrTokenMalformed},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			claims, err := verifier.Verify(context.Background(), tc.token)
+
+			// Assert
+			assert.Nil(t, claims)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestVerify_KeysUnavailable(t *testing.T) {
+	// Arrange
+	verifier := newTestVerifier(t)
+
+	for name, kid := range map[string]string{"Kid": "rsa", "NoKid": ""} {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			claims, err := verifier.Verify(context.Background(), mustSign(t, testClaims(), "RS256", kid, testRSAKey))
+
+			// Assert
+			assert.Nil(t, claims)
+			assert.ErrorIs(t, err, ErrKeysUnavailable)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+/**
+ * Proxy from the browser to the backend API
+ *
+ * Requests to /api/* are forwarded to API_URL with the API key of API_KEY, so the key stays on the
+ * server and never reaches the browser. Both are read when the server runs, not when it is built.
+ */
+
+import { NextRequest } from "next/server";
+
+/**
+ * Base URL of the backend API, as seen from the frontend server
+ */
+const API_URL = process.env.API_URL || "http://localhost:8080/api";
+
+/**
+ * Request headers passed on to the backend
+ */
+const FORWARDED_REQUEST_HEADERS = ["accept", "content-type"];
+
+/**
+ * Response headers passed back to the browser
+ */
+const FORWARDED_RESPONSE_HEADERS = [
+  "content-type",
+  "content-disposition",
+  "ratelimit-limit",
+  "ratelimit-remaining",
+  "ratelimit-reset",
+  "ratelimit-policy",
+  "retry-after",
+];
+
+async function proxy(
+  request: NextRequest,
+  { params }: { params: Promise<{ path: string[] }> }
+): Promise<Response> {
+  const { path } = await params;
+  const url = `${API_URL}/${path.map(encodeURIComponent).join("/")}${request.nextUrl.search}`;
+
+  const headers = new Headers();
+  for (const name of FORWARDED_REQUEST_HEADERS) {
+    const value = request.headers.get(name);
+    if (value) {
+      headers.set(name, value);
+    }
+  }
+  if (process.env.API_KEY) {
+    headers.set("X-API-Key", process.env.API_KEY);
+  }
+
+  const hasBody = request.method !== "GET" && request.method !== "HEAD";
+  const response = await fetch(url, {
+    method: request.method,
+    headers,
+    body: hasBody ? await request.arrayBuffer() : undefined,
+    cache: "no-store",
+  });
+
+  const responseHeaders = new Headers();
+  for (const name of FORWARDED_RESPONSE_HEADERS) {
+    const value = response.headers.get(name);
+    if (value) {
+      responseHeaders.set(name, value);
+    }
+  }
+  return new Response(response.body, {
+    status: response.status,
+    headers: responseHeaders,
+  });
+}
+
+export const GET = proxy;
+export const POST = proxy;
+export const PUT = proxy;
+export const PATCH = proxy;
+export const DELETE = proxy;
@@ -3,10 +3,10 @@
  */
 
 /**
- * Base URL for the API
+ * Base URL for the API. By default requests go to the proxy of this app (src/app/api), which adds
+ * the API key on the server, so that the key is never part of the JavaScript sent to browsers.
  */
-export const API_BASE_URL =
-  process.env.NEXT_PUBLIC_API_URL || "http://localhost:8080/api";
+export const API_BASE_URL = process.env.NEXT_PUBLIC_API_URL || "/api";
 
 /**
  * Default fetch options for API requests